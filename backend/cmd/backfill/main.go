@@ -0,0 +1,111 @@
+// backend/cmd/backfill/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/supraik/Freelance-Portfolio/internal/backfill"
+	"github.com/supraik/Freelance-Portfolio/internal/cache"
+	"github.com/supraik/Freelance-Portfolio/internal/config"
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/imagesize"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
+	"github.com/supraik/Freelance-Portfolio/internal/variants"
+)
+
+// job runs one backfill
+type job func(ctx context.Context, db *database.DB, cfg *config.Config, storage *services.StorageService, opts backfill.Options) (*backfill.Report, error)
+
+var jobs = map[string]job{
+	"dimensions": func(ctx context.Context, db *database.DB, cfg *config.Config, storage *services.StorageService, opts backfill.Options) (*backfill.Report, error) {
+		aspect := imagesize.Thresholds{Landscape: cfg.AspectLandscapeMin, Portrait: cfg.AspectPortraitMax}
+		return backfill.Dimensions(ctx, db, storage, aspect, opts)
+	},
+	"placeholders": func(ctx context.Context, db *database.DB, cfg *config.Config, storage *services.StorageService, opts backfill.Options) (*backfill.Report, error) {
+		return backfill.Placeholders(ctx, db, storage, opts)
+	},
+	"variants": func(ctx context.Context, db *database.DB, cfg *config.Config, storage *services.StorageService, opts backfill.Options) (*backfill.Report, error) {
+		return backfill.Variants(ctx, db, storage, opts)
+	},
+}
+
+func main() {
+	log.SetFlags(0)
+
+	all := flag.Bool("all", false, "redo every image, not only those missing a value")
+	remote := flag.Bool("remote", true, "fetch images hosted elsewhere (e.g. Cloudinary) over HTTP")
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: backfill [flags] JOB\n\nJobs: %s\n\n", strings.Join(jobNames(), ", "))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 || jobs[flag.Arg(0)] == nil {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.Connect(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// The columns being filled in come from the latest migrations
+	if err := database.Migrate(db); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	generator := variants.New(variants.Options{Widths: cfg.ImageVariantWidths, Quality: cfg.ImageVariantQuality, CWebP: cfg.CWebPPath})
+	storage := services.NewStorageService(cfg.UploadDir, cfg.MaxFileSize, generator)
+	report, err := jobs[flag.Arg(0)](ctx, db, cfg, storage, backfill.Options{
+		All:    *all,
+		Remote: *remote,
+		DryRun: *dryRun,
+		Logf:   log.Printf,
+	})
+	if err != nil {
+		log.Fatalf("Backfill failed: %v", err)
+	}
+
+	if *dryRun {
+		log.Println("Dry run - nothing was changed")
+	} else {
+		log.Printf("✅ Backfilled %s", flag.Arg(0))
+
+		// Running servers would keep serving cached images until they expire
+		if report.Updated > 0 {
+			if err := cache.PublishPurge(ctx, db); err != nil {
+				log.Printf("⚠️  Failed to notify running servers, restart them to see the changes: %v", err)
+			}
+		}
+	}
+	log.Printf("   %d updated, %d unchanged, %d skipped, %d failed",
+		report.Updated, report.Unchanged, report.Skipped, report.Failed)
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// jobNames lists the jobs in a stable order for the usage message
+func jobNames() []string {
+	names := make([]string, 0, len(jobs))
+	for name := range jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,71 @@
+// backend/cmd/backup/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/backup"
+	"github.com/supraik/Freelance-Portfolio/internal/config"
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	output := flag.String("o", "", "archive path (default portfolio-backup-<timestamp>.tar.gz)")
+	noUsers := flag.Bool("no-users", false, "do not export admin users")
+	noHashes := flag.Bool("no-password-hashes", false, "export users and password galleries without their password hashes")
+	noMedia := flag.Bool("no-media", false, "do not bundle files from the upload directory")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.Connect(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("portfolio-backup-%s.tar.gz", time.Now().Format("20060102-150405"))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("Failed to create archive: %v", err)
+	}
+
+	storage := services.NewStorageService(cfg.UploadDir, cfg.MaxFileSize, nil)
+	manifest, err := backup.Create(context.Background(), db, storage, f, backup.Options{
+		IncludeUsers:          !*noUsers,
+		IncludePasswordHashes: !*noHashes,
+		IncludeMedia:          !*noMedia,
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		log.Fatalf("Backup failed: %v", err)
+	}
+
+	images := 0
+	for _, cat := range manifest.Categories {
+		images += len(cat.Images)
+	}
+
+	log.Printf("✅ Backup written to %s", path)
+	log.Printf("   %d galleries, %d images, %d sections, %d messages, %d users, %d media files",
+		len(manifest.Categories), images, len(manifest.Sections), len(manifest.Contacts),
+		len(manifest.Users), len(manifest.Media))
+}
@@ -0,0 +1,219 @@
+// backend/cmd/migrate/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/supraik/Freelance-Portfolio/internal/config"
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+const usage = `Usage: migrate <command> [flags]
+
+Commands:
+  up      [-to VERSION]   Apply pending migrations (optionally up to VERSION)
+  down    [-steps N]      Roll back the last N applied migrations (default 1)
+  status                  Show applied and pending migrations
+  redo                    Roll back and re-apply the latest migration
+  create  NAME [-dir DIR] Scaffold a new numbered up/down migration pair
+  verify                  Check applied checksums and the repository schema manifest
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command, args := os.Args[1], os.Args[2:]
+
+	switch command {
+	case "up":
+		runUp(args)
+	case "down":
+		runDown(args)
+	case "status":
+		runStatus(args)
+	case "redo":
+		runRedo(args)
+	case "create":
+		runCreate(args)
+	case "verify":
+		runVerify(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n%s", command, usage)
+		os.Exit(2)
+	}
+}
+
+func runUp(args []string) {
+	fs := flag.NewFlagSet("up", flag.ExitOnError)
+	target := fs.Int("to", 0, "apply migrations up to and including this version")
+	fs.Parse(args)
+
+	migrator := newMigrator()
+	applied, err := migrator.UpTo(context.Background(), *target)
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+
+	log.Printf("✅ Applied %d migration(s)", applied)
+}
+
+func runDown(args []string) {
+	fs := flag.NewFlagSet("down", flag.ExitOnError)
+	steps := fs.Int("steps", 1, "number of migrations to roll back")
+	fs.Parse(args)
+
+	if *steps < 1 {
+		log.Fatal("-steps must be at least 1")
+	}
+
+	migrator := newMigrator()
+	rolledBack, err := migrator.Down(context.Background(), *steps)
+	for _, m := range rolledBack {
+		log.Printf("↩️  Rolled back %03d_%s", m.Version, m.Name)
+	}
+	if err != nil {
+		log.Fatalf("Rollback failed: %v", err)
+	}
+
+	log.Printf("✅ Rolled back %d migration(s)", len(rolledBack))
+}
+
+func runStatus(args []string) {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	fs.Parse(args)
+
+	migrator := newMigrator()
+	statuses, err := migrator.Status(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to read migration status: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+
+	pending := 0
+	for _, s := range statuses {
+		state := "pending"
+		appliedAt := "-"
+		switch {
+		case s.Missing:
+			state = "applied (file missing)"
+		case s.Modified:
+			state = "applied (modified)"
+		case s.Applied:
+			state = "applied"
+		default:
+			pending++
+		}
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d pending migration(s)\n", pending)
+}
+
+func runRedo(args []string) {
+	fs := flag.NewFlagSet("redo", flag.ExitOnError)
+	fs.Parse(args)
+
+	migrator := newMigrator()
+	m, err := migrator.Redo(context.Background())
+	if err != nil {
+		log.Fatalf("Redo failed: %v", err)
+	}
+
+	log.Printf("✅ Redid %03d_%s", m.Version, m.Name)
+}
+
+func runCreate(args []string) {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	dir := fs.String("dir", "internal/database/migrations", "migrations directory")
+
+	// Allow the name before or after the flags
+	var name string
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		name, args = args[0], args[1:]
+	}
+	fs.Parse(args)
+	if name == "" {
+		name = fs.Arg(0)
+	}
+	if name == "" {
+		log.Fatal("Usage: migrate create NAME [-dir DIR]")
+	}
+
+	upPath, downPath, err := database.CreateMigration(*dir, name)
+	if err != nil {
+		log.Fatalf("Failed to create migration: %v", err)
+	}
+
+	log.Printf("✅ Created %s", upPath)
+	log.Printf("✅ Created %s", downPath)
+}
+
+func runVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	fs.Parse(args)
+
+	db := connect()
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := migrator.Verify(ctx); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	diff, err := database.VerifySchema(ctx, db, repository.Manifest())
+	if err != nil {
+		log.Fatalf("Failed to verify schema: %v", err)
+	}
+	if !diff.OK() {
+		log.Fatalf("❌ %s", diff)
+	}
+
+	log.Println("✅ Migrations and schema match the repository manifest")
+}
+
+// connect opens the configured database
+func connect() *database.DB {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.Connect(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	return db
+}
+
+// newMigrator connects to the configured database and loads the migrations
+func newMigrator() *database.Migrator {
+	migrator, err := database.NewMigrator(connect())
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	return migrator
+}
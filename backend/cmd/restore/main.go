@@ -0,0 +1,99 @@
+// backend/cmd/restore/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/supraik/Freelance-Portfolio/internal/backup"
+	"github.com/supraik/Freelance-Portfolio/internal/cache"
+	"github.com/supraik/Freelance-Portfolio/internal/config"
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	policy := flag.String("on-conflict", backup.PolicySkip, "what to do when a slug or email exists: skip, overwrite or rename")
+	noUsers := flag.Bool("no-users", false, "do not import admin users")
+	noMedia := flag.Bool("no-media", false, "do not copy bundled files into the upload directory")
+	dryRun := flag.Bool("dry-run", false, "report what would be restored without changing anything")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: restore [flags] ARCHIVE")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if !backup.ValidPolicy(*policy) {
+		log.Fatalf("Invalid -on-conflict %q (use skip, overwrite or rename)", *policy)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.Connect(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Make sure the target database has the full schema
+	if err := database.Migrate(db); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("Failed to open archive: %v", err)
+	}
+	defer f.Close()
+
+	storage := services.NewStorageService(cfg.UploadDir, cfg.MaxFileSize, nil)
+	report, err := backup.Restore(context.Background(), db, storage, f, backup.RestoreOptions{
+		Policy:       *policy,
+		IncludeUsers: !*noUsers,
+		IncludeMedia: !*noMedia,
+		DryRun:       *dryRun,
+	})
+	if err != nil {
+		log.Fatalf("Restore failed: %v", err)
+	}
+
+	if *dryRun {
+		log.Println("Dry run - nothing was changed")
+	} else {
+		log.Printf("✅ Restored backup from %s", report.Manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+
+		// Running servers would keep serving cached content until it expires
+		if err := cache.PublishPurge(context.Background(), db); err != nil {
+			log.Printf("⚠️  Failed to notify running servers, restart them to see restored content: %v", err)
+		}
+	}
+	printCounts("galleries", report.Categories)
+	printCounts("images", report.Images)
+	printCounts("tags", report.Tags)
+	printCounts("sections", report.Sections)
+	printCounts("messages", report.Contacts)
+	printCounts("users", report.Users)
+	printCounts("media", report.Media)
+	for from, to := range report.Renamed {
+		log.Printf("   renamed %s -> %s", from, to)
+	}
+	for from, to := range report.RenamedMedia {
+		log.Printf("   moved %s -> %s", from, to)
+	}
+}
+
+func printCounts(label string, c backup.Counts) {
+	log.Printf("   %-10s %d created, %d updated, %d skipped", label, c.Created, c.Updated, c.Skipped)
+}
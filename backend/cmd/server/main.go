@@ -0,0 +1,96 @@
+// backend/cmd/server/main.go
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/supraik/Freelance-Portfolio/internal/cache"
+	"github.com/supraik/Freelance-Portfolio/internal/config"
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/internal/router"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Initialize database
+	db, err := database.Connect(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	db.SetTimeouts(database.Timeouts{
+		Default:    cfg.DBTimeout,
+		Operations: cfg.DBOperationTimeouts,
+	})
+
+	// Run migrations
+	if err := database.Migrate(db); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	// Verify the schema matches what the repositories query
+	if cfg.SchemaCheck != "off" {
+		diff, err := database.VerifySchema(context.Background(), db, repository.Manifest())
+		if err != nil {
+			log.Fatalf("Failed to verify schema: %v", err)
+		}
+		if !diff.OK() {
+			if cfg.SchemaCheck == "warn" {
+				log.Printf("⚠️  %s", diff)
+			} else {
+				log.Fatalf("Schema check failed: %s", diff)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Content cache, kept consistent across replicas with LISTEN/NOTIFY
+	var contentCache *cache.Cache
+	if cfg.CacheTTL > 0 {
+		contentCache = cache.New(cfg.CacheTTL)
+		go cache.NewSync(contentCache, db, cfg.DatabaseURL).Run(ctx)
+
+		// Scheduled galleries must not wait for their cached pages to expire
+		scheduled := repository.NewCachedGalleryRepository(repository.NewGalleryRepository(db), contentCache)
+		go services.NewPublishScheduler(scheduled).Run(ctx)
+	}
+
+	// Permanently delete trash past its retention period, and the variants
+	// of uploads it leaves unused (no generator is needed to delete them)
+	if cfg.TrashRetention > 0 {
+		storage := services.NewStorageService(cfg.UploadDir, cfg.MaxFileSize, nil)
+		go services.NewTrashJanitor(repository.NewGalleryRepository(db), storage, cfg.TrashRetention).Run(ctx)
+	}
+
+	// Initialize router
+	r := router.New(db, cfg, contentCache)
+
+	// Start server in goroutine
+	go func() {
+		log.Printf("🚀 Server starting on port %s", cfg.Port)
+		if err := r.Run(":" + cfg.Port); err != nil {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Graceful shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server...")
+}
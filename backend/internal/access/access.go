@@ -0,0 +1,58 @@
+// backend/internal/access/access.go
+
+// Package access signs and checks the tokens that open password-protected
+// galleries, and limits how often a password may be guessed.
+package access
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/token"
+)
+
+// Token errors
+var (
+	ErrInvalidToken = errors.New("invalid gallery access token")
+	ErrExpired      = errors.New("gallery access token expired")
+)
+
+// keyLabel is the token label of access tokens
+const keyLabel = "gallery-access-v1"
+
+// Signer mints and verifies access tokens
+type Signer struct {
+	tokens *token.Signer
+}
+
+// NewSigner creates a signer whose key is derived from secret
+func NewSigner(secret string) *Signer {
+	return &Signer{tokens: token.NewSigner(secret, keyLabel)}
+}
+
+// Sign returns a token opening the category until expiresAt. The password
+// hash is signed along, so changing the password invalidates the token.
+func (s *Signer) Sign(categoryID int, expiresAt time.Time, passwordHash string) string {
+	return s.tokens.Sign(fmt.Sprintf("%d.%d", categoryID, expiresAt.Unix()), passwordHash)
+}
+
+// Verify checks that token opens the category with the given password hash
+// at now and returns its expiry
+func (s *Signer) Verify(token string, categoryID int, passwordHash string, now time.Time) (time.Time, error) {
+	payload, err := s.tokens.Verify(token, passwordHash)
+	if err != nil {
+		return time.Time{}, ErrInvalidToken
+	}
+
+	var id int
+	var expires int64
+	if _, err := fmt.Sscanf(payload, "%d.%d", &id, &expires); err != nil || id != categoryID {
+		return time.Time{}, ErrInvalidToken
+	}
+	expiresAt := time.Unix(expires, 0).UTC()
+	if !now.Before(expiresAt) {
+		return time.Time{}, ErrExpired
+	}
+	return expiresAt, nil
+}
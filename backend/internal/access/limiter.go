@@ -0,0 +1,84 @@
+// backend/internal/access/limiter.go
+package access
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter counts attempts per key, e.g. client IP and gallery, until they
+// succeed. A key with max attempts within window must wait until the oldest
+// of them leaves the window. State is per process.
+type Limiter struct {
+	max    int
+	window time.Duration
+
+	mu       sync.Mutex
+	failures map[string][]time.Time // oldest first
+	sweepAt  int                    // map size that triggers dropping stale keys
+}
+
+// minSweep is the map size below which stale keys are not worth dropping
+const minSweep = 1024
+
+// NewLimiter creates a limiter allowing max failures per window
+func NewLimiter(max int, window time.Duration) *Limiter {
+	return &Limiter{
+		max:      max,
+		window:   window,
+		failures: make(map[string][]time.Time),
+		sweepAt:  minSweep,
+	}
+}
+
+// Try reserves an attempt by key at now. It returns 0 when the attempt may
+// go ahead, counted as a failure until Reset, or how long key has to wait.
+// Checking and counting happen under one lock, so parallel attempts cannot
+// all slip through before any of them is counted.
+func (l *Limiter) Try(key string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := l.recent(key, now)
+	if len(recent) >= l.max {
+		return recent[len(recent)-l.max].Add(l.window).Sub(now)
+	}
+	l.failures[key] = append(recent, now)
+
+	if len(l.failures) >= l.sweepAt {
+		for k := range l.failures {
+			if len(l.recent(k, now)) == 0 {
+				delete(l.failures, k)
+			}
+		}
+		l.sweepAt = max(minSweep, 2*len(l.failures))
+	}
+	return 0
+}
+
+// Reset forgets the attempts of key, e.g. after a correct password
+func (l *Limiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.failures, key)
+}
+
+// recent returns the failures of key inside the window ending at now,
+// storing the pruned list back
+func (l *Limiter) recent(key string, now time.Time) []time.Time {
+	times := l.failures[key]
+	i := 0
+	for i < len(times) && !times[i].Add(l.window).After(now) {
+		i++
+	}
+	if i == len(times) {
+		delete(l.failures, key)
+		return nil
+	}
+	if i > 0 {
+		times = times[i:]
+		l.failures[key] = times
+	}
+	return times
+}
@@ -0,0 +1,62 @@
+// backend/internal/access/limiter_test.go
+package access
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiterTry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := NewLimiter(3, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		if wait := l.Try("a", now.Add(time.Duration(i)*time.Second)); wait != 0 {
+			t.Fatalf("attempt %d wait = %v, want 0", i+1, wait)
+		}
+	}
+	if wait := l.Try("a", now.Add(3*time.Second)); wait != 57*time.Second {
+		t.Errorf("4th attempt wait = %v, want 57s until the first leaves the window", wait)
+	}
+	if wait := l.Try("b", now); wait != 0 {
+		t.Errorf("other key wait = %v, want 0", wait)
+	}
+	if wait := l.Try("a", now.Add(time.Minute)); wait != 0 {
+		t.Errorf("attempt after the window wait = %v, want 0", wait)
+	}
+
+	l.Reset("a")
+	for i := 0; i < 3; i++ {
+		if wait := l.Try("a", now.Add(time.Minute)); wait != 0 {
+			t.Fatalf("attempt %d after Reset wait = %v, want 0", i+1, wait)
+		}
+	}
+}
+
+func TestLimiterTryConcurrent(t *testing.T) {
+	const max, guesses = 5, 200
+	l := NewLimiter(max, time.Minute)
+	now := time.Now()
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if l.Try("ip slug", now) == 0 {
+				allowed.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if got := allowed.Load(); got != max {
+		t.Errorf("%d of %d parallel attempts allowed, want %d", got, guesses, max)
+	}
+}
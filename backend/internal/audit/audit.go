@@ -0,0 +1,90 @@
+// backend/internal/audit/audit.go
+
+// Package audit records who changed what through the admin API.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// recordTimeout bounds the audit write that follows a mutation
+const recordTimeout = 5 * time.Second
+
+// Actor is the admin behind a request
+type Actor struct {
+	UserID    int
+	Email     string
+	IP        string
+	UserAgent string
+}
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the actor of the current request
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor stored by WithActor
+func ActorFrom(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
+
+// Store persists audit entries
+type Store interface {
+	Record(ctx context.Context, entry *models.AuditEntry) error
+}
+
+// Recorder writes audit entries for changes made by an actor
+type Recorder struct {
+	store Store
+}
+
+// NewRecorder creates a recorder writing to store
+func NewRecorder(store Store) *Recorder {
+	return &Recorder{store: store}
+}
+
+// Record stores the change of one entity from before to after; either may
+// be nil for creations and deletions. Changes made without an actor in ctx,
+// such as public contact submissions, are not recorded. Failures are logged
+// rather than returned since the change itself has already been made.
+func (r *Recorder) Record(ctx context.Context, action, entityType, entityID string, before, after interface{}) {
+	actor, ok := ActorFrom(ctx)
+	if !ok {
+		return
+	}
+
+	changes, err := Diff(before, after)
+	if err != nil {
+		log.Printf("Failed to diff %s %s for audit: %v", entityType, entityID, err)
+		changes = json.RawMessage("{}")
+	}
+
+	entry := &models.AuditEntry{
+		ActorEmail: actor.Email,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+		IP:         actor.IP,
+		UserAgent:  actor.UserAgent,
+	}
+	if actor.UserID != 0 {
+		entry.ActorID = &actor.UserID
+	}
+
+	// The request may be cancelled once the change is made, the entry must still be written
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+
+	if err := r.store.Record(ctx, entry); err != nil {
+		log.Printf("Failed to record audit entry %s %s %s: %v", action, entityType, entityID, err)
+	}
+}
@@ -0,0 +1,69 @@
+// backend/internal/audit/diff.go
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Change is the before and after value of one field
+type Change struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// Diff compares the JSON encodings of before and after field by field and
+// returns the fields that differ. A nil side counts as having no fields, so
+// creations list every field with a null before and deletions the reverse.
+func Diff(before, after interface{}) (json.RawMessage, error) {
+	old, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	// Marshalling the map sorts the fields
+	changes := make(map[string]Change)
+	for name, a := range old {
+		if b := updated[name]; !bytes.Equal(a, b) {
+			changes[name] = Change{Before: a, After: orNull(b)}
+		}
+	}
+	for name, b := range updated {
+		if _, ok := old[name]; !ok {
+			changes[name] = Change{Before: orNull(nil), After: b}
+		}
+	}
+
+	return json.Marshal(changes)
+}
+
+// fields decodes the top-level JSON fields of v
+func fields(v interface{}) (map[string]json.RawMessage, error) {
+	out := make(map[string]json.RawMessage)
+	if v == nil {
+		return out, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(data, []byte("null")) {
+		return out, nil
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func orNull(v json.RawMessage) json.RawMessage {
+	if v == nil {
+		return json.RawMessage("null")
+	}
+	return v
+}
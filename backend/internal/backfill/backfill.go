@@ -0,0 +1,195 @@
+// backend/internal/backfill/backfill.go
+
+// Package backfill fills in derived columns of gallery images that were
+// added after the images were uploaded. Each job reads the image files,
+// from the upload directory or over HTTP, and is safe to run again.
+package backfill
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
+)
+
+// ErrNotReadable is returned for image sources the backfill cannot fetch:
+// neither local uploads nor, with Options.Remote, http(s) URLs
+var ErrNotReadable = errors.New("image source cannot be read")
+
+// Options control a backfill run
+type Options struct {
+	All    bool         // redo images that already have a value, not only missing ones
+	Remote bool         // fetch images that are not local uploads over HTTP
+	DryRun bool         // report what would change without writing
+	Client *http.Client // used for remote images, a client with a 30s timeout when nil
+	Logf   func(format string, args ...any)
+}
+
+// Report counts what a backfill did with each image
+type Report struct {
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Skipped   int `json:"skipped"` // sources that cannot be read or are not images
+	Failed    int `json:"failed"`
+}
+
+// image is the part of a gallery_images row a backfill works from
+type image struct {
+	ID  int
+	Src string
+}
+
+// images lists the images matching where, in ID order. Rows are read up
+// front so no query stays open while files are fetched.
+func images(ctx context.Context, db *database.DB, where string) ([]image, error) {
+	rows, err := db.QueryContext(ctx, `SELECT id, src FROM gallery_images WHERE `+where+` ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []image
+	for rows.Next() {
+		var img image
+		if err := rows.Scan(&img.ID, &img.Src); err != nil {
+			return nil, err
+		}
+		list = append(list, img)
+	}
+	return list, rows.Err()
+}
+
+// run lists the images matching where (every image with opts.All) and
+// stores the values read returns for each in columns. read returns nil
+// values for files that are not images it can handle. Only rows whose
+// values differ are written; versions and updated_at are left alone as
+// nothing was edited.
+func run(ctx context.Context, db *database.DB, where string, columns []string, opts Options,
+	read func(ctx context.Context, src string) ([]any, error)) (*Report, error) {
+	if opts.All {
+		where = "1 = 1"
+	}
+	list, err := images(ctx, db, where)
+	if err != nil {
+		return nil, err
+	}
+
+	// column = $1, ... and the check whether any of them differs
+	set := make([]string, len(columns))
+	differs := make([]string, len(columns))
+	for i, column := range columns {
+		set[i] = fmt.Sprintf("%s = $%d", column, i+1)
+		differs[i] = fmt.Sprintf("%s IS NULL OR %s <> $%d", column, column, i+1)
+	}
+	idParam := fmt.Sprintf("$%d", len(columns)+1)
+	check := `SELECT ` + strings.Join(differs, " OR ") + ` FROM gallery_images WHERE id = ` + idParam
+	update := `UPDATE gallery_images SET ` + strings.Join(set, ", ") +
+		` WHERE id = ` + idParam + ` AND (` + strings.Join(differs, " OR ") + `)`
+
+	report := &Report{}
+	for _, img := range list {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		values, err := read(ctx, img.Src)
+		switch {
+		case errors.Is(err, ErrNotReadable):
+			report.Skipped++
+			continue
+		case err != nil:
+			opts.logf("image %d (%s): %v", img.ID, img.Src, err)
+			report.Failed++
+			continue
+		case values == nil:
+			opts.logf("image %d (%s): not an image of a supported format", img.ID, img.Src)
+			report.Skipped++
+			continue
+		}
+
+		args := append(values, img.ID)
+		var changed bool
+		if opts.DryRun {
+			err = db.QueryRowContext(ctx, check, args...).Scan(&changed)
+		} else {
+			var result sql.Result
+			result, err = db.ExecContext(ctx, update, args...)
+			if err == nil {
+				var n int64
+				n, err = result.RowsAffected()
+				changed = n > 0
+			}
+		}
+		switch {
+		case err != nil:
+			return report, err
+		case changed:
+			report.Updated++
+		default:
+			report.Unchanged++
+		}
+	}
+	return report, nil
+}
+
+// open returns the file behind src, reading at most limit bytes of remote
+// images into memory
+func open(ctx context.Context, storage *services.StorageService, src string, limit int64, opts Options) (io.ReadSeekCloser, error) {
+	switch {
+	case strings.HasPrefix(src, "/uploads/"):
+		filePath, err := storage.GetFilePath(src)
+		if err != nil {
+			return nil, ErrNotReadable
+		}
+		return os.Open(filePath)
+	case opts.Remote && (strings.HasPrefix(src, "https://") || strings.HasPrefix(src, "http://")):
+	default:
+		return nil, ErrNotReadable
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", src, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	if err != nil {
+		return nil, err
+	}
+	return nopCloser{bytes.NewReader(data)}, nil
+}
+
+// nopCloser lets a fetched image be handled like an open file
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error { return nil }
+
+// logf reports progress through opts.Logf when set
+func (opts Options) logf(format string, args ...any) {
+	if opts.Logf != nil {
+		opts.Logf(format, args...)
+	}
+}
@@ -0,0 +1,40 @@
+// backend/internal/backfill/dimensions.go
+package backfill
+
+import (
+	"context"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/imagesize"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
+)
+
+// headerLimit is how much of a remote image is fetched to read its size.
+// Headers, EXIF included, sit well within it.
+const headerLimit = 1 << 20
+
+// Dimensions stores the displayed width and height of gallery images whose
+// size is unknown (every image with opts.All) and derives their aspect-ratio
+// class with aspect. Trashed images are included, so they come back
+// measured.
+func Dimensions(ctx context.Context, db *database.DB, storage *services.StorageService, aspect imagesize.Thresholds, opts Options) (*Report, error) {
+	columns := []string{"width", "height", "aspect_ratio"}
+	return run(ctx, db, "width = 0 OR height = 0", columns, opts, func(ctx context.Context, src string) ([]any, error) {
+		width, height, err := measure(ctx, storage, src, opts)
+		if err != nil || width == 0 {
+			return nil, err
+		}
+		return []any{width, height, aspect.Classify(width, height)}, nil
+	})
+}
+
+// measure reads the displayed size of the image at src
+func measure(ctx context.Context, storage *services.StorageService, src string, opts Options) (width, height int, err error) {
+	f, err := open(ctx, storage, src, headerLimit, opts)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	return imagesize.Displayed(f)
+}
@@ -0,0 +1,33 @@
+// backend/internal/backfill/placeholders.go
+package backfill
+
+import (
+	"context"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/placeholder"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
+)
+
+// imageLimit is how much of a remote image is fetched to decode it whole
+const imageLimit = 64 << 20
+
+// Placeholders stores the BlurHash, LQIP and dominant colour of gallery
+// images that have none (every image with opts.All). Images that cannot be
+// decoded, WebP included, are skipped. Trashed images are included.
+func Placeholders(ctx context.Context, db *database.DB, storage *services.StorageService, opts Options) (*Report, error) {
+	columns := []string{"blurhash", "lqip", "dominant_color"}
+	return run(ctx, db, "blurhash = ''", columns, opts, func(ctx context.Context, src string) ([]any, error) {
+		f, err := open(ctx, storage, src, imageLimit, opts)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		p, err := placeholder.Generate(f)
+		if err != nil || p == nil {
+			return nil, err
+		}
+		return []any{p.BlurHash, p.LQIP, p.DominantColor}, nil
+	})
+}
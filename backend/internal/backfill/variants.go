@@ -0,0 +1,43 @@
+// backend/internal/backfill/variants.go
+package backfill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
+)
+
+// ErrNoVariantWidths is returned by Variants when no widths are configured
+var ErrNoVariantWidths = errors.New("no image variant widths configured")
+
+// Variants writes the resized variants of local uploads that have none
+// recorded (every image with opts.All, rewriting existing files) and stores
+// their list. Images hosted elsewhere are skipped; their host resizes them.
+// Dry runs only work out the list.
+func Variants(ctx context.Context, db *database.DB, storage *services.StorageService, opts Options) (*Report, error) {
+	if !storage.VariantsEnabled() {
+		return nil, ErrNoVariantWidths
+	}
+	return run(ctx, db, "variants IS NULL", []string{"variants"}, opts, func(ctx context.Context, src string) ([]any, error) {
+		if !strings.HasPrefix(src, "/uploads/") {
+			return nil, ErrNotReadable
+		}
+
+		list, err := storage.PlanVariants(src)
+		if err == nil && list != nil && !opts.DryRun {
+			list, err = storage.Variants(src, opts.All)
+		}
+		if err != nil || list == nil {
+			return nil, err
+		}
+		value, err := json.Marshal(list)
+		if err != nil {
+			return nil, err
+		}
+		return []any{string(value)}, nil
+	})
+}
@@ -0,0 +1,479 @@
+// backend/internal/backup/backup.go
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
+)
+
+// FormatVersion is bumped whenever the manifest layout changes.
+// Version 2 added tags.
+const FormatVersion = 2
+
+// Archive entry names
+const (
+	manifestName = "manifest.json"
+	mediaPrefix  = "media/"
+)
+
+// Manifest is the JSON document stored at the root of a backup archive.
+// Trashed rows, proofing, preview links, resized variants and the audit
+// log are not archived: proofing and preview links are signed for the IDs
+// rows have here, which a restore does not keep, and variants are made
+// again by the variants backfill.
+type Manifest struct {
+	FormatVersion          int                       `json:"format_version"`
+	SchemaVersion          int                       `json:"schema_version"`
+	CreatedAt              time.Time                 `json:"created_at"`
+	IncludesPasswordHashes bool                      `json:"includes_password_hashes"`
+	Categories             []models.GalleryCategory  `json:"gallery_categories"`
+	GalleryPasswords       map[string]string         `json:"gallery_password_hashes,omitempty"` // slug -> bcrypt hash
+	Tags                   []models.Tag              `json:"tags,omitempty"`
+	GalleryTags            map[string][]string       `json:"gallery_tags,omitempty"` // gallery slug -> tag slugs
+	ImageTags              map[int][]string          `json:"image_tags,omitempty"`   // image ID -> tag slugs
+	Sections               []models.PortfolioSection `json:"portfolio_sections"`
+	Contacts               []models.ContactMessage   `json:"contact_messages"`
+	Users                  []User                    `json:"users"`
+	Media                  []MediaFile               `json:"media"`
+}
+
+// User is an admin account as stored in a backup.
+// models.User never serializes its password hash, so it has its own type.
+type User struct {
+	ID           int        `json:"id"`
+	Email        string     `json:"email"`
+	Username     string     `json:"username"`
+	PasswordHash string     `json:"password_hash,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastLogin    *time.Time `json:"last_login,omitempty"`
+}
+
+// MediaFile is an uploaded file bundled in the archive
+type MediaFile struct {
+	URL    string `json:"url"`  // e.g. /uploads/abc.jpg as referenced by the content
+	Path   string `json:"path"` // entry name inside the archive
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Options controls what Create exports
+type Options struct {
+	IncludeUsers          bool
+	IncludePasswordHashes bool // of users and password galleries
+	IncludeMedia          bool
+}
+
+// Create exports all site content from db and writes a gzipped tar archive to w
+func Create(ctx context.Context, db *database.DB, storage *services.StorageService, w io.Writer, opts Options) (*Manifest, error) {
+	manifest, err := export(ctx, db, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	if opts.IncludeMedia {
+		for _, url := range mediaURLs(manifest) {
+			file, err := writeMedia(tw, storage, url)
+			if err != nil {
+				return nil, err
+			}
+			if file != nil {
+				manifest.Media = append(manifest.Media, *file)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeEntry(tw, manifestName, data, manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// export reads every content table into a manifest
+func export(ctx context.Context, db *database.DB, opts Options) (*Manifest, error) {
+	manifest := &Manifest{
+		FormatVersion:          FormatVersion,
+		CreatedAt:              time.Now().UTC(),
+		IncludesPasswordHashes: opts.IncludeUsers && opts.IncludePasswordHashes,
+	}
+
+	err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&manifest.SchemaVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if manifest.Categories, err = exportCategories(ctx, db); err != nil {
+		return nil, fmt.Errorf("failed to export gallery categories: %w", err)
+	}
+	if opts.IncludePasswordHashes {
+		if manifest.GalleryPasswords, err = exportGalleryPasswords(ctx, db); err != nil {
+			return nil, fmt.Errorf("failed to export gallery passwords: %w", err)
+		}
+	}
+	if manifest.Tags, err = exportTags(ctx, db); err != nil {
+		return nil, fmt.Errorf("failed to export tags: %w", err)
+	}
+	if manifest.GalleryTags, err = exportGalleryTags(ctx, db); err != nil {
+		return nil, fmt.Errorf("failed to export gallery tags: %w", err)
+	}
+	if manifest.ImageTags, err = exportImageTags(ctx, db); err != nil {
+		return nil, fmt.Errorf("failed to export image tags: %w", err)
+	}
+	if manifest.Sections, err = exportSections(ctx, db); err != nil {
+		return nil, fmt.Errorf("failed to export portfolio sections: %w", err)
+	}
+	if manifest.Contacts, err = exportContacts(ctx, db); err != nil {
+		return nil, fmt.Errorf("failed to export contact messages: %w", err)
+	}
+	if opts.IncludeUsers {
+		if manifest.Users, err = exportUsers(ctx, db, opts.IncludePasswordHashes); err != nil {
+			return nil, fmt.Errorf("failed to export users: %w", err)
+		}
+	}
+
+	return manifest, nil
+}
+
+func exportCategories(ctx context.Context, db *database.DB) ([]models.GalleryCategory, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, slug, title, COALESCE(description, ''), COALESCE(cover_image, ''),
+			COALESCE(display_order, 0), status, publish_at, unpublish_at, access, created_at, updated_at
+		FROM gallery_categories
+		WHERE deleted_at IS NULL
+		ORDER BY id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []models.GalleryCategory
+	index := make(map[int]int)
+	for rows.Next() {
+		var cat models.GalleryCategory
+		if err := rows.Scan(&cat.ID, &cat.Slug, &cat.Title, &cat.Description, &cat.CoverImage,
+			&cat.DisplayOrder, &cat.Status, &cat.PublishAt, &cat.UnpublishAt, &cat.Access, &cat.CreatedAt, &cat.UpdatedAt); err != nil {
+			return nil, err
+		}
+		cat.Images = []models.GalleryImage{}
+		index[cat.ID] = len(categories)
+		categories = append(categories, cat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	imageRows, err := db.QueryContext(ctx, `
+		SELECT id, category_id, src, COALESCE(alt, ''), COALESCE(aspect_ratio, 'portrait'),
+			width, height, COALESCE(display_order, 0), created_at, metadata, blurhash, lqip, dominant_color,
+			original_filename
+		FROM gallery_images
+		WHERE category_id IS NOT NULL AND deleted_at IS NULL
+		ORDER BY category_id ASC, display_order ASC, id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer imageRows.Close()
+
+	for imageRows.Next() {
+		var img models.GalleryImage
+		var metadata []byte
+		if err := imageRows.Scan(&img.ID, &img.CategoryID, &img.Src, &img.Alt, &img.AspectRatio,
+			&img.Width, &img.Height, &img.DisplayOrder, &img.CreatedAt, &metadata,
+			&img.BlurHash, &img.LQIP, &img.DominantColor, &img.Filename); err != nil {
+			return nil, err
+		}
+		if metadata != nil {
+			img.Metadata = &models.ImageMetadata{}
+			if err := json.Unmarshal(metadata, img.Metadata); err != nil {
+				return nil, fmt.Errorf("image %d metadata: %w", img.ID, err)
+			}
+		}
+		if i, ok := index[img.CategoryID]; ok {
+			categories[i].Images = append(categories[i].Images, img)
+		}
+	}
+
+	return categories, imageRows.Err()
+}
+
+// exportGalleryPasswords returns the password hashes of live password galleries by slug
+func exportGalleryPasswords(ctx context.Context, db *database.DB) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT slug, password_hash
+		FROM gallery_categories
+		WHERE deleted_at IS NULL AND access = 'password' AND password_hash IS NOT NULL
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := make(map[string]string)
+	for rows.Next() {
+		var slug, hash string
+		if err := rows.Scan(&slug, &hash); err != nil {
+			return nil, err
+		}
+		hashes[slug] = hash
+	}
+	return hashes, rows.Err()
+}
+
+func exportTags(ctx context.Context, db *database.DB) ([]models.Tag, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, name, slug, created_at, updated_at
+		FROM tags
+		ORDER BY id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Slug, &tag.CreatedAt, &tag.UpdatedAt); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// exportGalleryTags returns the tag slugs of live galleries by gallery slug
+func exportGalleryTags(ctx context.Context, db *database.DB) (map[string][]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT c.slug, t.slug
+		FROM category_tags ct
+		JOIN gallery_categories c ON c.id = ct.category_id
+		JOIN tags t ON t.id = ct.tag_id
+		WHERE c.deleted_at IS NULL
+		ORDER BY c.slug, t.slug
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make(map[string][]string)
+	for rows.Next() {
+		var gallery, tag string
+		if err := rows.Scan(&gallery, &tag); err != nil {
+			return nil, err
+		}
+		links[gallery] = append(links[gallery], tag)
+	}
+	return links, rows.Err()
+}
+
+// exportImageTags returns the tag slugs of live images by image ID
+func exportImageTags(ctx context.Context, db *database.DB) (map[int][]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT it.image_id, t.slug
+		FROM image_tags it
+		JOIN gallery_images i ON i.id = it.image_id
+		JOIN tags t ON t.id = it.tag_id
+		WHERE i.deleted_at IS NULL
+		ORDER BY it.image_id, t.slug
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make(map[int][]string)
+	for rows.Next() {
+		var image int
+		var tag string
+		if err := rows.Scan(&image, &tag); err != nil {
+			return nil, err
+		}
+		links[image] = append(links[image], tag)
+	}
+	return links, rows.Err()
+}
+
+func exportSections(ctx context.Context, db *database.DB) ([]models.PortfolioSection, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, name, slug, COALESCE(description, ''), COALESCE(display_order, 0), created_at, updated_at
+		FROM portfolio_sections
+		ORDER BY id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sections []models.PortfolioSection
+	for rows.Next() {
+		var s models.PortfolioSection
+		if err := rows.Scan(&s.ID, &s.Name, &s.Slug, &s.Description, &s.DisplayOrder, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		sections = append(sections, s)
+	}
+
+	return sections, rows.Err()
+}
+
+func exportContacts(ctx context.Context, db *database.DB) ([]models.ContactMessage, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, name, email, COALESCE(phone, ''), subject, message, COALESCE(status, 'pending'), created_at, updated_at
+		FROM contact_messages
+		ORDER BY id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contacts []models.ContactMessage
+	for rows.Next() {
+		var m models.ContactMessage
+		if err := rows.Scan(&m.ID, &m.Name, &m.Email, &m.Phone, &m.Subject, &m.Message, &m.Status, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, m)
+	}
+
+	return contacts, rows.Err()
+}
+
+func exportUsers(ctx context.Context, db *database.DB, withHashes bool) ([]User, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, email, username, password_hash, created_at, last_login
+		FROM users
+		ORDER BY id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.Email, &u.Username, &u.PasswordHash, &u.CreatedAt, &u.LastLogin); err != nil {
+			return nil, err
+		}
+		if !withHashes {
+			u.PasswordHash = ""
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+// mediaURLs returns the distinct local upload URLs referenced by the content
+func mediaURLs(m *Manifest) []string {
+	seen := make(map[string]bool)
+	var urls []string
+
+	add := func(url string) {
+		if strings.HasPrefix(url, "/uploads/") && !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+
+	for _, cat := range m.Categories {
+		add(cat.CoverImage)
+		for _, img := range cat.Images {
+			add(img.Src)
+		}
+	}
+
+	return urls
+}
+
+// writeMedia copies an uploaded file into the archive.
+// Files that are referenced but missing on disk are skipped.
+func writeMedia(tw *tar.Writer, storage *services.StorageService, url string) (*MediaFile, error) {
+	if !storage.FileExists(url) {
+		return nil, nil
+	}
+
+	filePath, err := storage.GetFilePath(url)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	name := mediaPrefix + path.Base(url)
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}); err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tw, hash), f); err != nil {
+		return nil, fmt.Errorf("failed to archive %s: %w", url, err)
+	}
+
+	return &MediaFile{
+		URL:    url,
+		Path:   name,
+		Size:   info.Size(),
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// writeEntry adds an in-memory file to the archive
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
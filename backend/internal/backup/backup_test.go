@@ -0,0 +1,439 @@
+// backend/internal/backup/backup_test.go
+package backup_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/supraik/Freelance-Portfolio/internal/backup"
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/database/databasetest"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
+)
+
+// contentTables are emptied before each test, children first
+const contentTables = "image_tags, category_tags, tags, gallery_images, gallery_categories, portfolio_sections, contact_messages, users"
+
+// openTestDB returns a migrated database with the given tables empty. It is
+// Postgres by default and SQLite in builds with -tags sqlite (sqlite_test.go).
+var openTestDB = databasetest.OpenPostgres
+
+// site is a database and upload directory to back up or restore into
+type site struct {
+	db        *database.DB
+	storage   *services.StorageService
+	uploads   string
+	galleries *repository.GalleryRepository
+}
+
+func newSite(t *testing.T) *site {
+	t.Helper()
+	db := openTestDB(t, contentTables)
+	uploads := t.TempDir()
+	return &site{
+		db:        db,
+		storage:   services.NewStorageService(uploads, 1<<20, nil),
+		uploads:   uploads,
+		galleries: repository.NewGalleryRepository(db),
+	}
+}
+
+func (s *site) createCategory(t *testing.T, slug, title string) *models.GalleryCategory {
+	t.Helper()
+	cat := &models.GalleryCategory{Slug: slug, Title: title, Status: models.GalleryPublished, Access: models.GalleryAccessPublic}
+	if err := s.galleries.CreateCategory(context.Background(), cat); err != nil {
+		t.Fatalf("CreateCategory(%s): %v", slug, err)
+	}
+	return cat
+}
+
+func (s *site) createImage(t *testing.T, img *models.GalleryImage) {
+	t.Helper()
+	if err := s.galleries.CreateImage(context.Background(), img); err != nil {
+		t.Fatalf("CreateImage(%s): %v", img.Src, err)
+	}
+}
+
+// tag creates a tag and adds it to a gallery, or an image if image is set
+func (s *site) tag(t *testing.T, slug string, cat *models.GalleryCategory, image *models.GalleryImage) {
+	t.Helper()
+	ctx := context.Background()
+	if err := s.galleries.CreateTag(ctx, &models.Tag{Name: strings.ToUpper(slug), Slug: slug}); err != nil {
+		t.Fatalf("CreateTag(%s): %v", slug, err)
+	}
+	var err error
+	if image != nil {
+		err = s.galleries.SetImageTags(ctx, image.ID, []string{slug})
+	} else {
+		err = s.galleries.SetCategoryTags(ctx, cat.ID, []string{slug})
+	}
+	if err != nil {
+		t.Fatalf("tag %s: %v", slug, err)
+	}
+}
+
+// tags returns the comma-separated tag slugs of a gallery, or of an image if image is set
+func (s *site) tags(t *testing.T, cat *models.GalleryCategory, image *models.GalleryImage) string {
+	t.Helper()
+	var tags []models.Tag
+	var err error
+	if image != nil {
+		tags, err = s.galleries.ImageTags(context.Background(), image.ID)
+	} else {
+		tags, err = s.galleries.CategoryTags(context.Background(), cat.ID)
+	}
+	if err != nil {
+		t.Fatalf("tags: %v", err)
+	}
+	slugs := make([]string, len(tags))
+	for i, tag := range tags {
+		slugs[i] = tag.Slug
+	}
+	return strings.Join(slugs, ",")
+}
+
+func (s *site) category(t *testing.T, slug string) *models.GalleryCategory {
+	t.Helper()
+	cat, err := s.galleries.GetCategoryBySlug(context.Background(), slug)
+	if err != nil {
+		t.Fatalf("GetCategoryBySlug(%s): %v", slug, err)
+	}
+	return cat
+}
+
+func (s *site) count(t *testing.T, table string) int {
+	t.Helper()
+	var n int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return n
+}
+
+// source is a site with a public gallery of a local and a remote image, both
+// tagged, a password gallery, a section, a contact message and an admin
+func source(t *testing.T) *site {
+	t.Helper()
+	ctx := context.Background()
+	s := newSite(t)
+
+	// Rows created and deleted first, so archived IDs differ from the restored ones
+	for _, slug := range []string{"gone-1", "gone-2", "gone-3"} {
+		if _, err := s.galleries.PurgeCategory(ctx, trashed(t, s, slug)); err != nil {
+			t.Fatalf("PurgeCategory: %v", err)
+		}
+	}
+
+	wedding := s.createCategory(t, "wedding", "Wedding")
+	dance := &models.GalleryImage{
+		CategoryID: wedding.ID, Src: "/uploads/a.jpg", Alt: "First dance", AspectRatio: "landscape",
+		Width: 1200, Height: 800, DisplayOrder: 1,
+		Metadata:         &models.ImageMetadata{CameraMake: "FUJIFILM", ISO: 160},
+		ImagePlaceholder: models.ImagePlaceholder{BlurHash: "L00000fQfQfQfQfQfQfQfQfQfQfQ", DominantColor: "#000000"},
+	}
+	s.createImage(t, dance)
+	s.tag(t, "bride", wedding, nil)
+	s.tag(t, "film", wedding, dance)
+	s.createImage(t, &models.GalleryImage{CategoryID: wedding.ID, Src: "https://cdn.example.com/b.jpg", AspectRatio: "portrait", DisplayOrder: 2})
+	if err := os.WriteFile(filepath.Join(s.uploads, "a.jpg"), []byte("first dance"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	locked := s.createCategory(t, "locked", "Locked")
+	locked.Access = models.GalleryAccessPassword
+	if err := s.galleries.UpdateCategoryAccess(ctx, locked, "$2a$10$archivedhash"); err != nil {
+		t.Fatalf("UpdateCategoryAccess: %v", err)
+	}
+
+	if _, err := s.db.Exec(`INSERT INTO portfolio_sections (name, slug, description, display_order) VALUES ('Bridal', 'bridal', 'Brides', 1)`); err != nil {
+		t.Fatalf("seed section: %v", err)
+	}
+	if _, err := repository.NewContactRepository(s.db).Create(ctx, &models.ContactRequest{
+		Name: "Asha", Email: "asha@example.com", Subject: "Booking", Message: "Are you free in June?",
+	}); err != nil {
+		t.Fatalf("seed contact: %v", err)
+	}
+	if err := repository.NewUserRepository(s.db).Create(ctx, &models.User{Email: "admin@example.com", Username: "admin", PasswordHash: "$2a$10$adminhash"}); err != nil {
+		t.Fatalf("seed user: %v", err)
+	}
+	return s
+}
+
+// trashed creates a gallery and moves it to the trash, returning its ID
+func trashed(t *testing.T, s *site, slug string) int {
+	t.Helper()
+	cat := s.createCategory(t, slug, slug)
+	if err := s.galleries.DeleteCategory(context.Background(), cat.ID); err != nil {
+		t.Fatalf("DeleteCategory: %v", err)
+	}
+	return cat.ID
+}
+
+func archive(t *testing.T, s *site) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	opts := backup.Options{IncludeUsers: true, IncludePasswordHashes: true, IncludeMedia: true}
+	if _, err := backup.Create(context.Background(), s.db, s.storage, &buf, opts); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func restore(t *testing.T, s *site, data []byte, opts backup.RestoreOptions) *backup.Report {
+	t.Helper()
+	report, err := backup.Restore(context.Background(), s.db, s.storage, bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	return report
+}
+
+func all(policy string) backup.RestoreOptions {
+	return backup.RestoreOptions{Policy: policy, IncludeUsers: true, IncludeMedia: true}
+}
+
+func TestRoundTrip(t *testing.T) {
+	src := source(t)
+	data := archive(t, src)
+	dst := newSite(t)
+
+	report := restore(t, dst, data, all(backup.PolicySkip))
+
+	want := backup.Counts{Created: 2}
+	if report.Categories != want || report.Images != want || report.Tags != want {
+		t.Errorf("categories %+v, images %+v, tags %+v, want 2 created each", report.Categories, report.Images, report.Tags)
+	}
+	for name, got := range map[string]backup.Counts{"sections": report.Sections, "contacts": report.Contacts, "users": report.Users, "media": report.Media} {
+		if got != (backup.Counts{Created: 1}) {
+			t.Errorf("%s %+v, want 1 created", name, got)
+		}
+	}
+
+	// Archived IDs are remapped to the IDs the rows got here
+	archived := src.category(t, "wedding")
+	restored := dst.category(t, "wedding")
+	if archived.ID == restored.ID {
+		t.Fatalf("archived and restored gallery share ID %d, the test cannot see remapping", archived.ID)
+	}
+	if got := report.CategoryIDs[archived.ID]; got != restored.ID {
+		t.Errorf("CategoryIDs[%d] = %d, want %d", archived.ID, got, restored.ID)
+	}
+	if len(restored.Images) != 2 {
+		t.Fatalf("restored images = %+v, want 2", restored.Images)
+	}
+	for i, img := range archived.Images {
+		got := restored.Images[i]
+		if report.ImageIDs[img.ID] != got.ID || got.CategoryID != restored.ID {
+			t.Errorf("image %d restored as %d in gallery %d, report maps it to %d", img.ID, got.ID, got.CategoryID, report.ImageIDs[img.ID])
+		}
+		if got.Src != img.Src || got.Filename != img.Filename || got.Alt != img.Alt || got.Width != img.Width || got.ImagePlaceholder != img.ImagePlaceholder {
+			t.Errorf("restored image %+v, want %+v", got, img)
+		}
+	}
+	if m := restored.Images[0].Metadata; m == nil || m.CameraMake != "FUJIFILM" || m.ISO != 160 {
+		t.Errorf("restored metadata = %+v", m)
+	}
+	if got := dst.tags(t, restored, nil); got != "bride" {
+		t.Errorf("restored gallery tags = %q, want bride", got)
+	}
+	if got := dst.tags(t, nil, &restored.Images[0]); got != "film" {
+		t.Errorf("restored image tags = %q, want film", got)
+	}
+
+	locked := dst.category(t, "locked")
+	if hash, err := dst.galleries.CategoryPasswordHash(context.Background(), locked.ID); err != nil || hash != "$2a$10$archivedhash" {
+		t.Errorf("restored gallery password hash = %q, %v", hash, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dst.uploads, "a.jpg")); err != nil || string(data) != "first dance" {
+		t.Errorf("restored media = %q, %v", data, err)
+	}
+
+	// Restoring the same archive again changes nothing
+	again := restore(t, dst, data, all(backup.PolicySkip))
+	if again.Categories.Created != 0 || again.Tags.Skipped != 2 || again.Contacts.Skipped != 1 || again.Users.Skipped != 1 || again.Media.Skipped != 1 {
+		t.Errorf("second restore %+v, want everything skipped", again)
+	}
+	if n := dst.count(t, "gallery_images"); n != 2 {
+		t.Errorf("%d images after restoring twice, want 2", n)
+	}
+}
+
+func TestRestorePolicies(t *testing.T) {
+	data := archive(t, source(t))
+
+	// A site whose wedding gallery and a.jpg differ from the archived ones
+	existing := func(t *testing.T) *site {
+		dst := newSite(t)
+		wedding := dst.createCategory(t, "wedding", "Our Wedding")
+		dst.createImage(t, &models.GalleryImage{CategoryID: wedding.ID, Src: "/uploads/mine.jpg", AspectRatio: "square"})
+		dst.tag(t, "mine", wedding, nil)
+		if err := os.WriteFile(filepath.Join(dst.uploads, "a.jpg"), []byte("local edit"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dst
+	}
+	media := func(t *testing.T, dst *site) string {
+		data, err := os.ReadFile(filepath.Join(dst.uploads, "a.jpg"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		dst := existing(t)
+		_, err := backup.Restore(context.Background(), dst.db, dst.storage, bytes.NewReader(data), all(backup.PolicySkip))
+		if err == nil || !strings.Contains(err.Error(), "differs") {
+			t.Fatalf("Restore = %v, want an error for the differing a.jpg", err)
+		}
+
+		// Restored rows would have shown the local a.jpg, so nothing is restored
+		wedding := dst.category(t, "wedding")
+		if wedding.Title != "Our Wedding" || len(wedding.Images) != 1 || wedding.Images[0].Src != "/uploads/mine.jpg" {
+			t.Errorf("gallery = %+v, want it untouched", wedding)
+		}
+		if n := dst.count(t, "gallery_categories"); n != 1 {
+			t.Errorf("%d galleries after a failed restore, want 1", n)
+		}
+		if got := media(t, dst); got != "local edit" {
+			t.Errorf("media = %q, want the local file kept", got)
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		dst := existing(t)
+		before := dst.category(t, "wedding")
+		report := restore(t, dst, data, all(backup.PolicyOverwrite))
+
+		wedding := dst.category(t, "wedding")
+		if wedding.ID != before.ID || wedding.Title != "Wedding" {
+			t.Errorf("overwritten gallery = %+v, want the archived title on ID %d", wedding, before.ID)
+		}
+		if len(wedding.Images) != 2 || wedding.Images[0].Src != "/uploads/a.jpg" {
+			t.Errorf("overwritten images = %+v, want the archived ones", wedding.Images)
+		}
+		if report.Categories != (backup.Counts{Created: 1, Updated: 1}) {
+			t.Errorf("categories %+v", report.Categories)
+		}
+		if got := media(t, dst); got != "first dance" || report.Media.Updated != 1 {
+			t.Errorf("media = %q (%+v), want the archived file", got, report.Media)
+		}
+		if got := dst.tags(t, wedding, nil); got != "bride" {
+			t.Errorf("overwritten gallery tags = %q, want the archived bride", got)
+		}
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		dst := existing(t)
+		report := restore(t, dst, data, all(backup.PolicyRename))
+
+		if report.Renamed["wedding"] != "wedding-2" {
+			t.Errorf("Renamed = %v, want wedding -> wedding-2", report.Renamed)
+		}
+		if mine := dst.category(t, "wedding"); mine.Title != "Our Wedding" {
+			t.Errorf("existing gallery = %+v, want it untouched", mine)
+		}
+		renamed := dst.category(t, "wedding-2")
+		if renamed.Title != "Wedding" || len(renamed.Images) != 2 {
+			t.Errorf("renamed gallery = %+v", renamed)
+		}
+		if report.Categories != (backup.Counts{Created: 2}) {
+			t.Errorf("categories %+v", report.Categories)
+		}
+
+		// The archived a.jpg is written next to the local one and the gallery uses it
+		moved := report.RenamedMedia["/uploads/a.jpg"]
+		if moved == "" || renamed.Images[0].Src != moved {
+			t.Fatalf("RenamedMedia = %v, restored src %q, want a.jpg moved and the image pointing at it", report.RenamedMedia, renamed.Images[0].Src)
+		}
+		if data, err := os.ReadFile(filepath.Join(dst.uploads, path.Base(moved))); err != nil || string(data) != "first dance" {
+			t.Errorf("moved media = %q, %v, want the archived file", data, err)
+		}
+		if got := media(t, dst); got != "local edit" || report.Media.Created != 1 {
+			t.Errorf("media = %q (%+v), want the local file kept", got, report.Media)
+		}
+	})
+}
+
+func TestRestoreDryRun(t *testing.T) {
+	data := archive(t, source(t))
+	dst := newSite(t)
+
+	report := restore(t, dst, data, backup.RestoreOptions{Policy: backup.PolicySkip, IncludeUsers: true, IncludeMedia: true, DryRun: true})
+	if report.Categories.Created != 2 || report.Images.Created != 2 || report.Users.Created != 1 {
+		t.Errorf("dry run report %+v, want what a restore would create", report)
+	}
+	for _, table := range strings.Split(contentTables, ", ") {
+		if n := dst.count(t, table); n != 0 {
+			t.Errorf("dry run left %d rows in %s", n, table)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dst.uploads, "a.jpg")); !os.IsNotExist(err) {
+		t.Errorf("dry run copied media: %v", err)
+	}
+}
+
+func TestRestoreChecksMedia(t *testing.T) {
+	data := archive(t, source(t))
+
+	// Swap the bytes of the archived file, keeping the manifest and its checksum
+	var tampered bytes.Buffer
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	gw := gzip.NewWriter(&tampered)
+	tw := tar.NewWriter(gw)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasPrefix(header.Name, "media/") {
+			body = []byte("tampered!!!")
+			header.Size = int64(len(body))
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newSite(t)
+	_, err = backup.Restore(context.Background(), dst.db, dst.storage, &tampered, all(backup.PolicySkip))
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Restore = %v, want a checksum mismatch", err)
+	}
+	if n := dst.count(t, "gallery_categories"); n != 0 {
+		t.Errorf("failed restore left %d galleries", n)
+	}
+	if _, err := os.Stat(filepath.Join(dst.uploads, "a.jpg")); !os.IsNotExist(err) {
+		t.Errorf("failed restore wrote media: %v", err)
+	}
+}
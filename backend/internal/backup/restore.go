@@ -0,0 +1,629 @@
+// backend/internal/backup/restore.go
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
+)
+
+// Conflict policies for rows whose slug or email already exists
+const (
+	PolicySkip      = "skip"      // keep the existing row
+	PolicyOverwrite = "overwrite" // replace the existing row with the archived one
+	PolicyRename    = "rename"    // import under a new slug (slug-2, slug-3, ...)
+)
+
+// unusablePasswordHash is stored for users and password galleries restored
+// without a hash. It is not a valid bcrypt hash, so nobody can log in or
+// unlock the gallery until the password is reset.
+const unusablePasswordHash = "!"
+
+// RestoreOptions controls how an archive is imported
+type RestoreOptions struct {
+	Policy       string
+	IncludeUsers bool
+	IncludeMedia bool
+	DryRun       bool // run everything in a transaction that is rolled back
+}
+
+// Counts tallies what happened to one kind of record
+type Counts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
+
+// Report summarises a restore
+type Report struct {
+	Manifest     *Manifest         `json:"-"`
+	Categories   Counts            `json:"gallery_categories"`
+	Images       Counts            `json:"gallery_images"`
+	Tags         Counts            `json:"tags"`
+	Sections     Counts            `json:"portfolio_sections"`
+	Contacts     Counts            `json:"contact_messages"`
+	Users        Counts            `json:"users"`
+	Media        Counts            `json:"media"`
+	CategoryIDs  map[int]int       `json:"category_ids"` // archived ID -> restored ID
+	ImageIDs     map[int]int       `json:"image_ids"`
+	SectionIDs   map[int]int       `json:"section_ids"`
+	Renamed      map[string]string `json:"renamed"`       // archived slug -> restored slug
+	RenamedMedia map[string]string `json:"renamed_media"` // archived URL -> restored URL
+}
+
+// tagLinks holds the archived tag assignments and the IDs the tags were restored under
+type tagLinks struct {
+	ids        map[string]int      // tag slug -> restored ID
+	categories map[string][]string // gallery slug -> tag slugs
+	images     map[int][]string    // archived image ID -> tag slugs
+}
+
+// mediaCopy is an extracted file and the path it is restored to
+type mediaCopy struct {
+	src, dest string
+}
+
+// ValidPolicy reports whether p is a known conflict policy
+func ValidPolicy(p string) bool {
+	return p == PolicySkip || p == PolicyOverwrite || p == PolicyRename
+}
+
+// Restore imports an archive produced by Create into db and the upload directory
+func Restore(ctx context.Context, db *database.DB, storage *services.StorageService, r io.Reader, opts RestoreOptions) (*Report, error) {
+	if !ValidPolicy(opts.Policy) {
+		return nil, fmt.Errorf("unknown conflict policy: %q", opts.Policy)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "portfolio-restore-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	manifest, err := readArchive(r, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("archive format version %d is newer than supported version %d", manifest.FormatVersion, FormatVersion)
+	}
+
+	report := &Report{
+		Manifest:     manifest,
+		CategoryIDs:  make(map[int]int),
+		ImageIDs:     make(map[int]int),
+		SectionIDs:   make(map[int]int),
+		Renamed:      make(map[string]string),
+		RenamedMedia: make(map[string]string),
+	}
+
+	// Media is planned first, files that move change the rows pointing at them
+	var copies []mediaCopy
+	if opts.IncludeMedia {
+		if copies, err = planMedia(storage, tmpDir, manifest, opts.Policy, report); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tags := tagLinks{categories: manifest.GalleryTags, images: manifest.ImageTags}
+	if tags.ids, err = restoreTags(ctx, tx, manifest.Tags, opts.Policy, report); err != nil {
+		return nil, fmt.Errorf("failed to restore tags: %w", err)
+	}
+	for _, cat := range manifest.Categories {
+		if err := restoreCategory(ctx, tx, cat, manifest.GalleryPasswords[cat.Slug], tags, opts.Policy, report); err != nil {
+			return nil, fmt.Errorf("failed to restore gallery %q: %w", cat.Slug, err)
+		}
+	}
+	for _, section := range manifest.Sections {
+		if err := restoreSection(ctx, tx, section, opts.Policy, report); err != nil {
+			return nil, fmt.Errorf("failed to restore portfolio section %q: %w", section.Slug, err)
+		}
+	}
+	for _, msg := range manifest.Contacts {
+		if err := restoreContact(ctx, tx, msg, report); err != nil {
+			return nil, fmt.Errorf("failed to restore contact message %d: %w", msg.ID, err)
+		}
+	}
+	if opts.IncludeUsers {
+		for _, user := range manifest.Users {
+			if err := restoreUser(ctx, tx, user, opts.Policy, report); err != nil {
+				return nil, fmt.Errorf("failed to restore user %s: %w", user.Email, err)
+			}
+		}
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	// Copy media before committing so restored rows never point at missing files
+	for _, c := range copies {
+		if err := copyFile(c.src, c.dest); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// readArchive extracts media into dir and returns the decoded manifest
+func readArchive(r io.Reader, dir string) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	var manifest *Manifest
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		switch {
+		case header.Name == manifestName:
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+		case strings.HasPrefix(header.Name, mediaPrefix):
+			if err := extractFile(tr, filepath.Join(dir, path.Base(header.Name))); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if manifest == nil {
+		return nil, errors.New("archive has no manifest.json")
+	}
+	return manifest, nil
+}
+
+func extractFile(r io.Reader, dest string) error {
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
+
+// restoreTags imports tags keyed by slug and returns their IDs here.
+// An existing tag is reused, the overwrite policy also takes the archived name.
+func restoreTags(ctx context.Context, tx *database.Tx, tags []models.Tag, policy string, report *Report) (map[string]int, error) {
+	ids := make(map[string]int, len(tags))
+	for _, tag := range tags {
+		var id int
+		err := tx.QueryRowContext(ctx, `SELECT id FROM tags WHERE slug = $1`, tag.Slug).Scan(&id)
+		switch {
+		case err == sql.ErrNoRows:
+			err = tx.QueryRowContext(ctx, `
+				INSERT INTO tags (name, slug, created_at, updated_at)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id
+			`, tag.Name, tag.Slug, tag.CreatedAt, tag.UpdatedAt).Scan(&id)
+			if err != nil {
+				return nil, err
+			}
+			report.Tags.Created++
+		case err != nil:
+			return nil, err
+		case policy == PolicyOverwrite:
+			if _, err := tx.ExecContext(ctx, `UPDATE tags SET name = $1, updated_at = $2 WHERE id = $3`, tag.Name, tag.UpdatedAt, id); err != nil {
+				return nil, err
+			}
+			report.Tags.Updated++
+		default:
+			report.Tags.Skipped++
+		}
+		ids[tag.Slug] = id
+	}
+	return ids, nil
+}
+
+// linkTags assigns the tags named by slugs to a category or image
+func linkTags(ctx context.Context, tx *database.Tx, table, column string, id int, slugs []string, ids map[string]int) error {
+	query := fmt.Sprintf(`INSERT INTO %s (%s, tag_id) VALUES ($1, $2)`, table, column)
+	for _, slug := range slugs {
+		tagID, ok := ids[slug]
+		if !ok {
+			return fmt.Errorf("unknown tag %q", slug)
+		}
+		if _, err := tx.ExecContext(ctx, query, id, tagID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func restoreCategory(ctx context.Context, tx *database.Tx, cat models.GalleryCategory, passwordHash string, tags tagLinks, policy string, report *Report) error {
+	// Archives from before gallery statuses and access modes only held public galleries
+	if cat.Status == "" {
+		cat.Status = models.GalleryPublished
+	}
+	if cat.Access == "" {
+		cat.Access = models.GalleryAccessPublic
+	}
+	// A password gallery archived without its hash stays locked
+	var hash *string
+	if cat.Access == models.GalleryAccessPassword {
+		if passwordHash == "" {
+			passwordHash = unusablePasswordHash
+		}
+		hash = &passwordHash
+	}
+
+	var existingID int
+	err := tx.QueryRowContext(ctx, `SELECT id FROM gallery_categories WHERE slug = $1`, cat.Slug).Scan(&existingID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	exists := err == nil
+
+	switch {
+	case exists && policy == PolicySkip:
+		report.CategoryIDs[cat.ID] = existingID
+		report.Categories.Skipped++
+		report.Images.Skipped += len(cat.Images)
+		return nil
+
+	case exists && policy == PolicyOverwrite:
+		_, err := tx.ExecContext(ctx, `
+			UPDATE gallery_categories
+			SET title = $1, description = $2, cover_image = $3, display_order = $4, updated_at = $5, deleted_at = NULL,
+				status = $6, publish_at = $7, unpublish_at = $8, access = $9, password_hash = $10, version = version + 1
+			WHERE id = $11
+		`, cat.Title, cat.Description, cat.CoverImage, cat.DisplayOrder, cat.UpdatedAt,
+			cat.Status, cat.PublishAt, cat.UnpublishAt, cat.Access, hash, existingID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM gallery_images WHERE category_id = $1`, existingID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM category_tags WHERE category_id = $1`, existingID); err != nil {
+			return err
+		}
+		report.CategoryIDs[cat.ID] = existingID
+		report.Categories.Updated++
+
+	default:
+		slug := cat.Slug
+		if exists {
+			if slug, err = freeSlug(ctx, tx, "gallery_categories", cat.Slug); err != nil {
+				return err
+			}
+			report.Renamed[cat.Slug] = slug
+		}
+
+		var id int
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO gallery_categories (slug, title, description, cover_image, display_order, status, publish_at, unpublish_at,
+				access, password_hash, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING id
+		`, slug, cat.Title, cat.Description, cat.CoverImage, cat.DisplayOrder,
+			cat.Status, cat.PublishAt, cat.UnpublishAt, cat.Access, hash, cat.CreatedAt, cat.UpdatedAt).Scan(&id)
+		if err != nil {
+			return err
+		}
+		report.CategoryIDs[cat.ID] = id
+		report.Categories.Created++
+	}
+
+	categoryID := report.CategoryIDs[cat.ID]
+	if err := linkTags(ctx, tx, "category_tags", "category_id", categoryID, tags.categories[cat.Slug], tags.ids); err != nil {
+		return err
+	}
+	for _, img := range cat.Images {
+		// Archives made before images carried metadata have none
+		var metadata any
+		if img.Metadata != nil && !img.Metadata.IsEmpty() {
+			data, err := json.Marshal(img.Metadata)
+			if err != nil {
+				return err
+			}
+			metadata = string(data)
+		}
+
+		var id int
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO gallery_images (category_id, src, alt, aspect_ratio, width, height, display_order, metadata,
+				blurhash, lqip, dominant_color, original_filename, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $13)
+			RETURNING id
+		`, categoryID, img.Src, img.Alt, img.AspectRatio, img.Width, img.Height, img.DisplayOrder, metadata,
+			img.BlurHash, img.LQIP, img.DominantColor, img.Filename, img.CreatedAt).Scan(&id)
+		if err != nil {
+			return err
+		}
+		if err := linkTags(ctx, tx, "image_tags", "image_id", id, tags.images[img.ID], tags.ids); err != nil {
+			return err
+		}
+		report.ImageIDs[img.ID] = id
+		report.Images.Created++
+	}
+
+	return nil
+}
+
+func restoreSection(ctx context.Context, tx *database.Tx, section models.PortfolioSection, policy string, report *Report) error {
+	var existingID int
+	err := tx.QueryRowContext(ctx, `SELECT id FROM portfolio_sections WHERE slug = $1`, section.Slug).Scan(&existingID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	exists := err == nil
+
+	switch {
+	case exists && policy == PolicySkip:
+		report.SectionIDs[section.ID] = existingID
+		report.Sections.Skipped++
+		return nil
+
+	case exists && policy == PolicyOverwrite:
+		_, err := tx.ExecContext(ctx, `
+			UPDATE portfolio_sections
+			SET name = $1, description = $2, display_order = $3, updated_at = $4
+			WHERE id = $5
+		`, section.Name, section.Description, section.DisplayOrder, section.UpdatedAt, existingID)
+		if err != nil {
+			return err
+		}
+		report.SectionIDs[section.ID] = existingID
+		report.Sections.Updated++
+		return nil
+	}
+
+	slug := section.Slug
+	if exists {
+		if slug, err = freeSlug(ctx, tx, "portfolio_sections", section.Slug); err != nil {
+			return err
+		}
+		report.Renamed[section.Slug] = slug
+	}
+
+	var id int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO portfolio_sections (name, slug, description, display_order, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, section.Name, slug, section.Description, section.DisplayOrder, section.CreatedAt, section.UpdatedAt).Scan(&id)
+	if err != nil {
+		return err
+	}
+	report.SectionIDs[section.ID] = id
+	report.Sections.Created++
+	return nil
+}
+
+// restoreContact inserts a message unless the same message was already restored.
+// Messages have no natural key, so sender, subject and timestamp identify them.
+func restoreContact(ctx context.Context, tx *database.Tx, msg models.ContactMessage, report *Report) error {
+	var exists bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM contact_messages WHERE email = $1 AND subject = $2 AND created_at = $3)
+	`, msg.Email, msg.Subject, msg.CreatedAt).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		report.Contacts.Skipped++
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO contact_messages (name, email, phone, subject, message, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, msg.Name, msg.Email, msg.Phone, msg.Subject, msg.Message, msg.Status, msg.CreatedAt, msg.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	report.Contacts.Created++
+	return nil
+}
+
+// restoreUser imports an admin account keyed by email.
+// The rename policy does not apply to users, an existing email is kept.
+func restoreUser(ctx context.Context, tx *database.Tx, user User, policy string, report *Report) error {
+	var existingID int
+	err := tx.QueryRowContext(ctx, `SELECT id FROM users WHERE email = $1`, user.Email).Scan(&existingID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if err == nil {
+		if policy != PolicyOverwrite || user.PasswordHash == "" {
+			report.Users.Skipped++
+			return nil
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE users SET password_hash = $1 WHERE id = $2`, user.PasswordHash, existingID); err != nil {
+			return err
+		}
+		report.Users.Updated++
+		return nil
+	}
+
+	hash := user.PasswordHash
+	if hash == "" {
+		hash = unusablePasswordHash
+	}
+
+	username, err := freeUsername(ctx, tx, user.Username)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO users (email, username, password_hash, created_at, last_login)
+		VALUES ($1, $2, $3, $4, $5)
+	`, user.Email, username, hash, user.CreatedAt, user.LastLogin)
+	if err != nil {
+		return err
+	}
+	report.Users.Created++
+	return nil
+}
+
+// planMedia verifies the extracted files and decides where each one goes.
+// A file identical to the one already uploaded is skipped. One that differs
+// replaces it under the overwrite policy and gets a new name under rename,
+// with the manifest's covers and images rewritten to point at it; under
+// skip the restore fails, as the restored rows would show the wrong image.
+func planMedia(storage *services.StorageService, tmpDir string, manifest *Manifest, policy string, report *Report) ([]mediaCopy, error) {
+	var copies []mediaCopy
+	for _, file := range manifest.Media {
+		src := filepath.Join(tmpDir, path.Base(file.Path))
+		sum, err := fileChecksum(src)
+		if err != nil {
+			return nil, fmt.Errorf("media %s missing from archive: %w", file.URL, err)
+		}
+		if sum != file.SHA256 {
+			return nil, fmt.Errorf("media %s is corrupt (checksum mismatch)", file.URL)
+		}
+
+		url := "/uploads/" + path.Base(file.URL)
+		dest, err := storage.GetFilePath(url)
+		if err != nil {
+			return nil, fmt.Errorf("media %s: %w", file.URL, err)
+		}
+		if existing, err := fileChecksum(dest); err != nil {
+			report.Media.Created++
+		} else {
+			switch {
+			case existing == sum:
+				report.Media.Skipped++
+				dest = ""
+			case policy == PolicyOverwrite:
+				report.Media.Updated++
+			case policy == PolicyRename:
+				url = "/uploads/" + uuid.New().String() + path.Ext(url)
+				if dest, err = storage.GetFilePath(url); err != nil {
+					return nil, fmt.Errorf("media %s: %w", file.URL, err)
+				}
+				report.Media.Created++
+			default:
+				return nil, fmt.Errorf("media %s differs from the file already at %s, restore with the rename or overwrite policy", file.URL, url)
+			}
+		}
+
+		if url != file.URL {
+			report.RenamedMedia[file.URL] = url
+		}
+		if dest != "" {
+			copies = append(copies, mediaCopy{src: src, dest: dest})
+		}
+	}
+
+	for i := range manifest.Categories {
+		cat := &manifest.Categories[i]
+		if url, ok := report.RenamedMedia[cat.CoverImage]; ok {
+			cat.CoverImage = url
+		}
+		for j := range cat.Images {
+			if url, ok := report.RenamedMedia[cat.Images[j].Src]; ok {
+				cat.Images[j].Src = url
+			}
+		}
+	}
+	return copies, nil
+}
+
+// copyFile copies an extracted file into the upload directory
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", dest, err)
+	}
+	return nil
+}
+
+// freeSlug finds the first slug-N not used in table
+func freeSlug(ctx context.Context, tx *database.Tx, table, slug string) (string, error) {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", slug, n)
+
+		var exists bool
+		query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE slug = $1)`, table)
+		if err := tx.QueryRowContext(ctx, query, candidate).Scan(&exists); err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+}
+
+// freeUsername returns username, or username-N if it is already taken
+func freeUsername(ctx context.Context, tx *database.Tx, username string) (string, error) {
+	candidate := username
+	for n := 2; ; n++ {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`, candidate).Scan(&exists); err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", username, n)
+	}
+}
+
+func fileChecksum(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,12 @@
+// backend/internal/backup/sqlite_test.go
+
+//go:build sqlite
+
+package backup_test
+
+import "github.com/supraik/Freelance-Portfolio/internal/database/databasetest"
+
+// With -tags sqlite the backup tests run against SQLite instead
+func init() {
+	openTestDB = databasetest.OpenSQLite
+}
@@ -0,0 +1,214 @@
+// backend/internal/cache/cache.go
+
+// Package cache is a small in-process read-through cache with TTLs,
+// request coalescing and tag-based invalidation.
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Loader produces a value on a cache miss, along with the tags that
+// invalidate it
+type Loader func(ctx context.Context) (value interface{}, tags []string, err error)
+
+// Broadcaster tells other instances about tags invalidated locally
+type Broadcaster interface {
+	Broadcast(tags []string)
+}
+
+// Stats are the cache counters since startup
+type Stats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Coalesced     uint64 `json:"coalesced"`
+	Invalidations uint64 `json:"invalidations"`
+	Entries       int    `json:"entries"`
+}
+
+type entry struct {
+	value   interface{}
+	tags    []string
+	expires time.Time
+}
+
+// call is an in-flight load shared by concurrent callers of the same key
+type call struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// Cache stores loaded values by key until they expire or one of their tags
+// is invalidated
+type Cache struct {
+	ttl         time.Duration
+	now         func() time.Time
+	broadcaster Broadcaster
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	tags    map[string]map[string]struct{} // tag -> keys
+	calls   map[string]*call
+	// generation is bumped on every invalidation so loads that started
+	// before it neither store nor hand out stale values to later callers
+	generation uint64
+
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	coalesced     atomic.Uint64
+	invalidations atomic.Uint64
+}
+
+// New creates a cache whose entries live for ttl
+func New(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+		tags:    make(map[string]map[string]struct{}),
+		calls:   make(map[string]*call),
+	}
+}
+
+// GetOrLoad returns the cached value for key, or runs load once for all
+// concurrent callers and caches its result. Errors are never cached.
+func (c *Cache) GetOrLoad(ctx context.Context, key string, load Loader) (interface{}, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		if c.now().Before(e.expires) {
+			c.mu.Unlock()
+			c.hits.Add(1)
+			return e.value, nil
+		}
+		c.remove(key)
+	}
+
+	if inflight, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		c.coalesced.Add(1)
+		return c.wait(ctx, inflight)
+	}
+
+	cl := &call{done: make(chan struct{})}
+	c.calls[key] = cl
+	generation := c.generation
+	c.mu.Unlock()
+	c.misses.Add(1)
+
+	// The load is shared, so one caller going away must not cancel it for
+	// the others; repositories still apply their own deadlines
+	go c.load(context.WithoutCancel(ctx), key, generation, cl, load)
+
+	return c.wait(ctx, cl)
+}
+
+func (c *Cache) load(ctx context.Context, key string, generation uint64, cl *call, load Loader) {
+	value, tags, err := load(ctx)
+
+	c.mu.Lock()
+	if c.calls[key] == cl {
+		delete(c.calls, key)
+	}
+	if err == nil && generation == c.generation {
+		c.entries[key] = &entry{value: value, tags: tags, expires: c.now().Add(c.ttl)}
+		for _, tag := range tags {
+			if c.tags[tag] == nil {
+				c.tags[tag] = make(map[string]struct{})
+			}
+			c.tags[tag][key] = struct{}{}
+		}
+	}
+	c.mu.Unlock()
+
+	cl.value, cl.err = value, err
+	close(cl.done)
+}
+
+func (c *Cache) wait(ctx context.Context, cl *call) (interface{}, error) {
+	select {
+	case <-cl.done:
+		return cl.value, cl.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// SetBroadcaster publishes every later Invalidate call through b.
+// It must be called before the cache is used.
+func (c *Cache) SetBroadcaster(b Broadcaster) {
+	c.broadcaster = b
+}
+
+// Invalidate drops every entry carrying one of the tags, here and on the
+// instances reached by the broadcaster
+func (c *Cache) Invalidate(tags ...string) {
+	c.invalidate(tags)
+	if c.broadcaster != nil {
+		c.broadcaster.Broadcast(tags)
+	}
+}
+
+// invalidate drops the tagged entries of this instance only
+func (c *Cache) invalidate(tags []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.forgetCalls()
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			c.remove(key)
+		}
+	}
+}
+
+// Purge drops every entry of this instance
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.forgetCalls()
+	c.entries = make(map[string]*entry)
+	c.tags = make(map[string]map[string]struct{})
+}
+
+// Stats returns the current counters
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Coalesced:     c.coalesced.Load(),
+		Invalidations: c.invalidations.Load(),
+		Entries:       entries,
+	}
+}
+
+// forgetCalls detaches in-flight loads so later callers start fresh ones;
+// c.mu must be held
+func (c *Cache) forgetCalls() {
+	c.generation++
+	c.invalidations.Add(1)
+	c.calls = make(map[string]*call)
+}
+
+// remove deletes key and its tag index entries; c.mu must be held
+func (c *Cache) remove(key string) {
+	e, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	delete(c.entries, key)
+	for _, tag := range e.tags {
+		delete(c.tags[tag], key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// backend/internal/cache/cache_test.go
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// never returns a loader that fails the test if it runs
+func never(t *testing.T) Loader {
+	return func(context.Context) (interface{}, []string, error) {
+		t.Error("loader ran, want a cache hit")
+		return nil, nil, nil
+	}
+}
+
+// value returns a loader of v tagged with tags
+func value(v interface{}, tags ...string) Loader {
+	return func(context.Context) (interface{}, []string, error) {
+		return v, tags, nil
+	}
+}
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGetOrLoadCoalesces(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Minute)
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (interface{}, []string, error) {
+		loads.Add(1)
+		<-release
+		return "galleries", []string{"gallery"}, nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]interface{}, callers)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := c.GetOrLoad(ctx, "galleries", load)
+			if err != nil {
+				t.Errorf("GetOrLoad: %v", err)
+			}
+			results[i] = v
+		}(i)
+	}
+	waitFor(t, "the callers to join one load", func() bool { return c.Stats().Coalesced == callers-1 })
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loader ran %d times, want once", n)
+	}
+	for i, v := range results {
+		if v != "galleries" {
+			t.Errorf("caller %d got %v", i, v)
+		}
+	}
+	if v, _ := c.GetOrLoad(ctx, "galleries", never(t)); v != "galleries" {
+		t.Errorf("GetOrLoad after the load = %v, want the cached value", v)
+	}
+	if s := c.Stats(); s.Misses != 1 || s.Coalesced != callers-1 || s.Hits != 1 || s.Entries != 1 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
+
+func TestGetOrLoadCallerGivesUp(t *testing.T) {
+	c := New(time.Minute)
+	release := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, []string, error) {
+		<-release
+		return "galleries", nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetOrLoad(ctx, "galleries", load); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetOrLoad = %v, want the caller's deadline", err)
+	}
+
+	// The load goes on for everyone else and is cached
+	close(release)
+	waitFor(t, "the load to be cached", func() bool { return c.Stats().Entries == 1 })
+	if v, err := c.GetOrLoad(context.Background(), "galleries", never(t)); err != nil || v != "galleries" {
+		t.Errorf("GetOrLoad = %v, %v, want the value loaded for the caller that gave up", v, err)
+	}
+}
+
+func TestGetOrLoadExpires(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Minute)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	if v, _ := c.GetOrLoad(ctx, "k", value("first")); v != "first" {
+		t.Fatalf("GetOrLoad = %v", v)
+	}
+	now = now.Add(time.Minute - time.Nanosecond)
+	if v, _ := c.GetOrLoad(ctx, "k", never(t)); v != "first" {
+		t.Errorf("GetOrLoad before the TTL = %v, want the cached value", v)
+	}
+	now = now.Add(time.Nanosecond)
+	if v, _ := c.GetOrLoad(ctx, "k", value("second")); v != "second" {
+		t.Errorf("GetOrLoad at the TTL = %v, want a fresh load", v)
+	}
+
+	// Errors are not cached
+	boom := errors.New("boom")
+	fail := func(context.Context) (interface{}, []string, error) { return nil, nil, boom }
+	if _, err := c.GetOrLoad(ctx, "err", fail); !errors.Is(err, boom) {
+		t.Fatalf("GetOrLoad = %v, want the loader's error", err)
+	}
+	if v, err := c.GetOrLoad(ctx, "err", value("loaded")); err != nil || v != "loaded" {
+		t.Errorf("GetOrLoad after an error = %v, %v, want a fresh load", v, err)
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Minute)
+	c.GetOrLoad(ctx, "wedding", value("w", "gallery:wedding", "gallery"))
+	c.GetOrLoad(ctx, "portraits", value("p", "gallery:portraits", "gallery"))
+
+	c.Invalidate("gallery:wedding")
+	if v, _ := c.GetOrLoad(ctx, "wedding", value("w2")); v != "w2" {
+		t.Errorf("invalidated entry = %v, want reloaded", v)
+	}
+	if v, _ := c.GetOrLoad(ctx, "portraits", never(t)); v != "p" {
+		t.Errorf("entry with other tags = %v, want kept", v)
+	}
+
+	c.Invalidate("gallery")
+	if s := c.Stats(); s.Entries != 1 || s.Invalidations != 2 {
+		t.Errorf("Stats = %+v, want only the untagged reload left after 2 invalidations", s)
+	}
+}
+
+func TestInvalidateDuringLoad(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Minute)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	stale := func(context.Context) (interface{}, []string, error) {
+		close(started)
+		<-release
+		return "stale", []string{"gallery"}, nil
+	}
+
+	done := make(chan interface{})
+	go func() {
+		v, _ := c.GetOrLoad(ctx, "galleries", stale)
+		done <- v
+	}()
+	<-started
+
+	// The data changed while the load was reading it
+	c.Invalidate("gallery")
+
+	// A caller arriving now must not join the stale load
+	if v, _ := c.GetOrLoad(ctx, "galleries", value("fresh", "gallery")); v != "fresh" {
+		t.Errorf("GetOrLoad after Invalidate = %v, want a fresh load", v)
+	}
+
+	close(release)
+	if v := <-done; v != "stale" {
+		t.Errorf("caller of the stale load got %v", v)
+	}
+	if v, _ := c.GetOrLoad(ctx, "galleries", never(t)); v != "fresh" {
+		t.Errorf("cached value = %v, want the load finished last not to replace the fresh one", v)
+	}
+
+	// Without the fresh load in between, the stale result is not cached either
+	c.Purge()
+	started, release = make(chan struct{}), make(chan struct{})
+	go func() {
+		v, _ := c.GetOrLoad(ctx, "galleries", stale)
+		done <- v
+	}()
+	<-started
+	c.Invalidate("gallery")
+	close(release)
+	<-done
+	if s := c.Stats(); s.Entries != 0 {
+		t.Errorf("%d entries after a load invalidated in flight, want none", s.Entries)
+	}
+}
+
+type recorder struct {
+	mu   sync.Mutex
+	tags [][]string
+}
+
+func (r *recorder) Broadcast(tags []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tags = append(r.tags, tags)
+}
+
+func TestInvalidateBroadcasts(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Minute)
+	r := &recorder{}
+	c.SetBroadcaster(r)
+	c.GetOrLoad(ctx, "wedding", value("w", "gallery:wedding"))
+
+	c.Invalidate("gallery:wedding", "gallery")
+	if len(r.tags) != 1 || len(r.tags[0]) != 2 {
+		t.Errorf("broadcast %v, want the invalidated tags once", r.tags)
+	}
+
+	// Invalidations received from other instances are not sent back
+	c.GetOrLoad(ctx, "wedding", value("w", "gallery:wedding"))
+	c.invalidate([]string{"gallery:wedding"})
+	if len(r.tags) != 1 || c.Stats().Entries != 0 {
+		t.Errorf("received invalidation broadcast %v with %d entries left", r.tags, c.Stats().Entries)
+	}
+}
@@ -0,0 +1,108 @@
+// backend/internal/cache/sync.go
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+)
+
+// Channel is the Postgres NOTIFY channel carrying cache invalidations
+const Channel = "portfolio_cache_invalidate"
+
+const notifyTimeout = 2 * time.Second
+
+// message is the NOTIFY payload
+type message struct {
+	Origin string   `json:"origin"`
+	Tags   []string `json:"tags,omitempty"`
+	All    bool     `json:"all,omitempty"`
+}
+
+// Sync keeps caches on several replicas consistent: local invalidations are
+// published with NOTIFY and notifications from other replicas invalidate
+// the local cache
+type Sync struct {
+	cache       *Cache
+	db          *database.DB
+	databaseURL string
+	origin      string
+}
+
+// NewSync connects c to the other replicas sharing the database and
+// registers itself as the cache's broadcaster
+func NewSync(c *Cache, db *database.DB, databaseURL string) *Sync {
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	s := &Sync{
+		cache:       c,
+		db:          db,
+		databaseURL: databaseURL,
+		origin:      hex.EncodeToString(id),
+	}
+	c.SetBroadcaster(s)
+	return s
+}
+
+// Broadcast publishes invalidated tags to the other replicas
+func (s *Sync) Broadcast(tags []string) {
+	payload, err := json.Marshal(message{Origin: s.origin, Tags: tags})
+	if err != nil {
+		log.Printf("Failed to encode cache invalidation: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	if err := s.db.Notify(ctx, Channel, string(payload)); err != nil {
+		log.Printf("Failed to publish cache invalidation %v: %v", tags, err)
+	}
+}
+
+// PublishPurge tells every running replica to drop its whole cache, for
+// tools such as restore that write to the database directly
+func PublishPurge(ctx context.Context, db *database.DB) error {
+	payload, err := json.Marshal(message{Origin: "cli", All: true})
+	if err != nil {
+		return err
+	}
+	return db.Notify(ctx, Channel, string(payload))
+}
+
+// Run listens for invalidations from other replicas until ctx is done
+func (s *Sync) Run(ctx context.Context) {
+	if s.db.IsSQLite() {
+		return
+	}
+
+	err := database.Listen(ctx, s.databaseURL, Channel, s.handle, func() {
+		// Invalidations may have been sent while disconnected
+		s.cache.Purge()
+	})
+	if err != nil {
+		log.Printf("Cache invalidation listener stopped: %v", err)
+	}
+}
+
+func (s *Sync) handle(payload string) {
+	var msg message
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		log.Printf("Ignoring malformed cache invalidation %q: %v", payload, err)
+		return
+	}
+	if msg.Origin == s.origin {
+		return
+	}
+	if msg.All {
+		s.cache.Purge()
+		return
+	}
+	s.cache.invalidate(msg.Tags)
+}
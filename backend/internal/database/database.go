@@ -2,7 +2,9 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -10,109 +12,71 @@ import (
 	_ "github.com/lib/pq"
 )
 
-// Connect establishes a connection to PostgreSQL
-func Connect(databaseURL string) (*sql.DB, error) {
-	db, err := sql.Open("postgres", databaseURL)
+// Connect establishes a connection to PostgreSQL, or to SQLite for
+// sqlite:// URLs when the binary is built with the sqlite tag
+func Connect(databaseURL string) (*DB, error) {
+	dialect, driver, dsn := parseDatabaseURL(databaseURL)
+	if dialect == SQLite && !driverRegistered(driver) {
+		return nil, errors.New("SQLite support is not compiled in, rebuild with -tags sqlite")
+	}
+
+	db, err := sql.Open(driver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	if dialect == SQLite {
+		// SQLite allows a single writer, a small pool avoids lock contention
+		db.SetMaxOpenConns(4)
+		db.SetMaxIdleConns(4)
+	} else {
+		db.SetMaxOpenConns(25)
+		db.SetMaxIdleConns(5)
+		db.SetConnMaxLifetime(5 * time.Minute)
+	}
 
 	// Test connection
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Println("✅ Database connected successfully")
-	return db, nil
+	log.Printf("✅ Database connected successfully (%s)", dialect)
+	return &DB{DB: db, Dialect: dialect, timeouts: Timeouts{Default: DefaultQueryTimeout}}, nil
 }
 
-// Migrate runs database migrations
-func Migrate(db *sql.DB) error {
-	migrations := []string{
-		// Contact messages table
-		`CREATE TABLE IF NOT EXISTS contact_messages (
-			id SERIAL PRIMARY KEY,
-			name VARCHAR(255) NOT NULL,
-			email VARCHAR(255) NOT NULL,
-			subject VARCHAR(255),
-			message TEXT NOT NULL,
-			is_read BOOLEAN DEFAULT FALSE,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-
-		// Gallery categories table
-		`CREATE TABLE IF NOT EXISTS gallery_categories (
-			id SERIAL PRIMARY KEY,
-			slug VARCHAR(100) UNIQUE NOT NULL,
-			title VARCHAR(255) NOT NULL,
-			description TEXT,
-			cover_image VARCHAR(500),
-			display_order INT DEFAULT 0,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-
-		// Gallery images table
-		`CREATE TABLE IF NOT EXISTS gallery_images (
-			id SERIAL PRIMARY KEY,
-			category_id INT REFERENCES gallery_categories(id) ON DELETE CASCADE,
-			src VARCHAR(500) NOT NULL,
-			alt VARCHAR(255),
-			aspect_ratio VARCHAR(20) DEFAULT 'portrait',
-			display_order INT DEFAULT 0,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-
-		// Admin users table
-		`CREATE TABLE IF NOT EXISTS admin_users (
-			id SERIAL PRIMARY KEY,
-			email VARCHAR(255) UNIQUE NOT NULL,
-			password_hash VARCHAR(255) NOT NULL,
-			name VARCHAR(255),
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			last_login TIMESTAMP
-		)`,
-
-		// Page analytics table (optional)
-		`CREATE TABLE IF NOT EXISTS page_analytics (
-			id SERIAL PRIMARY KEY,
-			page_path VARCHAR(255) NOT NULL,
-			visitor_id VARCHAR(100),
-			user_agent TEXT,
-			referrer VARCHAR(500),
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
+// driverRegistered reports whether a database/sql driver is linked in
+func driverRegistered(name string) bool {
+	for _, driver := range sql.Drivers() {
+		if driver == name {
+			return true
+		}
+	}
+	return false
+}
 
-		// Create indexes for better performance
-		`CREATE INDEX IF NOT EXISTS idx_contact_messages_created_at ON contact_messages(created_at DESC)`,
-		`CREATE INDEX IF NOT EXISTS idx_contact_messages_is_read ON contact_messages(is_read)`,
-		`CREATE INDEX IF NOT EXISTS idx_gallery_images_category ON gallery_images(category_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_gallery_categories_slug ON gallery_categories(slug)`,
-		`CREATE INDEX IF NOT EXISTS idx_page_analytics_path ON page_analytics(page_path)`,
-		`CREATE INDEX IF NOT EXISTS idx_page_analytics_created_at ON page_analytics(created_at DESC)`,
+// Migrate applies all pending migrations from the embedded migration files.
+// It refuses to run when a previously applied migration file has been edited.
+func Migrate(db *DB) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
 	}
 
-	for i, migration := range migrations {
-		_, err := db.Exec(migration)
-		if err != nil {
-			return fmt.Errorf("migration %d failed: %w", i+1, err)
-		}
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		return err
 	}
 
-	log.Println("✅ Database migrations completed")
+	log.Printf("✅ Database migrations completed (%d applied)", applied)
 	return nil
 }
 
 // SeedAdminUser creates a default admin user if none exists
 // Call this only once during initial setup
-func SeedAdminUser(db *sql.DB, email, passwordHash, name string) error {
+func SeedAdminUser(db *DB, email, passwordHash, name string) error {
 	query := `
-		INSERT INTO admin_users (email, password_hash, name)
+		INSERT INTO users (email, password_hash, username)
 		VALUES ($1, $2, $3)
 		ON CONFLICT (email) DO NOTHING
 		RETURNING id
@@ -0,0 +1,69 @@
+// backend/internal/database/databasetest/databasetest.go
+
+// Package databasetest opens migrated, empty databases for tests that run
+// against Postgres or, in builds with -tags sqlite, an embedded SQLite file.
+package databasetest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+)
+
+// OpenPostgres connects to TEST_DATABASE_URL, migrates it and empties the
+// given comma-separated tables. The test is skipped when the variable is
+// unset. The database is wiped, so never point it at real data.
+func OpenPostgres(t *testing.T, tables string) *database.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := database.Connect(url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if _, err := db.Exec(`TRUNCATE ` + tables + ` RESTART IDENTITY CASCADE`); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	return db
+}
+
+// OpenSQLite creates a migrated SQLite database in a temporary file and
+// empties the given comma-separated tables, children first, of the rows the
+// migrations seed. It needs the driver, so callers build with -tags sqlite.
+func OpenSQLite(t *testing.T, tables string) *database.DB {
+	t.Helper()
+
+	db, err := database.Connect("sqlite://" + filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	for _, table := range strings.Split(tables, ",") {
+		table = strings.TrimSpace(table)
+		if _, err := db.Exec(`DELETE FROM ` + table); err != nil {
+			t.Fatalf("empty %s: %v", table, err)
+		}
+		if _, err := db.Exec(`DELETE FROM sqlite_sequence WHERE name = $1`, table); err != nil {
+			t.Fatalf("reset %s ids: %v", table, err)
+		}
+	}
+
+	return db
+}
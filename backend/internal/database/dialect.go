@@ -0,0 +1,145 @@
+// backend/internal/database/dialect.go
+package database
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+)
+
+// Dialect identifies the SQL flavour of the connected database
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// DB wraps *sql.DB with the dialect of the connected database.
+// Queries are written with Postgres placeholders ($1, $2, ...) and are
+// rebound automatically when running on SQLite.
+type DB struct {
+	*sql.DB
+	Dialect Dialect
+
+	timeouts Timeouts
+}
+
+// Tx is a transaction that rebinds queries like DB does
+type Tx struct {
+	*sql.Tx
+	dialect Dialect
+}
+
+var postgresPlaceholder = regexp.MustCompile(`\$(\d+)`)
+
+// rebind rewrites $N placeholders into the form the dialect understands
+func rebind(dialect Dialect, query string) string {
+	if dialect != SQLite {
+		return query
+	}
+	// SQLite supports numbered ?NNN parameters, so repeated and
+	// out-of-order placeholders keep working
+	return postgresPlaceholder.ReplaceAllString(query, "?$1")
+}
+
+// Rebind rewrites a Postgres-style query for this database
+func (db *DB) Rebind(query string) string {
+	return rebind(db.Dialect, query)
+}
+
+// IsSQLite reports whether the database is SQLite
+func (db *DB) IsSQLite() bool {
+	return db.Dialect == SQLite
+}
+
+// The query methods below shadow the ones on *sql.DB and *sql.Tx so callers
+// can keep writing $N placeholders regardless of the dialect.
+
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.DB.Exec(db.Rebind(query), args...)
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.DB.ExecContext(ctx, db.Rebind(query), args...)
+}
+
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.DB.Query(db.Rebind(query), args...)
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return db.DB.QueryContext(ctx, db.Rebind(query), args...)
+}
+
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.DB.QueryRow(db.Rebind(query), args...)
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return db.DB.QueryRowContext(ctx, db.Rebind(query), args...)
+}
+
+// BeginTx starts a dialect-aware transaction
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, dialect: db.Dialect}, nil
+}
+
+// Begin starts a dialect-aware transaction
+func (db *DB) Begin() (*Tx, error) {
+	return db.BeginTx(context.Background(), nil)
+}
+
+func (tx *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.Exec(rebind(tx.dialect, query), args...)
+}
+
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.ExecContext(ctx, rebind(tx.dialect, query), args...)
+}
+
+func (tx *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.Query(rebind(tx.dialect, query), args...)
+}
+
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.QueryContext(ctx, rebind(tx.dialect, query), args...)
+}
+
+func (tx *Tx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return tx.Tx.QueryRow(rebind(tx.dialect, query), args...)
+}
+
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return tx.Tx.QueryRowContext(ctx, rebind(tx.dialect, query), args...)
+}
+
+// parseDatabaseURL returns the dialect, driver name and DSN for a DATABASE_URL.
+// sqlite://path/to/file.db selects SQLite, anything else is passed to lib/pq.
+func parseDatabaseURL(databaseURL string) (Dialect, string, string) {
+	for _, prefix := range []string{"sqlite://", "sqlite:", "file:"} {
+		if strings.HasPrefix(databaseURL, prefix) {
+			path := strings.TrimPrefix(databaseURL, prefix)
+			if prefix == "file:" {
+				path = databaseURL
+			}
+			return SQLite, "sqlite", sqliteDSN(path)
+		}
+	}
+	return Postgres, "postgres", databaseURL
+}
+
+// sqliteDSN enables foreign keys (for ON DELETE CASCADE), WAL and a busy
+// timeout on every pooled connection
+func sqliteDSN(path string) string {
+	pragmas := "_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)"
+	if strings.Contains(path, "?") {
+		return path + "&" + pragmas
+	}
+	return path + "?" + pragmas
+}
@@ -0,0 +1,5 @@
+// backend/internal/database/export_test.go
+package database
+
+// MigrationLockID exposes the advisory lock key to the external tests
+const MigrationLockID = migrationLockID
@@ -0,0 +1,492 @@
+// backend/internal/database/migrate.go
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// Migration directories inside migrationFiles, one set per dialect
+var migrationDirs = map[Dialect]string{
+	Postgres: "migrations",
+	SQLite:   "migrations/sqlite",
+}
+
+// migrationLockID is the advisory lock key held while migrating so that
+// instances starting at the same time apply migrations one after another
+const migrationLockID int64 = 72631088410
+
+// ErrMigrationModified is returned when an applied migration file has changed
+var ErrMigrationModified = errors.New("applied migration has been modified")
+
+// ErrMigrationMissing is returned when the database has a version with no file
+var ErrMigrationMissing = errors.New("applied migration not found")
+
+var (
+	migrationFilename = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	nonMigrationChars = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// AppliedMigration is a row of the schema_migrations table
+type AppliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator applies the embedded SQL migrations and tracks them in schema_migrations
+type Migrator struct {
+	db         *DB
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for the embedded migrations of the database's dialect
+func NewMigrator(db *DB) (*Migrator, error) {
+	migrations, err := LoadMigrations(migrationFiles, migrationDirs[db.Dialect])
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// LoadMigrations reads NNN_name.up.sql / NNN_name.down.sql pairs from dir in fsys
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	paths, err := fs.Glob(fsys, dir+"/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, path := range paths {
+		name := path[len(dir)+1:]
+		match := migrationFilename.FindStringSubmatch(name)
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration filename: %s", name)
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names: %s and %s", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+			m.Checksum = checksum(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrations returns all known migrations in version order
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Applied returns the migrations recorded in schema_migrations keyed by version
+func (m *Migrator) Applied(ctx context.Context) (map[int]AppliedMigration, error) {
+	if err := ensureMigrationsTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+	return appliedMigrations(ctx, m.db)
+}
+
+// Verify checks that every applied migration still matches its file
+func (m *Migrator) Verify(ctx context.Context) error {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return err
+	}
+	return m.verify(applied)
+}
+
+// MigrationStatus describes the state of a migration in the database
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Modified  bool // applied file differs from the embedded one
+	Missing   bool // applied in the database but no longer embedded
+}
+
+// Up applies all pending migrations and returns how many were applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	return m.UpTo(ctx, 0)
+}
+
+// UpTo applies pending migrations up to and including target.
+// A target of 0 applies every pending migration.
+func (m *Migrator) UpTo(ctx context.Context, target int) (int, error) {
+	count := 0
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if target > 0 && migration.Version > target {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := applyUp(ctx, conn, m.db.Dialect, migration); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// Down rolls back the most recently applied migrations, newest first
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var rolledBack []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		var err error
+		rolledBack, err = m.down(ctx, conn, steps)
+		return err
+	})
+
+	return rolledBack, err
+}
+
+// Redo rolls back the latest applied migration and applies it again
+func (m *Migrator) Redo(ctx context.Context) (*Migration, error) {
+	var redone *Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		rolledBack, err := m.down(ctx, conn, 1)
+		if err != nil {
+			return err
+		}
+		if len(rolledBack) == 0 {
+			return errors.New("no applied migrations to redo")
+		}
+
+		redone = &rolledBack[0]
+		return applyUp(ctx, conn, m.db.Dialect, *redone)
+	})
+
+	return redone, err
+}
+
+// Status reports every known migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if a, ok := applied[migration.Version]; ok {
+			appliedAt := a.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.Modified = a.Checksum != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	// Anything left was applied from a file that no longer exists
+	for _, a := range applied {
+		appliedAt := a.AppliedAt
+		statuses = append(statuses, MigrationStatus{
+			Version:   a.Version,
+			Name:      a.Name,
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Missing:   true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// down rolls back up to steps migrations on a locked connection
+func (m *Migrator) down(ctx context.Context, conn *sql.Conn, steps int) ([]Migration, error) {
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verify(applied); err != nil {
+		return nil, err
+	}
+
+	var rolledBack []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if err := applyDown(ctx, conn, m.db.Dialect, migration); err != nil {
+			return rolledBack, err
+		}
+		rolledBack = append(rolledBack, migration)
+	}
+
+	return rolledBack, nil
+}
+
+// verify compares applied migrations with the embedded files
+func (m *Migrator) verify(applied map[int]AppliedMigration) error {
+	known := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+
+	for _, version := range versions {
+		migration, ok := known[version]
+		if !ok {
+			return fmt.Errorf("%w: version %d (%s)", ErrMigrationMissing, version, applied[version].Name)
+		}
+		if migration.Checksum != applied[version].Checksum {
+			return fmt.Errorf("%w: %03d_%s.up.sql (checksum %s, applied %s)",
+				ErrMigrationModified, version, migration.Name,
+				shortChecksum(migration.Checksum), shortChecksum(applied[version].Checksum))
+		}
+	}
+
+	return nil
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory lock.
+// SQLite has no advisory locks; it only ever has one writer, so none is taken.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if m.db.Dialect == Postgres {
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+	}
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// execer is satisfied by *sql.DB, *sql.Conn and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// ensureMigrationsTable creates the schema_migrations table if needed
+func ensureMigrationsTable(ctx context.Context, db execer) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// appliedMigrations loads the rows of schema_migrations
+func appliedMigrations(ctx context.Context, db execer) (map[int]AppliedMigration, error) {
+	query := `
+		SELECT version, name, checksum, applied_at
+		FROM schema_migrations
+		ORDER BY version ASC
+	`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]AppliedMigration)
+	for rows.Next() {
+		var a AppliedMigration
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[a.Version] = a
+	}
+
+	return applied, rows.Err()
+}
+
+// applyUp runs a migration and records it in a single transaction
+func applyUp(ctx context.Context, conn *sql.Conn, dialect Dialect, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+		return fmt.Errorf("migration %03d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+
+	query := rebind(dialect, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`)
+	if _, err := tx.ExecContext(ctx, query, migration.Version, migration.Name, migration.Checksum); err != nil {
+		return fmt.Errorf("failed to record migration %03d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	return tx.Commit()
+}
+
+// applyDown reverts a migration and removes its record in a single transaction
+func applyDown(ctx context.Context, conn *sql.Conn, dialect Dialect, migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("migration %03d_%s has no down file", migration.Version, migration.Name)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+		return fmt.Errorf("rollback of %03d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+
+	query := rebind(dialect, `DELETE FROM schema_migrations WHERE version = $1`)
+	if _, err := tx.ExecContext(ctx, query, migration.Version); err != nil {
+		return fmt.Errorf("failed to unrecord migration %03d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	return tx.Commit()
+}
+
+// CreateMigration scaffolds the next numbered up/down pair in dir
+func CreateMigration(dir, name string) (upPath, downPath string, err error) {
+	name = strings.Trim(nonMigrationChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", errors.New("migration name is required")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	next := 1
+	for _, entry := range entries {
+		match := migrationFilename.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		if version, _ := strconv.Atoi(match[1]); version >= next {
+			next = version + 1
+		}
+	}
+
+	base := fmt.Sprintf("%03d_%s", next, name)
+	upPath = filepath.Join(dir, base+".up.sql")
+	downPath = filepath.Join(dir, base+".down.sql")
+
+	if err := os.WriteFile(upPath, []byte("-- "+base+".up.sql\n"), 0644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(downPath, []byte("-- "+base+".down.sql\n"), 0644); err != nil {
+		os.Remove(upPath)
+		return "", "", err
+	}
+
+	return upPath, downPath, nil
+}
+
+// checksum returns the hex encoded SHA-256 of a migration file
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// shortChecksum abbreviates a checksum for error messages
+func shortChecksum(sum string) string {
+	if len(sum) > 12 {
+		return sum[:12]
+	}
+	return sum
+}
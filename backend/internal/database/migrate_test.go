@@ -0,0 +1,67 @@
+// backend/internal/database/migrate_test.go
+package database_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+)
+
+// TestMigrationLock holds the advisory lock from another connection and
+// checks that a migrator waits for it. It needs TEST_DATABASE_URL.
+func TestMigrationLock(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+
+	db, err := database.Connect(url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	holder, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Close()
+	if _, err := holder.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, database.MigrationLockID); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := migrator.Up(ctx)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Up returned %v while another instance held the lock", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if _, err := holder.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, database.MigrationLockID); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Up after the lock was released: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Up still waiting after the lock was released")
+	}
+}
@@ -0,0 +1,2 @@
+-- Track admin logins on the users table (UserRepository.UpdateLastLogin)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login TIMESTAMP;
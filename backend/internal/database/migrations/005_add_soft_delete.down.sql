@@ -0,0 +1,9 @@
+-- Trashed rows would become visible again, so drop them first
+DELETE FROM gallery_images WHERE deleted_at IS NOT NULL;
+DELETE FROM gallery_categories WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_gallery_images_deleted_at;
+DROP INDEX IF EXISTS idx_gallery_categories_deleted_at;
+
+ALTER TABLE gallery_images DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE gallery_categories DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,7 @@
+-- Soft delete for galleries and images. Images trashed together with their
+-- gallery share its deleted_at, which is how a restore finds them again.
+ALTER TABLE gallery_categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE gallery_images ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_gallery_categories_deleted_at ON gallery_categories(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_gallery_images_deleted_at ON gallery_images(deleted_at);
@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_contact_messages_search;
+DROP INDEX IF EXISTS idx_gallery_images_search;
+DROP INDEX IF EXISTS idx_gallery_categories_search;
+
+DROP TRIGGER IF EXISTS contact_messages_search_update ON contact_messages;
+DROP TRIGGER IF EXISTS gallery_images_search_update ON gallery_images;
+DROP TRIGGER IF EXISTS gallery_categories_search_update ON gallery_categories;
+
+DROP FUNCTION IF EXISTS contact_messages_search_update();
+DROP FUNCTION IF EXISTS gallery_images_search_update();
+DROP FUNCTION IF EXISTS gallery_categories_search_update();
+
+ALTER TABLE contact_messages DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE gallery_images DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE gallery_categories DROP COLUMN IF EXISTS search_vector;
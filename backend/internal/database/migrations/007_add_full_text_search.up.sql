@@ -0,0 +1,56 @@
+-- Full-text search. Each table gets a weighted tsvector kept current by a
+-- trigger, so writes never have to compute it themselves.
+
+ALTER TABLE gallery_categories ADD COLUMN IF NOT EXISTS search_vector tsvector;
+ALTER TABLE gallery_images ADD COLUMN IF NOT EXISTS search_vector tsvector;
+ALTER TABLE contact_messages ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION gallery_categories_search_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('english', COALESCE(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(NEW.description, '')), 'B');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION gallery_images_search_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := to_tsvector('english', COALESCE(NEW.alt, ''));
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION contact_messages_search_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('english', COALESCE(NEW.name, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(NEW.subject, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(NEW.message, '')), 'B');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS gallery_categories_search_update ON gallery_categories;
+CREATE TRIGGER gallery_categories_search_update
+    BEFORE INSERT OR UPDATE OF title, description ON gallery_categories
+    FOR EACH ROW EXECUTE FUNCTION gallery_categories_search_update();
+
+DROP TRIGGER IF EXISTS gallery_images_search_update ON gallery_images;
+CREATE TRIGGER gallery_images_search_update
+    BEFORE INSERT OR UPDATE OF alt ON gallery_images
+    FOR EACH ROW EXECUTE FUNCTION gallery_images_search_update();
+
+DROP TRIGGER IF EXISTS contact_messages_search_update ON contact_messages;
+CREATE TRIGGER contact_messages_search_update
+    BEFORE INSERT OR UPDATE OF name, subject, message ON contact_messages
+    FOR EACH ROW EXECUTE FUNCTION contact_messages_search_update();
+
+-- Backfill existing rows through the triggers
+UPDATE gallery_categories SET title = title;
+UPDATE gallery_images SET alt = alt;
+UPDATE contact_messages SET name = name;
+
+CREATE INDEX IF NOT EXISTS idx_gallery_categories_search ON gallery_categories USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_gallery_images_search ON gallery_images USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_contact_messages_search ON contact_messages USING GIN(search_vector);
@@ -0,0 +1,3 @@
+ALTER TABLE gallery_images DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE gallery_images DROP COLUMN IF EXISTS version;
+ALTER TABLE gallery_categories DROP COLUMN IF EXISTS version;
@@ -0,0 +1,9 @@
+-- Version counters for optimistic concurrency. Every update bumps the
+-- version, and admin writes sent with If-Match only apply to the version
+-- the client last read.
+ALTER TABLE gallery_categories ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE gallery_images ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE gallery_images ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT NOW();
+
+-- Existing images have never been updated
+UPDATE gallery_images SET updated_at = created_at;
@@ -0,0 +1,9 @@
+-- Publishing workflow for galleries. Only published galleries are public,
+-- and only between their optional publish_at and unpublish_at (stored in UTC).
+-- Existing galleries were all public, so they start out published.
+ALTER TABLE gallery_categories ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'published', 'archived'));
+ALTER TABLE gallery_categories ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+ALTER TABLE gallery_categories ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_gallery_categories_status ON gallery_categories(status);
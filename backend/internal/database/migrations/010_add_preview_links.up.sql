@@ -0,0 +1,15 @@
+-- Expiring links that let someone review an unpublished gallery. The token
+-- itself is never stored: it is an HMAC over the row, re-derived on demand.
+-- Purging a gallery drops its links; times are stored in UTC.
+CREATE TABLE IF NOT EXISTS preview_links (
+    id SERIAL PRIMARY KEY,
+    category_id INTEGER NOT NULL REFERENCES gallery_categories(id) ON DELETE CASCADE,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_by INTEGER,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_preview_links_category ON preview_links(category_id);
+CREATE INDEX IF NOT EXISTS idx_preview_links_expires ON preview_links(expires_at);
@@ -0,0 +1,24 @@
+-- Tags group images and galleries across categories. Links are removed with
+-- the tag or the image/gallery; trashed items keep theirs for a restore.
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS image_tags (
+    image_id INTEGER NOT NULL REFERENCES gallery_images(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (image_id, tag_id)
+);
+
+CREATE TABLE IF NOT EXISTS category_tags (
+    category_id INTEGER NOT NULL REFERENCES gallery_categories(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (category_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_image_tags_tag ON image_tags(tag_id);
+CREATE INDEX IF NOT EXISTS idx_category_tags_tag ON category_tags(tag_id);
@@ -0,0 +1,20 @@
+-- Access modes for galleries. Public galleries are listed and open to all,
+-- unlisted ones are left out of lists, search and tags but open to anyone
+-- with the link, and password galleries additionally require the password,
+-- stored as a bcrypt hash like admin passwords.
+ALTER TABLE gallery_categories ADD COLUMN IF NOT EXISTS access VARCHAR(20) NOT NULL DEFAULT 'public'
+    CHECK (access IN ('public', 'unlisted', 'password'));
+ALTER TABLE gallery_categories ADD COLUMN IF NOT EXISTS password_hash VARCHAR(255);
+
+-- Unlock attempts and views of password galleries. Purging a gallery drops
+-- its log; times are stored in UTC.
+CREATE TABLE IF NOT EXISTS gallery_access_log (
+    id SERIAL PRIMARY KEY,
+    category_id INTEGER NOT NULL REFERENCES gallery_categories(id) ON DELETE CASCADE,
+    event VARCHAR(20) NOT NULL,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_gallery_access_log_category ON gallery_access_log(category_id, created_at);
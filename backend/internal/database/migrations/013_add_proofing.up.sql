@@ -0,0 +1,34 @@
+-- Client proofing. A gallery with a proofing_galleries row is in proofing
+-- mode; each invited client gets a link whose token is an HMAC over the
+-- client row, like preview links. Clients mark images as favorites and
+-- comment on them until they submit their selection. max_picks = 0 means
+-- no limit. Purging a gallery or image drops its proofing rows; times are
+-- stored in UTC.
+CREATE TABLE IF NOT EXISTS proofing_galleries (
+    category_id INTEGER PRIMARY KEY REFERENCES gallery_categories(id) ON DELETE CASCADE,
+    max_picks INTEGER NOT NULL DEFAULT 0 CHECK (max_picks >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS proofing_clients (
+    id SERIAL PRIMARY KEY,
+    category_id INTEGER NOT NULL REFERENCES gallery_categories(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    submitted_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS proofing_marks (
+    client_id INTEGER NOT NULL REFERENCES proofing_clients(id) ON DELETE CASCADE,
+    image_id INTEGER NOT NULL REFERENCES gallery_images(id) ON DELETE CASCADE,
+    favorite BOOLEAN NOT NULL DEFAULT FALSE,
+    comment TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (client_id, image_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_proofing_clients_category ON proofing_clients(category_id);
+CREATE INDEX IF NOT EXISTS idx_proofing_marks_image ON proofing_marks(image_id);
@@ -0,0 +1,4 @@
+-- Camera metadata read from the EXIF and XMP blocks of uploaded photos:
+-- camera, lens, exposure, capture date and orientation. NULL when the file
+-- carried none or was not uploaded here. Location tags are never stored.
+ALTER TABLE gallery_images ADD COLUMN IF NOT EXISTS metadata JSONB;
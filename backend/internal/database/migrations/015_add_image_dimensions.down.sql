@@ -0,0 +1,2 @@
+ALTER TABLE gallery_images DROP COLUMN IF EXISTS height;
+ALTER TABLE gallery_images DROP COLUMN IF EXISTS width;
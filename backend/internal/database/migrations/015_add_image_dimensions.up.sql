@@ -0,0 +1,5 @@
+-- Pixel size of gallery images as displayed, read from the image headers
+-- on upload or by the dimensions backfill; 0 while unknown. aspect_ratio is
+-- derived from it whenever it is known.
+ALTER TABLE gallery_images ADD COLUMN IF NOT EXISTS width INTEGER NOT NULL DEFAULT 0 CHECK (width >= 0);
+ALTER TABLE gallery_images ADD COLUMN IF NOT EXISTS height INTEGER NOT NULL DEFAULT 0 CHECK (height >= 0);
@@ -0,0 +1,3 @@
+ALTER TABLE gallery_images DROP COLUMN IF EXISTS dominant_color;
+ALTER TABLE gallery_images DROP COLUMN IF EXISTS lqip;
+ALTER TABLE gallery_images DROP COLUMN IF EXISTS blurhash;
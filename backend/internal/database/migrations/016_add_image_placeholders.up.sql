@@ -0,0 +1,6 @@
+-- What the site shows while a gallery image loads, computed from the image
+-- on upload or by the placeholders backfill; empty while unknown or for
+-- images that cannot be decoded.
+ALTER TABLE gallery_images ADD COLUMN IF NOT EXISTS blurhash VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE gallery_images ADD COLUMN IF NOT EXISTS lqip TEXT NOT NULL DEFAULT '';
+ALTER TABLE gallery_images ADD COLUMN IF NOT EXISTS dominant_color VARCHAR(7) NOT NULL DEFAULT '';
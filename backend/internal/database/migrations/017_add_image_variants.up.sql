@@ -0,0 +1,5 @@
+-- Resized variants of locally uploaded gallery images for srcset, written
+-- on upload or by the variants backfill: a JSON array of url, width,
+-- height and type. NULL until variants were made, [] when the image is
+-- smaller than every variant width.
+ALTER TABLE gallery_images ADD COLUMN IF NOT EXISTS variants JSONB;
@@ -0,0 +1,4 @@
+-- The name a gallery image's file had when it was uploaded, which proofing
+-- selections are exported under; empty for images added before it was kept
+-- or from a URL.
+ALTER TABLE gallery_images ADD COLUMN IF NOT EXISTS original_filename VARCHAR(255) NOT NULL DEFAULT '';
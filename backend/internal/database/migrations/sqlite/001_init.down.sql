@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS gallery_images;
+DROP TABLE IF EXISTS gallery_categories;
+DROP TABLE IF EXISTS portfolio_sections;
+DROP TABLE IF EXISTS contact_messages;
+DROP TABLE IF EXISTS users;
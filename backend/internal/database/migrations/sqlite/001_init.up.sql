@@ -0,0 +1,74 @@
+-- SQLite schema for local development, equivalent to Postgres migrations 001-004
+
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username VARCHAR(50) UNIQUE NOT NULL,
+    email VARCHAR(255) UNIQUE NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(20) DEFAULT 'admin',
+    last_login TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS contact_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    phone VARCHAR(15),
+    subject VARCHAR(200) NOT NULL,
+    message TEXT NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_status ON contact_messages(status);
+CREATE INDEX IF NOT EXISTS idx_contact_created ON contact_messages(created_at DESC);
+
+CREATE TABLE IF NOT EXISTS portfolio_sections (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    display_order INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_sections_order ON portfolio_sections(display_order);
+
+CREATE TABLE IF NOT EXISTS gallery_categories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    title VARCHAR(100) NOT NULL,
+    description TEXT,
+    cover_image VARCHAR(500),
+    display_order INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS gallery_images (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    category_id INTEGER REFERENCES gallery_categories(id) ON DELETE CASCADE,
+    src VARCHAR(500) NOT NULL,
+    alt VARCHAR(255),
+    aspect_ratio VARCHAR(20) DEFAULT 'portrait',
+    display_order INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_gallery_images_category_id ON gallery_images(category_id);
+CREATE INDEX IF NOT EXISTS idx_gallery_images_display_order ON gallery_images(category_id, display_order);
+
+INSERT OR IGNORE INTO portfolio_sections (name, slug, description, display_order) VALUES
+    ('Hero Section', 'hero', 'Main landing section with featured image', 1),
+    ('About Section', 'about', 'About section images', 2),
+    ('Services Section', 'services', 'Services and offerings', 3);
+
+INSERT OR IGNORE INTO gallery_categories (slug, title, description, cover_image, display_order) VALUES
+    ('wedding', 'Wedding', 'Bridal & Wedding Editorials', '/placeholder.svg', 1),
+    ('saree-branding', 'Saree Branding', 'Traditional Saree Campaigns', '/placeholder.svg', 2),
+    ('makeup', 'Makeup', 'Beauty & Makeup Artistry', '/placeholder.svg', 3),
+    ('aesthetic', 'Aesthetic', 'Artistic & Creative Work', '/placeholder.svg', 4);
@@ -0,0 +1,8 @@
+DELETE FROM gallery_images WHERE deleted_at IS NOT NULL;
+DELETE FROM gallery_categories WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_gallery_images_deleted_at;
+DROP INDEX IF EXISTS idx_gallery_categories_deleted_at;
+
+ALTER TABLE gallery_images DROP COLUMN deleted_at;
+ALTER TABLE gallery_categories DROP COLUMN deleted_at;
@@ -0,0 +1,6 @@
+-- Soft delete for galleries and images, see 005_add_soft_delete in the Postgres set
+ALTER TABLE gallery_categories ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE gallery_images ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_gallery_categories_deleted_at ON gallery_categories(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_gallery_images_deleted_at ON gallery_images(deleted_at);
@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS contact_messages_fts_update;
+DROP TRIGGER IF EXISTS contact_messages_fts_delete;
+DROP TRIGGER IF EXISTS contact_messages_fts_insert;
+DROP TRIGGER IF EXISTS gallery_images_fts_update;
+DROP TRIGGER IF EXISTS gallery_images_fts_delete;
+DROP TRIGGER IF EXISTS gallery_images_fts_insert;
+DROP TRIGGER IF EXISTS gallery_categories_fts_update;
+DROP TRIGGER IF EXISTS gallery_categories_fts_delete;
+DROP TRIGGER IF EXISTS gallery_categories_fts_insert;
+
+DROP TABLE IF EXISTS contact_messages_fts;
+DROP TABLE IF EXISTS gallery_images_fts;
+DROP TABLE IF EXISTS gallery_categories_fts;
@@ -0,0 +1,50 @@
+-- Full-text search with FTS5, standing in for the tsvector columns of
+-- 007_add_full_text_search in the Postgres set. Each index mirrors its table
+-- through triggers and is rebuilt here for existing rows.
+
+CREATE VIRTUAL TABLE IF NOT EXISTS gallery_categories_fts USING fts5(
+    title, description, content='gallery_categories', content_rowid='id', tokenize='porter unicode61'
+);
+CREATE VIRTUAL TABLE IF NOT EXISTS gallery_images_fts USING fts5(
+    alt, content='gallery_images', content_rowid='id', tokenize='porter unicode61'
+);
+CREATE VIRTUAL TABLE IF NOT EXISTS contact_messages_fts USING fts5(
+    name, subject, message, content='contact_messages', content_rowid='id', tokenize='porter unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS gallery_categories_fts_insert AFTER INSERT ON gallery_categories BEGIN
+    INSERT INTO gallery_categories_fts(rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
+CREATE TRIGGER IF NOT EXISTS gallery_categories_fts_delete AFTER DELETE ON gallery_categories BEGIN
+    INSERT INTO gallery_categories_fts(gallery_categories_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+END;
+CREATE TRIGGER IF NOT EXISTS gallery_categories_fts_update AFTER UPDATE OF title, description ON gallery_categories BEGIN
+    INSERT INTO gallery_categories_fts(gallery_categories_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+    INSERT INTO gallery_categories_fts(rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS gallery_images_fts_insert AFTER INSERT ON gallery_images BEGIN
+    INSERT INTO gallery_images_fts(rowid, alt) VALUES (new.id, new.alt);
+END;
+CREATE TRIGGER IF NOT EXISTS gallery_images_fts_delete AFTER DELETE ON gallery_images BEGIN
+    INSERT INTO gallery_images_fts(gallery_images_fts, rowid, alt) VALUES ('delete', old.id, old.alt);
+END;
+CREATE TRIGGER IF NOT EXISTS gallery_images_fts_update AFTER UPDATE OF alt ON gallery_images BEGIN
+    INSERT INTO gallery_images_fts(gallery_images_fts, rowid, alt) VALUES ('delete', old.id, old.alt);
+    INSERT INTO gallery_images_fts(rowid, alt) VALUES (new.id, new.alt);
+END;
+
+CREATE TRIGGER IF NOT EXISTS contact_messages_fts_insert AFTER INSERT ON contact_messages BEGIN
+    INSERT INTO contact_messages_fts(rowid, name, subject, message) VALUES (new.id, new.name, new.subject, new.message);
+END;
+CREATE TRIGGER IF NOT EXISTS contact_messages_fts_delete AFTER DELETE ON contact_messages BEGIN
+    INSERT INTO contact_messages_fts(contact_messages_fts, rowid, name, subject, message) VALUES ('delete', old.id, old.name, old.subject, old.message);
+END;
+CREATE TRIGGER IF NOT EXISTS contact_messages_fts_update AFTER UPDATE OF name, subject, message ON contact_messages BEGIN
+    INSERT INTO contact_messages_fts(contact_messages_fts, rowid, name, subject, message) VALUES ('delete', old.id, old.name, old.subject, old.message);
+    INSERT INTO contact_messages_fts(rowid, name, subject, message) VALUES (new.id, new.name, new.subject, new.message);
+END;
+
+INSERT INTO gallery_categories_fts(gallery_categories_fts) VALUES ('rebuild');
+INSERT INTO gallery_images_fts(gallery_images_fts) VALUES ('rebuild');
+INSERT INTO contact_messages_fts(contact_messages_fts) VALUES ('rebuild');
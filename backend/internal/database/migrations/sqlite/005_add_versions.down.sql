@@ -0,0 +1,3 @@
+ALTER TABLE gallery_images DROP COLUMN updated_at;
+ALTER TABLE gallery_images DROP COLUMN version;
+ALTER TABLE gallery_categories DROP COLUMN version;
@@ -0,0 +1,7 @@
+-- Version counters for optimistic concurrency, see 008_add_versions in the Postgres set.
+-- SQLite cannot add a column defaulting to CURRENT_TIMESTAMP, so inserts set updated_at.
+ALTER TABLE gallery_categories ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE gallery_images ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE gallery_images ADD COLUMN updated_at TIMESTAMP;
+
+UPDATE gallery_images SET updated_at = created_at;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_gallery_categories_status;
+
+ALTER TABLE gallery_categories DROP COLUMN unpublish_at;
+ALTER TABLE gallery_categories DROP COLUMN publish_at;
+ALTER TABLE gallery_categories DROP COLUMN status;
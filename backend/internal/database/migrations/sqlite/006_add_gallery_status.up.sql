@@ -0,0 +1,7 @@
+-- Publishing workflow for galleries, see 009_add_gallery_status in the Postgres set
+ALTER TABLE gallery_categories ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'published', 'archived'));
+ALTER TABLE gallery_categories ADD COLUMN publish_at TIMESTAMP;
+ALTER TABLE gallery_categories ADD COLUMN unpublish_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_gallery_categories_status ON gallery_categories(status);
@@ -0,0 +1,13 @@
+-- Gallery preview links, see 010_add_preview_links in the Postgres set
+CREATE TABLE IF NOT EXISTS preview_links (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    category_id INTEGER NOT NULL REFERENCES gallery_categories(id) ON DELETE CASCADE,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_by INTEGER,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_preview_links_category ON preview_links(category_id);
+CREATE INDEX IF NOT EXISTS idx_preview_links_expires ON preview_links(expires_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS category_tags;
+DROP TABLE IF EXISTS image_tags;
+DROP TABLE IF EXISTS tags;
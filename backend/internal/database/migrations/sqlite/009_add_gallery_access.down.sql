@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS gallery_access_log;
+
+ALTER TABLE gallery_categories DROP COLUMN password_hash;
+ALTER TABLE gallery_categories DROP COLUMN access;
@@ -0,0 +1,15 @@
+-- Gallery access modes and access log, see 012_add_gallery_access in the Postgres set
+ALTER TABLE gallery_categories ADD COLUMN access VARCHAR(20) NOT NULL DEFAULT 'public'
+    CHECK (access IN ('public', 'unlisted', 'password'));
+ALTER TABLE gallery_categories ADD COLUMN password_hash VARCHAR(255);
+
+CREATE TABLE IF NOT EXISTS gallery_access_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    category_id INTEGER NOT NULL REFERENCES gallery_categories(id) ON DELETE CASCADE,
+    event VARCHAR(20) NOT NULL,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_gallery_access_log_category ON gallery_access_log(category_id, created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS proofing_marks;
+DROP TABLE IF EXISTS proofing_clients;
+DROP TABLE IF EXISTS proofing_galleries;
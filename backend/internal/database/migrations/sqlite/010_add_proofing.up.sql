@@ -0,0 +1,29 @@
+-- Client proofing, see 013_add_proofing in the Postgres set
+CREATE TABLE IF NOT EXISTS proofing_galleries (
+    category_id INTEGER PRIMARY KEY REFERENCES gallery_categories(id) ON DELETE CASCADE,
+    max_picks INTEGER NOT NULL DEFAULT 0 CHECK (max_picks >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS proofing_clients (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    category_id INTEGER NOT NULL REFERENCES gallery_categories(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    submitted_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS proofing_marks (
+    client_id INTEGER NOT NULL REFERENCES proofing_clients(id) ON DELETE CASCADE,
+    image_id INTEGER NOT NULL REFERENCES gallery_images(id) ON DELETE CASCADE,
+    favorite BOOLEAN NOT NULL DEFAULT FALSE,
+    comment TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (client_id, image_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_proofing_clients_category ON proofing_clients(category_id);
+CREATE INDEX IF NOT EXISTS idx_proofing_marks_image ON proofing_marks(image_id);
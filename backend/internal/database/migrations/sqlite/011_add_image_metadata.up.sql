@@ -0,0 +1,2 @@
+-- Image camera metadata, see 014_add_image_metadata in the Postgres set
+ALTER TABLE gallery_images ADD COLUMN metadata JSON;
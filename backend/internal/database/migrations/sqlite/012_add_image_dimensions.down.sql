@@ -0,0 +1,2 @@
+ALTER TABLE gallery_images DROP COLUMN height;
+ALTER TABLE gallery_images DROP COLUMN width;
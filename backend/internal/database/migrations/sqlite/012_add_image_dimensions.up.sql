@@ -0,0 +1,3 @@
+-- Image dimensions, see 015_add_image_dimensions in the Postgres set
+ALTER TABLE gallery_images ADD COLUMN width INTEGER NOT NULL DEFAULT 0 CHECK (width >= 0);
+ALTER TABLE gallery_images ADD COLUMN height INTEGER NOT NULL DEFAULT 0 CHECK (height >= 0);
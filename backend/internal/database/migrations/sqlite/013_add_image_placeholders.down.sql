@@ -0,0 +1,3 @@
+ALTER TABLE gallery_images DROP COLUMN dominant_color;
+ALTER TABLE gallery_images DROP COLUMN lqip;
+ALTER TABLE gallery_images DROP COLUMN blurhash;
@@ -0,0 +1,4 @@
+-- Image placeholders, see 016_add_image_placeholders in the Postgres set
+ALTER TABLE gallery_images ADD COLUMN blurhash VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE gallery_images ADD COLUMN lqip TEXT NOT NULL DEFAULT '';
+ALTER TABLE gallery_images ADD COLUMN dominant_color VARCHAR(7) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE gallery_images DROP COLUMN variants;
@@ -0,0 +1,2 @@
+-- Image variants, see 017_add_image_variants in the Postgres set
+ALTER TABLE gallery_images ADD COLUMN variants JSON;
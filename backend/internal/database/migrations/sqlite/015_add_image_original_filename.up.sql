@@ -0,0 +1,2 @@
+-- Original upload names, see 018_add_image_original_filename in the Postgres set
+ALTER TABLE gallery_images ADD COLUMN original_filename VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,69 @@
+// backend/internal/database/notify.go
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Listener reconnect backoff and keepalive interval
+const (
+	listenMinReconnect = 1 * time.Second
+	listenMaxReconnect = 1 * time.Minute
+	listenPingInterval = 90 * time.Second
+)
+
+// Notify publishes payload on a Postgres NOTIFY channel.
+// SQLite runs in a single process, so there is nobody to notify.
+func (db *DB) Notify(ctx context.Context, channel, payload string) error {
+	if db.IsSQLite() {
+		return nil
+	}
+	_, err := db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, channel, payload)
+	return err
+}
+
+// Listen subscribes to a NOTIFY channel on its own connection and calls
+// handle for every notification until ctx is done. Dropped connections are
+// re-established with backoff; reconnected is called afterwards because
+// notifications sent in between are lost.
+func Listen(ctx context.Context, databaseURL, channel string, handle func(payload string), reconnected func()) error {
+	listener := pq.NewListener(databaseURL, listenMinReconnect, listenMaxReconnect, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			log.Printf("⚠️  LISTEN %s disconnected: %v", channel, err)
+		case pq.ListenerEventConnectionAttemptFailed:
+			log.Printf("⚠️  LISTEN %s reconnect failed: %v", channel, err)
+		case pq.ListenerEventReconnected:
+			log.Printf("✅ LISTEN %s reconnected", channel)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(channel); err != nil {
+		return err
+	}
+
+	ping := time.NewTicker(listenPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			// pq sends nil after re-establishing the connection
+			if n == nil {
+				reconnected()
+				continue
+			}
+			handle(n.Extra)
+		case <-ping.C:
+			// Detects half-open connections the server side never closed
+			go listener.Ping()
+		}
+	}
+}
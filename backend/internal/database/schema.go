@@ -0,0 +1,229 @@
+// backend/internal/database/schema.go
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Column types as reported by information_schema.columns.data_type
+const (
+	TypeInteger   = "integer"
+	TypeBigInt    = "bigint"
+	TypeVarchar   = "character varying"
+	TypeText      = "text"
+	TypeBoolean   = "boolean"
+	TypeTimestamp = "timestamp without time zone"
+	TypeJSONB     = "jsonb"
+)
+
+// Column is a column a repository expects to exist
+type Column struct {
+	Name string
+	Type string
+}
+
+// Table is the set of columns a repository reads or writes in one table
+type Table struct {
+	Name    string
+	Columns []Column
+}
+
+// SchemaIssue kinds
+const (
+	IssueMissingTable  = "missing_table"
+	IssueMissingColumn = "missing_column"
+	IssueTypeMismatch  = "type_mismatch"
+)
+
+// SchemaIssue is a single difference between the expected and actual schema
+type SchemaIssue struct {
+	Kind     string
+	Table    string
+	Column   string
+	Expected string
+	Actual   string
+}
+
+// SchemaDiff lists every difference found by VerifySchema
+type SchemaDiff struct {
+	Issues []SchemaIssue
+}
+
+// OK reports whether the schema matched the manifest
+func (d *SchemaDiff) OK() bool {
+	return len(d.Issues) == 0
+}
+
+// Error implements error so a diff can be returned or logged directly
+func (d *SchemaDiff) Error() string {
+	return d.String()
+}
+
+// String renders the diff grouped by table
+func (d *SchemaDiff) String() string {
+	if d.OK() {
+		return "schema matches repository manifest"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "schema does not match repository manifest (%d problem(s))", len(d.Issues))
+
+	table := ""
+	for _, issue := range d.Issues {
+		if issue.Table != table {
+			table = issue.Table
+			fmt.Fprintf(&b, "\n  table %s:", table)
+		}
+
+		switch issue.Kind {
+		case IssueMissingTable:
+			b.WriteString("\n    - table is missing")
+		case IssueMissingColumn:
+			fmt.Fprintf(&b, "\n    - missing column %s (expected %s)", issue.Column, issue.Expected)
+		case IssueTypeMismatch:
+			fmt.Fprintf(&b, "\n    ~ column %s is %s, expected %s", issue.Column, issue.Actual, issue.Expected)
+		}
+	}
+
+	return b.String()
+}
+
+// VerifySchema compares the live schema with the tables the repositories expect
+func VerifySchema(ctx context.Context, db *DB, manifest []Table) (*SchemaDiff, error) {
+	query := `
+		SELECT table_name, column_name, data_type
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()
+	`
+	if db.IsSQLite() {
+		query = `
+			SELECT m.name, p.name, p.type
+			FROM sqlite_master m
+			JOIN pragma_table_info(m.name) p
+			WHERE m.type = 'table'
+		`
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect schema: %w", err)
+	}
+	defer rows.Close()
+
+	actual := make(map[string]map[string]string)
+	for rows.Next() {
+		var table, column, dataType string
+		if err := rows.Scan(&table, &column, &dataType); err != nil {
+			return nil, err
+		}
+		if db.IsSQLite() {
+			dataType = sqliteDataType(dataType)
+		}
+		if actual[table] == nil {
+			actual[table] = make(map[string]string)
+		}
+		actual[table][column] = dataType
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return diffSchema(mergeManifest(manifest), actual), nil
+}
+
+// sqliteDataType maps a SQLite declared column type onto the
+// information_schema name used in the manifest
+func sqliteDataType(declared string) string {
+	declared = strings.ToUpper(declared)
+	if i := strings.IndexByte(declared, '('); i >= 0 {
+		declared = declared[:i]
+	}
+
+	switch strings.TrimSpace(declared) {
+	case "INTEGER", "INT", "SERIAL":
+		return TypeInteger
+	case "BIGINT":
+		return TypeBigInt
+	case "VARCHAR", "CHARACTER VARYING":
+		return TypeVarchar
+	case "TEXT":
+		return TypeText
+	case "BOOLEAN", "BOOL":
+		return TypeBoolean
+	case "TIMESTAMP", "DATETIME":
+		return TypeTimestamp
+	case "JSON", "JSONB":
+		return TypeJSONB
+	}
+	return strings.ToLower(declared)
+}
+
+// mergeManifest combines tables declared by several repositories
+func mergeManifest(manifest []Table) []Table {
+	byName := make(map[string]map[string]string)
+	for _, table := range manifest {
+		if byName[table.Name] == nil {
+			byName[table.Name] = make(map[string]string)
+		}
+		for _, column := range table.Columns {
+			byName[table.Name][column.Name] = column.Type
+		}
+	}
+
+	merged := make([]Table, 0, len(byName))
+	for name, columns := range byName {
+		table := Table{Name: name}
+		for column, dataType := range columns {
+			table.Columns = append(table.Columns, Column{Name: column, Type: dataType})
+		}
+		sort.Slice(table.Columns, func(i, j int) bool {
+			return table.Columns[i].Name < table.Columns[j].Name
+		})
+		merged = append(merged, table)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Name < merged[j].Name
+	})
+
+	return merged
+}
+
+// diffSchema reports expected tables and columns absent from actual
+func diffSchema(expected []Table, actual map[string]map[string]string) *SchemaDiff {
+	diff := &SchemaDiff{}
+
+	for _, table := range expected {
+		columns, ok := actual[table.Name]
+		if !ok {
+			diff.Issues = append(diff.Issues, SchemaIssue{Kind: IssueMissingTable, Table: table.Name})
+			continue
+		}
+
+		for _, column := range table.Columns {
+			dataType, ok := columns[column.Name]
+			switch {
+			case !ok:
+				diff.Issues = append(diff.Issues, SchemaIssue{
+					Kind:     IssueMissingColumn,
+					Table:    table.Name,
+					Column:   column.Name,
+					Expected: column.Type,
+				})
+			case column.Type != "" && dataType != column.Type:
+				diff.Issues = append(diff.Issues, SchemaIssue{
+					Kind:     IssueTypeMismatch,
+					Table:    table.Name,
+					Column:   column.Name,
+					Expected: column.Type,
+					Actual:   dataType,
+				})
+			}
+		}
+	}
+
+	return diff
+}
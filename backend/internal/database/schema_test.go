@@ -0,0 +1,87 @@
+// backend/internal/database/schema_test.go
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeManifest(t *testing.T) {
+	got := mergeManifest([]Table{
+		{Name: "users", Columns: []Column{{"id", TypeInteger}, {"email", TypeVarchar}}},
+		{Name: "audit_log", Columns: []Column{{"id", TypeInteger}}},
+		{Name: "users", Columns: []Column{{"email", TypeVarchar}, {"last_login", TypeTimestamp}}},
+	})
+
+	want := []Table{
+		{Name: "audit_log", Columns: []Column{{"id", TypeInteger}}},
+		{Name: "users", Columns: []Column{{"email", TypeVarchar}, {"id", TypeInteger}, {"last_login", TypeTimestamp}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeManifest = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffSchema(t *testing.T) {
+	expected := []Table{
+		{Name: "gallery_images", Columns: []Column{
+			{"alt", ""}, // any type
+			{"id", TypeInteger},
+			{"metadata", TypeJSONB},
+			{"src", TypeText},
+			{"width", TypeInteger},
+		}},
+		{Name: "tags", Columns: []Column{{"id", TypeInteger}}},
+		{Name: "users", Columns: []Column{{"email", TypeVarchar}}},
+	}
+	actual := map[string]map[string]string{
+		"gallery_images": {"alt": TypeText, "id": TypeInteger, "metadata": TypeText, "src": TypeVarchar},
+		"users":          {"email": TypeVarchar, "username": TypeVarchar},
+	}
+
+	diff := diffSchema(expected, actual)
+	want := []SchemaIssue{
+		{Kind: IssueTypeMismatch, Table: "gallery_images", Column: "metadata", Expected: TypeJSONB, Actual: TypeText},
+		{Kind: IssueTypeMismatch, Table: "gallery_images", Column: "src", Expected: TypeText, Actual: TypeVarchar},
+		{Kind: IssueMissingColumn, Table: "gallery_images", Column: "width", Expected: TypeInteger},
+		{Kind: IssueMissingTable, Table: "tags"},
+	}
+	if !reflect.DeepEqual(diff.Issues, want) {
+		t.Fatalf("diffSchema issues = %+v, want %+v", diff.Issues, want)
+	}
+	if diff.OK() {
+		t.Error("OK() = true with issues")
+	}
+	for _, line := range []string{
+		"(4 problem(s))",
+		"table gallery_images:",
+		"~ column metadata is text, expected jsonb",
+		"- missing column width (expected integer)",
+		"table tags:\n    - table is missing",
+	} {
+		if !strings.Contains(diff.String(), line) {
+			t.Errorf("diff.String() = %q, missing %q", diff.String(), line)
+		}
+	}
+
+	if diff := diffSchema(expected[2:], actual); !diff.OK() {
+		t.Errorf("diffSchema of a matching table = %+v, want no issues", diff.Issues)
+	}
+}
+
+func TestSQLiteDataType(t *testing.T) {
+	for declared, want := range map[string]string{
+		"INTEGER":      TypeInteger,
+		"varchar(255)": TypeVarchar,
+		"TEXT":         TypeText,
+		"BOOLEAN":      TypeBoolean,
+		"DATETIME":     TypeTimestamp,
+		"JSON":         TypeJSONB,
+		"REAL":         "real",
+	} {
+		if got := sqliteDataType(declared); got != want {
+			t.Errorf("sqliteDataType(%q) = %q, want %q", declared, got, want)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+// backend/internal/database/sqlite.go
+
+//go:build sqlite
+
+package database
+
+// Pure-Go SQLite driver, registered as "sqlite". Only linked into binaries
+// built with -tags sqlite so production builds stay Postgres-only.
+import _ "modernc.org/sqlite"
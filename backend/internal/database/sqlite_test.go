@@ -0,0 +1,207 @@
+// backend/internal/database/sqlite_test.go
+
+//go:build sqlite
+
+package database_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// openEmptySQLite returns a SQLite database in a temporary file with no tables
+func openEmptySQLite(t *testing.T) (*database.DB, *database.Migrator) {
+	t.Helper()
+	db, err := database.Connect("sqlite://" + filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	return db, migrator
+}
+
+// appliedVersions returns the versions in schema_migrations, oldest first
+func appliedVersions(t *testing.T, m *database.Migrator) []int {
+	t.Helper()
+	var versions []int
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, s := range statuses {
+		if s.Applied {
+			versions = append(versions, s.Version)
+		}
+	}
+	return versions
+}
+
+// tables returns how many tables the migrations left, not counting
+// schema_migrations and SQLite's own
+func tables(t *testing.T, db *database.DB) int {
+	t.Helper()
+	var n int
+	err := db.QueryRow(`
+		SELECT COUNT(*) FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name <> 'schema_migrations'
+	`).Scan(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestMigratorRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db, m := openEmptySQLite(t)
+	all := m.Migrations()
+	last := len(all) - 1
+
+	if n, err := m.UpTo(ctx, all[2].Version); err != nil || n != 3 {
+		t.Fatalf("UpTo(%d) = %d, %v, want 3 applied", all[2].Version, n, err)
+	}
+	if got := appliedVersions(t, m); len(got) != 3 || got[2] != all[2].Version {
+		t.Fatalf("applied after UpTo = %v, want the first 3", got)
+	}
+	if n, err := m.Up(ctx); err != nil || n != len(all)-3 {
+		t.Fatalf("Up = %d, %v, want the other %d applied", n, err, len(all)-3)
+	}
+	if n, err := m.Up(ctx); err != nil || n != 0 {
+		t.Fatalf("second Up = %d, %v, want nothing to do", n, err)
+	}
+
+	rolledBack, err := m.Down(ctx, 2)
+	if err != nil {
+		t.Fatalf("Down(2): %v", err)
+	}
+	if len(rolledBack) != 2 || rolledBack[0].Version != all[last].Version || rolledBack[1].Version != all[last-1].Version {
+		t.Fatalf("Down(2) rolled back %+v, want the newest two, newest first", rolledBack)
+	}
+	if got := appliedVersions(t, m); len(got) != len(all)-2 {
+		t.Fatalf("applied after Down(2) = %v", got)
+	}
+
+	redone, err := m.Redo(ctx)
+	if err != nil || redone.Version != all[last-2].Version {
+		t.Fatalf("Redo = %+v, %v, want %d redone", redone, err, all[last-2].Version)
+	}
+	if got := appliedVersions(t, m); len(got) != len(all)-2 {
+		t.Fatalf("applied after Redo = %v, want unchanged", got)
+	}
+
+	// Every down file reverts its up file, so nothing is left
+	if rolledBack, err := m.Down(ctx, len(all)); err != nil || len(rolledBack) != len(all)-2 {
+		t.Fatalf("Down(all) = %d rolled back, %v", len(rolledBack), err)
+	}
+	if n := tables(t, db); n != 0 {
+		t.Errorf("%d tables left after rolling everything back", n)
+	}
+	if _, err := m.Redo(ctx); err == nil {
+		t.Error("Redo with nothing applied succeeded")
+	}
+
+	if n, err := m.Up(ctx); err != nil || n != len(all) {
+		t.Fatalf("Up after rolling back = %d, %v, want all applied again", n, err)
+	}
+}
+
+func TestMigratorRefusesChangedMigrations(t *testing.T) {
+	ctx := context.Background()
+	db, m := openEmptySQLite(t)
+	all := m.Migrations()
+	if _, err := m.UpTo(ctx, all[1].Version); err != nil {
+		t.Fatal(err)
+	}
+
+	// The applied file has since been edited
+	if _, err := db.Exec(`UPDATE schema_migrations SET checksum = 'edited' WHERE version = $1`, all[1].Version); err != nil {
+		t.Fatal(err)
+	}
+	run := map[string]func() error{
+		"Verify": func() error { return m.Verify(ctx) },
+		"Up":     func() error { _, err := m.Up(ctx); return err },
+		"Down":   func() error { _, err := m.Down(ctx, 1); return err },
+		"Redo":   func() error { _, err := m.Redo(ctx); return err },
+	}
+	for name, fn := range run {
+		if err := fn(); !errors.Is(err, database.ErrMigrationModified) {
+			t.Errorf("%s = %v, want ErrMigrationModified", name, err)
+		}
+	}
+	if got := appliedVersions(t, m); len(got) != 2 {
+		t.Errorf("applied = %v, want nothing changed", got)
+	}
+	if statuses, _ := m.Status(ctx); !statuses[1].Modified || statuses[0].Modified {
+		t.Errorf("Status = %+v, want only the second modified", statuses[:2])
+	}
+
+	// A version applied from a file that no longer exists
+	if _, err := db.Exec(`UPDATE schema_migrations SET checksum = $1 WHERE version = $2`, all[1].Checksum, all[1].Version); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO schema_migrations (version, name, checksum) VALUES (999, 'removed', 'gone')`); err != nil {
+		t.Fatal(err)
+	}
+	for name, fn := range run {
+		if err := fn(); !errors.Is(err, database.ErrMigrationMissing) {
+			t.Errorf("%s = %v, want ErrMigrationMissing", name, err)
+		}
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := statuses[len(statuses)-1]; s.Version != 999 || !s.Missing {
+		t.Errorf("last status = %+v, want 999 missing", s)
+	}
+}
+
+func TestVerifySchemaSQLite(t *testing.T) {
+	ctx := context.Background()
+	db, _ := openEmptySQLite(t)
+	if err := database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := database.VerifySchema(ctx, db, repository.Manifest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.OK() {
+		t.Fatalf("migrated schema does not match the repositories:\n%s", diff)
+	}
+
+	if _, err := db.Exec(`ALTER TABLE contact_messages DROP COLUMN phone`); err != nil {
+		t.Fatal(err)
+	}
+	manifest := append(repository.Manifest(), database.Table{
+		Name:    "users",
+		Columns: []database.Column{{Name: "email", Type: database.TypeInteger}},
+	})
+	diff, err = database.VerifySchema(ctx, db, manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []database.SchemaIssue{
+		{Kind: database.IssueMissingColumn, Table: "contact_messages", Column: "phone", Expected: database.TypeVarchar},
+		{Kind: database.IssueTypeMismatch, Table: "users", Column: "email", Expected: database.TypeInteger, Actual: database.TypeVarchar},
+	}
+	if len(diff.Issues) != len(want) {
+		t.Fatalf("VerifySchema issues = %+v, want %+v", diff.Issues, want)
+	}
+	for i := range want {
+		if diff.Issues[i] != want[i] {
+			t.Errorf("issue %d = %+v, want %+v", i, diff.Issues[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// backend/internal/database/timeout.go
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrTimeout is returned when a repository operation exceeds its deadline
+var ErrTimeout = errors.New("database operation timed out")
+
+// DefaultQueryTimeout is used when no timeout has been configured
+const DefaultQueryTimeout = 5 * time.Second
+
+// Timeouts holds the deadlines applied to repository operations
+type Timeouts struct {
+	Default    time.Duration
+	Operations map[string]time.Duration
+}
+
+// For returns the deadline for an operation, falling back to the default.
+// A zero or negative duration disables the deadline.
+func (t Timeouts) For(op string) time.Duration {
+	if d, ok := t.Operations[op]; ok {
+		return d
+	}
+	return t.Default
+}
+
+// Operations are the names repositories pass to WithTimeout, sorted.
+// Keep it in step with the repositories; their tests check both ways.
+var Operations = []string{
+	"access_log.list", "access_log.record",
+	"audit.list", "audit.record",
+	"contact.create", "contact.delete", "contact.get_all", "contact.get_by_id", "contact.search",
+	"contact.update_status",
+	"gallery.category_password_hash", "gallery.copy_images", "gallery.create_category",
+	"gallery.create_image", "gallery.delete_category", "gallery.delete_image",
+	"gallery.get_all_categories", "gallery.get_category_by_id", "gallery.get_category_by_slug",
+	"gallery.get_image_by_id", "gallery.get_images_by_category", "gallery.list_trash",
+	"gallery.move_images", "gallery.purge_category", "gallery.purge_image", "gallery.purge_trash",
+	"gallery.reorder_categories", "gallery.reorder_images", "gallery.restore_category",
+	"gallery.restore_image", "gallery.schedule_transitions", "gallery.search",
+	"gallery.update_category", "gallery.update_category_access", "gallery.update_category_status",
+	"gallery.update_image",
+	"portfolio_section.get_all", "portfolio_section.update_image",
+	"preview.create", "preview.get", "preview.list", "preview.revoke",
+	"proofing.client_create", "proofing.client_get", "proofing.client_list", "proofing.client_revoke",
+	"proofing.disable", "proofing.enable", "proofing.mark", "proofing.marks", "proofing.reopen",
+	"proofing.settings", "proofing.submit",
+	"tag.categories", "tag.category_tags", "tag.create", "tag.delete", "tag.get", "tag.image_tags",
+	"tag.images", "tag.list", "tag.merge", "tag.public", "tag.set_category_tags",
+	"tag.set_image_tags", "tag.update",
+	"user.create", "user.email_exists", "user.get_by_email", "user.get_by_id",
+	"user.update_last_login",
+}
+
+// ParseOperationTimeouts parses a list like "gallery.get_all_categories=2s,contact.create=500ms".
+// Unknown operation names are rejected, so a typo does not silently keep the default.
+func ParseOperationTimeouts(spec string) (map[string]time.Duration, error) {
+	operations := make(map[string]time.Duration)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		op, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid operation timeout %q, expected op=duration", entry)
+		}
+
+		op = strings.TrimSpace(op)
+		if _, found := slices.BinarySearch(Operations, op); !found {
+			return nil, fmt.Errorf("unknown operation %q in timeout %q", op, entry)
+		}
+
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout for %s: %w", op, err)
+		}
+		operations[op] = d
+	}
+
+	return operations, nil
+}
+
+// SetTimeouts configures the deadlines used by WithTimeout
+func (db *DB) SetTimeouts(timeouts Timeouts) {
+	db.timeouts = timeouts
+}
+
+// WithTimeout bounds ctx by the deadline configured for op. The returned
+// finish func releases the context and turns errors caused by the deadline
+// into ErrTimeout, so callers can defer it over their named error result.
+func (db *DB) WithTimeout(ctx context.Context, op string) (context.Context, func(error) error) {
+	d := db.timeouts.For(op)
+	if d <= 0 {
+		return ctx, func(err error) error { return err }
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d)
+	return ctx, func(err error) error {
+		defer cancel()
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%s exceeded %s: %w", op, d, errors.Join(ErrTimeout, err))
+		}
+		return err
+	}
+}
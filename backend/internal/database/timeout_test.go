@@ -0,0 +1,45 @@
+// backend/internal/database/timeout_test.go
+package database_test
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+)
+
+func TestParseOperationTimeouts(t *testing.T) {
+	got, err := database.ParseOperationTimeouts(" gallery.get_all_categories = 2s, contact.create=500ms,, ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]time.Duration{"gallery.get_all_categories": 2 * time.Second, "contact.create": 500 * time.Millisecond}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseOperationTimeouts = %v, want %v", got, want)
+	}
+
+	for spec, msg := range map[string]string{
+		"gallery.list_categories=2s":    `unknown operation "gallery.list_categories"`,
+		"gallery.get_all_categories":    "expected op=duration",
+		"contact.create=soon":           "invalid timeout for contact.create",
+		"contact.create=1s,nope=1s":     `unknown operation "nope"`,
+		"=1s":                           `unknown operation ""`,
+		"Gallery.Get_All_Categories=1s": "unknown operation",
+	} {
+		if _, err := database.ParseOperationTimeouts(spec); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("ParseOperationTimeouts(%q) = %v, want %q", spec, err, msg)
+		}
+	}
+}
+
+func TestOperationsSorted(t *testing.T) {
+	if !slices.IsSorted(database.Operations) {
+		t.Error("Operations is not sorted, ParseOperationTimeouts searches it")
+	}
+	if len(slices.Compact(slices.Clone(database.Operations))) != len(database.Operations) {
+		t.Error("Operations has duplicates")
+	}
+}
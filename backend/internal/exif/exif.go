@@ -0,0 +1,243 @@
+// backend/internal/exif/exif.go
+
+// Package exif reads camera metadata from the EXIF and XMP blocks of JPEG,
+// PNG and WebP files. Location tags are deliberately left alone, so
+// uploads never publish where a photo was taken.
+package exif
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// ErrMalformed is returned for metadata blocks that cannot be parsed
+var ErrMalformed = errors.New("malformed image metadata")
+
+// maxBlock caps the size of a metadata block read into memory
+const maxBlock = 1 << 20
+
+var (
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	exifHeader   = []byte("Exif\x00\x00")
+	xmpHeader    = []byte("http://ns.adobe.com/xap/1.0/\x00")
+)
+
+// blocks holds the first EXIF (a TIFF structure) and XMP packet of a file
+type blocks struct {
+	exif []byte
+	xmp  []byte
+}
+
+// Decode reads the metadata of the image in r. It returns nil without an
+// error when the image carries none or is not a JPEG, PNG or WebP file.
+// EXIF values win over XMP ones; XMP fills in what EXIF lacks.
+func Decode(r io.Reader) (*models.ImageMetadata, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(12)
+
+	var found blocks
+	var err error
+	switch {
+	case bytes.HasPrefix(magic, []byte{0xFF, 0xD8}):
+		err = readJPEG(br, &found)
+	case bytes.HasPrefix(magic, pngSignature):
+		err = readPNG(br, &found)
+	case len(magic) == 12 && string(magic[:4]) == "RIFF" && string(magic[8:]) == "WEBP":
+		err = readWebP(br, &found)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &models.ImageMetadata{}
+	if found.exif != nil {
+		if err := parseTIFF(found.exif, meta); err != nil {
+			return nil, err
+		}
+	}
+	if found.xmp != nil {
+		parseXMP(found.xmp, meta)
+	}
+	if meta.IsEmpty() {
+		return nil, nil
+	}
+	return meta, nil
+}
+
+// readJPEG collects the APP1 segments ahead of the image data
+func readJPEG(r *bufio.Reader, found *blocks) error {
+	if _, err := r.Discard(2); err != nil {
+		return err
+	}
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return endOfFile(err)
+		}
+		if b != 0xFF {
+			return ErrMalformed
+		}
+		// Markers may be padded with any number of 0xFF fill bytes
+		marker := byte(0xFF)
+		for marker == 0xFF {
+			if marker, err = r.ReadByte(); err != nil {
+				return endOfFile(err)
+			}
+		}
+
+		switch {
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD8):
+			continue // no length
+		case marker == 0xDA || marker == 0xD9:
+			return nil // scan data or end of image, metadata always comes first
+		}
+
+		var length uint16
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+			return endOfFile(err)
+		}
+		if length < 2 {
+			return ErrMalformed
+		}
+		n := int(length) - 2
+		if marker != 0xE1 {
+			if _, err := r.Discard(n); err != nil {
+				return endOfFile(err)
+			}
+			continue
+		}
+
+		data := make([]byte, n)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return endOfFile(err)
+		}
+		switch {
+		case bytes.HasPrefix(data, exifHeader) && found.exif == nil:
+			found.exif = data[len(exifHeader):]
+		case bytes.HasPrefix(data, xmpHeader) && found.xmp == nil:
+			found.xmp = data[len(xmpHeader):]
+		}
+	}
+}
+
+// readPNG collects the eXIf chunk and the XMP iTXt chunk
+func readPNG(r *bufio.Reader, found *blocks) error {
+	if _, err := r.Discard(len(pngSignature)); err != nil {
+		return err
+	}
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return endOfFile(err)
+		}
+		length := binary.BigEndian.Uint32(header[:4])
+		kind := string(header[4:])
+		if kind == "IEND" {
+			return nil
+		}
+
+		wanted := (kind == "eXIf" && found.exif == nil) || (kind == "iTXt" && found.xmp == nil)
+		if !wanted || length > maxBlock {
+			// Skip the data and its CRC
+			if _, err := r.Discard(int(length) + 4); err != nil {
+				return endOfFile(err)
+			}
+			continue
+		}
+
+		data := make([]byte, length+4)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return endOfFile(err)
+		}
+		data = data[:length]
+		if kind == "eXIf" {
+			found.exif = data
+		} else {
+			found.xmp = pngXMP(data)
+		}
+	}
+}
+
+// pngXMP returns the text of an iTXt chunk holding an XMP packet, or nil
+func pngXMP(data []byte) []byte {
+	keyword, rest, ok := bytes.Cut(data, []byte{0})
+	if !ok || string(keyword) != "XML:com.adobe.xmp" || len(rest) < 2 {
+		return nil
+	}
+	compressed := rest[0] == 1
+	// Skip the compression flag and method, the language tag and the translated keyword
+	rest = rest[2:]
+	for i := 0; i < 2; i++ {
+		if _, rest, ok = bytes.Cut(rest, []byte{0}); !ok {
+			return nil
+		}
+	}
+	if !compressed {
+		return rest
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(rest))
+	if err != nil {
+		return nil
+	}
+	defer zr.Close()
+	text, err := io.ReadAll(io.LimitReader(zr, maxBlock))
+	if err != nil {
+		return nil
+	}
+	return text
+}
+
+// readWebP collects the EXIF and XMP chunks of a RIFF container
+func readWebP(r *bufio.Reader, found *blocks) error {
+	if _, err := r.Discard(12); err != nil {
+		return err
+	}
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return endOfFile(err)
+		}
+		kind := string(header[:4])
+		length := binary.LittleEndian.Uint32(header[4:])
+		// Chunks are padded to an even size
+		padded := int(length) + int(length&1)
+
+		wanted := (kind == "EXIF" && found.exif == nil) || (kind == "XMP " && found.xmp == nil)
+		if !wanted || length > maxBlock {
+			if _, err := r.Discard(padded); err != nil {
+				return endOfFile(err)
+			}
+			continue
+		}
+
+		data := make([]byte, padded)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return endOfFile(err)
+		}
+		data = data[:length]
+		if kind == "EXIF" {
+			// Some writers keep the JPEG APP1 header
+			found.exif = bytes.TrimPrefix(data, exifHeader)
+		} else {
+			found.xmp = data
+		}
+	}
+}
+
+// endOfFile treats running out of data as the end of the metadata, so
+// truncated files still give what was read before the cut
+func endOfFile(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil
+	}
+	return err
+}
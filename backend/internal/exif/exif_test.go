@@ -0,0 +1,203 @@
+// backend/internal/exif/exif_test.go
+package exif_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/exif"
+	"github.com/supraik/Freelance-Portfolio/internal/exif/exiftest"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// cameraBlock returns an EXIF block with every tag Decode reads
+func cameraBlock(order binary.ByteOrder) []byte {
+	b := &exiftest.Block{Order: order}
+	b.IFD0 = []exiftest.Entry{
+		b.ASCII(exiftest.TagMake, "Canon"),
+		b.ASCII(exiftest.TagModel, "Canon EOS R5"),
+		b.Short(exiftest.TagOrientation, 6),
+		b.ASCII(exiftest.TagDateTime, "2024:06:01 09:00:00"),
+	}
+	b.Exif = []exiftest.Entry{
+		b.Rational(exiftest.TagExposureTime, 1, 250),
+		b.Rational(exiftest.TagFNumber, 28, 10),
+		b.Short(exiftest.TagISO, 400),
+		b.ASCII(exiftest.TagDateTimeOriginal, "2024:05:01 10:30:00"),
+		b.ASCII(exiftest.TagOffsetOriginal, "+05:30"),
+		b.Rational(exiftest.TagFocalLength, 50, 1),
+		b.Short(exiftest.TagFocalLength35, 75),
+		b.ASCII(exiftest.TagLensModel, "RF50mm F1.2 L USM"),
+	}
+	return b.Bytes()
+}
+
+func cameraMetadata() *models.ImageMetadata {
+	captured := time.Date(2024, 5, 1, 10, 30, 0, 0, time.FixedZone("", 5*3600+1800))
+	return &models.ImageMetadata{
+		CameraMake:    "Canon",
+		CameraModel:   "Canon EOS R5",
+		Lens:          "RF50mm F1.2 L USM",
+		FocalLength:   50,
+		FocalLength35: 75,
+		Aperture:      2.8,
+		ExposureTime:  "1/250",
+		ISO:           400,
+		CapturedAt:    &captured,
+		Orientation:   6,
+	}
+}
+
+func decode(t *testing.T, file []byte) (*models.ImageMetadata, error) {
+	t.Helper()
+	return exif.Decode(bytes.NewReader(file))
+}
+
+func assertMetadata(t *testing.T, got, want *models.ImageMetadata) {
+	t.Helper()
+	if got != nil && want != nil && got.CapturedAt != nil && want.CapturedAt != nil {
+		if !got.CapturedAt.Equal(*want.CapturedAt) {
+			t.Errorf("CapturedAt = %v, want %v", got.CapturedAt, want.CapturedAt)
+		}
+		g, w := *got, *want
+		g.CapturedAt, w.CapturedAt = nil, nil
+		got, want = &g, &w
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("metadata = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeJPEG(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 8, 8))
+	for name, order := range map[string]binary.ByteOrder{"little endian": binary.LittleEndian, "big endian": binary.BigEndian} {
+		t.Run(name, func(t *testing.T) {
+			meta, err := decode(t, exiftest.JPEG(t, img, cameraBlock(order)))
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			assertMetadata(t, meta, cameraMetadata())
+		})
+	}
+}
+
+func TestDecodePNGAndWebP(t *testing.T) {
+	block := cameraBlock(binary.LittleEndian)
+
+	png := []byte("\x89PNG\r\n\x1a\n")
+	png = binary.BigEndian.AppendUint32(png, uint32(len(block)))
+	png = append(append(append(png, "eXIf"...), block...), 0, 0, 0, 0) // CRC is not checked
+	png = append(png, 0, 0, 0, 0)
+	png = append(png, "IEND"...)
+
+	chunk := append([]byte("Exif\x00\x00"), block...)
+	webp := []byte("RIFF\x00\x00\x00\x00WEBPEXIF")
+	webp = binary.LittleEndian.AppendUint32(webp, uint32(len(chunk)))
+	webp = append(webp, chunk...)
+
+	for name, file := range map[string][]byte{"png": png, "webp": webp} {
+		meta, err := decode(t, file)
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", name, err)
+		}
+		assertMetadata(t, meta, cameraMetadata())
+	}
+}
+
+func TestDecodeXMPFillsIn(t *testing.T) {
+	b := &exiftest.Block{Order: binary.LittleEndian}
+	b.IFD0 = []exiftest.Entry{b.ASCII(exiftest.TagMake, "Nikon")}
+	xmp := []byte(`http://ns.adobe.com/xap/1.0/` + "\x00" +
+		`<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">` +
+		`<rdf:Description xmlns:tiff="http://ns.adobe.com/tiff/1.0/" xmlns:exif="http://ns.adobe.com/exif/1.0/" tiff:Make="Sony" exif:FNumber="14/10">` +
+		`<exif:ISOSpeedRatings><rdf:Seq><rdf:li>800</rdf:li></rdf:Seq></exif:ISOSpeedRatings>` +
+		`</rdf:Description></rdf:RDF></x:xmpmeta>`)
+	segment := append([]byte{0xFF, 0xE1, byte((len(xmp) + 2) >> 8), byte(len(xmp) + 2)}, xmp...)
+
+	file := exiftest.JPEG(t, image.NewGray(image.Rect(0, 0, 8, 8)), b.Bytes())
+	file = append(append(append([]byte(nil), file[:2]...), segment...), file[2:]...)
+
+	meta, err := decode(t, file)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	assertMetadata(t, meta, &models.ImageMetadata{CameraMake: "Nikon", Aperture: 1.4, ISO: 800})
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	valid := func() []byte { return cameraBlock(binary.LittleEndian) }
+	le := binary.LittleEndian
+
+	tests := []struct {
+		name  string
+		block func() []byte
+	}{
+		{"too short", func() []byte { return []byte("II*\x00") }},
+		{"unknown byte order", func() []byte { b := valid(); copy(b, "XX"); return b }},
+		{"not TIFF", func() []byte { b := valid(); le.PutUint16(b[2:], 43); return b }},
+		{"IFD0 offset past the end", func() []byte { b := valid(); le.PutUint32(b[4:], 0xFFFFFFF0); return b }},
+		{"IFD0 offset at the last byte", func() []byte { b := valid(); le.PutUint32(b[4:], uint32(len(b)-1)); return b }},
+		{"IFD0 entries past the end", func() []byte { b := valid(); le.PutUint16(b[8:], 0xFFFF); return b }},
+		{"Exif IFD offset past the end", func() []byte {
+			b := &exiftest.Block{Order: le}
+			b.IFD0 = []exiftest.Entry{b.Long(exiftest.TagExifIFD, 0x7FFFFFFF)}
+			return b.Bytes()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, err := decode(t, exiftest.JPEG(t, image.NewGray(image.Rect(0, 0, 8, 8)), tt.block()))
+			if !errors.Is(err, exif.ErrMalformed) {
+				t.Errorf("Decode = %+v, %v, want ErrMalformed", meta, err)
+			}
+		})
+	}
+}
+
+func TestDecodeSkipsValuesOutsideTheBlock(t *testing.T) {
+	b := &exiftest.Block{Order: binary.BigEndian}
+	tooLong := b.ASCII(exiftest.TagMake, "Canon")
+	tooLong.Count = 0x7FFFFFFF // points far past the block
+	b.IFD0 = []exiftest.Entry{
+		tooLong,
+		b.ASCII(exiftest.TagModel, "EOS"),
+		{Tag: exiftest.TagOrientation, Type: 99, Count: 1}, // unknown type
+	}
+
+	meta, err := decode(t, exiftest.JPEG(t, image.NewGray(image.Rect(0, 0, 8, 8)), b.Bytes()))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	assertMetadata(t, meta, &models.ImageMetadata{CameraModel: "EOS"})
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	file := exiftest.JPEG(t, image.NewGray(image.Rect(0, 0, 8, 8)), cameraBlock(binary.LittleEndian))
+	segmentEnd := 2 + 2 + int(binary.BigEndian.Uint16(file[4:]))
+
+	// Cut inside the EXIF segment: nothing was read, nothing is returned
+	for _, n := range []int{1, 3, 5, 20, segmentEnd - 1} {
+		if meta, err := decode(t, file[:n]); err != nil || meta != nil {
+			t.Errorf("cut at %d: Decode = %+v, %v, want nothing", n, meta, err)
+		}
+	}
+	// Cut after it: what came before the cut is kept
+	meta, err := decode(t, file[:segmentEnd])
+	if err != nil {
+		t.Fatalf("cut after the segment: %v", err)
+	}
+	assertMetadata(t, meta, cameraMetadata())
+}
+
+func TestDecodeNotAnImage(t *testing.T) {
+	for _, file := range [][]byte{nil, []byte("GIF89a"), []byte("plain text")} {
+		if meta, err := decode(t, file); err != nil || meta != nil {
+			t.Errorf("Decode(%q) = %+v, %v, want nothing", file, meta, err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// backend/internal/exif/exiftest/exiftest.go
+
+// Package exiftest builds EXIF blocks, and JPEG files carrying them, for
+// the tests of the packages that read image files
+package exiftest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// TIFF field types
+const (
+	TypeASCII    = 2
+	TypeShort    = 3
+	TypeLong     = 4
+	TypeRational = 5
+)
+
+// Tags
+const (
+	TagMake             = 0x010F
+	TagModel            = 0x0110
+	TagOrientation      = 0x0112
+	TagDateTime         = 0x0132
+	TagExifIFD          = 0x8769
+	TagExposureTime     = 0x829A
+	TagFNumber          = 0x829D
+	TagISO              = 0x8827
+	TagDateTimeOriginal = 0x9003
+	TagOffsetOriginal   = 0x9011
+	TagFocalLength      = 0x920A
+	TagFocalLength35    = 0xA405
+	TagLensModel        = 0xA434
+)
+
+// Entry is an IFD entry. Values of more than 4 bytes are stored after the
+// IFDs and pointed to.
+type Entry struct {
+	Tag   uint16
+	Type  uint16
+	Count uint32
+	Data  []byte
+}
+
+// Block is an EXIF block: a TIFF header, IFD0 and an optional Exif IFD
+type Block struct {
+	Order binary.ByteOrder
+	IFD0  []Entry
+	Exif  []Entry // linked from IFD0 when not empty
+}
+
+// Short returns a SHORT entry
+func (b *Block) Short(tag uint16, v uint16) Entry {
+	data := make([]byte, 2)
+	b.Order.PutUint16(data, v)
+	return Entry{Tag: tag, Type: TypeShort, Count: 1, Data: data}
+}
+
+// Long returns a LONG entry
+func (b *Block) Long(tag uint16, v uint32) Entry {
+	data := make([]byte, 4)
+	b.Order.PutUint32(data, v)
+	return Entry{Tag: tag, Type: TypeLong, Count: 1, Data: data}
+}
+
+// Rational returns a RATIONAL entry of num/den
+func (b *Block) Rational(tag uint16, num, den uint32) Entry {
+	data := make([]byte, 8)
+	b.Order.PutUint32(data, num)
+	b.Order.PutUint32(data[4:], den)
+	return Entry{Tag: tag, Type: TypeRational, Count: 1, Data: data}
+}
+
+// ASCII returns a NUL terminated ASCII entry
+func (b *Block) ASCII(tag uint16, s string) Entry {
+	return Entry{Tag: tag, Type: TypeASCII, Count: uint32(len(s) + 1), Data: append([]byte(s), 0)}
+}
+
+// Bytes lays the block out: header, IFD0, Exif IFD, then the values that
+// do not fit their entries
+func (b *Block) Bytes() []byte {
+	ifd0 := b.IFD0
+	exifAt := 8 + 2 + 12*len(ifd0) + 4
+	if len(b.Exif) > 0 {
+		exifAt += 12
+		ifd0 = append(append([]Entry(nil), ifd0...), b.Long(TagExifIFD, uint32(exifAt)))
+	}
+	dataAt := exifAt
+	if len(b.Exif) > 0 {
+		dataAt += 2 + 12*len(b.Exif) + 4
+	}
+
+	out := make([]byte, dataAt)
+	if b.Order == binary.ByteOrder(binary.BigEndian) {
+		copy(out, "MM")
+	} else {
+		copy(out, "II")
+	}
+	b.Order.PutUint16(out[2:], 42)
+	b.Order.PutUint32(out[4:], 8)
+
+	var values []byte
+	write := func(at int, entries []Entry) {
+		b.Order.PutUint16(out[at:], uint16(len(entries)))
+		for i, e := range entries {
+			p := out[at+2+12*i:]
+			b.Order.PutUint16(p, e.Tag)
+			b.Order.PutUint16(p[2:], e.Type)
+			b.Order.PutUint32(p[4:], e.Count)
+			if len(e.Data) <= 4 {
+				copy(p[8:12], e.Data)
+			} else {
+				b.Order.PutUint32(p[8:], uint32(dataAt+len(values)))
+				values = append(values, e.Data...)
+			}
+		}
+	}
+	write(8, ifd0)
+	if len(b.Exif) > 0 {
+		write(exifAt, b.Exif)
+	}
+	return append(out, values...)
+}
+
+// Orientation returns an EXIF block holding only an orientation
+func Orientation(orientation int) []byte {
+	b := &Block{Order: binary.LittleEndian}
+	b.IFD0 = []Entry{b.Short(TagOrientation, uint16(orientation))}
+	return b.Bytes()
+}
+
+// APP1 returns a JPEG APP1 segment holding an EXIF block
+func APP1(block []byte) []byte {
+	n := 2 + 6 + len(block)
+	segment := []byte{0xFF, 0xE1, byte(n >> 8), byte(n)}
+	segment = append(segment, "Exif\x00\x00"...)
+	return append(segment, block...)
+}
+
+// JPEG encodes img as a JPEG file carrying the EXIF block, if any
+func JPEG(t testing.TB, img image.Image, block []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatalf("encode JPEG: %v", err)
+	}
+	if block == nil {
+		return buf.Bytes()
+	}
+	file := buf.Bytes()
+	return append(append(append([]byte(nil), file[:2]...), APP1(block)...), file[2:]...)
+}
+
+// Quadrants returns a width×height image whose quarters are red (top
+// left), green (top right), blue (bottom left) and white, so turning and
+// mirroring it can be told apart
+func Quadrants(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, QuadrantColor(x*2/width, y*2/height))
+		}
+	}
+	return img
+}
+
+// QuadrantColor is the colour of a Quadrants quarter, column and row 0 or 1
+func QuadrantColor(column, row int) color.RGBA {
+	return [2][2]color.RGBA{
+		{{0xFF, 0, 0, 0xFF}, {0, 0xFF, 0, 0xFF}},
+		{{0, 0, 0xFF, 0xFF}, {0xFF, 0xFF, 0xFF, 0xFF}},
+	}[row][column]
+}
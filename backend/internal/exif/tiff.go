@@ -0,0 +1,243 @@
+// backend/internal/exif/tiff.go
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// Tags read from IFD0
+const (
+	tagMake        = 0x010F
+	tagModel       = 0x0110
+	tagOrientation = 0x0112
+	tagDateTime    = 0x0132
+	tagExifIFD     = 0x8769
+)
+
+// Tags read from the Exif IFD
+const (
+	tagExposureTime      = 0x829A
+	tagFNumber           = 0x829D
+	tagISO               = 0x8827
+	tagDateTimeOriginal  = 0x9003
+	tagDateTimeDigitized = 0x9004
+	tagOffsetOriginal    = 0x9011
+	tagFocalLength       = 0x920A
+	tagFocalLength35     = 0xA405
+	tagLensModel         = 0xA434
+)
+
+// Field types and their sizes in bytes
+const (
+	typeByte      = 1
+	typeASCII     = 2
+	typeShort     = 3
+	typeLong      = 4
+	typeRational  = 5
+	typeUndefined = 7
+	typeSLong     = 9
+	typeSRational = 10
+)
+
+var typeSizes = map[uint16]uint64{
+	typeByte:      1,
+	typeASCII:     1,
+	typeShort:     2,
+	typeLong:      4,
+	typeRational:  8,
+	typeUndefined: 1,
+	typeSLong:     4,
+	typeSRational: 8,
+}
+
+// exifDate is how EXIF writes dates
+const exifDate = "2006:01:02 15:04:05"
+
+// field is an IFD entry with its value bytes resolved
+type field struct {
+	typ   uint16
+	value []byte
+}
+
+// tiff is an EXIF block: a TIFF header followed by IFDs
+type tiff struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// parseTIFF fills meta from the EXIF block in data
+func parseTIFF(data []byte, meta *models.ImageMetadata) error {
+	if len(data) < 8 {
+		return ErrMalformed
+	}
+	t := &tiff{data: data}
+	switch string(data[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return ErrMalformed
+	}
+	if t.order.Uint16(data[2:]) != 42 {
+		return ErrMalformed
+	}
+
+	ifd0, err := t.ifd(t.order.Uint32(data[4:]))
+	if err != nil {
+		return err
+	}
+	exif := map[uint16]field{}
+	if pointer, ok := t.uint(ifd0[tagExifIFD]); ok {
+		if exif, err = t.ifd(uint32(pointer)); err != nil {
+			return err
+		}
+	}
+
+	meta.CameraMake = ascii(ifd0[tagMake])
+	meta.CameraModel = ascii(ifd0[tagModel])
+	if o, ok := t.uint(ifd0[tagOrientation]); ok && o >= 1 && o <= 8 {
+		meta.Orientation = o
+	}
+
+	meta.Lens = ascii(exif[tagLensModel])
+	if v, ok := t.rational(exif[tagFocalLength]); ok {
+		meta.FocalLength = round1(v)
+	}
+	if v, ok := t.uint(exif[tagFocalLength35]); ok {
+		meta.FocalLength35 = v
+	}
+	if v, ok := t.rational(exif[tagFNumber]); ok {
+		meta.Aperture = round1(v)
+	}
+	if num, den, ok := t.fraction(exif[tagExposureTime]); ok {
+		meta.ExposureTime = exposure(num, den)
+	}
+	if v, ok := t.uint(exif[tagISO]); ok {
+		meta.ISO = v
+	}
+
+	offset := ascii(exif[tagOffsetOriginal])
+	for _, date := range []string{ascii(exif[tagDateTimeOriginal]), ascii(exif[tagDateTimeDigitized]), ascii(ifd0[tagDateTime])} {
+		if at, ok := parseDate(date, offset); ok {
+			meta.CapturedAt = &at
+			break
+		}
+		// The offset belongs to DateTimeOriginal only
+		offset = ""
+	}
+
+	return nil
+}
+
+// ifd reads the entries of the IFD at offset. Entries whose values point
+// outside the block are skipped.
+func (t *tiff) ifd(offset uint32) (map[uint16]field, error) {
+	start := uint64(offset)
+	if start+2 > uint64(len(t.data)) {
+		return nil, ErrMalformed
+	}
+	count := uint64(t.order.Uint16(t.data[start:]))
+	start += 2
+	if start+count*12 > uint64(len(t.data)) {
+		return nil, ErrMalformed
+	}
+
+	fields := make(map[uint16]field, count)
+	for i := uint64(0); i < count; i++ {
+		entry := t.data[start+i*12 : start+i*12+12]
+		tag := t.order.Uint16(entry)
+		typ := t.order.Uint16(entry[2:])
+		size, known := typeSizes[typ]
+		if !known {
+			continue
+		}
+		size *= uint64(t.order.Uint32(entry[4:]))
+
+		var value []byte
+		if size <= 4 {
+			value = entry[8 : 8+size]
+		} else {
+			at := uint64(t.order.Uint32(entry[8:]))
+			if at+size > uint64(len(t.data)) {
+				continue
+			}
+			value = t.data[at : at+size]
+		}
+		fields[tag] = field{typ: typ, value: value}
+	}
+	return fields, nil
+}
+
+// uint returns the first value of a SHORT or LONG field
+func (t *tiff) uint(f field) (int, bool) {
+	switch {
+	case f.typ == typeShort && len(f.value) >= 2:
+		return int(t.order.Uint16(f.value)), true
+	case f.typ == typeLong && len(f.value) >= 4:
+		return int(t.order.Uint32(f.value)), true
+	}
+	return 0, false
+}
+
+// fraction returns the first value of a RATIONAL field
+func (t *tiff) fraction(f field) (num, den uint32, ok bool) {
+	if f.typ != typeRational || len(f.value) < 8 {
+		return 0, 0, false
+	}
+	num, den = t.order.Uint32(f.value), t.order.Uint32(f.value[4:])
+	return num, den, num != 0 && den != 0
+}
+
+// rational returns the first value of a RATIONAL field as a number
+func (t *tiff) rational(f field) (float64, bool) {
+	num, den, ok := t.fraction(f)
+	if !ok {
+		return 0, false
+	}
+	return float64(num) / float64(den), true
+}
+
+// ascii returns an ASCII field without its NUL terminator and padding
+func ascii(f field) string {
+	if f.typ != typeASCII {
+		return ""
+	}
+	value, _, _ := bytes.Cut(f.value, []byte{0})
+	return strings.TrimSpace(string(value))
+}
+
+// parseDate reads an EXIF date in the camera's time, with an optional
+// "+05:30" style offset. Without one the time is taken as UTC.
+func parseDate(date, offset string) (time.Time, bool) {
+	if date == "" {
+		return time.Time{}, false
+	}
+	if len(offset) == 6 {
+		if at, err := time.Parse(exifDate+"-07:00", date+offset); err == nil {
+			return at, true
+		}
+	}
+	at, err := time.ParseInLocation(exifDate, date, time.UTC)
+	return at, err == nil
+}
+
+// exposure formats an exposure time in seconds the way cameras show it
+func exposure(num, den uint32) string {
+	if num < den {
+		return "1/" + strconv.FormatFloat(math.Round(float64(den)/float64(num)), 'f', -1, 64)
+	}
+	return strconv.FormatFloat(round1(float64(num)/float64(den)), 'f', -1, 64)
+}
+
+// round1 rounds to one decimal, as lenses and apertures are labelled
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
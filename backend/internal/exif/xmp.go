@@ -0,0 +1,151 @@
+// backend/internal/exif/xmp.go
+package exif
+
+import (
+	"bytes"
+	"encoding/xml"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// XMP namespaces
+const (
+	nsTIFF      = "http://ns.adobe.com/tiff/1.0/"
+	nsEXIF      = "http://ns.adobe.com/exif/1.0/"
+	nsEXIFEX    = "http://cipa.jp/exif/1.0/"
+	nsAux       = "http://ns.adobe.com/exif/1.0/aux/"
+	nsXMP       = "http://ns.adobe.com/xap/1.0/"
+	nsPhotoshop = "http://ns.adobe.com/photoshop/1.0/"
+)
+
+// xmpProperties maps the XMP properties read to the metadata they fill.
+// A value may be an attribute or the text of an element, also inside an
+// rdf:Seq or rdf:Alt.
+var xmpProperties = map[xml.Name]string{
+	{Space: nsTIFF, Local: "Make"}:                      "make",
+	{Space: nsTIFF, Local: "Model"}:                     "model",
+	{Space: nsTIFF, Local: "Orientation"}:               "orientation",
+	{Space: nsEXIFEX, Local: "LensModel"}:               "lens",
+	{Space: nsAux, Local: "Lens"}:                       "lens",
+	{Space: nsEXIF, Local: "FocalLength"}:               "focal_length",
+	{Space: nsEXIF, Local: "FocalLengthIn35mmFilm"}:     "focal_length_35mm",
+	{Space: nsEXIF, Local: "FNumber"}:                   "aperture",
+	{Space: nsEXIF, Local: "ExposureTime"}:              "exposure_time",
+	{Space: nsEXIF, Local: "ISOSpeedRatings"}:           "iso",
+	{Space: nsEXIFEX, Local: "PhotographicSensitivity"}: "iso",
+	{Space: nsEXIF, Local: "DateTimeOriginal"}:          "captured_at",
+	{Space: nsPhotoshop, Local: "DateCreated"}:          "captured_at",
+	{Space: nsXMP, Local: "CreateDate"}:                 "captured_at",
+}
+
+// xmpDates are the date forms XMP allows, most precise first
+var xmpDates = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// parseXMP fills the values meta still lacks from an XMP packet. A packet
+// that stops parsing half way still gives what was read before.
+func parseXMP(data []byte, meta *models.ImageMetadata) {
+	values := map[string]string{}
+	set := func(key, value string) {
+		if value = strings.TrimSpace(value); key != "" && value != "" && values[key] == "" {
+			values[key] = value
+		}
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var open []string // the property each open element belongs to
+	for {
+		token, err := d.Token()
+		if err != nil {
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			for _, attr := range t.Attr {
+				set(xmpProperties[attr.Name], attr.Value)
+			}
+			key, ok := xmpProperties[t.Name]
+			if !ok && len(open) > 0 {
+				key = open[len(open)-1]
+			}
+			open = append(open, key)
+		case xml.EndElement:
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+		case xml.CharData:
+			if len(open) > 0 {
+				set(open[len(open)-1], string(t))
+			}
+		}
+	}
+
+	if meta.CameraMake == "" {
+		meta.CameraMake = values["make"]
+	}
+	if meta.CameraModel == "" {
+		meta.CameraModel = values["model"]
+	}
+	if meta.Lens == "" {
+		meta.Lens = values["lens"]
+	}
+	if o, err := strconv.Atoi(values["orientation"]); err == nil && meta.Orientation == 0 && o >= 1 && o <= 8 {
+		meta.Orientation = o
+	}
+	if v, ok := parseNumber(values["focal_length"]); ok && meta.FocalLength == 0 {
+		meta.FocalLength = round1(v)
+	}
+	if v, err := strconv.Atoi(values["focal_length_35mm"]); err == nil && meta.FocalLength35 == 0 && v > 0 {
+		meta.FocalLength35 = v
+	}
+	if v, ok := parseNumber(values["aperture"]); ok && meta.Aperture == 0 {
+		meta.Aperture = round1(v)
+	}
+	if v, ok := parseNumber(values["exposure_time"]); ok && meta.ExposureTime == "" {
+		if v < 1 {
+			meta.ExposureTime = "1/" + strconv.FormatFloat(math.Round(1/v), 'f', -1, 64)
+		} else {
+			meta.ExposureTime = strconv.FormatFloat(round1(v), 'f', -1, 64)
+		}
+	}
+	if v, err := strconv.Atoi(values["iso"]); err == nil && meta.ISO == 0 && v > 0 {
+		meta.ISO = v
+	}
+	if meta.CapturedAt == nil && values["captured_at"] != "" {
+		for _, layout := range xmpDates {
+			if at, err := time.ParseInLocation(layout, values["captured_at"], time.UTC); err == nil {
+				meta.CapturedAt = &at
+				break
+			}
+		}
+	}
+}
+
+// parseNumber reads an XMP rational ("28/10") or plain number
+func parseNumber(s string) (float64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	num, den, isFraction := strings.Cut(s, "/")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, false
+	}
+	if isFraction {
+		d, err := strconv.ParseFloat(den, 64)
+		if err != nil || d == 0 {
+			return 0, false
+		}
+		n /= d
+	}
+	return n, n > 0
+}
@@ -0,0 +1,91 @@
+// backend/internal/handlers/audit.go
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/pkg/response"
+)
+
+// Audit log page sizes
+const (
+	defaultAuditPerPage = 50
+	maxAuditPerPage     = 200
+)
+
+// AuditHandler serves the admin audit log
+type AuditHandler struct {
+	repo repository.AuditStore
+}
+
+// NewAuditHandler creates a new handler
+func NewAuditHandler(repo repository.AuditStore) *AuditHandler {
+	return &AuditHandler{repo: repo}
+}
+
+// List handles GET /api/admin/audit
+// Filters: actor_id, action, entity_type, entity_id, since and until (RFC 3339).
+// Pages: page (from 1) and per_page (up to 200).
+func (h *AuditHandler) List(c *gin.Context) {
+	filter := repository.AuditFilter{
+		Action:     c.Query("action"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+	}
+
+	if actorParam := c.Query("actor_id"); actorParam != "" {
+		actorID, err := strconv.Atoi(actorParam)
+		if err != nil || actorID <= 0 {
+			response.Error(c, http.StatusBadRequest, "Invalid actor_id")
+			return
+		}
+		filter.ActorID = actorID
+	}
+
+	for _, bound := range []struct {
+		param string
+		dest  *time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		if value := c.Query(bound.param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				response.Error(c, http.StatusBadRequest, "Invalid "+bound.param+", expected RFC 3339")
+				return
+			}
+			*bound.dest = t
+		}
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		response.Error(c, http.StatusBadRequest, "Invalid page")
+		return
+	}
+	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(defaultAuditPerPage)))
+	if err != nil || perPage < 1 || perPage > maxAuditPerPage {
+		response.Error(c, http.StatusBadRequest, "Invalid per_page")
+		return
+	}
+	filter.Limit = perPage
+	filter.Offset = (page - 1) * perPage
+
+	entries, total, err := h.repo.List(c.Request.Context(), filter)
+	if err != nil {
+		log.Printf("Failed to fetch audit log: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch audit log")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Audit log retrieved", gin.H{
+		"entries":  entries,
+		"total":    total,
+		"page":     page,
+		"per_page": perPage,
+	})
+}
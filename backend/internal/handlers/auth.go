@@ -19,13 +19,13 @@ import (
 
 // AuthHandler handles authentication requests
 type AuthHandler struct {
-	repo     *repository.UserRepository
+	repo     repository.UserStore
 	config   *config.Config
 	validate *validator.Validate
 }
 
 // NewAuthHandler creates a new handler
-func NewAuthHandler(repo *repository.UserRepository, cfg *config.Config) *AuthHandler {
+func NewAuthHandler(repo repository.UserStore, cfg *config.Config) *AuthHandler {
 	return &AuthHandler{
 		repo:     repo,
 		config:   cfg,
@@ -51,7 +51,11 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	println("Login attempt with email:", req.Email)
 
 	// Find user
-	user, err := h.repo.GetByEmail(req.Email)
+	user, err := h.repo.GetByEmail(c.Request.Context(), req.Email)
+	if isTimeout(err) {
+		dbError(c, err, http.StatusInternalServerError, "")
+		return
+	}
 	if err != nil {
 		println("User not found:", req.Email)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found with email: " + req.Email})
@@ -85,7 +89,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// Update last login
-	h.repo.UpdateLastLogin(user.ID)
+	h.repo.UpdateLastLogin(c.Request.Context(), user.ID)
 
 	response.Success(c, http.StatusOK, "Login successful", models.LoginResponse{
 		Token: tokenString,
@@ -119,9 +123,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 
 	// Check if email exists
-	exists, err := h.repo.EmailExists(req.Email)
+	exists, err := h.repo.EmailExists(c.Request.Context(), req.Email)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, "Database error")
+		dbError(c, err, http.StatusInternalServerError, "Database error")
 		return
 	}
 	if exists {
@@ -143,8 +147,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		Name:         req.Name,
 	}
 
-	if err := h.repo.Create(user); err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to create user")
+	if err := h.repo.Create(c.Request.Context(), user); err != nil {
+		dbError(c, err, http.StatusInternalServerError, "Failed to create user")
 		return
 	}
 
@@ -17,13 +17,13 @@ import (
 
 // ContactHandler handles contact form requests
 type ContactHandler struct {
-	repo     *repository.ContactRepository
+	repo     repository.ContactStore
 	email    *services.EmailService
 	validate *validator.Validate
 }
 
 // NewContactHandler creates a new handler
-func NewContactHandler(repo *repository.ContactRepository, email *services.EmailService) *ContactHandler {
+func NewContactHandler(repo repository.ContactStore, email *services.EmailService) *ContactHandler {
 	return &ContactHandler{
 		repo:     repo,
 		email:    email,
@@ -48,10 +48,10 @@ func (h *ContactHandler) Submit(c *gin.Context) {
 	}
 
 	// Save to database
-	msg, err := h.repo.Create(&req)
+	msg, err := h.repo.Create(c.Request.Context(), &req)
 	if err != nil {
 		log.Printf("Failed to save contact message: %v", err)
-		response.Error(c, http.StatusInternalServerError, "Failed to submit message")
+		dbError(c, err, http.StatusInternalServerError, "Failed to submit message")
 		return
 	}
 
@@ -73,16 +73,33 @@ func (h *ContactHandler) Submit(c *gin.Context) {
 
 // GetAll handles GET /api/admin/contacts (protected)
 func (h *ContactHandler) GetAll(c *gin.Context) {
-	messages, err := h.repo.GetAll()
+	messages, err := h.repo.GetAll(c.Request.Context())
 	if err != nil {
 		log.Printf("Failed to fetch contact messages: %v", err)
-		response.Error(c, http.StatusInternalServerError, "Failed to fetch messages")
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch messages")
 		return
 	}
 
 	response.Success(c, http.StatusOK, "Messages retrieved", messages)
 }
 
+// Search handles GET /api/admin/contacts/search?q= (protected)
+func (h *ContactHandler) Search(c *gin.Context) {
+	query, limit, ok := searchParams(c)
+	if !ok {
+		return
+	}
+
+	hits, err := h.repo.Search(c.Request.Context(), query, limit)
+	if err != nil {
+		log.Printf("Failed to search contact messages for %q: %v", query, err)
+		dbError(c, err, http.StatusInternalServerError, "Search failed")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Search results retrieved", hits)
+}
+
 // MarkAsRead handles PATCH /api/admin/contacts/:id/read
 func (h *ContactHandler) MarkAsRead(c *gin.Context) {
 	idParam := c.Param("id")
@@ -92,8 +109,8 @@ func (h *ContactHandler) MarkAsRead(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.MarkAsRead(id); err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to update message")
+	if err := h.repo.MarkAsRead(c.Request.Context(), id); err != nil {
+		dbError(c, err, http.StatusInternalServerError, "Failed to update message")
 		return
 	}
 
@@ -109,9 +126,9 @@ func (h *ContactHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.Delete(id); err != nil {
+	if err := h.repo.Delete(c.Request.Context(), id); err != nil {
 		log.Printf("Failed to delete contact message: %v", err)
-		response.Error(c, http.StatusInternalServerError, "Failed to delete message")
+		dbError(c, err, http.StatusInternalServerError, "Failed to delete message")
 		return
 	}
 
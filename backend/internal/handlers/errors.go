@@ -0,0 +1,28 @@
+// backend/internal/handlers/errors.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/pkg/response"
+)
+
+// dbError responds to a failed repository call. Timeouts become a 503 so
+// clients know to retry; anything else uses the given status and message.
+func dbError(c *gin.Context, err error, status int, message string) {
+	if isTimeout(err) {
+		c.Header("Retry-After", "5")
+		response.Error(c, http.StatusServiceUnavailable, "Service temporarily unavailable, please try again")
+		return
+	}
+	response.Error(c, status, message)
+}
+
+// isTimeout reports whether a repository call ran past its deadline
+func isTimeout(err error) bool {
+	return errors.Is(err, repository.ErrTimeout)
+}
@@ -2,38 +2,82 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"golang.org/x/crypto/bcrypt"
 
+	"github.com/supraik/Freelance-Portfolio/internal/imagesize"
 	"github.com/supraik/Freelance-Portfolio/internal/models"
 	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
 	"github.com/supraik/Freelance-Portfolio/pkg/response"
 )
 
 // GalleryHandler handles gallery requests
 type GalleryHandler struct {
-	repo     *repository.GalleryRepository
+	repo     repository.GalleryStore
+	storage  *services.StorageService
+	aspect   imagesize.Thresholds
 	validate *validator.Validate
 }
 
-// NewGalleryHandler creates a new handler
-func NewGalleryHandler(repo *repository.GalleryRepository) *GalleryHandler {
+// NewGalleryHandler creates a new handler. storage is used to read the
+// size and camera metadata of uploaded images, aspect to classify them.
+func NewGalleryHandler(repo repository.GalleryStore, storage *services.StorageService, aspect imagesize.Thresholds) *GalleryHandler {
 	return &GalleryHandler{
 		repo:     repo,
+		storage:  storage,
+		aspect:   aspect,
 		validate: validator.New(),
 	}
 }
 
 // GetAll handles GET /api/galleries
+// Images are included unless ?include= omits them (e.g. ?include=none for
+// covers only); ?images_limit=N returns at most N images per gallery.
+// Only published galleries inside their publishing window are listed.
 func (h *GalleryHandler) GetAll(c *gin.Context) {
-	categories, err := h.repo.GetAllCategories()
+	h.list(c, false)
+}
+
+// GetAllAdmin handles GET /api/admin/galleries
+// Like GetAll, but drafts, archived and scheduled galleries are listed too.
+func (h *GalleryHandler) GetAllAdmin(c *gin.Context) {
+	h.list(c, true)
+}
+
+func (h *GalleryHandler) list(c *gin.Context, allStatuses bool) {
+	opts := repository.CategoryListOptions{IncludeImages: true, AllStatuses: allStatuses}
+	if include, ok := c.GetQuery("include"); ok {
+		opts.IncludeImages = false
+		for _, field := range strings.Split(include, ",") {
+			if strings.TrimSpace(field) == "images" {
+				opts.IncludeImages = true
+			}
+		}
+	}
+
+	if limitParam := c.Query("images_limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 0 {
+			response.Error(c, http.StatusBadRequest, "Invalid images_limit")
+			return
+		}
+		opts.ImagesLimit = limit
+	}
+
+	categories, err := h.repo.GetAllCategories(c.Request.Context(), opts)
 	if err != nil {
 		log.Printf("Failed to fetch galleries: %v", err)
-		response.Error(c, http.StatusInternalServerError, "Failed to fetch galleries")
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch galleries")
 		return
 	}
 
@@ -44,16 +88,40 @@ func (h *GalleryHandler) GetAll(c *gin.Context) {
 func (h *GalleryHandler) GetBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 
-	category, err := h.repo.GetCategoryBySlug(slug)
+	category, err := h.repo.GetCategoryBySlug(c.Request.Context(), slug)
 	if err != nil {
 		log.Printf("Gallery not found: %s - %v", slug, err)
-		response.Error(c, http.StatusNotFound, "Gallery not found")
+		dbError(c, err, http.StatusNotFound, "Gallery not found")
 		return
 	}
 
 	response.Success(c, http.StatusOK, "Gallery retrieved", category)
 }
 
+// GetByID handles GET /api/admin/galleries/:id
+// The ETag carries the gallery's version for a later If-Match update.
+func (h *GalleryHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid gallery ID")
+		return
+	}
+
+	category, err := h.repo.GetCategoryByID(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Gallery not found: %d - %v", id, err)
+		dbError(c, err, http.StatusNotFound, "Gallery not found")
+		return
+	}
+
+	tag := etag(category.Version)
+	c.Header("ETag", tag)
+	if notModified(c, tag) {
+		return
+	}
+	response.Success(c, http.StatusOK, "Gallery retrieved", category)
+}
+
 // Create handles POST /api/admin/galleries
 func (h *GalleryHandler) Create(c *gin.Context) {
 	var category models.GalleryCategory
@@ -62,10 +130,30 @@ func (h *GalleryHandler) Create(c *gin.Context) {
 		response.Error(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if category.Status == "" {
+		category.Status = models.GalleryPublished
+	}
+	if message := checkSchedule(category.Status, category.PublishAt, category.UnpublishAt); message != "" {
+		response.Error(c, http.StatusBadRequest, message)
+		return
+	}
+	switch category.Access {
+	case "", models.GalleryAccessPublic, models.GalleryAccessUnlisted:
+	case models.GalleryAccessPassword:
+		response.Error(c, http.StatusBadRequest, "Set a password with PUT /api/admin/galleries/:id/access")
+		return
+	default:
+		response.Error(c, http.StatusBadRequest, "Access must be public, unlisted or password")
+		return
+	}
 
-	if err := h.repo.CreateCategory(&category); err != nil {
+	if err := h.repo.CreateCategory(c.Request.Context(), &category); err != nil {
+		if errors.Is(err, repository.ErrDuplicateSlug) {
+			response.Error(c, http.StatusConflict, "A gallery with this slug already exists")
+			return
+		}
 		log.Printf("Failed to create gallery: %v", err)
-		response.Error(c, http.StatusInternalServerError, "Failed to create gallery")
+		dbError(c, err, http.StatusInternalServerError, "Failed to create gallery")
 		return
 	}
 
@@ -73,6 +161,8 @@ func (h *GalleryHandler) Create(c *gin.Context) {
 }
 
 // Update handles PUT /api/admin/galleries/:id
+// With If-Match the update only applies to that version of the gallery;
+// otherwise it responds 412 with the current gallery and its ETag.
 func (h *GalleryHandler) Update(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
@@ -81,6 +171,12 @@ func (h *GalleryHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		h.categoryConflict(c, id)
+		return
+	}
+
 	var category models.GalleryCategory
 	if err := c.ShouldBindJSON(&category); err != nil {
 		response.Error(c, http.StatusBadRequest, "Invalid request body")
@@ -88,16 +184,242 @@ func (h *GalleryHandler) Update(c *gin.Context) {
 	}
 
 	category.ID = id
-
-	if err := h.repo.UpdateCategory(&category); err != nil {
-		log.Printf("Failed to update gallery: %v", err)
-		response.Error(c, http.StatusInternalServerError, "Failed to update gallery")
+	// Only If-Match makes the update conditional, not a version in the body
+	category.Version = version
+
+	if err := h.repo.UpdateCategory(c.Request.Context(), &category); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrVersionConflict):
+			h.categoryConflict(c, id)
+		case errors.Is(err, sql.ErrNoRows):
+			response.Error(c, http.StatusNotFound, "Gallery not found")
+		default:
+			log.Printf("Failed to update gallery: %v", err)
+			dbError(c, err, http.StatusInternalServerError, "Failed to update gallery")
+		}
 		return
 	}
 
+	c.Header("ETag", etag(category.Version))
 	response.Success(c, http.StatusOK, "Gallery updated successfully", category)
 }
 
+// statusRequest is the body of UpdateStatus
+type statusRequest struct {
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// UpdateStatus handles PUT /api/admin/galleries/:id/status
+// Sets the status and publishing window, honouring If-Match like Update.
+func (h *GalleryHandler) UpdateStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid gallery ID")
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		h.categoryConflict(c, id)
+		return
+	}
+
+	var req statusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if message := checkSchedule(req.Status, req.PublishAt, req.UnpublishAt); message != "" {
+		response.Error(c, http.StatusBadRequest, message)
+		return
+	}
+
+	category := models.GalleryCategory{
+		ID:          id,
+		Status:      req.Status,
+		PublishAt:   req.PublishAt,
+		UnpublishAt: req.UnpublishAt,
+		Version:     version,
+	}
+	if err := h.repo.UpdateCategoryStatus(c.Request.Context(), &category); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrVersionConflict):
+			h.categoryConflict(c, id)
+		case errors.Is(err, sql.ErrNoRows):
+			response.Error(c, http.StatusNotFound, "Gallery not found")
+		default:
+			log.Printf("Failed to update gallery status: %v", err)
+			dbError(c, err, http.StatusInternalServerError, "Failed to update gallery status")
+		}
+		return
+	}
+
+	updated, err := h.repo.GetCategoryByID(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Failed to fetch gallery: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch gallery")
+		return
+	}
+
+	c.Header("ETag", etag(updated.Version))
+	response.Success(c, http.StatusOK, "Gallery status updated successfully", updated)
+}
+
+// Gallery password length limits; bcrypt ignores bytes past 72
+const (
+	minGalleryPasswordLength = 8
+	maxGalleryPasswordLength = 72
+)
+
+// accessRequest is the body of UpdateAccess
+type accessRequest struct {
+	Access   string `json:"access"`
+	Password string `json:"password"` // required to enable password access, optional to keep it
+}
+
+// UpdateAccess handles PUT /api/admin/galleries/:id/access
+// Sets the access mode, honouring If-Match like Update. A new password
+// locks out everyone who unlocked the gallery with the old one.
+func (h *GalleryHandler) UpdateAccess(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid gallery ID")
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		h.categoryConflict(c, id)
+		return
+	}
+
+	var req accessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	switch req.Access {
+	case models.GalleryAccessPublic, models.GalleryAccessUnlisted:
+		if req.Password != "" {
+			response.Error(c, http.StatusBadRequest, "Only password access takes a password")
+			return
+		}
+	case models.GalleryAccessPassword:
+		if req.Password != "" && (len(req.Password) < minGalleryPasswordLength || len(req.Password) > maxGalleryPasswordLength) {
+			response.Error(c, http.StatusBadRequest, "Password must be 8 to 72 characters")
+			return
+		}
+	default:
+		response.Error(c, http.StatusBadRequest, "Access must be public, unlisted or password")
+		return
+	}
+
+	var hash []byte
+	if req.Password != "" {
+		if hash, err = bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost); err != nil {
+			response.Error(c, http.StatusInternalServerError, "Failed to hash password")
+			return
+		}
+	} else if req.Access == models.GalleryAccessPassword {
+		// Keeping the password is only possible if there is one
+		current, err := h.repo.CategoryPasswordHash(c.Request.Context(), id)
+		if err != nil {
+			log.Printf("Gallery not found: %d - %v", id, err)
+			dbError(c, err, http.StatusNotFound, "Gallery not found")
+			return
+		}
+		if current == "" {
+			response.Error(c, http.StatusBadRequest, "Password is required")
+			return
+		}
+	}
+
+	category := models.GalleryCategory{ID: id, Access: req.Access, Version: version}
+	if err := h.repo.UpdateCategoryAccess(c.Request.Context(), &category, string(hash)); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrVersionConflict):
+			h.categoryConflict(c, id)
+		case errors.Is(err, sql.ErrNoRows):
+			response.Error(c, http.StatusNotFound, "Gallery not found")
+		default:
+			log.Printf("Failed to update gallery access: %v", err)
+			dbError(c, err, http.StatusInternalServerError, "Failed to update gallery access")
+		}
+		return
+	}
+
+	updated, err := h.repo.GetCategoryByID(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Failed to fetch gallery: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch gallery")
+		return
+	}
+
+	c.Header("ETag", etag(updated.Version))
+	response.Success(c, http.StatusOK, "Gallery access updated successfully", updated)
+}
+
+// checkSchedule validates a status and publishing window, returning an error message
+func checkSchedule(status string, publishAt, unpublishAt *time.Time) string {
+	switch status {
+	case models.GalleryDraft, models.GalleryPublished, models.GalleryArchived:
+	default:
+		return "Status must be draft, published or archived"
+	}
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return "unpublish_at must be after publish_at"
+	}
+	return ""
+}
+
+// categoryConflict responds 412 with the current state of a gallery
+func (h *GalleryHandler) categoryConflict(c *gin.Context, id int) {
+	current, err := h.repo.GetCategoryByID(c.Request.Context(), id)
+	if err != nil {
+		dbError(c, err, http.StatusNotFound, "Gallery not found")
+		return
+	}
+
+	c.Header("ETag", etag(current.Version))
+	response.ErrorWithData(c, http.StatusPreconditionFailed, "Gallery was changed since it was loaded", current)
+}
+
+// orderRequest is the body of the reorder endpoints
+type orderRequest struct {
+	IDs []int `json:"ids"`
+}
+
+// ReorderCategories handles PUT /api/admin/galleries/order
+// The body lists every gallery ID in its new order.
+func (h *GalleryHandler) ReorderCategories(c *gin.Context) {
+	var req orderRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.IDs == nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.repo.ReorderCategories(c.Request.Context(), req.IDs); err != nil {
+		if errors.Is(err, repository.ErrOrderMismatch) {
+			response.Error(c, http.StatusConflict, "The order must list every gallery exactly once")
+			return
+		}
+		log.Printf("Failed to reorder galleries: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to reorder galleries")
+		return
+	}
+
+	categories, err := h.repo.GetAllCategories(c.Request.Context(), repository.CategoryListOptions{AllStatuses: true})
+	if err != nil {
+		log.Printf("Failed to fetch galleries: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch galleries")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Galleries reordered successfully", categories)
+}
+
 // Delete handles DELETE /api/admin/galleries/:id
 func (h *GalleryHandler) Delete(c *gin.Context) {
 	idParam := c.Param("id")
@@ -107,13 +429,13 @@ func (h *GalleryHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.DeleteCategory(id); err != nil {
+	if err := h.repo.DeleteCategory(c.Request.Context(), id); err != nil {
 		log.Printf("Failed to delete gallery: %v", err)
-		response.Error(c, http.StatusInternalServerError, "Failed to delete gallery")
+		dbError(c, err, http.StatusInternalServerError, "Failed to delete gallery")
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Gallery deleted successfully", nil)
+	response.Success(c, http.StatusOK, "Gallery moved to trash", nil)
 }
 
 // CreateImage handles POST /api/admin/galleries/:id/images
@@ -132,16 +454,140 @@ func (h *GalleryHandler) CreateImage(c *gin.Context) {
 	}
 
 	image.CategoryID = categoryID
-
-	if err := h.repo.CreateImage(&image); err != nil {
+	image.Filename = uploadName(image.Filename)
+	image.Variants = nil // the server's own, see describe
+	h.describe(&image)
+
+	if err := h.repo.CreateImage(c.Request.Context(), &image); err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			response.Error(c, http.StatusNotFound, "Gallery not found")
+			return
+		}
 		log.Printf("Failed to create image: %v", err)
-		response.Error(c, http.StatusInternalServerError, "Failed to create image")
+		dbError(c, err, http.StatusInternalServerError, "Failed to create image")
 		return
 	}
 
 	response.Success(c, http.StatusCreated, "Image created successfully", image)
 }
 
+// ReorderImages handles PUT /api/admin/galleries/:id/images/order
+// The body lists every image ID of the gallery in its new order.
+func (h *GalleryHandler) ReorderImages(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid gallery ID")
+		return
+	}
+
+	var req orderRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.IDs == nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.repo.ReorderImages(c.Request.Context(), categoryID, req.IDs); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCategoryNotFound):
+			response.Error(c, http.StatusNotFound, "Gallery not found")
+		case errors.Is(err, repository.ErrOrderMismatch):
+			response.Error(c, http.StatusConflict, "The order must list every image of the gallery exactly once")
+		default:
+			log.Printf("Failed to reorder images: %v", err)
+			dbError(c, err, http.StatusInternalServerError, "Failed to reorder images")
+		}
+		return
+	}
+
+	images, err := h.repo.GetImagesByCategory(c.Request.Context(), categoryID)
+	if err != nil {
+		log.Printf("Failed to fetch images: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch images")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Images reordered successfully", images)
+}
+
+// transferRequest is the body of the move and copy endpoints
+type transferRequest struct {
+	IDs        []int `json:"ids"`
+	CategoryID int   `json:"category_id"`
+	Position   *int  `json:"position"` // index in the destination, the end when omitted
+}
+
+// MoveImages handles POST /api/admin/images/move
+func (h *GalleryHandler) MoveImages(c *gin.Context) {
+	h.transfer(c, false)
+}
+
+// CopyImages handles POST /api/admin/images/copy
+// Copies reference the same stored file as the originals.
+func (h *GalleryHandler) CopyImages(c *gin.Context) {
+	h.transfer(c, true)
+}
+
+func (h *GalleryHandler) transfer(c *gin.Context, copying bool) {
+	var req transferRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.IDs) == 0 || req.CategoryID <= 0 {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	position := -1
+	if req.Position != nil {
+		position = *req.Position
+	}
+
+	transfer, verb := h.repo.MoveImages, "move"
+	if copying {
+		transfer, verb = h.repo.CopyImages, "copy"
+	}
+
+	images, err := transfer(c.Request.Context(), req.IDs, req.CategoryID, position)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCategoryNotFound):
+			response.Error(c, http.StatusNotFound, "Gallery not found")
+		case errors.Is(err, sql.ErrNoRows):
+			response.Error(c, http.StatusNotFound, "Image not found")
+		default:
+			log.Printf("Failed to %s images: %v", verb, err)
+			dbError(c, err, http.StatusInternalServerError, "Failed to "+verb+" images")
+		}
+		return
+	}
+
+	if copying {
+		response.Success(c, http.StatusCreated, "Images copied successfully", images)
+		return
+	}
+	response.Success(c, http.StatusOK, "Images moved successfully", images)
+}
+
+// GetImage handles GET /api/admin/images/:id
+// The ETag carries the image's version for a later If-Match update.
+func (h *GalleryHandler) GetImage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid image ID")
+		return
+	}
+
+	image, err := h.repo.GetImageByID(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Image not found: %d - %v", id, err)
+		dbError(c, err, http.StatusNotFound, "Image not found")
+		return
+	}
+
+	tag := etag(image.Version)
+	c.Header("ETag", tag)
+	if notModified(c, tag) {
+		return
+	}
+	response.Success(c, http.StatusOK, "Image retrieved", image)
+}
+
 // DeleteImage handles DELETE /api/admin/images/:id
 func (h *GalleryHandler) DeleteImage(c *gin.Context) {
 	idParam := c.Param("id")
@@ -151,16 +597,17 @@ func (h *GalleryHandler) DeleteImage(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.DeleteImage(id); err != nil {
+	if err := h.repo.DeleteImage(c.Request.Context(), id); err != nil {
 		log.Printf("Failed to delete image: %v", err)
-		response.Error(c, http.StatusInternalServerError, "Failed to delete image")
+		dbError(c, err, http.StatusInternalServerError, "Failed to delete image")
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Image deleted successfully", nil)
+	response.Success(c, http.StatusOK, "Image moved to trash", nil)
 }
 
 // UpdateImage handles PUT /api/admin/images/:id
+// If-Match is honoured like in Update.
 func (h *GalleryHandler) UpdateImage(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
@@ -169,6 +616,12 @@ func (h *GalleryHandler) UpdateImage(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		h.imageConflict(c, id)
+		return
+	}
+
 	var image models.GalleryImage
 	if err := c.ShouldBindJSON(&image); err != nil {
 		response.Error(c, http.StatusBadRequest, "Invalid request body")
@@ -176,12 +629,82 @@ func (h *GalleryHandler) UpdateImage(c *gin.Context) {
 	}
 
 	image.ID = id
-
-	if err := h.repo.UpdateImage(&image); err != nil {
-		log.Printf("Failed to update image: %v", err)
-		response.Error(c, http.StatusInternalServerError, "Failed to update image")
+	image.Version = version
+	image.Filename = uploadName(image.Filename)
+	// Keep what was read from the file while the src stays the same.
+	// Variants are the server's own, never taken from the body.
+	image.Variants = nil
+	if current, err := h.repo.GetImageByID(c.Request.Context(), id); err == nil && current.Src == image.Src {
+		if image.Width <= 0 || image.Height <= 0 {
+			image.Width, image.Height = current.Width, current.Height
+		}
+		if image.BlurHash == "" {
+			image.ImagePlaceholder = current.ImagePlaceholder
+		}
+		if image.Filename == "" {
+			image.Filename = current.Filename
+		}
+		image.Variants = current.Variants
+	}
+	h.describe(&image)
+
+	if err := h.repo.UpdateImage(c.Request.Context(), &image); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrVersionConflict):
+			h.imageConflict(c, id)
+		case errors.Is(err, sql.ErrNoRows):
+			response.Error(c, http.StatusNotFound, "Image not found")
+		default:
+			log.Printf("Failed to update image: %v", err)
+			dbError(c, err, http.StatusInternalServerError, "Failed to update image")
+		}
 		return
 	}
 
+	c.Header("ETag", etag(image.Version))
 	response.Success(c, http.StatusOK, "Image updated successfully", image)
 }
+
+// describe fills in the camera metadata, size, loading placeholder and
+// resized variants of an image whose src is a local upload, each unless the
+// request supplied it or the image kept its stored one, reading the file
+// once for all of them. The aspect-ratio class is derived from the size when
+// it is known; images of unknown size keep the class they were sent with.
+// Details that cannot be read are logged and left out.
+func (h *GalleryHandler) describe(image *models.GalleryImage) {
+	sized := image.Width > 0 && image.Height > 0
+	listVariants := image.Variants == nil && h.storage.VariantsEnabled()
+	if image.Metadata == nil || !sized || image.BlurHash == "" || listVariants {
+		upload, err := h.storage.Describe(image.Src)
+		if err != nil {
+			log.Printf("Failed to read the details of %s: %v", image.Src, err)
+		}
+		if image.Metadata == nil {
+			image.Metadata = upload.Metadata
+		}
+		if !sized {
+			image.Width, image.Height = upload.Width, upload.Height
+		}
+		if image.BlurHash == "" && upload.Placeholder != nil {
+			image.ImagePlaceholder = *upload.Placeholder
+		}
+		if image.Variants == nil {
+			image.Variants = upload.Variants
+		}
+	}
+	if class := h.aspect.Classify(image.Width, image.Height); class != "" {
+		image.AspectRatio = class
+	}
+}
+
+// imageConflict responds 412 with the current state of an image
+func (h *GalleryHandler) imageConflict(c *gin.Context, id int) {
+	current, err := h.repo.GetImageByID(c.Request.Context(), id)
+	if err != nil {
+		dbError(c, err, http.StatusNotFound, "Image not found")
+		return
+	}
+
+	c.Header("ETag", etag(current.Version))
+	response.ErrorWithData(c, http.StatusPreconditionFailed, "Image was changed since it was loaded", current)
+}
@@ -0,0 +1,232 @@
+// backend/internal/handlers/gallery_access.go
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/supraik/Freelance-Portfolio/internal/access"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/pkg/response"
+)
+
+// Where clients present the token from Unlock; the cookie name ends in the gallery ID
+const (
+	accessCookiePrefix = "gallery_access_"
+	accessTokenHeader  = "X-Gallery-Token"
+)
+
+// Access log page sizes
+const (
+	defaultAccessLogPerPage = 50
+	maxAccessLogPerPage     = 200
+)
+
+// GalleryAccessHandler guards password-protected galleries
+type GalleryAccessHandler struct {
+	galleries repository.GalleryStore
+	log       repository.AccessLogStore
+	signer    *access.Signer
+	limiter   *access.Limiter
+	ttl       time.Duration
+}
+
+// NewGalleryAccessHandler creates a new handler; tokens last for ttl
+func NewGalleryAccessHandler(galleries repository.GalleryStore, accessLog repository.AccessLogStore, signer *access.Signer, limiter *access.Limiter, ttl time.Duration) *GalleryAccessHandler {
+	return &GalleryAccessHandler{galleries: galleries, log: accessLog, signer: signer, limiter: limiter, ttl: ttl}
+}
+
+// unlockRequest is the body of Unlock
+type unlockRequest struct {
+	Password string `json:"password"`
+}
+
+// Gallery handles GET /api/galleries/:slug ahead of GalleryHandler.GetBySlug.
+// Password galleries need the token from Unlock, in the cookie it sets or
+// the X-Gallery-Token header; other galleries pass straight through.
+func (h *GalleryAccessHandler) Gallery(c *gin.Context) {
+	category, err := h.galleries.GetCategoryBySlug(c.Request.Context(), c.Param("slug"))
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, repository.ErrCategoryNotFound):
+		// Missing galleries are left to GetBySlug
+		return
+	case err != nil:
+		// Anything else could be a password gallery, so never let it through
+		c.Abort()
+		log.Printf("Failed to fetch gallery: %s - %v", c.Param("slug"), err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch gallery")
+		return
+	case category.Access == models.GalleryAccessPublic:
+		return
+	}
+
+	c.Header("X-Robots-Tag", "noindex")
+	if category.Access != models.GalleryAccessPassword {
+		return
+	}
+	c.Header("Cache-Control", "private, no-store")
+
+	hash, err := h.galleries.CategoryPasswordHash(c.Request.Context(), category.ID)
+	if err != nil {
+		c.Abort()
+		log.Printf("Failed to fetch gallery password: %d - %v", category.ID, err)
+		dbError(c, err, http.StatusNotFound, "Gallery not found")
+		return
+	}
+
+	// Without a hash no token was ever issued, see Unlock
+	if _, err := h.signer.Verify(accessToken(c, category.ID), category.ID, hash, time.Now()); err != nil || hash == "" {
+		c.Abort()
+		response.ErrorWithData(c, http.StatusUnauthorized, "This gallery requires a password", gin.H{
+			"slug":   category.Slug,
+			"access": category.Access,
+		})
+		return
+	}
+
+	h.record(c, category.ID, models.AccessViewed)
+}
+
+// accessToken returns the token the request carries for a gallery, if any
+func accessToken(c *gin.Context, categoryID int) string {
+	if token := c.GetHeader(accessTokenHeader); token != "" {
+		return token
+	}
+	token, _ := c.Cookie(accessCookiePrefix + strconv.Itoa(categoryID))
+	return token
+}
+
+// Unlock handles POST /api/galleries/:slug/access
+// A correct password is answered with a token, also set as a cookie scoped
+// to the gallery. Wrong passwords count against the client's IP; once it has
+// used up its attempts it gets 429 with Retry-After.
+func (h *GalleryAccessHandler) Unlock(c *gin.Context) {
+	slug := c.Param("slug")
+	key := c.ClientIP() + " " + slug
+	now := time.Now()
+
+	var req unlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Password == "" {
+		response.Error(c, http.StatusBadRequest, "password is required")
+		return
+	}
+
+	category, err := h.galleries.GetCategoryBySlug(c.Request.Context(), slug)
+	if err != nil {
+		log.Printf("Gallery not found: %s - %v", slug, err)
+		dbError(c, err, http.StatusNotFound, "Gallery not found")
+		return
+	}
+	if category.Access != models.GalleryAccessPassword {
+		response.Error(c, http.StatusBadRequest, "This gallery does not require a password")
+		return
+	}
+
+	hash, err := h.galleries.CategoryPasswordHash(c.Request.Context(), category.ID)
+	if err != nil {
+		log.Printf("Failed to fetch gallery password: %d - %v", category.ID, err)
+		dbError(c, err, http.StatusNotFound, "Gallery not found")
+		return
+	}
+
+	// The attempt is counted before the password is compared and only
+	// forgiven once it turns out right
+	if wait := h.limiter.Try(key, now); wait > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		response.Error(c, http.StatusTooManyRequests, "Too many attempts, try again later")
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(req.Password)); err != nil {
+		h.record(c, category.ID, models.AccessDenied)
+		response.Error(c, http.StatusUnauthorized, "Incorrect password")
+		return
+	}
+	h.limiter.Reset(key)
+
+	// Tokens carry whole seconds, so the cookie expiry does too
+	expires := now.Add(h.ttl).Truncate(time.Second)
+	token := h.signer.Sign(category.ID, expires, hash)
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     accessCookiePrefix + strconv.Itoa(category.ID),
+		Value:    token,
+		Path:     "/api/galleries/" + category.Slug,
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	h.record(c, category.ID, models.AccessUnlocked)
+	response.Success(c, http.StatusOK, "Gallery unlocked", gin.H{
+		"token":      token,
+		"expires_at": expires.UTC(),
+	})
+}
+
+// Log handles GET /api/admin/galleries/:id/access-log
+// Filter: event (unlocked, denied or viewed). Pages: page (from 1) and
+// per_page (up to 200).
+func (h *GalleryAccessHandler) Log(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid gallery ID")
+		return
+	}
+
+	filter := repository.AccessLogFilter{CategoryID: id, Event: c.Query("event")}
+	switch filter.Event {
+	case "", models.AccessUnlocked, models.AccessDenied, models.AccessViewed:
+	default:
+		response.Error(c, http.StatusBadRequest, "event must be unlocked, denied or viewed")
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		response.Error(c, http.StatusBadRequest, "Invalid page")
+		return
+	}
+	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(defaultAccessLogPerPage)))
+	if err != nil || perPage < 1 || perPage > maxAccessLogPerPage {
+		response.Error(c, http.StatusBadRequest, "Invalid per_page")
+		return
+	}
+	filter.Limit = perPage
+	filter.Offset = (page - 1) * perPage
+
+	entries, total, err := h.log.List(c.Request.Context(), filter)
+	if err != nil {
+		log.Printf("Failed to fetch access log: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch access log")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Access log retrieved", gin.H{
+		"entries":  entries,
+		"total":    total,
+		"page":     page,
+		"per_page": perPage,
+	})
+}
+
+// record adds an event to the access log; failing to record does not fail the request
+func (h *GalleryAccessHandler) record(c *gin.Context, categoryID int, event string) {
+	entry := &models.GalleryAccessEntry{
+		CategoryID: categoryID,
+		Event:      event,
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	}
+	if err := h.log.Record(c.Request.Context(), entry); err != nil {
+		log.Printf("Failed to record gallery access: %v", err)
+	}
+}
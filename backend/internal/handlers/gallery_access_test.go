@@ -0,0 +1,160 @@
+// backend/internal/handlers/gallery_access_test.go
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/supraik/Freelance-Portfolio/internal/access"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/internal/repository/memory"
+)
+
+// failingGalleries is a gallery store whose slug lookups fail with err
+type failingGalleries struct {
+	repository.GalleryStore
+	err error
+}
+
+func (f failingGalleries) GetCategoryBySlug(context.Context, string) (*models.GalleryCategory, error) {
+	return nil, f.err
+}
+
+// accessRouter serves GET /galleries/:slug through the access check, the
+// next handler answering 200 "reached"
+func accessRouter(galleries repository.GalleryStore, limiter *access.Limiter) (*gin.Engine, *access.Signer) {
+	gin.SetMode(gin.TestMode)
+	signer := access.NewSigner("test-secret")
+	h := NewGalleryAccessHandler(galleries, memory.NewAccessLogRepository(), signer, limiter, time.Hour)
+
+	r := gin.New()
+	r.GET("/galleries/:slug", h.Gallery, func(c *gin.Context) { c.String(http.StatusOK, "reached") })
+	r.POST("/galleries/:slug/access", h.Unlock)
+	return r, signer
+}
+
+// passwordGallery stores a published password gallery with password "secret"
+func passwordGallery(t *testing.T) (*memory.GalleryRepository, *models.GalleryCategory, string) {
+	t.Helper()
+	repo := memory.NewGalleryRepository()
+	cat := &models.GalleryCategory{Slug: "locked", Title: "Locked", Status: models.GalleryPublished}
+	if err := repo.CreateCategory(context.Background(), cat); err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword: %v", err)
+	}
+	cat.Access = models.GalleryAccessPassword
+	if err := repo.UpdateCategoryAccess(context.Background(), cat, string(hash)); err != nil {
+		t.Fatalf("UpdateCategoryAccess: %v", err)
+	}
+	return repo, cat, string(hash)
+}
+
+func TestGalleryAccessGallery(t *testing.T) {
+	repo, cat, hash := passwordGallery(t)
+	limiter := access.NewLimiter(5, time.Minute)
+
+	tests := []struct {
+		name      string
+		galleries repository.GalleryStore
+		slug      string
+		token     bool
+		want      int
+	}{
+		{"lookup error", failingGalleries{repo, errors.New("connection reset")}, "locked", false, http.StatusInternalServerError},
+		{"lookup timeout", failingGalleries{repo, repository.ErrTimeout}, "locked", false, http.StatusServiceUnavailable},
+		{"missing", repo, "missing", false, http.StatusOK},
+		{"no token", repo, "locked", false, http.StatusUnauthorized},
+		{"token", repo, "locked", true, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, signer := accessRouter(tt.galleries, limiter)
+			req := httptest.NewRequest(http.MethodGet, "/galleries/"+tt.slug, nil)
+			if tt.token {
+				req.Header.Set(accessTokenHeader, signer.Sign(cat.ID, time.Now().Add(time.Hour), hash))
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (%s)", w.Code, tt.want, w.Body)
+			}
+			if reached := w.Body.String() == "reached"; reached != (tt.want == http.StatusOK) {
+				t.Errorf("reached next handler = %v with status %d", reached, w.Code)
+			}
+		})
+	}
+}
+
+func TestGalleryAccessUnlockConcurrent(t *testing.T) {
+	const attempts, guesses = 3, 30
+	repo, _, _ := passwordGallery(t)
+	r, _ := accessRouter(repo, access.NewLimiter(attempts, time.Minute))
+
+	codes := make(chan int, guesses)
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/galleries/locked/access", strings.NewReader(`{"password":"guess"}`)))
+			codes <- w.Code
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(codes)
+
+	counts := map[int]int{}
+	for code := range codes {
+		counts[code]++
+	}
+	if counts[http.StatusUnauthorized] != attempts || counts[http.StatusTooManyRequests] != guesses-attempts {
+		t.Errorf("responses = %v, want %d wrong passwords and the rest limited", counts, attempts)
+	}
+
+	// The right password is limited too once the attempts are used up
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/galleries/locked/access", strings.NewReader(`{"password":"secret"}`)))
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("right password after the limit: status = %d, want 429", w.Code)
+	}
+}
+
+func TestGalleryAccessUnlockResets(t *testing.T) {
+	repo, _, _ := passwordGallery(t)
+	r, _ := accessRouter(repo, access.NewLimiter(2, time.Minute))
+
+	for i, tt := range []struct {
+		password string
+		want     int
+	}{
+		{"guess", http.StatusUnauthorized},
+		{"secret", http.StatusOK}, // forgives the wrong guess
+		{"guess", http.StatusUnauthorized},
+		{"guess", http.StatusUnauthorized},
+		{"secret", http.StatusTooManyRequests},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/galleries/locked/access", strings.NewReader(`{"password":"`+tt.password+`"}`)))
+		if w.Code != tt.want {
+			t.Errorf("attempt %d (%s): status = %d, want %d", i+1, tt.password, w.Code, tt.want)
+		}
+	}
+}
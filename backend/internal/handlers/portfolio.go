@@ -11,12 +11,12 @@ import (
 )
 
 type PortfolioHandler struct {
-	sectionRepo *repository.PortfolioSectionRepository
-	imageRepo   *repository.GalleryRepository
+	sectionRepo repository.PortfolioSectionStore
+	imageRepo   repository.GalleryStore
 	cloudinary  *services.CloudinaryService
 }
 
-func NewPortfolioHandler(sectionRepo *repository.PortfolioSectionRepository, imageRepo *repository.GalleryRepository, cloudinary *services.CloudinaryService) *PortfolioHandler {
+func NewPortfolioHandler(sectionRepo repository.PortfolioSectionStore, imageRepo repository.GalleryStore, cloudinary *services.CloudinaryService) *PortfolioHandler {
 	return &PortfolioHandler{
 		sectionRepo: sectionRepo,
 		imageRepo:   imageRepo,
@@ -27,6 +27,10 @@ func NewPortfolioHandler(sectionRepo *repository.PortfolioSectionRepository, ima
 // GetSections returns all portfolio sections
 func (h *PortfolioHandler) GetSections(c *gin.Context) {
 	sections, err := h.sectionRepo.GetAll(c.Request.Context())
+	if isTimeout(err) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Service temporarily unavailable, please try again"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sections"})
 		return
@@ -64,6 +68,10 @@ func (h *PortfolioHandler) UpdateSectionImage(c *gin.Context) {
 	if err != nil {
 		// Cleanup uploaded image
 		h.cloudinary.DeleteImage(c.Request.Context(), result.PublicID)
+		if isTimeout(err) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Service temporarily unavailable, please try again"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update section"})
 		return
 	}
@@ -73,6 +81,9 @@ func (h *PortfolioHandler) UpdateSectionImage(c *gin.Context) {
 		"image": gin.H{
 			"url":       result.SecureURL,
 			"thumbnail": result.ThumbnailURL,
+			"width":     result.Width,
+			"height":    result.Height,
+			"metadata":  result.Metadata,
 		},
 	})
 }
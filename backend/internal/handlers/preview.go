@@ -0,0 +1,224 @@
+// backend/internal/handlers/preview.go
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/preview"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/pkg/response"
+)
+
+// Preview link lifetimes
+const (
+	defaultPreviewTTL = 7 * 24 * time.Hour
+	maxPreviewTTL     = 90 * 24 * time.Hour
+)
+
+// PreviewHandler hands out signed links to unpublished galleries and serves them
+type PreviewHandler struct {
+	links     repository.PreviewStore
+	galleries repository.GalleryStore
+	signer    *preview.Signer
+}
+
+// NewPreviewHandler creates a new handler
+func NewPreviewHandler(links repository.PreviewStore, galleries repository.GalleryStore, signer *preview.Signer) *PreviewHandler {
+	return &PreviewHandler{links: links, galleries: galleries, signer: signer}
+}
+
+// previewRequest is the body of Create; both fields are optional
+type previewRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+	Note      string     `json:"note"`
+}
+
+// previewLink is a link as shown to admins, with its token and gallery
+type previewLink struct {
+	models.PreviewLink
+	Token   string          `json:"token"`
+	Gallery *previewGallery `json:"gallery"` // nil once the gallery is in the trash
+}
+
+type previewGallery struct {
+	ID     int    `json:"id"`
+	Slug   string `json:"slug"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+// Gallery handles GET /api/galleries/:slug?preview=<token>. It runs ahead
+// of GalleryHandler.GetBySlug, which serves requests without a token.
+func (h *PreviewHandler) Gallery(c *gin.Context) {
+	token := c.Query("preview")
+	if token == "" {
+		return
+	}
+	c.Abort()
+
+	// Draft content must not end up in shared caches or search results
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Robots-Tag", "noindex")
+
+	claims, err := h.signer.Verify(token, time.Now())
+	if err != nil {
+		response.Error(c, http.StatusForbidden, "Preview link is invalid or has expired")
+		return
+	}
+
+	link, err := h.links.GetByID(c.Request.Context(), claims.LinkID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Failed to fetch preview link: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch gallery")
+		return
+	}
+	if link == nil || link.CategoryID != claims.CategoryID || !link.IsActive(time.Now()) {
+		response.Error(c, http.StatusForbidden, "Preview link is invalid or has expired")
+		return
+	}
+
+	slug := c.Param("slug")
+	category, err := h.galleries.GetCategoryByID(c.Request.Context(), link.CategoryID)
+	if err != nil {
+		log.Printf("Gallery not found for preview: %s - %v", slug, err)
+		dbError(c, err, http.StatusNotFound, "Gallery not found")
+		return
+	}
+	// A token only opens the gallery it was minted for
+	if category.Slug != slug {
+		response.Error(c, http.StatusNotFound, "Gallery not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Gallery preview retrieved", category)
+}
+
+// Create handles POST /api/admin/galleries/:id/previews
+// expires_at defaults to a week from now and may be at most 90 days away.
+func (h *PreviewHandler) Create(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid gallery ID")
+		return
+	}
+
+	var req previewRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if len(req.Note) > 255 {
+		response.Error(c, http.StatusBadRequest, "note must be at most 255 characters")
+		return
+	}
+
+	// Tokens carry whole seconds, so the stored expiry does too
+	now := time.Now().UTC()
+	expires := now.Add(defaultPreviewTTL)
+	if req.ExpiresAt != nil {
+		expires = req.ExpiresAt.UTC()
+		if !expires.After(now) || expires.Sub(now) > maxPreviewTTL {
+			response.Error(c, http.StatusBadRequest, "expires_at must be in the future and at most 90 days away")
+			return
+		}
+	}
+	expires = expires.Truncate(time.Second)
+
+	category, err := h.galleries.GetCategoryByID(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Gallery not found: %d - %v", id, err)
+		dbError(c, err, http.StatusNotFound, "Gallery not found")
+		return
+	}
+
+	link := models.PreviewLink{CategoryID: id, Note: req.Note, ExpiresAt: expires}
+	if userID := c.GetInt("user_id"); userID != 0 {
+		link.CreatedBy = &userID
+	}
+	if err := h.links.Create(c.Request.Context(), &link); err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			response.Error(c, http.StatusNotFound, "Gallery not found")
+			return
+		}
+		log.Printf("Failed to create preview link: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to create preview link")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Preview link created", h.present(link, category))
+}
+
+// List handles GET /api/admin/previews
+// Only links that are neither revoked nor expired are listed, newest first.
+func (h *PreviewHandler) List(c *gin.Context) {
+	links, err := h.links.ListActive(c.Request.Context(), time.Now())
+	if err != nil {
+		log.Printf("Failed to fetch preview links: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch preview links")
+		return
+	}
+
+	categories := make(map[int]*models.GalleryCategory)
+	result := make([]previewLink, 0, len(links))
+	for _, link := range links {
+		category, seen := categories[link.CategoryID]
+		if !seen {
+			category, err = h.galleries.GetCategoryByID(c.Request.Context(), link.CategoryID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				log.Printf("Failed to fetch gallery %d for preview links: %v", link.CategoryID, err)
+				dbError(c, err, http.StatusInternalServerError, "Failed to fetch preview links")
+				return
+			}
+			categories[link.CategoryID] = category
+		}
+		result = append(result, h.present(link, category))
+	}
+
+	response.Success(c, http.StatusOK, "Preview links retrieved", result)
+}
+
+// Revoke handles DELETE /api/admin/previews/:id
+func (h *PreviewHandler) Revoke(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid preview link ID")
+		return
+	}
+
+	if err := h.links.Revoke(c.Request.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(c, http.StatusNotFound, "Preview link not found or already revoked")
+			return
+		}
+		log.Printf("Failed to revoke preview link: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to revoke preview link")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Preview link revoked", nil)
+}
+
+// present adds the token and gallery to a link; category may be nil
+func (h *PreviewHandler) present(link models.PreviewLink, category *models.GalleryCategory) previewLink {
+	p := previewLink{
+		PreviewLink: link,
+		Token: h.signer.Sign(preview.Claims{
+			LinkID:     link.ID,
+			CategoryID: link.CategoryID,
+			ExpiresAt:  link.ExpiresAt,
+		}),
+	}
+	if category != nil {
+		p.Gallery = &previewGallery{ID: category.ID, Slug: category.Slug, Title: category.Title, Status: category.Status}
+	}
+	return p
+}
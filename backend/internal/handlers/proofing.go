@@ -0,0 +1,524 @@
+// backend/internal/handlers/proofing.go
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/proofing"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/pkg/response"
+)
+
+// maxProofingComment caps a client's comment on one image, in characters
+const maxProofingComment = 2000
+
+// ProofingHandler serves proofing galleries to invited clients and their
+// selections to admins
+type ProofingHandler struct {
+	proofing  repository.ProofingStore
+	galleries repository.GalleryStore
+	signer    *proofing.Signer
+	validate  *validator.Validate
+}
+
+// NewProofingHandler creates a new handler
+func NewProofingHandler(store repository.ProofingStore, galleries repository.GalleryStore, signer *proofing.Signer) *ProofingHandler {
+	return &ProofingHandler{proofing: store, galleries: galleries, signer: signer, validate: validator.New()}
+}
+
+// proofingSettingsRequest is the body of Enable
+type proofingSettingsRequest struct {
+	MaxPicks int `json:"max_picks"`
+}
+
+// proofingClientRequest is the body of Invite
+type proofingClientRequest struct {
+	Name  string `json:"name" validate:"required,max=255"`
+	Email string `json:"email" validate:"omitempty,email,max=255"`
+}
+
+// proofingMarkRequest is the body of Mark; it replaces the client's mark on the image
+type proofingMarkRequest struct {
+	Favorite bool   `json:"favorite"`
+	Comment  string `json:"comment"`
+}
+
+// proofingView is what a client sees through its link
+type proofingView struct {
+	Gallery  *models.GalleryCategory `json:"gallery"`
+	Client   models.ProofingClient   `json:"client"`
+	MaxPicks int                     `json:"max_picks"`
+	Picks    int                     `json:"picks"`
+	Marks    []models.ProofingMark   `json:"marks"`
+}
+
+// proofingSelection is a client as shown to admins, with its link token and marks
+type proofingSelection struct {
+	models.ProofingClient
+	Token string                `json:"token"`
+	Picks int                   `json:"picks"`
+	Marks []models.ProofingMark `json:"marks"`
+}
+
+// Gallery handles GET /api/proofing/:token
+// The gallery is shown whatever its status, with the client's marks.
+func (h *ProofingHandler) Gallery(c *gin.Context) {
+	client, settings, ok := h.client(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.galleries.GetCategoryByID(c.Request.Context(), client.CategoryID)
+	if err != nil {
+		log.Printf("Gallery not found for proofing: %d - %v", client.CategoryID, err)
+		dbError(c, err, http.StatusNotFound, "Gallery not found")
+		return
+	}
+	marks, err := h.proofing.ProofingMarks(c.Request.Context(), client.ID)
+	if err != nil {
+		log.Printf("Failed to fetch proofing marks: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch gallery")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Proofing gallery retrieved", proofingView{
+		Gallery:  category,
+		Client:   *client,
+		MaxPicks: settings.MaxPicks,
+		Picks:    countPicks(marks),
+		Marks:    marks,
+	})
+}
+
+// Mark handles PUT /api/proofing/:token/images/:imageID
+// A mark that is neither a favorite nor has a comment is removed.
+func (h *ProofingHandler) Mark(c *gin.Context) {
+	imageID, err := strconv.Atoi(c.Param("imageID"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid image ID")
+		return
+	}
+
+	var req proofingMarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if utf8.RuneCountInString(req.Comment) > maxProofingComment {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("comment must be at most %d characters", maxProofingComment))
+		return
+	}
+
+	client, settings, ok := h.client(c)
+	if !ok {
+		return
+	}
+
+	mark := models.ProofingMark{ClientID: client.ID, ImageID: imageID, Favorite: req.Favorite, Comment: req.Comment}
+	if err := h.proofing.SetProofingMark(c.Request.Context(), &mark); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			response.Error(c, http.StatusNotFound, "Image not found")
+		case errors.Is(err, repository.ErrSelectionSubmitted):
+			response.Error(c, http.StatusConflict, "Selection has already been submitted")
+		case errors.Is(err, repository.ErrPickLimit):
+			response.ErrorWithData(c, http.StatusConflict, "Pick limit reached", gin.H{"max_picks": settings.MaxPicks})
+		default:
+			log.Printf("Failed to save proofing mark: %v", err)
+			dbError(c, err, http.StatusInternalServerError, "Failed to save mark")
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Mark saved", mark)
+}
+
+// Submit handles POST /api/proofing/:token/submit
+// The selection needs at least one favorite and at most max_picks of them;
+// once submitted the client can no longer change it.
+func (h *ProofingHandler) Submit(c *gin.Context) {
+	client, settings, ok := h.client(c)
+	if !ok {
+		return
+	}
+
+	if err := h.proofing.SubmitProofingSelection(c.Request.Context(), client.ID); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			response.Error(c, http.StatusNotFound, "Gallery not found")
+		case errors.Is(err, repository.ErrSelectionSubmitted):
+			response.Error(c, http.StatusConflict, "Selection has already been submitted")
+		case errors.Is(err, repository.ErrEmptySelection):
+			response.Error(c, http.StatusBadRequest, "Pick at least one favorite before submitting")
+		case errors.Is(err, repository.ErrPickLimit):
+			response.ErrorWithData(c, http.StatusConflict, "Too many favorites", gin.H{"max_picks": settings.MaxPicks})
+		default:
+			log.Printf("Failed to submit proofing selection: %v", err)
+			dbError(c, err, http.StatusInternalServerError, "Failed to submit selection")
+		}
+		return
+	}
+
+	client, err := h.proofing.GetProofingClient(c.Request.Context(), client.ID)
+	if err != nil {
+		log.Printf("Failed to fetch proofing client: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to submit selection")
+		return
+	}
+	response.Success(c, http.StatusOK, "Selection submitted", client)
+}
+
+// client resolves the token in the URL to a client that may use it and its
+// gallery's settings, responding itself when it cannot
+func (h *ProofingHandler) client(c *gin.Context) (*models.ProofingClient, *models.ProofingSettings, bool) {
+	// Client selections must not end up in shared caches or search results
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Robots-Tag", "noindex")
+
+	claims, err := h.signer.Verify(c.Param("token"))
+	if err != nil {
+		response.Error(c, http.StatusForbidden, "Proofing link is invalid or has been revoked")
+		return nil, nil, false
+	}
+
+	client, err := h.proofing.GetProofingClient(c.Request.Context(), claims.ClientID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Failed to fetch proofing client: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch gallery")
+		return nil, nil, false
+	}
+	if client == nil || client.CategoryID != claims.CategoryID || client.RevokedAt != nil {
+		response.Error(c, http.StatusForbidden, "Proofing link is invalid or has been revoked")
+		return nil, nil, false
+	}
+
+	settings, err := h.proofing.ProofingSettings(c.Request.Context(), client.CategoryID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Failed to fetch proofing settings: %v", err)
+		}
+		dbError(c, err, http.StatusNotFound, "Gallery not found")
+		return nil, nil, false
+	}
+
+	return client, settings, true
+}
+
+// Get handles GET /api/admin/galleries/:id/proofing
+// It lists the settings (null when proofing is off) and every client with
+// its link token and marks.
+func (h *ProofingHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid gallery ID")
+		return
+	}
+
+	settings, err := h.proofing.ProofingSettings(c.Request.Context(), id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Failed to fetch proofing settings: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch proofing")
+		return
+	}
+
+	clients, err := h.proofing.ListProofingClients(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Failed to fetch proofing clients: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch proofing")
+		return
+	}
+
+	selections := make([]proofingSelection, 0, len(clients))
+	for _, client := range clients {
+		marks, err := h.proofing.ProofingMarks(c.Request.Context(), client.ID)
+		if err != nil {
+			log.Printf("Failed to fetch proofing marks: %v", err)
+			dbError(c, err, http.StatusInternalServerError, "Failed to fetch proofing")
+			return
+		}
+		selections = append(selections, h.present(client, marks))
+	}
+
+	response.Success(c, http.StatusOK, "Proofing retrieved", gin.H{
+		"settings": settings,
+		"clients":  selections,
+	})
+}
+
+// Enable handles PUT /api/admin/galleries/:id/proofing
+// It turns proofing on or changes max_picks (0 means no limit).
+func (h *ProofingHandler) Enable(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid gallery ID")
+		return
+	}
+
+	var req proofingSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.MaxPicks < 0 {
+		response.Error(c, http.StatusBadRequest, "max_picks must not be negative")
+		return
+	}
+
+	settings := models.ProofingSettings{CategoryID: id, MaxPicks: req.MaxPicks}
+	if err := h.proofing.EnableProofing(c.Request.Context(), &settings); err != nil {
+		log.Printf("Failed to enable proofing: %d - %v", id, err)
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(c, http.StatusNotFound, "Gallery not found")
+			return
+		}
+		dbError(c, err, http.StatusInternalServerError, "Failed to enable proofing")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Proofing enabled", settings)
+}
+
+// Disable handles DELETE /api/admin/galleries/:id/proofing
+// Client links stop working; clients and their marks are kept for when
+// proofing is enabled again.
+func (h *ProofingHandler) Disable(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid gallery ID")
+		return
+	}
+
+	if err := h.proofing.DisableProofing(c.Request.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(c, http.StatusNotFound, "Gallery is not in proofing mode")
+			return
+		}
+		log.Printf("Failed to disable proofing: %d - %v", id, err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to disable proofing")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Proofing disabled", nil)
+}
+
+// Invite handles POST /api/admin/galleries/:id/proofing/clients
+// The response carries the token for the client's link.
+func (h *ProofingHandler) Invite(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid gallery ID")
+		return
+	}
+
+	var req proofingClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	if _, err := h.galleries.GetCategoryByID(c.Request.Context(), id); err != nil {
+		log.Printf("Gallery not found: %d - %v", id, err)
+		dbError(c, err, http.StatusNotFound, "Gallery not found")
+		return
+	}
+	if _, err := h.proofing.ProofingSettings(c.Request.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(c, http.StatusConflict, "Gallery is not in proofing mode")
+			return
+		}
+		log.Printf("Failed to fetch proofing settings: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to invite client")
+		return
+	}
+
+	client := models.ProofingClient{CategoryID: id, Name: req.Name, Email: req.Email}
+	if err := h.proofing.CreateProofingClient(c.Request.Context(), &client); err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			response.Error(c, http.StatusNotFound, "Gallery not found")
+			return
+		}
+		log.Printf("Failed to create proofing client: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to invite client")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Client invited", h.present(client, []models.ProofingMark{}))
+}
+
+// Revoke handles DELETE /api/admin/proofing/clients/:id
+func (h *ProofingHandler) Revoke(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid client ID")
+		return
+	}
+
+	if err := h.proofing.RevokeProofingClient(c.Request.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(c, http.StatusNotFound, "Client not found or already revoked")
+			return
+		}
+		log.Printf("Failed to revoke proofing client: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to revoke client")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Client revoked", nil)
+}
+
+// Reopen handles POST /api/admin/proofing/clients/:id/reopen
+// The client can change and submit its selection again.
+func (h *ProofingHandler) Reopen(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid client ID")
+		return
+	}
+
+	if err := h.proofing.ReopenProofingSelection(c.Request.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(c, http.StatusNotFound, "Client not found or selection not submitted")
+			return
+		}
+		log.Printf("Failed to reopen proofing selection: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to reopen selection")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Selection reopened", nil)
+}
+
+// Selection handles GET /api/admin/proofing/clients/:id/selection
+// format=json (default) returns the client's favorites, csv a file of
+// filename and comment rows and txt a file with one filename per line.
+// Files are named as they were uploaded where that is known.
+func (h *ProofingHandler) Selection(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid client ID")
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	switch format {
+	case "json", "csv", "txt":
+	default:
+		response.Error(c, http.StatusBadRequest, "format must be json, csv or txt")
+		return
+	}
+
+	client, err := h.proofing.GetProofingClient(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Proofing client not found: %d - %v", id, err)
+		dbError(c, err, http.StatusNotFound, "Client not found")
+		return
+	}
+	marks, err := h.proofing.ProofingMarks(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Failed to fetch proofing marks: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch selection")
+		return
+	}
+
+	favorites := []models.ProofingMark{}
+	for _, mark := range marks {
+		if mark.Favorite {
+			favorites = append(favorites, mark)
+		}
+	}
+
+	var body bytes.Buffer
+	switch format {
+	case "json":
+		response.Success(c, http.StatusOK, "Selection retrieved", gin.H{
+			"client":    client,
+			"favorites": favorites,
+		})
+		return
+	case "csv":
+		w := csv.NewWriter(&body)
+		w.Write([]string{"filename", "comment"})
+		for _, mark := range favorites {
+			w.Write([]string{csvCell(markFilename(mark)), csvCell(mark.Comment)})
+		}
+		w.Flush()
+	case "txt":
+		for _, mark := range favorites {
+			body.WriteString(markFilename(mark) + "\n")
+		}
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == "txt" {
+		contentType = "text/plain; charset=utf-8"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="selection-%d-client-%d.%s"`, client.CategoryID, client.ID, format))
+	c.Data(http.StatusOK, contentType, body.Bytes())
+}
+
+// present adds the link token and marks to a client
+func (h *ProofingHandler) present(client models.ProofingClient, marks []models.ProofingMark) proofingSelection {
+	return proofingSelection{
+		ProofingClient: client,
+		Token:          h.signer.Sign(proofing.Claims{ClientID: client.ID, CategoryID: client.CategoryID}),
+		Picks:          countPicks(marks),
+		Marks:          marks,
+	}
+}
+
+// countPicks counts the favorites among marks
+func countPicks(marks []models.ProofingMark) int {
+	n := 0
+	for _, mark := range marks {
+		if mark.Favorite {
+			n++
+		}
+	}
+	return n
+}
+
+// markFilename returns the name a marked image was uploaded under, or the
+// name of its file when that is not known
+func markFilename(mark models.ProofingMark) string {
+	if mark.Filename != "" {
+		return mark.Filename
+	}
+	return filename(mark.Src)
+}
+
+// csvCell keeps a spreadsheet from reading a cell as a formula: values
+// starting with =, +, -, @, a tab or a carriage return get a leading
+// apostrophe
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// filename returns the last path element of an image's src, URL or path
+func filename(src string) string {
+	if u, err := url.Parse(src); err == nil && u.Path != "" {
+		src = u.Path
+	}
+	return path.Base(src)
+}
@@ -0,0 +1,88 @@
+// backend/internal/handlers/proofing_test.go
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/proofing"
+	"github.com/supraik/Freelance-Portfolio/internal/repository/memory"
+)
+
+func TestSelectionExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	store := memory.NewGalleryRepository()
+
+	cat := &models.GalleryCategory{Slug: "proofs", Title: "Proofs"}
+	if err := store.CreateCategory(ctx, cat); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.EnableProofing(ctx, &models.ProofingSettings{CategoryID: cat.ID}); err != nil {
+		t.Fatal(err)
+	}
+	client := &models.ProofingClient{CategoryID: cat.ID, Name: "Asha"}
+	if err := store.CreateProofingClient(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	for i, img := range []struct{ src, filename, comment string }{
+		{"/uploads/3f2a.jpg", "DSC_0042.JPG", "=HYPERLINK(\"http://example.com\")"},
+		{"/uploads/9b1c.jpg", "", "-crop tighter"},
+		{"/uploads/5d7e.jpg", "@first.jpg", "print 2, please"},
+	} {
+		image := &models.GalleryImage{CategoryID: cat.ID, Src: img.src, Filename: img.filename, DisplayOrder: i + 1}
+		if err := store.CreateImage(ctx, image); err != nil {
+			t.Fatal(err)
+		}
+		mark := &models.ProofingMark{ClientID: client.ID, ImageID: image.ID, Favorite: true, Comment: img.comment}
+		if err := store.SetProofingMark(ctx, mark); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h := NewProofingHandler(store, store, proofing.NewSigner("test-secret"))
+	r := gin.New()
+	r.GET("/clients/:id/selection", h.Selection)
+
+	for _, tt := range []struct {
+		format string
+		want   string
+	}{
+		{"csv", "filename,comment\n" +
+			"DSC_0042.JPG,\"'=HYPERLINK(\"\"http://example.com\"\")\"\n" +
+			"9b1c.jpg,'-crop tighter\n" +
+			"'@first.jpg,\"print 2, please\"\n"},
+		{"txt", "DSC_0042.JPG\n9b1c.jpg\n@first.jpg\n"},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/clients/"+strconv.Itoa(client.ID)+"/selection?format="+tt.format, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s export = %d %s", tt.format, w.Code, w.Body)
+		}
+		if got := w.Body.String(); got != tt.want {
+			t.Errorf("%s export =\n%s\nwant\n%s", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestUploadName(t *testing.T) {
+	for _, tt := range []struct{ name, want string }{
+		{"DSC_0042.JPG", "DSC_0042.JPG"},
+		{`C:\Users\asha\Pictures\wedding.jpg`, "wedding.jpg"},
+		{"../../etc/passwd", "passwd"},
+		{"line\nbreak.jpg", "linebreak.jpg"},
+		{"  spaced.png ", "spaced.png"},
+		{"", ""},
+		{"/", ""},
+	} {
+		if got := uploadName(tt.name); got != tt.want {
+			t.Errorf("uploadName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// backend/internal/handlers/search.go
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/pkg/response"
+)
+
+// Search limits
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	maxQueryLength     = 200
+)
+
+// SearchHandler handles public content search
+type SearchHandler struct {
+	repo repository.GalleryStore
+}
+
+// NewSearchHandler creates a new handler
+func NewSearchHandler(repo repository.GalleryStore) *SearchHandler {
+	return &SearchHandler{repo: repo}
+}
+
+// Search handles GET /api/search?q=
+// Returns up to ?limit= galleries and images each, best matches first.
+func (h *SearchHandler) Search(c *gin.Context) {
+	query, limit, ok := searchParams(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.repo.Search(c.Request.Context(), query, limit)
+	if err != nil {
+		log.Printf("Failed to search content for %q: %v", query, err)
+		dbError(c, err, http.StatusInternalServerError, "Search failed")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Search results retrieved", results)
+}
+
+// searchParams reads ?q= and ?limit=, responding with 400 when they are invalid
+func searchParams(c *gin.Context) (query string, limit int, ok bool) {
+	query = strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		response.Error(c, http.StatusBadRequest, "Query parameter q is required")
+		return "", 0, false
+	}
+	if utf8.RuneCountInString(query) > maxQueryLength {
+		response.Error(c, http.StatusBadRequest, "Query is too long")
+		return "", 0, false
+	}
+
+	limit = defaultSearchLimit
+	if limitParam := c.Query("limit"); limitParam != "" {
+		n, err := strconv.Atoi(limitParam)
+		if err != nil || n < 1 || n > maxSearchLimit {
+			response.Error(c, http.StatusBadRequest, "Invalid limit")
+			return "", 0, false
+		}
+		limit = n
+	}
+
+	return query, limit, true
+}
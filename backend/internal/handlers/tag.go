@@ -0,0 +1,373 @@
+// backend/internal/handlers/tag.go
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/pkg/response"
+	"github.com/supraik/Freelance-Portfolio/pkg/validator"
+)
+
+// Tag filter limits
+const (
+	maxFilterTags          = 10
+	maxTagNameLength       = 100
+	defaultTaggedPerPage   = 50
+	maxTaggedImagesPerPage = 100
+)
+
+// TagHandler handles tags and the public tag filters
+type TagHandler struct {
+	repo repository.TagStore
+}
+
+// NewTagHandler creates a new handler
+func NewTagHandler(repo repository.TagStore) *TagHandler {
+	return &TagHandler{repo: repo}
+}
+
+// tagRequest is the body of Create and Update; the slug defaults to one made from the name
+type tagRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// mergeRequest is the body of Merge
+type mergeRequest struct {
+	SourceIDs []int `json:"source_ids"`
+}
+
+// tagsRequest is the body of SetImageTags and SetCategoryTags
+type tagsRequest struct {
+	Tags []string `json:"tags"` // slugs
+}
+
+// PublicTags handles GET /api/tags
+// Lists the tags of public images and galleries with their counts.
+func (h *TagHandler) PublicTags(c *gin.Context) {
+	tags, err := h.repo.PublicTags(c.Request.Context())
+	if err != nil {
+		log.Printf("Failed to fetch tags: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch tags")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Tags retrieved", tags)
+}
+
+// Images handles GET /api/images?tags=bridal,red
+// Images must carry every tag, or any of them with ?match=any. Pages: page
+// (from 1) and per_page (up to 100). Facets count the tags of all matches.
+func (h *TagHandler) Images(c *gin.Context) {
+	q, ok := tagQuery(c)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		response.Error(c, http.StatusBadRequest, "Invalid page")
+		return
+	}
+	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(defaultTaggedPerPage)))
+	if err != nil || perPage < 1 || perPage > maxTaggedImagesPerPage {
+		response.Error(c, http.StatusBadRequest, "Invalid per_page")
+		return
+	}
+	q.Limit = perPage
+	q.Offset = (page - 1) * perPage
+
+	results, err := h.repo.ImagesByTags(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Failed to fetch images by tag: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch images")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Images retrieved", gin.H{
+		"images":   results.Images,
+		"facets":   results.Facets,
+		"total":    results.Total,
+		"page":     page,
+		"per_page": perPage,
+	})
+}
+
+// Galleries handles GET /api/galleries?tags=bridal,red ahead of
+// GalleryHandler.GetAll, which serves requests without tags.
+// ?match=any works like it does for Images.
+func (h *TagHandler) Galleries(c *gin.Context) {
+	if c.Query("tags") == "" {
+		return
+	}
+	c.Abort()
+
+	q, ok := tagQuery(c)
+	if !ok {
+		return
+	}
+
+	galleries, err := h.repo.CategoriesByTags(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Failed to fetch galleries by tag: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch galleries")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Galleries retrieved", galleries)
+}
+
+// tagQuery reads ?tags= and ?match=, responding with 400 when they are invalid
+func tagQuery(c *gin.Context) (repository.TagQuery, bool) {
+	var q repository.TagQuery
+	for _, slug := range strings.Split(c.Query("tags"), ",") {
+		if slug = strings.ToLower(strings.TrimSpace(slug)); slug != "" {
+			q.Slugs = append(q.Slugs, slug)
+		}
+	}
+	if len(q.Slugs) > maxFilterTags {
+		response.Error(c, http.StatusBadRequest, "Too many tags, at most 10")
+		return q, false
+	}
+
+	switch c.DefaultQuery("match", "all") {
+	case "all":
+		q.MatchAll = true
+	case "any":
+	default:
+		response.Error(c, http.StatusBadRequest, "match must be all or any")
+		return q, false
+	}
+
+	return q, true
+}
+
+// List handles GET /api/admin/tags
+// Counts include drafts and other unpublished galleries.
+func (h *TagHandler) List(c *gin.Context) {
+	tags, err := h.repo.ListTags(c.Request.Context())
+	if err != nil {
+		log.Printf("Failed to fetch tags: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch tags")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Tags retrieved", tags)
+}
+
+// Create handles POST /api/admin/tags
+func (h *TagHandler) Create(c *gin.Context) {
+	tag, ok := bindTag(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.CreateTag(c.Request.Context(), tag); err != nil {
+		if errors.Is(err, repository.ErrDuplicateSlug) {
+			response.Error(c, http.StatusConflict, "A tag with this slug already exists")
+			return
+		}
+		log.Printf("Failed to create tag: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to create tag")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Tag created successfully", tag)
+}
+
+// Update handles PUT /api/admin/tags/:id
+// Renaming keeps every image and gallery tagged.
+func (h *TagHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	tag, ok := bindTag(c)
+	if !ok {
+		return
+	}
+	tag.ID = id
+
+	if err := h.repo.UpdateTag(c.Request.Context(), tag); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateSlug):
+			response.Error(c, http.StatusConflict, "A tag with this slug already exists")
+		case errors.Is(err, sql.ErrNoRows):
+			response.Error(c, http.StatusNotFound, "Tag not found")
+		default:
+			log.Printf("Failed to update tag: %v", err)
+			dbError(c, err, http.StatusInternalServerError, "Failed to update tag")
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Tag updated successfully", tag)
+}
+
+// bindTag reads a tagRequest, responding with 400 when it is invalid
+func bindTag(c *gin.Context) (*models.Tag, bool) {
+	var req tagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return nil, false
+	}
+
+	tag := &models.Tag{Name: strings.TrimSpace(req.Name), Slug: strings.TrimSpace(req.Slug)}
+	if tag.Name == "" || len(tag.Name) > maxTagNameLength {
+		response.Error(c, http.StatusBadRequest, "name is required, at most 100 characters")
+		return nil, false
+	}
+	if tag.Slug == "" {
+		tag.Slug = validator.GenerateSlug(tag.Name)
+	}
+	if !validator.IsValidSlug(tag.Slug) || len(tag.Slug) > maxTagNameLength {
+		response.Error(c, http.StatusBadRequest, "Invalid slug")
+		return nil, false
+	}
+
+	return tag, true
+}
+
+// Delete handles DELETE /api/admin/tags/:id
+func (h *TagHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	if err := h.repo.DeleteTag(c.Request.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(c, http.StatusNotFound, "Tag not found")
+			return
+		}
+		log.Printf("Failed to delete tag: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to delete tag")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Tag deleted successfully", nil)
+}
+
+// Merge handles POST /api/admin/tags/:id/merge
+// Everything tagged with one of source_ids is tagged with :id instead and
+// the source tags are deleted.
+func (h *TagHandler) Merge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	var req mergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.SourceIDs) == 0 {
+		response.Error(c, http.StatusBadRequest, "source_ids must list the tags to merge")
+		return
+	}
+
+	if err := h.repo.MergeTags(c.Request.Context(), req.SourceIDs, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(c, http.StatusNotFound, "Tag not found")
+			return
+		}
+		log.Printf("Failed to merge tags: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to merge tags")
+		return
+	}
+
+	tag, err := h.repo.GetTag(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Failed to fetch merged tag: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch merged tag")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Tags merged successfully", tag)
+}
+
+// GetImageTags handles GET /api/admin/images/:id/tags
+func (h *TagHandler) GetImageTags(c *gin.Context) {
+	h.getTags(c, "image", h.repo.ImageTags)
+}
+
+// SetImageTags handles PUT /api/admin/images/:id/tags
+func (h *TagHandler) SetImageTags(c *gin.Context) {
+	h.setTags(c, "image", h.repo.SetImageTags, h.repo.ImageTags)
+}
+
+// GetCategoryTags handles GET /api/admin/galleries/:id/tags
+func (h *TagHandler) GetCategoryTags(c *gin.Context) {
+	h.getTags(c, "gallery", h.repo.CategoryTags)
+}
+
+// SetCategoryTags handles PUT /api/admin/galleries/:id/tags
+func (h *TagHandler) SetCategoryTags(c *gin.Context) {
+	h.setTags(c, "gallery", h.repo.SetCategoryTags, h.repo.CategoryTags)
+}
+
+// getTags responds with the tags of the :id item of kind
+func (h *TagHandler) getTags(c *gin.Context, kind string, load func(ctx context.Context, id int) ([]models.Tag, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid "+kind+" ID")
+		return
+	}
+
+	tags, err := load(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Failed to fetch %s tags: %d - %v", kind, id, err)
+		dbError(c, err, http.StatusNotFound, strings.ToUpper(kind[:1])+kind[1:]+" not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Tags retrieved", tags)
+}
+
+// setTags replaces the tags of the :id item of kind and responds with them
+func (h *TagHandler) setTags(c *gin.Context, kind string, set func(ctx context.Context, id int, slugs []string) error, load func(ctx context.Context, id int) ([]models.Tag, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid "+kind+" ID")
+		return
+	}
+
+	var req tagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Tags == nil {
+		response.Error(c, http.StatusBadRequest, "tags must list tag slugs")
+		return
+	}
+
+	if err := set(c.Request.Context(), id, req.Tags); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrTagNotFound):
+			response.Error(c, http.StatusBadRequest, "Unknown tag, create it first")
+		case errors.Is(err, sql.ErrNoRows):
+			response.Error(c, http.StatusNotFound, strings.ToUpper(kind[:1])+kind[1:]+" not found")
+		default:
+			log.Printf("Failed to set %s tags: %v", kind, err)
+			dbError(c, err, http.StatusInternalServerError, "Failed to set tags")
+		}
+		return
+	}
+
+	tags, err := load(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Failed to fetch %s tags: %v", kind, err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch tags")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Tags updated successfully", tags)
+}
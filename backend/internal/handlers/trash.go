@@ -0,0 +1,135 @@
+// backend/internal/handlers/trash.go
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/internal/services"
+	"github.com/supraik/Freelance-Portfolio/pkg/response"
+)
+
+// TrashHandler handles the admin trash bin for galleries and images
+type TrashHandler struct {
+	repo    repository.GalleryStore
+	storage *services.StorageService
+}
+
+// NewTrashHandler creates a new handler
+func NewTrashHandler(repo repository.GalleryStore, storage *services.StorageService) *TrashHandler {
+	return &TrashHandler{repo: repo, storage: storage}
+}
+
+// List handles GET /api/admin/trash
+func (h *TrashHandler) List(c *gin.Context) {
+	trash, err := h.repo.ListTrash(c.Request.Context())
+	if err != nil {
+		log.Printf("Failed to fetch trash: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to fetch trash")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Trash retrieved", trash)
+}
+
+// RestoreCategory handles POST /api/admin/trash/galleries/:id/restore
+func (h *TrashHandler) RestoreCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid gallery ID")
+		return
+	}
+
+	category, err := h.repo.RestoreCategory(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(c, http.StatusNotFound, "Gallery not found in trash")
+			return
+		}
+		log.Printf("Failed to restore gallery: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to restore gallery")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Gallery restored successfully", category)
+}
+
+// RestoreImage handles POST /api/admin/trash/images/:id/restore
+func (h *TrashHandler) RestoreImage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid image ID")
+		return
+	}
+
+	image, err := h.repo.RestoreImage(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(c, http.StatusNotFound, "Image not found in trash")
+			return
+		}
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			response.Error(c, http.StatusConflict, "The image's gallery is in the trash, restore the gallery first")
+			return
+		}
+		log.Printf("Failed to restore image: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to restore image")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Image restored successfully", image)
+}
+
+// PurgeCategory handles DELETE /api/admin/trash/galleries/:id
+func (h *TrashHandler) PurgeCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid gallery ID")
+		return
+	}
+
+	unused, err := h.repo.PurgeCategory(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(c, http.StatusNotFound, "Gallery not found in trash")
+			return
+		}
+		log.Printf("Failed to purge gallery: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to purge gallery")
+		return
+	}
+
+	h.storage.DeleteUnused(unused)
+
+	response.Success(c, http.StatusOK, "Gallery permanently deleted", nil)
+}
+
+// PurgeImage handles DELETE /api/admin/trash/images/:id
+func (h *TrashHandler) PurgeImage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid image ID")
+		return
+	}
+
+	unused, err := h.repo.PurgeImage(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Error(c, http.StatusNotFound, "Image not found in trash")
+			return
+		}
+		log.Printf("Failed to purge image: %v", err)
+		dbError(c, err, http.StatusInternalServerError, "Failed to purge image")
+		return
+	}
+
+	h.storage.DeleteUnused(unused)
+
+	response.Success(c, http.StatusOK, "Image permanently deleted", nil)
+}
@@ -2,10 +2,18 @@
 package handlers
 
 import (
+	"log"
+	"mime/multipart"
 	"net/http"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/supraik/Freelance-Portfolio/internal/audit"
+	"github.com/supraik/Freelance-Portfolio/internal/imagesize"
 	"github.com/supraik/Freelance-Portfolio/internal/services"
 	"github.com/supraik/Freelance-Portfolio/pkg/response"
 )
@@ -13,11 +21,14 @@ import (
 // UploadHandler handles file upload requests
 type UploadHandler struct {
 	storage *services.StorageService
+	aspect  imagesize.Thresholds
+	audit   *audit.Recorder
 }
 
-// NewUploadHandler creates a new handler
-func NewUploadHandler(storage *services.StorageService) *UploadHandler {
-	return &UploadHandler{storage: storage}
+// NewUploadHandler creates a new handler. aspect classifies the uploaded
+// images by their size.
+func NewUploadHandler(storage *services.StorageService, aspect imagesize.Thresholds, recorder *audit.Recorder) *UploadHandler {
+	return &UploadHandler{storage: storage, aspect: aspect, audit: recorder}
 }
 
 // Upload handles POST /api/admin/upload
@@ -35,10 +46,9 @@ func (h *UploadHandler) Upload(c *gin.Context) {
 		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	h.record(c, file, url)
 
-	response.Success(c, http.StatusOK, "File uploaded successfully", gin.H{
-		"url": url,
-	})
+	response.Success(c, http.StatusOK, "File uploaded successfully", h.describe(url, file))
 }
 
 // UploadMultiple handles multiple file uploads
@@ -56,6 +66,7 @@ func (h *UploadHandler) UploadMultiple(c *gin.Context) {
 	}
 
 	var urls []string
+	var saved []gin.H
 
 	for _, file := range files {
 		url, err := h.storage.SaveFile(file)
@@ -63,10 +74,71 @@ func (h *UploadHandler) UploadMultiple(c *gin.Context) {
 			response.Error(c, http.StatusBadRequest, err.Error())
 			return
 		}
+		h.record(c, file, url)
 		urls = append(urls, url)
+		saved = append(saved, h.describe(url, file))
 	}
 
 	response.Success(c, http.StatusOK, "Files uploaded successfully", gin.H{
-		"urls": urls,
+		"urls":  urls,
+		"files": saved,
+	})
+}
+
+// describe returns a saved upload with its original file name, displayed
+// size, aspect-ratio class, camera metadata, loading placeholder and resized
+// variants, which are written here. The file is read and decoded once for
+// all of them. Files whose details cannot be read are still uploaded, just
+// without them.
+func (h *UploadHandler) describe(url string, file *multipart.FileHeader) gin.H {
+	upload, err := h.storage.Describe(url)
+	if err != nil {
+		log.Printf("Failed to read the details of %s: %v", url, err)
+	}
+
+	saved := gin.H{"url": url, "original_filename": uploadName(file.Filename), "metadata": upload.Metadata}
+	if class := h.aspect.Classify(upload.Width, upload.Height); class != "" {
+		saved["width"] = upload.Width
+		saved["height"] = upload.Height
+		saved["aspect_ratio"] = class
+	}
+	if p := upload.Placeholder; p != nil {
+		saved["blurhash"] = p.BlurHash
+		saved["lqip"] = p.LQIP
+		saved["dominant_color"] = p.DominantColor
+	}
+	if upload.Variants != nil {
+		saved["variants"] = upload.Variants
+	}
+	return saved
+}
+
+// uploadName returns the last element of a file name sent by a client,
+// without control characters and at most 255 bytes long. Browsers send the
+// bare name, other clients may include a Windows or Unix path.
+func uploadName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, `\`, "/")))
+	if name == "." || name == "/" {
+		return ""
+	}
+	for len(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+// record adds a saved upload to the audit log
+func (h *UploadHandler) record(c *gin.Context, file *multipart.FileHeader, url string) {
+	h.audit.Record(c.Request.Context(), "upload.create", "upload", url, nil, gin.H{
+		"filename": file.Filename,
+		"size":     file.Size,
+		"url":      url,
 	})
 }
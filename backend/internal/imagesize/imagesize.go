@@ -0,0 +1,128 @@
+// backend/internal/imagesize/imagesize.go
+
+// Package imagesize reads the pixel size of JPEG, PNG, GIF and WebP images
+// from their headers, without decoding the pixels, and classifies it as
+// portrait, landscape or square.
+package imagesize
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	_ "image/gif"  // register the GIF header decoder
+	_ "image/jpeg" // register the JPEG header decoder
+	_ "image/png"  // register the PNG header decoder
+	"io"
+
+	"github.com/supraik/Freelance-Portfolio/internal/exif"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// ErrMalformed is returned for image headers that cannot be parsed
+var ErrMalformed = errors.New("malformed image header")
+
+// Decode reads the stored size of the image in r. It returns zero without
+// an error when r is not a JPEG, PNG, GIF or WebP image.
+func Decode(r io.Reader) (width, height int, err error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(12)
+	if len(magic) == 12 && string(magic[:4]) == "RIFF" && string(magic[8:]) == "WEBP" {
+		return decodeWebP(br)
+	}
+
+	config, _, err := image.DecodeConfig(br)
+	switch {
+	case errors.Is(err, image.ErrFormat):
+		return 0, 0, nil
+	case err != nil:
+		return 0, 0, err
+	}
+	return config.Width, config.Height, nil
+}
+
+// decodeWebP reads the canvas size from the first chunk of a WebP file
+func decodeWebP(r io.Reader) (width, height int, err error) {
+	var header [30]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, 0, ErrMalformed
+	}
+	data := header[20:]
+
+	switch string(header[12:16]) {
+	case "VP8 ": // lossy: frame tag, start code, then 14-bit sizes
+		if !bytes.Equal(data[3:6], []byte{0x9D, 0x01, 0x2A}) {
+			return 0, 0, ErrMalformed
+		}
+		width = int(binary.LittleEndian.Uint16(data[6:]) & 0x3FFF)
+		height = int(binary.LittleEndian.Uint16(data[8:]) & 0x3FFF)
+	case "VP8L": // lossless: signature, then 14-bit sizes minus one
+		if data[0] != 0x2F {
+			return 0, 0, ErrMalformed
+		}
+		bits := binary.LittleEndian.Uint32(data[1:])
+		width = int(bits&0x3FFF) + 1
+		height = int(bits>>14&0x3FFF) + 1
+	case "VP8X": // extended: flags, then 24-bit canvas sizes minus one
+		width = int(uint32(data[4])|uint32(data[5])<<8|uint32(data[6])<<16) + 1
+		height = int(uint32(data[7])|uint32(data[8])<<8|uint32(data[9])<<16) + 1
+	default:
+		return 0, 0, ErrMalformed
+	}
+	if width == 0 || height == 0 {
+		return 0, 0, ErrMalformed
+	}
+	return width, height, nil
+}
+
+// Displayed reads the size the image in r is displayed at: its stored size
+// turned by its EXIF orientation. Unreadable EXIF data is taken as no
+// orientation.
+func Displayed(r io.ReadSeeker) (width, height int, err error) {
+	if width, height, err = Decode(r); err != nil || width == 0 {
+		return width, height, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+	if meta, err := exif.Decode(r); err == nil && meta != nil {
+		width, height = Oriented(width, height, meta.Orientation)
+	}
+	return width, height, nil
+}
+
+// Oriented returns the size an image is displayed at once its EXIF
+// orientation is applied: orientations 5 to 8 turn it a quarter
+func Oriented(width, height, orientation int) (int, int) {
+	if orientation >= 5 && orientation <= 8 {
+		return height, width
+	}
+	return width, height
+}
+
+// Thresholds decide the aspect-ratio class of an image from its width
+// divided by its height. Ratios between the two are square.
+type Thresholds struct {
+	Landscape float64 // ratios at or above are landscape
+	Portrait  float64 // ratios at or below are portrait
+}
+
+// DefaultThresholds treat images within about 10% of square as square
+var DefaultThresholds = Thresholds{Landscape: 1.1, Portrait: 0.9}
+
+// Classify returns the aspect-ratio class of an image of the given size,
+// or "" when the size is unknown
+func (t Thresholds) Classify(width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	ratio := float64(width) / float64(height)
+	switch {
+	case ratio >= t.Landscape:
+		return models.AspectLandscape
+	case ratio <= t.Portrait:
+		return models.AspectPortrait
+	}
+	return models.AspectSquare
+}
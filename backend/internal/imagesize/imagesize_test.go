@@ -0,0 +1,123 @@
+// backend/internal/imagesize/imagesize_test.go
+package imagesize_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/supraik/Freelance-Portfolio/internal/exif/exiftest"
+	"github.com/supraik/Freelance-Portfolio/internal/imagesize"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// webp returns a WebP file whose first chunk is kind with data
+func webp(kind string, data []byte) []byte {
+	file := []byte("RIFF\x00\x00\x00\x00WEBP" + kind)
+	file = binary.LittleEndian.AppendUint32(file, uint32(len(data)))
+	return append(file, data...)
+}
+
+func TestDecode(t *testing.T) {
+	var pngFile, gifFile bytes.Buffer
+	if err := png.Encode(&pngFile, image.NewGray(image.Rect(0, 0, 30, 20))); err != nil {
+		t.Fatal(err)
+	}
+	if err := gif.Encode(&gifFile, image.NewGray(image.Rect(0, 0, 7, 9)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// VP8L packs width-1 and height-1 into 14 bits each
+	lossless := binary.LittleEndian.AppendUint32([]byte{0x2F}, uint32(400-1)|uint32(300-1)<<14)
+	// VP8X keeps 24-bit canvas sizes minus one after 4 bytes of flags
+	extended := []byte{0, 0, 0, 0, 0x7F, 0x0C, 0, 0x37, 0x04, 0}
+
+	tests := []struct {
+		name          string
+		file          []byte
+		width, height int
+	}{
+		{"jpeg", exiftest.JPEG(t, image.NewGray(image.Rect(0, 0, 16, 8)), nil), 16, 8},
+		{"png", pngFile.Bytes(), 30, 20},
+		{"gif", gifFile.Bytes(), 7, 9},
+		{"webp lossy", webp("VP8 ", []byte{0, 0, 0, 0x9D, 0x01, 0x2A, 0x80, 0x02, 0xE0, 0x01}), 640, 480},
+		{"webp lossless", webp("VP8L", append(lossless, 0, 0, 0, 0, 0)), 400, 300},
+		{"webp extended", webp("VP8X", extended), 3200, 1080},
+		{"not an image", []byte("hello, world"), 0, 0},
+	}
+	for _, tt := range tests {
+		width, height, err := imagesize.Decode(bytes.NewReader(tt.file))
+		if err != nil || width != tt.width || height != tt.height {
+			t.Errorf("%s: Decode = %d×%d, %v, want %d×%d", tt.name, width, height, err, tt.width, tt.height)
+		}
+	}
+}
+
+func TestDecodeMalformedWebP(t *testing.T) {
+	for name, file := range map[string][]byte{
+		"truncated":     webp("VP8 ", []byte{0, 0, 0}),
+		"no start code": webp("VP8 ", []byte{0, 0, 0, 1, 2, 3, 0x80, 0x02, 0xE0, 0x01}),
+		"zero size":     webp("VP8 ", []byte{0, 0, 0, 0x9D, 0x01, 0x2A, 0, 0, 0, 0}),
+		"no signature":  webp("VP8L", []byte{0x2E, 0, 0, 0, 0, 0, 0, 0, 0, 0}),
+		"unknown chunk": webp("ALPH", make([]byte, 10)),
+	} {
+		if width, height, err := imagesize.Decode(bytes.NewReader(file)); !errors.Is(err, imagesize.ErrMalformed) {
+			t.Errorf("%s: Decode = %d×%d, %v, want ErrMalformed", name, width, height, err)
+		}
+	}
+}
+
+func TestDisplayed(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 40, 20))
+	for orientation := 0; orientation <= 9; orientation++ {
+		var block []byte
+		if orientation > 0 {
+			block = exiftest.Orientation(orientation)
+		}
+		width, height, err := imagesize.Displayed(bytes.NewReader(exiftest.JPEG(t, img, block)))
+		if err != nil {
+			t.Fatalf("orientation %d: %v", orientation, err)
+		}
+		wantWidth, wantHeight := 40, 20
+		if orientation >= 5 && orientation <= 8 {
+			wantWidth, wantHeight = 20, 40
+		}
+		if width != wantWidth || height != wantHeight {
+			t.Errorf("orientation %d: Displayed = %d×%d, want %d×%d", orientation, width, height, wantWidth, wantHeight)
+		}
+	}
+}
+
+func TestDisplayedIgnoresBadEXIF(t *testing.T) {
+	block := exiftest.Orientation(6)
+	binary.LittleEndian.PutUint32(block[4:], 0xFFFFFF00) // IFD0 past the end
+	width, height, err := imagesize.Displayed(bytes.NewReader(exiftest.JPEG(t, image.NewGray(image.Rect(0, 0, 40, 20)), block)))
+	if err != nil || width != 40 || height != 20 {
+		t.Errorf("Displayed = %d×%d, %v, want 40×20 as stored", width, height, err)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		width, height int
+		want          string
+	}{
+		{0, 100, ""},
+		{100, -1, ""},
+		{110, 100, models.AspectLandscape},
+		{109, 100, models.AspectSquare},
+		{100, 100, models.AspectSquare},
+		{91, 100, models.AspectSquare},
+		{90, 100, models.AspectPortrait},
+		{2000, 3000, models.AspectPortrait},
+	}
+	for _, tt := range tests {
+		if got := imagesize.DefaultThresholds.Classify(tt.width, tt.height); got != tt.want {
+			t.Errorf("Classify(%d, %d) = %q, want %q", tt.width, tt.height, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// backend/internal/imaging/imaging.go
+
+// Package imaging transforms decoded images for the placeholder and
+// variant generators
+package imaging
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"  // register the GIF decoder
+	_ "image/jpeg" // register the JPEG decoder
+	_ "image/png"  // register the PNG decoder
+	"io"
+	"math"
+)
+
+// MaxPixels caps the images decoded, so a small file claiming a huge size
+// cannot exhaust memory
+const MaxPixels = 100_000_000
+
+// ErrTooLarge is returned for images with more pixels than MaxPixels
+var ErrTooLarge = errors.New("image too large to decode")
+
+// Decode decodes the image in r and names its format, refusing images over
+// MaxPixels before reading their pixels. It returns nil without an error
+// for formats the standard library cannot decode, WebP included.
+func Decode(r io.ReadSeeker) (image.Image, string, error) {
+	config, _, err := image.DecodeConfig(r)
+	switch {
+	case errors.Is(err, image.ErrFormat):
+		return nil, "", nil
+	case err != nil:
+		return nil, "", err
+	case config.Width <= 0 || config.Height <= 0:
+		return nil, "", nil
+	case config.Width*config.Height > MaxPixels:
+		return nil, "", ErrTooLarge
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, "", err
+	}
+	return image.Decode(r)
+}
+
+// Orient applies an EXIF orientation (2 to 8) to img
+func Orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	turned := orientation >= 5
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	if turned {
+		out = image.NewRGBA(image.Rect(0, 0, h, w))
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored
+				dx, dy = w-1-x, y
+			case 3: // upside down
+				dx, dy = w-1-x, h-1-y
+			case 4: // upside down, mirrored
+				dx, dy = x, h-1-y
+			case 5: // mirrored, turned left
+				dx, dy = y, x
+			case 6: // turned right
+				dx, dy = h-1-y, x
+			case 7: // mirrored, turned right
+				dx, dy = h-1-y, w-1-x
+			case 8: // turned left
+				dx, dy = y, w-1-x
+			}
+			out.SetRGBA(dx, dy, img.RGBAAt(x+img.Rect.Min.X, y+img.Rect.Min.Y))
+		}
+	}
+	return out
+}
+
+// Resize shrinks img to width by height pixels, each the average of the
+// source pixels it covers. Sizes larger than img are clamped to it.
+func Resize(img image.Image, width, height int) *image.RGBA {
+	b := img.Bounds()
+	width, height = max(1, min(width, b.Dx())), max(1, min(height, b.Dy()))
+	xs, ys := spread(b.Dx(), width), spread(b.Dy(), height)
+
+	// Rows are read once each and added to the output rows they fall in
+	sums := make([]float32, width*height*4)
+	row := make([]uint8, b.Dx()*4)
+	scaled := make([]float32, width*4)
+	for sy := 0; sy < b.Dy(); sy++ {
+		readRow(img, b.Min.Y+sy, row)
+		clear(scaled)
+		for sx, parts := range xs {
+			p := row[sx*4 : sx*4+4]
+			for _, part := range parts {
+				s := scaled[part.index*4 : part.index*4+4]
+				s[0] += part.weight * float32(p[0])
+				s[1] += part.weight * float32(p[1])
+				s[2] += part.weight * float32(p[2])
+				s[3] += part.weight * float32(p[3])
+			}
+		}
+		for _, part := range ys[sy] {
+			out := sums[part.index*width*4 : (part.index+1)*width*4]
+			for i, v := range scaled {
+				out[i] += part.weight * v
+			}
+		}
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i, v := range sums {
+		out.Pix[i] = uint8(math.Min(255, math.Max(0, math.Round(float64(v)))))
+	}
+	return out
+}
+
+// part is the share of one source pixel in one output pixel
+type part struct {
+	index  int
+	weight float32
+}
+
+// spread returns for each of src pixels on an axis the output pixels, of
+// dst, it overlaps and by how much. The weights of each output pixel add
+// up to 1.
+func spread(src, dst int) [][]part {
+	scale := float64(src) / float64(dst)
+	parts := make([][]part, src)
+	for i := range parts {
+		first := int(float64(i) / scale)
+		last := min(dst-1, int(math.Ceil(float64(i+1)/scale))-1)
+		for j := first; j <= last; j++ {
+			overlap := math.Min(float64(i+1), float64(j+1)*scale) - math.Max(float64(i), float64(j)*scale)
+			if overlap > 0 {
+				parts[i] = append(parts[i], part{j, float32(overlap / scale)})
+			}
+		}
+	}
+	return parts
+}
+
+// readRow copies row y of img into row as premultiplied RGBA, avoiding
+// the per-pixel conversions of At for the types the decoders return
+func readRow(img image.Image, y int, row []uint8) {
+	b := img.Bounds()
+	switch p := img.(type) {
+	case *image.YCbCr:
+		for x := b.Min.X; x < b.Max.X; x++ {
+			yi, ci := p.YOffset(x, y), p.COffset(x, y)
+			r, g, bl := color.YCbCrToRGB(p.Y[yi], p.Cb[ci], p.Cr[ci])
+			i := (x - b.Min.X) * 4
+			row[i], row[i+1], row[i+2], row[i+3] = r, g, bl, 0xFF
+		}
+	case *image.RGBA:
+		copy(row, p.Pix[p.PixOffset(b.Min.X, y):])
+	case *image.Gray:
+		for x := b.Min.X; x < b.Max.X; x++ {
+			v := p.Pix[p.PixOffset(x, y)]
+			i := (x - b.Min.X) * 4
+			row[i], row[i+1], row[i+2], row[i+3] = v, v, v, 0xFF
+		}
+	default:
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			i := (x - b.Min.X) * 4
+			row[i], row[i+1], row[i+2], row[i+3] = c.R, c.G, c.B, c.A
+		}
+	}
+}
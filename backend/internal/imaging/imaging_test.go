@@ -0,0 +1,133 @@
+// backend/internal/imaging/imaging_test.go
+package imaging_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/supraik/Freelance-Portfolio/internal/exif/exiftest"
+	"github.com/supraik/Freelance-Portfolio/internal/imaging"
+)
+
+func TestOrient(t *testing.T) {
+	// The quarter of the 4×2 quadrants image each orientation shows top
+	// left and top right, as column and row
+	tests := []struct {
+		orientation       int
+		width, height     int
+		topLeft, topRight [2]int
+	}{
+		{1, 4, 2, [2]int{0, 0}, [2]int{1, 0}},
+		{2, 4, 2, [2]int{1, 0}, [2]int{0, 0}},
+		{3, 4, 2, [2]int{1, 1}, [2]int{0, 1}},
+		{4, 4, 2, [2]int{0, 1}, [2]int{1, 1}},
+		{5, 2, 4, [2]int{0, 0}, [2]int{0, 1}},
+		{6, 2, 4, [2]int{0, 1}, [2]int{0, 0}},
+		{7, 2, 4, [2]int{1, 1}, [2]int{1, 0}},
+		{8, 2, 4, [2]int{1, 0}, [2]int{1, 1}},
+		{0, 4, 2, [2]int{0, 0}, [2]int{1, 0}},
+		{9, 4, 2, [2]int{0, 0}, [2]int{1, 0}},
+	}
+	for _, tt := range tests {
+		out := imaging.Orient(exiftest.Quadrants(4, 2), tt.orientation)
+		if b := out.Bounds(); b.Dx() != tt.width || b.Dy() != tt.height {
+			t.Errorf("orientation %d: size %dx%d, want %dx%d", tt.orientation, b.Dx(), b.Dy(), tt.width, tt.height)
+			continue
+		}
+		if got, want := out.RGBAAt(0, 0), exiftest.QuadrantColor(tt.topLeft[0], tt.topLeft[1]); got != want {
+			t.Errorf("orientation %d: top left %v, want %v", tt.orientation, got, want)
+		}
+		if got, want := out.RGBAAt(tt.width-1, 0), exiftest.QuadrantColor(tt.topRight[0], tt.topRight[1]); got != want {
+			t.Errorf("orientation %d: top right %v, want %v", tt.orientation, got, want)
+		}
+	}
+}
+
+func TestOrientSubImage(t *testing.T) {
+	whole := exiftest.Quadrants(4, 4)
+	bottomRight := whole.SubImage(image.Rect(2, 2, 4, 4)).(*image.RGBA)
+	out := imaging.Orient(bottomRight, 6)
+	if got, want := out.RGBAAt(0, 0), exiftest.QuadrantColor(1, 1); got != want {
+		t.Errorf("top left %v, want %v", got, want)
+	}
+}
+
+func TestResize(t *testing.T) {
+	checker := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if (x+y)%2 == 0 {
+				checker.SetRGBA(x, y, color.RGBA{0xFF, 0xFF, 0xFF, 0xFF})
+			} else {
+				checker.SetRGBA(x, y, color.RGBA{0, 0, 0, 0xFF})
+			}
+		}
+	}
+
+	tests := []struct {
+		name          string
+		width, height int
+		wantW, wantH  int
+	}{
+		{"halved", 2, 2, 2, 2},
+		{"uneven", 3, 3, 3, 3},
+		{"one pixel", 1, 1, 1, 1},
+		{"clamped", 10, 8, 4, 4},
+		{"zero", 0, -1, 1, 1},
+	}
+	for _, tt := range tests {
+		out := imaging.Resize(checker, tt.width, tt.height)
+		if b := out.Bounds(); b.Dx() != tt.wantW || b.Dy() != tt.wantH {
+			t.Errorf("%s: size %dx%d, want %dx%d", tt.name, b.Dx(), b.Dy(), tt.wantW, tt.wantH)
+		}
+	}
+
+	// Each pixel of a halved checkerboard averages two black and two white
+	out := imaging.Resize(checker, 2, 2)
+	for i := 0; i < len(out.Pix); i += 4 {
+		if p := out.Pix[i : i+4]; p[0] != 128 || p[1] != 128 || p[2] != 128 || p[3] != 0xFF {
+			t.Fatalf("halved checkerboard pixel %v, want mid grey", p)
+		}
+	}
+}
+
+// Uneven scales must still weigh every source pixel fully: a flat image
+// stays flat, whatever the decoder's pixel type
+func TestResizeKeepsFlatImages(t *testing.T) {
+	rgba := image.NewRGBA(image.Rect(0, 0, 7, 5))
+	gray := image.NewGray(image.Rect(0, 0, 7, 5))
+	ycbcr := image.NewYCbCr(image.Rect(0, 0, 7, 5), image.YCbCrSubsampleRatio420)
+	nrgba := image.NewNRGBA(image.Rect(0, 0, 7, 5))
+	for i := range rgba.Pix {
+		rgba.Pix[i], nrgba.Pix[i] = 0x64, 0x64
+	}
+	for i := range gray.Pix {
+		gray.Pix[i] = 0x64
+	}
+	for i := range ycbcr.Y {
+		ycbcr.Y[i] = 0x64
+	}
+	for i := range ycbcr.Cb {
+		ycbcr.Cb[i], ycbcr.Cr[i] = 0x80, 0x80
+	}
+	for i := 3; i < len(rgba.Pix); i += 4 {
+		rgba.Pix[i], nrgba.Pix[i] = 0xFF, 0xFF
+	}
+
+	for name, img := range map[string]image.Image{"rgba": rgba, "gray": gray, "ycbcr": ycbcr, "nrgba": nrgba} {
+		for _, size := range [][2]int{{3, 2}, {6, 4}, {4, 3}} {
+			out := imaging.Resize(img, size[0], size[1])
+			want := out.Pix[0]
+			for i := 0; i < len(out.Pix); i += 4 {
+				if p := out.Pix[i : i+4]; p[0] != want || p[1] != want || p[2] != want || p[3] != 0xFF {
+					t.Errorf("%s to %v: pixel %v, want all %d", name, size, p, want)
+					break
+				}
+			}
+			if want < 0x63 || want > 0x65 {
+				t.Errorf("%s to %v: level %d, want about %d", name, size, want, 0x64)
+			}
+		}
+	}
+}
@@ -0,0 +1,25 @@
+// backend/internal/middleware/audit.go
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/supraik/Freelance-Portfolio/internal/audit"
+)
+
+// AuditActor attaches the authenticated admin, client IP and user agent to
+// the request context so audited repositories know who made a change.
+// It must run after AuthRequired.
+func AuditActor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := audit.Actor{
+			UserID:    c.GetInt("user_id"),
+			Email:     c.GetString("email"),
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), actor))
+
+		c.Next()
+	}
+}
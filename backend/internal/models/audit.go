@@ -0,0 +1,21 @@
+// backend/internal/models/audit.go
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records one change made through the admin API
+type AuditEntry struct {
+	ID         int             `json:"id" db:"id"`
+	ActorID    *int            `json:"actor_id" db:"actor_id"`
+	ActorEmail string          `json:"actor_email" db:"actor_email"`
+	Action     string          `json:"action" db:"action"`           // e.g. gallery.update
+	EntityType string          `json:"entity_type" db:"entity_type"` // gallery, image, section, contact, upload, preview
+	EntityID   string          `json:"entity_id" db:"entity_id"`
+	Changes    json.RawMessage `json:"changes" db:"changes"` // field -> {"before": ..., "after": ...}
+	IP         string          `json:"ip" db:"ip"`
+	UserAgent  string          `json:"user_agent" db:"user_agent"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
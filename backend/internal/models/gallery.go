@@ -11,18 +11,116 @@ type GalleryCategory struct {
 	Description  string         `json:"description"`
 	CoverImage   string         `json:"cover_image"`
 	DisplayOrder int            `json:"display_order"`
+	Status       string         `json:"status"` // draft, published or archived
+	PublishAt    *time.Time     `json:"publish_at"`
+	UnpublishAt  *time.Time     `json:"unpublish_at"`
+	Access       string         `json:"access"` // public, unlisted or password
 	Images       []GalleryImage `json:"images"`
+	Version      int            `json:"version"` // bumped by every update, see repository.ErrVersionConflict
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    *time.Time     `json:"deleted_at,omitempty"`
 }
 
+// Gallery statuses
+const (
+	GalleryDraft     = "draft"
+	GalleryPublished = "published"
+	GalleryArchived  = "archived"
+)
+
+// Gallery access modes. Only public galleries are listed; unlisted and
+// password galleries are found by slug, the latter with the password.
+const (
+	GalleryAccessPublic   = "public"
+	GalleryAccessUnlisted = "unlisted"
+	GalleryAccessPassword = "password"
+)
+
+// IsPublic reports whether the public site shows the gallery at now: it
+// must be published and inside its publish_at/unpublish_at window
+func (c *GalleryCategory) IsPublic(now time.Time) bool {
+	return c.Status == GalleryPublished &&
+		(c.PublishAt == nil || !c.PublishAt.After(now)) &&
+		(c.UnpublishAt == nil || c.UnpublishAt.After(now))
+}
+
+// IsListed reports whether the gallery appears in public lists, search and
+// tag queries at now: it must be public and have public access
+func (c *GalleryCategory) IsListed(now time.Time) bool {
+	return c.IsPublic(now) && c.Access == GalleryAccessPublic
+}
+
+// Aspect-ratio classes of gallery images. Images of known size are
+// classified by the server, the others keep the class they were sent with.
+const (
+	AspectPortrait  = "portrait"
+	AspectLandscape = "landscape"
+	AspectSquare    = "square"
+)
+
 // GalleryImage represents an image in a gallery
 type GalleryImage struct {
-	ID           int       `json:"id"`
-	CategoryID   int       `json:"category_id"`
-	Src          string    `json:"src" validate:"required"`
-	Alt          string    `json:"alt"`
-	AspectRatio  string    `json:"aspect_ratio"` // "portrait", "landscape", "square"
-	DisplayOrder int       `json:"display_order"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           int            `json:"id"`
+	CategoryID   int            `json:"category_id"`
+	Src          string         `json:"src" validate:"required"`
+	Filename     string         `json:"original_filename,omitempty"` // name of the uploaded file, empty when unknown
+	Alt          string         `json:"alt"`
+	AspectRatio  string         `json:"aspect_ratio"`     // AspectPortrait, AspectLandscape or AspectSquare
+	Width        int            `json:"width,omitempty"`  // pixels as displayed, 0 when unknown
+	Height       int            `json:"height,omitempty"` // pixels as displayed, 0 when unknown
+	Metadata     *ImageMetadata `json:"metadata,omitempty"`
+	DisplayOrder int            `json:"display_order"`
+	Version      int            `json:"version"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    *time.Time     `json:"deleted_at,omitempty"`
+	ImagePlaceholder
+	Variants []ImageVariant `json:"variants,omitempty"` // smaller copies for srcset, nil when none were made
+}
+
+// ImageMetadata is what the camera recorded about a photo, read from its
+// EXIF and XMP data on upload. Missing values are left out.
+type ImageMetadata struct {
+	CameraMake    string     `json:"camera_make,omitempty"`
+	CameraModel   string     `json:"camera_model,omitempty"`
+	Lens          string     `json:"lens,omitempty"`
+	FocalLength   float64    `json:"focal_length,omitempty"`      // millimetres
+	FocalLength35 int        `json:"focal_length_35mm,omitempty"` // 35mm equivalent
+	Aperture      float64    `json:"aperture,omitempty"`          // f-number
+	ExposureTime  string     `json:"exposure_time,omitempty"`     // seconds, e.g. "1/250"
+	ISO           int        `json:"iso,omitempty"`
+	CapturedAt    *time.Time `json:"captured_at,omitempty"` // UTC when the camera recorded no offset
+	Orientation   int        `json:"orientation,omitempty"` // EXIF orientation, 1 to 8
+}
+
+// IsEmpty reports whether no value was found
+func (m *ImageMetadata) IsEmpty() bool {
+	return *m == ImageMetadata{}
+}
+
+// ImagePlaceholder is what the site shows while an image loads, computed
+// from the image file. Its fields are empty for images that could not be
+// decoded (e.g. WebP).
+type ImagePlaceholder struct {
+	BlurHash      string `json:"blurhash,omitempty"`
+	LQIP          string `json:"lqip,omitempty"`           // data:image/jpeg;base64 URI of a tiny preview
+	DominantColor string `json:"dominant_color,omitempty"` // #rrggbb
+}
+
+// ImageVariant is a resized copy of a locally uploaded image. An image's
+// variants of one type make up a srcset: "url 480w, url 960w".
+type ImageVariant struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Type   string `json:"type"` // image/jpeg, image/png or image/webp
+}
+
+// GalleryTrash lists soft-deleted galleries and images.
+// Trashed galleries carry the images that were trashed along with them;
+// Images holds images trashed on their own from galleries that still exist.
+type GalleryTrash struct {
+	Categories []GalleryCategory `json:"categories"`
+	Images     []GalleryImage    `json:"images"`
 }
@@ -0,0 +1,21 @@
+// backend/internal/models/gallery_access.go
+package models
+
+import "time"
+
+// GalleryAccessEntry records one unlock attempt or view of a password gallery
+type GalleryAccessEntry struct {
+	ID         int       `json:"id"`
+	CategoryID int       `json:"category_id"`
+	Event      string    `json:"event"` // unlocked, denied or viewed
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Gallery access events
+const (
+	AccessUnlocked = "unlocked"
+	AccessDenied   = "denied"
+	AccessViewed   = "viewed"
+)
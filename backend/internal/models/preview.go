@@ -0,0 +1,20 @@
+// backend/internal/models/preview.go
+package models
+
+import "time"
+
+// PreviewLink lets whoever holds its token view one gallery before it is public
+type PreviewLink struct {
+	ID         int        `json:"id"`
+	CategoryID int        `json:"category_id"`
+	Note       string     `json:"note"` // who the link was sent to, for the admin list
+	CreatedBy  *int       `json:"created_by"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the link still grants access at now
+func (l *PreviewLink) IsActive(now time.Time) bool {
+	return l.RevokedAt == nil && l.ExpiresAt.After(now)
+}
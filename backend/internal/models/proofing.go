@@ -0,0 +1,34 @@
+// backend/internal/models/proofing.go
+package models
+
+import "time"
+
+// ProofingSettings puts a gallery in proofing mode
+type ProofingSettings struct {
+	CategoryID int       `json:"category_id"`
+	MaxPicks   int       `json:"max_picks"` // 0 means no limit
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ProofingClient is someone invited to pick images from a proofing gallery
+type ProofingClient struct {
+	ID          int        `json:"id"`
+	CategoryID  int        `json:"category_id"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	SubmittedAt *time.Time `json:"submitted_at"` // set once the selection is final
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ProofingMark is a client's favorite flag and comment on one image
+type ProofingMark struct {
+	ClientID  int       `json:"client_id"`
+	ImageID   int       `json:"image_id"`
+	Src       string    `json:"src"`
+	Filename  string    `json:"original_filename,omitempty"` // of the image, see GalleryImage
+	Favorite  bool      `json:"favorite"`
+	Comment   string    `json:"comment"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
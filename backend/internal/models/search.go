@@ -0,0 +1,37 @@
+// backend/internal/models/search.go
+package models
+
+// GallerySearchHit is a gallery matching a search
+type GallerySearchHit struct {
+	ID         int     `json:"id"`
+	Slug       string  `json:"slug"`
+	Title      string  `json:"title"`
+	CoverImage string  `json:"cover_image"`
+	Snippet    string  `json:"snippet"` // HTML-escaped, matches wrapped in <mark>
+	Rank       float64 `json:"rank"`
+}
+
+// ImageSearchHit is an image matching a search
+type ImageSearchHit struct {
+	ID           int     `json:"id"`
+	CategoryID   int     `json:"category_id"`
+	CategorySlug string  `json:"category_slug"`
+	Src          string  `json:"src"`
+	Alt          string  `json:"alt"`
+	Snippet      string  `json:"snippet"`
+	Rank         float64 `json:"rank"`
+}
+
+// ContentSearchResults are the public portfolio content matching a search,
+// best matches first
+type ContentSearchResults struct {
+	Galleries []GallerySearchHit `json:"galleries"`
+	Images    []ImageSearchHit   `json:"images"`
+}
+
+// ContactSearchHit is a contact message matching an inbox search
+type ContactSearchHit struct {
+	ContactMessage
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
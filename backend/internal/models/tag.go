@@ -0,0 +1,47 @@
+// backend/internal/models/tag.go
+package models
+
+import "time"
+
+// Tag groups images and galleries across categories
+type Tag struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name" validate:"required,max=100"`
+	Slug      string    `json:"slug" validate:"required,slug,max=100"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TagUsage is a tag with the number of images and galleries carrying it
+type TagUsage struct {
+	Tag
+	ImageCount   int `json:"image_count"`
+	GalleryCount int `json:"gallery_count"`
+}
+
+// TagFacet is a tag with the number of results of a filtered query carrying it
+type TagFacet struct {
+	Tag
+	Count int `json:"count"`
+}
+
+// TaggedImage is a public image found by tag
+type TaggedImage struct {
+	GalleryImage
+	CategorySlug string `json:"category_slug"`
+	Tags         []Tag  `json:"tags"`
+}
+
+// TaggedImages is a page of images matching a tag filter. Facets count
+// the tags of every match, not just the page, most used first.
+type TaggedImages struct {
+	Images []TaggedImage `json:"images"`
+	Total  int           `json:"total"`
+	Facets []TagFacet    `json:"facets"`
+}
+
+// TaggedGallery is a public gallery found by tag, without its images
+type TaggedGallery struct {
+	GalleryCategory
+	Tags []Tag `json:"tags"`
+}
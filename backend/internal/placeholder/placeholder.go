@@ -0,0 +1,122 @@
+// backend/internal/placeholder/placeholder.go
+
+// Package placeholder computes what the site shows while a photo loads: a
+// BlurHash, a tiny base64 JPEG (LQIP) and the dominant colour. JPEG, PNG
+// and GIF images are supported; the standard library cannot decode WebP.
+package placeholder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register the GIF decoder
+	"image/jpeg"
+	_ "image/png" // register the PNG decoder
+	"io"
+
+	"github.com/supraik/Freelance-Portfolio/internal/exif"
+	"github.com/supraik/Freelance-Portfolio/internal/imaging"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+const (
+	hashSide    = 32 // longer side of the thumbnail the BlurHash and colour come from
+	lqipSide    = 16 // longer side of the LQIP
+	lqipQuality = 50
+)
+
+// Generate computes the placeholder of the image in r, turned by its EXIF
+// orientation like browsers show it. It returns nil without an error for
+// formats it cannot decode.
+func Generate(r io.ReadSeeker) (*models.ImagePlaceholder, error) {
+	img, _, err := imaging.Decode(r)
+	if img == nil || err != nil {
+		return nil, err
+	}
+
+	orientation := 1
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if meta, err := exif.Decode(r); err == nil && meta != nil && meta.Orientation != 0 {
+		orientation = meta.Orientation
+	}
+	return FromImage(img, orientation)
+}
+
+// FromImage computes the placeholder of an image already decoded, turned
+// by orientation (1 to 8, as in EXIF)
+func FromImage(img image.Image, orientation int) (*models.ImagePlaceholder, error) {
+	thumb := imaging.Orient(shrink(img, hashSide), orientation)
+	xComponents, yComponents := 4, 3
+	if thumb.Bounds().Dy() > thumb.Bounds().Dx() {
+		xComponents, yComponents = 3, 4
+	}
+
+	var lqip bytes.Buffer
+	if err := jpeg.Encode(&lqip, imaging.Orient(shrink(img, lqipSide), orientation), &jpeg.Options{Quality: lqipQuality}); err != nil {
+		return nil, err
+	}
+
+	return &models.ImagePlaceholder{
+		BlurHash:      blurHash(thumb, xComponents, yComponents),
+		LQIP:          "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(lqip.Bytes()),
+		DominantColor: dominant(thumb),
+	}, nil
+}
+
+// shrink returns img scaled to side pixels on its longer side, each pixel
+// the average of a grid of samples from the area it covers
+func shrink(img image.Image, side int) *image.RGBA {
+	b := img.Bounds()
+	width, height := side, side
+	if b.Dx() >= b.Dy() {
+		height = max(1, (b.Dy()*side+b.Dx()/2)/b.Dx())
+	} else {
+		width = max(1, (b.Dx()*side+b.Dy()/2)/b.Dy())
+	}
+	width, height = min(width, b.Dx()), min(height, b.Dy())
+
+	const samples = 8 // per axis and output pixel
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var r, g, bl, n uint32
+			for sy := 0; sy < samples; sy++ {
+				py := b.Min.Y + (y*samples+sy)*b.Dy()/(height*samples)
+				for sx := 0; sx < samples; sx++ {
+					px := b.Min.X + (x*samples+sx)*b.Dx()/(width*samples)
+					c := color.RGBAModel.Convert(img.At(px, py)).(color.RGBA)
+					r, g, bl, n = r+uint32(c.R), g+uint32(c.G), bl+uint32(c.B), n+1
+				}
+			}
+			out.SetRGBA(x, y, color.RGBA{uint8(r / n), uint8(g / n), uint8(bl / n), 0xFF})
+		}
+	}
+	return out
+}
+
+// dominant returns the most common colour of img as #rrggbb: pixels are
+// grouped into coarse colour buckets and the fullest bucket is averaged
+func dominant(img *image.RGBA) string {
+	type bucket struct{ r, g, b, n int }
+	var buckets [512]bucket // 3 bits per channel
+	best := 0
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			c := img.RGBAAt(x, y)
+			i := int(c.R>>5)<<6 | int(c.G>>5)<<3 | int(c.B>>5)
+			buckets[i].r += int(c.R)
+			buckets[i].g += int(c.G)
+			buckets[i].b += int(c.B)
+			buckets[i].n++
+			if buckets[i].n > buckets[best].n {
+				best = i
+			}
+		}
+	}
+	b := buckets[best]
+	return fmt.Sprintf("#%02x%02x%02x", b.r/b.n, b.g/b.n, b.b/b.n)
+}
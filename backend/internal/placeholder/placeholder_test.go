@@ -0,0 +1,157 @@
+// backend/internal/placeholder/placeholder_test.go
+package placeholder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/supraik/Freelance-Portfolio/internal/exif/exiftest"
+	"github.com/supraik/Freelance-Portfolio/internal/imaging"
+)
+
+func solid(width, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// Vectors worked out by hand from the BlurHash spec. Its basis is sampled
+// at pixel corners, so even a flat image has odd components, except black
+// where every factor is zero and encodes as "fQ".
+func TestBlurHashVectors(t *testing.T) {
+	black, white, red := color.RGBA{0, 0, 0, 0xFF}, color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}, color.RGBA{0xFF, 0, 0, 0xFF}
+	tests := []struct {
+		img          *image.RGBA
+		xComp, yComp int
+		want         string
+	}{
+		{solid(8, 8, black), 1, 1, "000000"},
+		{solid(8, 8, white), 1, 1, "00TSUA"},
+		{solid(8, 6, black), 4, 3, "L00000" + strings.Repeat("fQ", 11)},
+		{solid(6, 8, white), 3, 4, "TsTSUA~qfQ_3t7fQfQfQfQ_3t7fQ"},
+		{solid(8, 6, red), 4, 3, "LsTI:j]9fQ]9|csUfQsUfQfQfQfQ"},
+	}
+	for _, tt := range tests {
+		if got := blurHash(tt.img, tt.xComp, tt.yComp); got != tt.want {
+			t.Errorf("blurHash(%v %dx%d) = %s, want %s", tt.img.RGBAAt(0, 0), tt.xComp, tt.yComp, got, tt.want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	file := encodePNG(t, solid(40, 20, color.RGBA{0x33, 0x66, 0x99, 0xFF}))
+	p, err := Generate(bytes.NewReader(file))
+	if err != nil || p == nil {
+		t.Fatalf("Generate = %v, %v", p, err)
+	}
+	if p.DominantColor != "#336699" {
+		t.Errorf("DominantColor = %s, want #336699", p.DominantColor)
+	}
+	if want := blurHash(solid(32, 16, color.RGBA{0x33, 0x66, 0x99, 0xFF}), 4, 3); p.BlurHash != want {
+		t.Errorf("BlurHash = %s, want %s", p.BlurHash, want)
+	}
+	if lqip := decodeLQIP(t, p.LQIP); lqip.Bounds().Dx() != 16 || lqip.Bounds().Dy() != 8 {
+		t.Errorf("LQIP is %v, want 16x8", lqip.Bounds())
+	}
+}
+
+func TestGenerateDominant(t *testing.T) {
+	img := solid(40, 40, color.RGBA{0x10, 0x80, 0x20, 0xFF})
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 40; x++ {
+			img.SetRGBA(x, y, color.RGBA{0xF0, 0xF0, 0xF0, 0xFF})
+		}
+	}
+	p, err := Generate(bytes.NewReader(encodePNG(t, img)))
+	if err != nil || p == nil {
+		t.Fatalf("Generate = %v, %v", p, err)
+	}
+	if p.DominantColor != "#108020" {
+		t.Errorf("DominantColor = %s, want the green that covers most of the image", p.DominantColor)
+	}
+}
+
+func TestGenerateOrientation(t *testing.T) {
+	// Turned right, the bottom left quarter (blue) is shown top left
+	file := exiftest.JPEG(t, exiftest.Quadrants(40, 20), exiftest.Orientation(6))
+	p, err := Generate(bytes.NewReader(file))
+	if err != nil || p == nil {
+		t.Fatalf("Generate = %v, %v", p, err)
+	}
+	if p.BlurHash[0] != 'T' {
+		t.Errorf("BlurHash %s does not use 3x4 components for a portrait image", p.BlurHash)
+	}
+	lqip := decodeLQIP(t, p.LQIP)
+	if lqip.Bounds().Dx() != 8 || lqip.Bounds().Dy() != 16 {
+		t.Fatalf("LQIP is %v, want 8x16", lqip.Bounds())
+	}
+	r, g, b, _ := lqip.At(1, 1).RGBA()
+	if b < 0x8000 || r > 0x8000 || g > 0x8000 {
+		t.Errorf("top left of the LQIP is %v, want blue", lqip.At(1, 1))
+	}
+}
+
+func TestGenerateUnsupported(t *testing.T) {
+	webp := []byte("RIFF\x1a\x00\x00\x00WEBPVP8L\x0d\x00\x00\x00\x2f\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+	if p, err := Generate(bytes.NewReader(webp)); err != nil || p != nil {
+		t.Errorf("Generate(webp) = %v, %v, want nothing", p, err)
+	}
+
+	file := encodePNG(t, solid(40, 20, color.RGBA{0x33, 0x66, 0x99, 0xFF}))
+	if _, err := Generate(bytes.NewReader(file[:len(file)-20])); err == nil {
+		t.Error("Generate(truncated png) succeeded")
+	}
+}
+
+func TestGenerateTooLarge(t *testing.T) {
+	// A PNG header claiming 20000x20000 pixels, with no pixel data
+	ihdr := binary.BigEndian.AppendUint32(nil, 20000)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, 20000)
+	ihdr = append(ihdr, 8, 2, 0, 0, 0)
+	file := []byte("\x89PNG\r\n\x1a\n")
+	file = binary.BigEndian.AppendUint32(file, uint32(len(ihdr)))
+	chunk := append([]byte("IHDR"), ihdr...)
+	file = append(file, chunk...)
+	file = binary.BigEndian.AppendUint32(file, crc32.ChecksumIEEE(chunk))
+
+	if _, err := Generate(bytes.NewReader(file)); !errors.Is(err, imaging.ErrTooLarge) {
+		t.Errorf("Generate = %v, want imaging.ErrTooLarge", err)
+	}
+}
+
+func decodeLQIP(t *testing.T, uri string) image.Image {
+	t.Helper()
+	data, ok := strings.CutPrefix(uri, "data:image/jpeg;base64,")
+	if !ok {
+		t.Fatalf("LQIP %.40s is not a base64 JPEG data URI", uri)
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		t.Fatalf("LQIP base64: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("LQIP JPEG: %v", err)
+	}
+	return img
+}
@@ -0,0 +1,63 @@
+// backend/internal/preview/preview.go
+
+// Package preview signs and checks the tokens in links to unpublished galleries.
+package preview
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/token"
+)
+
+// Token errors
+var (
+	ErrInvalidToken = errors.New("invalid preview token")
+	ErrExpired      = errors.New("preview token expired")
+)
+
+// keyLabel is the token label of preview tokens
+const keyLabel = "gallery-preview-v1"
+
+// Claims is what a token vouches for
+type Claims struct {
+	LinkID     int
+	CategoryID int
+	ExpiresAt  time.Time
+}
+
+// Signer mints and verifies preview tokens
+type Signer struct {
+	tokens *token.Signer
+}
+
+// NewSigner creates a signer whose key is derived from secret
+func NewSigner(secret string) *Signer {
+	return &Signer{tokens: token.NewSigner(secret, keyLabel)}
+}
+
+// Sign returns the token for claims. The same claims always give the same
+// token, so it can be derived again from the stored link.
+func (s *Signer) Sign(claims Claims) string {
+	return s.tokens.Sign(fmt.Sprintf("%d.%d.%d", claims.LinkID, claims.CategoryID, claims.ExpiresAt.Unix()))
+}
+
+// Verify checks the token's signature and expiry at now
+func (s *Signer) Verify(token string, now time.Time) (Claims, error) {
+	payload, err := s.tokens.Verify(token)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var claims Claims
+	var expires int64
+	if _, err := fmt.Sscanf(payload, "%d.%d.%d", &claims.LinkID, &claims.CategoryID, &expires); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	claims.ExpiresAt = time.Unix(expires, 0).UTC()
+	if !now.Before(claims.ExpiresAt) {
+		return Claims{}, ErrExpired
+	}
+	return claims, nil
+}
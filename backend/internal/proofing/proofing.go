@@ -0,0 +1,55 @@
+// backend/internal/proofing/proofing.go
+
+// Package proofing signs and checks the tokens in the links handed to
+// clients of proofing galleries.
+package proofing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/supraik/Freelance-Portfolio/internal/token"
+)
+
+// ErrInvalidToken is returned for tokens this signer did not mint
+var ErrInvalidToken = errors.New("invalid proofing token")
+
+// keyLabel is the token label of proofing tokens
+const keyLabel = "gallery-proofing-v1"
+
+// Claims is what a token vouches for
+type Claims struct {
+	ClientID   int
+	CategoryID int
+}
+
+// Signer mints and verifies proofing tokens. Tokens do not expire; a link
+// stops working when its client is revoked.
+type Signer struct {
+	tokens *token.Signer
+}
+
+// NewSigner creates a signer whose key is derived from secret
+func NewSigner(secret string) *Signer {
+	return &Signer{tokens: token.NewSigner(secret, keyLabel)}
+}
+
+// Sign returns the token for claims. The same claims always give the same
+// token, so it can be derived again from the stored client.
+func (s *Signer) Sign(claims Claims) string {
+	return s.tokens.Sign(fmt.Sprintf("%d.%d", claims.ClientID, claims.CategoryID))
+}
+
+// Verify checks the token's signature
+func (s *Signer) Verify(token string) (Claims, error) {
+	payload, err := s.tokens.Verify(token)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var claims Claims
+	if _, err := fmt.Sscanf(payload, "%d.%d", &claims.ClientID, &claims.CategoryID); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	return claims, nil
+}
@@ -0,0 +1,104 @@
+// backend/internal/repository/access_log_repo.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// accessLogTables lists the columns AccessLogRepository reads and writes
+var accessLogTables = []database.Table{
+	{Name: "gallery_access_log", Columns: []database.Column{
+		{Name: "id", Type: database.TypeInteger},
+		{Name: "category_id", Type: database.TypeInteger},
+		{Name: "event", Type: database.TypeVarchar},
+		{Name: "ip", Type: database.TypeVarchar},
+		{Name: "user_agent", Type: database.TypeText},
+		{Name: "created_at", Type: database.TypeTimestamp},
+	}},
+}
+
+// AccessLogRepository handles the access log of password galleries
+type AccessLogRepository struct {
+	db *database.DB
+}
+
+// NewAccessLogRepository creates a new access log repository
+func NewAccessLogRepository(db *database.DB) *AccessLogRepository {
+	return &AccessLogRepository{db: db}
+}
+
+// Record appends an entry to the log, filling in its ID and CreatedAt.
+// It returns ErrCategoryNotFound if the category does not exist.
+func (r *AccessLogRepository) Record(ctx context.Context, entry *models.GalleryAccessEntry) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "access_log.record")
+	defer func() { err = finish(err) }()
+
+	entry.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO gallery_access_log (category_id, event, ip, user_agent, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	err = r.db.QueryRowContext(ctx, query, entry.CategoryID, entry.Event, entry.IP, entry.UserAgent, entry.CreatedAt).Scan(&entry.ID)
+	return translateError(err)
+}
+
+// List returns a page of a category's entries, newest first, and the total
+// number of matching entries
+func (r *AccessLogRepository) List(ctx context.Context, filter AccessLogFilter) (_ []models.GalleryAccessEntry, _ int, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "access_log.list")
+	defer func() { err = finish(err) }()
+
+	clause := "WHERE category_id = $1"
+	args := []interface{}{filter.CategoryID}
+	if filter.Event != "" {
+		clause += " AND event = $2"
+		args = append(args, filter.Event)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM gallery_access_log `+clause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, category_id, event, ip, user_agent, created_at
+		FROM gallery_access_log
+		` + clause + `
+		ORDER BY created_at DESC, id DESC
+	`
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d OFFSET %d", filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []models.GalleryAccessEntry{}
+	for rows.Next() {
+		var entry models.GalleryAccessEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.CategoryID,
+			&entry.Event,
+			&entry.IP,
+			&entry.UserAgent,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, total, rows.Err()
+}
@@ -0,0 +1,154 @@
+// backend/internal/repository/audit_repo.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// auditTables lists the columns AuditRepository reads and writes
+var auditTables = []database.Table{
+	{Name: "audit_log", Columns: []database.Column{
+		{Name: "id", Type: database.TypeInteger},
+		{Name: "actor_id", Type: database.TypeInteger},
+		{Name: "actor_email", Type: database.TypeVarchar},
+		{Name: "action", Type: database.TypeVarchar},
+		{Name: "entity_type", Type: database.TypeVarchar},
+		{Name: "entity_id", Type: database.TypeVarchar},
+		{Name: "changes", Type: database.TypeJSONB},
+		{Name: "ip", Type: database.TypeVarchar},
+		{Name: "user_agent", Type: database.TypeText},
+		{Name: "created_at", Type: database.TypeTimestamp},
+	}},
+}
+
+// AuditRepository handles audit log data operations
+type AuditRepository struct {
+	db *database.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *database.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Record appends an entry to the audit log, filling in its ID and CreatedAt
+func (r *AuditRepository) Record(ctx context.Context, entry *models.AuditEntry) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "audit.record")
+	defer func() { err = finish(err) }()
+
+	// Set in Go rather than by the database so SQLite compares the same format in List
+	entry.CreatedAt = time.Now().UTC()
+	changes := string(entry.Changes)
+	if changes == "" {
+		changes = "{}"
+	}
+
+	query := `
+		INSERT INTO audit_log (actor_id, actor_email, action, entity_type, entity_id, changes, ip, user_agent, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	return r.db.QueryRowContext(
+		ctx,
+		query,
+		entry.ActorID,
+		entry.ActorEmail,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		changes,
+		entry.IP,
+		entry.UserAgent,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+}
+
+// List returns a page of entries matching filter, newest first, and the
+// total number of matching entries
+func (r *AuditRepository) List(ctx context.Context, filter AuditFilter) (_ []models.AuditEntry, _ int, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "audit.list")
+	defer func() { err = finish(err) }()
+
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ActorID != 0 {
+		where("actor_id = $%d", filter.ActorID)
+	}
+	if filter.Action != "" {
+		where("action = $%d", filter.Action)
+	}
+	if filter.EntityType != "" {
+		where("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		where("entity_id = $%d", filter.EntityID)
+	}
+	if !filter.Since.IsZero() {
+		where("created_at >= $%d", filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		where("created_at < $%d", filter.Until.UTC())
+	}
+
+	clause := ""
+	if len(conditions) > 0 {
+		clause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_log `+clause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, actor_id, actor_email, action, entity_type, entity_id, changes, ip, user_agent, created_at
+		FROM audit_log
+		` + clause + `
+		ORDER BY created_at DESC, id DESC
+	`
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d OFFSET %d", filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var entry models.AuditEntry
+		var changes []byte
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.ActorID,
+			&entry.ActorEmail,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&changes,
+			&entry.IP,
+			&entry.UserAgent,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		entry.Changes = changes
+		entries = append(entries, entry)
+	}
+
+	return entries, total, rows.Err()
+}
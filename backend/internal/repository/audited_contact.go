@@ -0,0 +1,91 @@
+// backend/internal/repository/audited_contact.go
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/supraik/Freelance-Portfolio/internal/audit"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// AuditedContactRepository records admin changes to the inbox in the audit log
+type AuditedContactRepository struct {
+	store ContactStore
+	audit *audit.Recorder
+}
+
+var _ ContactStore = (*AuditedContactRepository)(nil)
+
+// NewAuditedContactRepository wraps store so admin writes are recorded by recorder
+func NewAuditedContactRepository(store ContactStore, recorder *audit.Recorder) *AuditedContactRepository {
+	return &AuditedContactRepository{store: store, audit: recorder}
+}
+
+// Create is not audited, messages are submitted by visitors
+func (r *AuditedContactRepository) Create(ctx context.Context, req *models.ContactRequest) (*models.ContactMessage, error) {
+	return r.store.Create(ctx, req)
+}
+
+// GetAll is not audited
+func (r *AuditedContactRepository) GetAll(ctx context.Context) ([]models.ContactMessage, error) {
+	return r.store.GetAll(ctx)
+}
+
+// GetByID is not audited
+func (r *AuditedContactRepository) GetByID(ctx context.Context, id int) (*models.ContactMessage, error) {
+	return r.store.GetByID(ctx, id)
+}
+
+// Search is not audited
+func (r *AuditedContactRepository) Search(ctx context.Context, query string, limit int) ([]models.ContactSearchHit, error) {
+	return r.store.Search(ctx, query, limit)
+}
+
+// UpdateStatus changes a message's status and records it
+func (r *AuditedContactRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	before := r.message(ctx, id)
+	if err := r.store.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+	if before != nil {
+		r.audit.Record(ctx, "contact.update_status", "contact", strconv.Itoa(id), before, r.message(ctx, id))
+	}
+	return nil
+}
+
+// MarkAsRead marks a message as read and records it
+func (r *AuditedContactRepository) MarkAsRead(ctx context.Context, id int) error {
+	before := r.message(ctx, id)
+	if err := r.store.MarkAsRead(ctx, id); err != nil {
+		return err
+	}
+	if before != nil {
+		r.audit.Record(ctx, "contact.mark_read", "contact", strconv.Itoa(id), before, r.message(ctx, id))
+	}
+	return nil
+}
+
+// Delete removes a message and records what it said
+func (r *AuditedContactRepository) Delete(ctx context.Context, id int) error {
+	before := r.message(ctx, id)
+	if err := r.store.Delete(ctx, id); err != nil {
+		return err
+	}
+	if before != nil {
+		r.audit.Record(ctx, "contact.delete", "contact", strconv.Itoa(id), before, nil)
+	}
+	return nil
+}
+
+// message returns the stored message or nil, for before and after snapshots
+func (r *AuditedContactRepository) message(ctx context.Context, id int) *models.ContactMessage {
+	if _, ok := audit.ActorFrom(ctx); !ok {
+		return nil
+	}
+	msg, err := r.store.GetByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return msg
+}
@@ -0,0 +1,313 @@
+// backend/internal/repository/audited_gallery.go
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/audit"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// AuditedGalleryRepository records every successful admin write to the
+// audit log, with the state of the gallery or image before and after it
+type AuditedGalleryRepository struct {
+	store GalleryStore
+	audit *audit.Recorder
+}
+
+var _ GalleryStore = (*AuditedGalleryRepository)(nil)
+
+// NewAuditedGalleryRepository wraps store so writes are recorded by recorder
+func NewAuditedGalleryRepository(store GalleryStore, recorder *audit.Recorder) *AuditedGalleryRepository {
+	return &AuditedGalleryRepository{store: store, audit: recorder}
+}
+
+// GetAllCategories is not audited
+func (r *AuditedGalleryRepository) GetAllCategories(ctx context.Context, opts CategoryListOptions) ([]models.GalleryCategory, error) {
+	return r.store.GetAllCategories(ctx, opts)
+}
+
+// GetCategoryBySlug is not audited
+func (r *AuditedGalleryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*models.GalleryCategory, error) {
+	return r.store.GetCategoryBySlug(ctx, slug)
+}
+
+// GetCategoryByID is not audited
+func (r *AuditedGalleryRepository) GetCategoryByID(ctx context.Context, id int) (*models.GalleryCategory, error) {
+	return r.store.GetCategoryByID(ctx, id)
+}
+
+// GetImageByID is not audited
+func (r *AuditedGalleryRepository) GetImageByID(ctx context.Context, id int) (*models.GalleryImage, error) {
+	return r.store.GetImageByID(ctx, id)
+}
+
+// GetImagesByCategory is not audited
+func (r *AuditedGalleryRepository) GetImagesByCategory(ctx context.Context, categoryID int) ([]models.GalleryImage, error) {
+	return r.store.GetImagesByCategory(ctx, categoryID)
+}
+
+// Search is not audited
+func (r *AuditedGalleryRepository) Search(ctx context.Context, query string, limit int) (*models.ContentSearchResults, error) {
+	return r.store.Search(ctx, query, limit)
+}
+
+// ListTrash is not audited
+func (r *AuditedGalleryRepository) ListTrash(ctx context.Context) (*models.GalleryTrash, error) {
+	return r.store.ListTrash(ctx)
+}
+
+// CreateCategory creates a category and records it
+func (r *AuditedGalleryRepository) CreateCategory(ctx context.Context, cat *models.GalleryCategory) error {
+	if err := r.store.CreateCategory(ctx, cat); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "gallery.create", "gallery", strconv.Itoa(cat.ID), nil, cat)
+	return nil
+}
+
+// UpdateCategory updates a category and records the fields that changed
+func (r *AuditedGalleryRepository) UpdateCategory(ctx context.Context, cat *models.GalleryCategory) error {
+	before := r.category(ctx, cat.ID)
+	if err := r.store.UpdateCategory(ctx, cat); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "gallery.update", "gallery", strconv.Itoa(cat.ID), before, r.category(ctx, cat.ID))
+	return nil
+}
+
+// UpdateCategoryStatus changes a category's status and records it
+func (r *AuditedGalleryRepository) UpdateCategoryStatus(ctx context.Context, cat *models.GalleryCategory) error {
+	before := r.category(ctx, cat.ID)
+	if err := r.store.UpdateCategoryStatus(ctx, cat); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "gallery.status", "gallery", strconv.Itoa(cat.ID), before, r.category(ctx, cat.ID))
+	return nil
+}
+
+// UpdateCategoryAccess changes a category's access mode and records it.
+// The password hash never reaches the log, only the mode.
+func (r *AuditedGalleryRepository) UpdateCategoryAccess(ctx context.Context, cat *models.GalleryCategory, passwordHash string) error {
+	before := r.category(ctx, cat.ID)
+	if err := r.store.UpdateCategoryAccess(ctx, cat, passwordHash); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "gallery.access", "gallery", strconv.Itoa(cat.ID), before, r.category(ctx, cat.ID))
+	return nil
+}
+
+// CategoryPasswordHash is not audited
+func (r *AuditedGalleryRepository) CategoryPasswordHash(ctx context.Context, id int) (string, error) {
+	return r.store.CategoryPasswordHash(ctx, id)
+}
+
+// ScheduleTransitions is not audited
+func (r *AuditedGalleryRepository) ScheduleTransitions(ctx context.Context, after, until time.Time) ([]int, error) {
+	return r.store.ScheduleTransitions(ctx, after, until)
+}
+
+// DeleteCategory moves a category to the trash and records what it held
+func (r *AuditedGalleryRepository) DeleteCategory(ctx context.Context, id int) error {
+	before := r.category(ctx, id)
+	if err := r.store.DeleteCategory(ctx, id); err != nil {
+		return err
+	}
+	if before != nil {
+		r.audit.Record(ctx, "gallery.delete", "gallery", strconv.Itoa(id), before, nil)
+	}
+	return nil
+}
+
+// RestoreCategory takes a category out of the trash and records it
+func (r *AuditedGalleryRepository) RestoreCategory(ctx context.Context, id int) (*models.GalleryCategory, error) {
+	cat, err := r.store.RestoreCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.audit.Record(ctx, "gallery.restore", "gallery", strconv.Itoa(id), nil, r.category(ctx, id))
+	return cat, nil
+}
+
+// PurgeCategory permanently deletes a trashed category and records it
+func (r *AuditedGalleryRepository) PurgeCategory(ctx context.Context, id int) ([]string, error) {
+	unused, err := r.store.PurgeCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.audit.Record(ctx, "gallery.purge", "gallery", strconv.Itoa(id), nil, nil)
+	return unused, nil
+}
+
+// CreateImage creates an image and records it
+func (r *AuditedGalleryRepository) CreateImage(ctx context.Context, img *models.GalleryImage) error {
+	if err := r.store.CreateImage(ctx, img); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "image.create", "image", strconv.Itoa(img.ID), nil, img)
+	return nil
+}
+
+// UpdateImage updates an image and records the fields that changed
+func (r *AuditedGalleryRepository) UpdateImage(ctx context.Context, img *models.GalleryImage) error {
+	before := r.image(ctx, img.ID)
+	if err := r.store.UpdateImage(ctx, img); err != nil {
+		return err
+	}
+	if before != nil {
+		r.audit.Record(ctx, "image.update", "image", strconv.Itoa(img.ID), before, r.image(ctx, img.ID))
+	}
+	return nil
+}
+
+// DeleteImage moves an image to the trash and records it
+func (r *AuditedGalleryRepository) DeleteImage(ctx context.Context, id int) error {
+	before := r.image(ctx, id)
+	if err := r.store.DeleteImage(ctx, id); err != nil {
+		return err
+	}
+	if before != nil {
+		r.audit.Record(ctx, "image.delete", "image", strconv.Itoa(id), before, nil)
+	}
+	return nil
+}
+
+// RestoreImage takes an image out of the trash and records it
+func (r *AuditedGalleryRepository) RestoreImage(ctx context.Context, id int) (*models.GalleryImage, error) {
+	img, err := r.store.RestoreImage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.audit.Record(ctx, "image.restore", "image", strconv.Itoa(id), nil, img)
+	return img, nil
+}
+
+// PurgeImage permanently deletes a trashed image and records it
+func (r *AuditedGalleryRepository) PurgeImage(ctx context.Context, id int) ([]string, error) {
+	unused, err := r.store.PurgeImage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.audit.Record(ctx, "image.purge", "image", strconv.Itoa(id), nil, nil)
+	return unused, nil
+}
+
+// ReorderCategories reorders the categories and records the old and new order
+func (r *AuditedGalleryRepository) ReorderCategories(ctx context.Context, ids []int) error {
+	before := r.categoryOrder(ctx)
+	if err := r.store.ReorderCategories(ctx, ids); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "gallery.reorder", "gallery", "", before, order{IDs: ids})
+	return nil
+}
+
+// ReorderImages reorders a category's images and records the old and new order
+func (r *AuditedGalleryRepository) ReorderImages(ctx context.Context, categoryID int, ids []int) error {
+	before := r.imageOrder(ctx, categoryID)
+	if err := r.store.ReorderImages(ctx, categoryID, ids); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "image.reorder", "gallery", strconv.Itoa(categoryID), before, order{IDs: ids})
+	return nil
+}
+
+// MoveImages moves images and records each of them
+func (r *AuditedGalleryRepository) MoveImages(ctx context.Context, ids []int, categoryID, position int) ([]models.GalleryImage, error) {
+	before := make(map[int]*models.GalleryImage, len(ids))
+	for _, id := range ids {
+		before[id] = r.image(ctx, id)
+	}
+
+	images, err := r.store.MoveImages(ctx, ids, categoryID, position)
+	if err != nil {
+		return nil, err
+	}
+	for i := range images {
+		img := &images[i]
+		r.audit.Record(ctx, "image.move", "image", strconv.Itoa(img.ID), before[img.ID], img)
+	}
+	return images, nil
+}
+
+// CopyImages copies images and records each copy
+func (r *AuditedGalleryRepository) CopyImages(ctx context.Context, ids []int, categoryID, position int) ([]models.GalleryImage, error) {
+	images, err := r.store.CopyImages(ctx, ids, categoryID, position)
+	if err != nil {
+		return nil, err
+	}
+	for i := range images {
+		img := &images[i]
+		r.audit.Record(ctx, "image.copy", "image", strconv.Itoa(img.ID), nil, img)
+	}
+	return images, nil
+}
+
+// PurgeTrash is run by the trash janitor rather than an admin, so it is not audited
+func (r *AuditedGalleryRepository) PurgeTrash(ctx context.Context, before time.Time) (int64, int64, []string, error) {
+	return r.store.PurgeTrash(ctx, before)
+}
+
+// category returns the live category or nil, for before and after snapshots.
+// Nothing is looked up for writes that will not be recorded.
+func (r *AuditedGalleryRepository) category(ctx context.Context, id int) *models.GalleryCategory {
+	if _, ok := audit.ActorFrom(ctx); !ok {
+		return nil
+	}
+	cat, err := r.store.GetCategoryByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return cat
+}
+
+// image returns the live image or nil, for before and after snapshots
+func (r *AuditedGalleryRepository) image(ctx context.Context, id int) *models.GalleryImage {
+	if _, ok := audit.ActorFrom(ctx); !ok {
+		return nil
+	}
+	img, err := r.store.GetImageByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+// order is the audited state of a reorder
+type order struct {
+	IDs []int `json:"order"`
+}
+
+// categoryOrder returns the current order of the categories, for before snapshots
+func (r *AuditedGalleryRepository) categoryOrder(ctx context.Context) *order {
+	if _, ok := audit.ActorFrom(ctx); !ok {
+		return nil
+	}
+	categories, err := r.store.GetAllCategories(ctx, CategoryListOptions{AllStatuses: true})
+	if err != nil {
+		return nil
+	}
+	o := &order{IDs: []int{}}
+	for _, cat := range categories {
+		o.IDs = append(o.IDs, cat.ID)
+	}
+	return o
+}
+
+// imageOrder returns the current order of a category's images, for before snapshots
+func (r *AuditedGalleryRepository) imageOrder(ctx context.Context, categoryID int) *order {
+	if _, ok := audit.ActorFrom(ctx); !ok {
+		return nil
+	}
+	images, err := r.store.GetImagesByCategory(ctx, categoryID)
+	if err != nil {
+		return nil
+	}
+	o := &order{IDs: []int{}}
+	for _, img := range images {
+		o.IDs = append(o.IDs, img.ID)
+	}
+	return o
+}
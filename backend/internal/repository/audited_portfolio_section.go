@@ -0,0 +1,42 @@
+// backend/internal/repository/audited_portfolio_section.go
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/supraik/Freelance-Portfolio/internal/audit"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// AuditedPortfolioSectionRepository records section image changes in the audit log
+type AuditedPortfolioSectionRepository struct {
+	store PortfolioSectionStore
+	audit *audit.Recorder
+}
+
+var _ PortfolioSectionStore = (*AuditedPortfolioSectionRepository)(nil)
+
+// NewAuditedPortfolioSectionRepository wraps store so writes are recorded by recorder
+func NewAuditedPortfolioSectionRepository(store PortfolioSectionStore, recorder *audit.Recorder) *AuditedPortfolioSectionRepository {
+	return &AuditedPortfolioSectionRepository{store: store, audit: recorder}
+}
+
+// GetAll is not audited
+func (r *AuditedPortfolioSectionRepository) GetAll(ctx context.Context) ([]models.PortfolioSection, error) {
+	return r.store.GetAll(ctx)
+}
+
+// UpdateImage replaces a section's image and records the new one
+func (r *AuditedPortfolioSectionRepository) UpdateImage(ctx context.Context, sectionID int, cloudinaryID, imageURL string) error {
+	if err := r.store.UpdateImage(ctx, sectionID, cloudinaryID, imageURL); err != nil {
+		return err
+	}
+
+	// Sections do not store their image, so there is no previous one to compare with
+	r.audit.Record(ctx, "section.update_image", "section", strconv.Itoa(sectionID), nil, map[string]string{
+		"cloudinary_id": cloudinaryID,
+		"image_url":     imageURL,
+	})
+	return nil
+}
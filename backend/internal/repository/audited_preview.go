@@ -0,0 +1,66 @@
+// backend/internal/repository/audited_preview.go
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/audit"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// AuditedPreviewRepository records preview links being handed out and
+// revoked. Tokens are derived from the link, so none end up in the log.
+type AuditedPreviewRepository struct {
+	store PreviewStore
+	audit *audit.Recorder
+}
+
+var _ PreviewStore = (*AuditedPreviewRepository)(nil)
+
+// NewAuditedPreviewRepository wraps store so writes are recorded by recorder
+func NewAuditedPreviewRepository(store PreviewStore, recorder *audit.Recorder) *AuditedPreviewRepository {
+	return &AuditedPreviewRepository{store: store, audit: recorder}
+}
+
+// Create creates a link and records it
+func (r *AuditedPreviewRepository) Create(ctx context.Context, link *models.PreviewLink) error {
+	if err := r.store.Create(ctx, link); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "preview.create", "preview", strconv.Itoa(link.ID), nil, link)
+	return nil
+}
+
+// GetByID is not audited
+func (r *AuditedPreviewRepository) GetByID(ctx context.Context, id int) (*models.PreviewLink, error) {
+	return r.store.GetByID(ctx, id)
+}
+
+// ListActive is not audited
+func (r *AuditedPreviewRepository) ListActive(ctx context.Context, now time.Time) ([]models.PreviewLink, error) {
+	return r.store.ListActive(ctx, now)
+}
+
+// Revoke revokes a link and records its state before and after
+func (r *AuditedPreviewRepository) Revoke(ctx context.Context, id int) error {
+	before := r.link(ctx, id)
+	if err := r.store.Revoke(ctx, id); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "preview.revoke", "preview", strconv.Itoa(id), before, r.link(ctx, id))
+	return nil
+}
+
+// link returns the link or nil, for before and after snapshots
+func (r *AuditedPreviewRepository) link(ctx context.Context, id int) *models.PreviewLink {
+	if _, ok := audit.ActorFrom(ctx); !ok {
+		return nil
+	}
+	link, err := r.store.GetByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return link
+}
@@ -0,0 +1,128 @@
+// backend/internal/repository/audited_proofing.go
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/supraik/Freelance-Portfolio/internal/audit"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// AuditedProofingRepository records admin writes to proofing galleries and
+// their clients. Clients are not admins, so their marks and submissions
+// are never recorded.
+type AuditedProofingRepository struct {
+	store ProofingStore
+	audit *audit.Recorder
+}
+
+var _ ProofingStore = (*AuditedProofingRepository)(nil)
+
+// NewAuditedProofingRepository wraps store so writes are recorded by recorder
+func NewAuditedProofingRepository(store ProofingStore, recorder *audit.Recorder) *AuditedProofingRepository {
+	return &AuditedProofingRepository{store: store, audit: recorder}
+}
+
+// ProofingSettings is not audited
+func (r *AuditedProofingRepository) ProofingSettings(ctx context.Context, categoryID int) (*models.ProofingSettings, error) {
+	return r.store.ProofingSettings(ctx, categoryID)
+}
+
+// EnableProofing enables proofing or changes the pick limit and records the settings before and after
+func (r *AuditedProofingRepository) EnableProofing(ctx context.Context, settings *models.ProofingSettings) error {
+	before := r.settings(ctx, settings.CategoryID)
+	if err := r.store.EnableProofing(ctx, settings); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "gallery.proofing", "gallery", strconv.Itoa(settings.CategoryID), before, settings)
+	return nil
+}
+
+// DisableProofing disables proofing and records it
+func (r *AuditedProofingRepository) DisableProofing(ctx context.Context, categoryID int) error {
+	before := r.settings(ctx, categoryID)
+	if err := r.store.DisableProofing(ctx, categoryID); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "gallery.proofing", "gallery", strconv.Itoa(categoryID), before, nil)
+	return nil
+}
+
+// CreateProofingClient invites a client and records it
+func (r *AuditedProofingRepository) CreateProofingClient(ctx context.Context, client *models.ProofingClient) error {
+	if err := r.store.CreateProofingClient(ctx, client); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "proofing.invite", "proofing_client", strconv.Itoa(client.ID), nil, client)
+	return nil
+}
+
+// GetProofingClient is not audited
+func (r *AuditedProofingRepository) GetProofingClient(ctx context.Context, id int) (*models.ProofingClient, error) {
+	return r.store.GetProofingClient(ctx, id)
+}
+
+// ListProofingClients is not audited
+func (r *AuditedProofingRepository) ListProofingClients(ctx context.Context, categoryID int) ([]models.ProofingClient, error) {
+	return r.store.ListProofingClients(ctx, categoryID)
+}
+
+// RevokeProofingClient revokes a client and records its state before and after
+func (r *AuditedProofingRepository) RevokeProofingClient(ctx context.Context, id int) error {
+	before := r.client(ctx, id)
+	if err := r.store.RevokeProofingClient(ctx, id); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "proofing.revoke", "proofing_client", strconv.Itoa(id), before, r.client(ctx, id))
+	return nil
+}
+
+// ProofingMarks is not audited
+func (r *AuditedProofingRepository) ProofingMarks(ctx context.Context, clientID int) ([]models.ProofingMark, error) {
+	return r.store.ProofingMarks(ctx, clientID)
+}
+
+// SetProofingMark is made by clients and not audited
+func (r *AuditedProofingRepository) SetProofingMark(ctx context.Context, mark *models.ProofingMark) error {
+	return r.store.SetProofingMark(ctx, mark)
+}
+
+// SubmitProofingSelection is made by clients and not audited
+func (r *AuditedProofingRepository) SubmitProofingSelection(ctx context.Context, clientID int) error {
+	return r.store.SubmitProofingSelection(ctx, clientID)
+}
+
+// ReopenProofingSelection reopens a selection and records the client's state before and after
+func (r *AuditedProofingRepository) ReopenProofingSelection(ctx context.Context, clientID int) error {
+	before := r.client(ctx, clientID)
+	if err := r.store.ReopenProofingSelection(ctx, clientID); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "proofing.reopen", "proofing_client", strconv.Itoa(clientID), before, r.client(ctx, clientID))
+	return nil
+}
+
+// settings returns the proofing settings or nil, for before snapshots
+func (r *AuditedProofingRepository) settings(ctx context.Context, categoryID int) *models.ProofingSettings {
+	if _, ok := audit.ActorFrom(ctx); !ok {
+		return nil
+	}
+	settings, err := r.store.ProofingSettings(ctx, categoryID)
+	if err != nil {
+		return nil
+	}
+	return settings
+}
+
+// client returns the client or nil, for before and after snapshots
+func (r *AuditedProofingRepository) client(ctx context.Context, id int) *models.ProofingClient {
+	if _, ok := audit.ActorFrom(ctx); !ok {
+		return nil
+	}
+	client, err := r.store.GetProofingClient(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return client
+}
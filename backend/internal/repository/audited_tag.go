@@ -0,0 +1,155 @@
+// backend/internal/repository/audited_tag.go
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/supraik/Freelance-Portfolio/internal/audit"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// AuditedTagRepository records every successful admin write to tags and
+// their assignments in the audit log
+type AuditedTagRepository struct {
+	store TagStore
+	audit *audit.Recorder
+}
+
+var _ TagStore = (*AuditedTagRepository)(nil)
+
+// NewAuditedTagRepository wraps store so writes are recorded by recorder
+func NewAuditedTagRepository(store TagStore, recorder *audit.Recorder) *AuditedTagRepository {
+	return &AuditedTagRepository{store: store, audit: recorder}
+}
+
+// ListTags is not audited
+func (r *AuditedTagRepository) ListTags(ctx context.Context) ([]models.TagUsage, error) {
+	return r.store.ListTags(ctx)
+}
+
+// GetTag is not audited
+func (r *AuditedTagRepository) GetTag(ctx context.Context, id int) (*models.Tag, error) {
+	return r.store.GetTag(ctx, id)
+}
+
+// CreateTag creates a tag and records it
+func (r *AuditedTagRepository) CreateTag(ctx context.Context, tag *models.Tag) error {
+	if err := r.store.CreateTag(ctx, tag); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "tag.create", "tag", strconv.Itoa(tag.ID), nil, tag)
+	return nil
+}
+
+// UpdateTag renames a tag and records the old and new name
+func (r *AuditedTagRepository) UpdateTag(ctx context.Context, tag *models.Tag) error {
+	before := r.tag(ctx, tag.ID)
+	if err := r.store.UpdateTag(ctx, tag); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "tag.update", "tag", strconv.Itoa(tag.ID), before, r.tag(ctx, tag.ID))
+	return nil
+}
+
+// DeleteTag deletes a tag and records it
+func (r *AuditedTagRepository) DeleteTag(ctx context.Context, id int) error {
+	before := r.tag(ctx, id)
+	if err := r.store.DeleteTag(ctx, id); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "tag.delete", "tag", strconv.Itoa(id), before, nil)
+	return nil
+}
+
+// MergeTags merges tags and records the merged tags against the target
+func (r *AuditedTagRepository) MergeTags(ctx context.Context, sourceIDs []int, targetID int) error {
+	if err := r.store.MergeTags(ctx, sourceIDs, targetID); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "tag.merge", "tag", strconv.Itoa(targetID), nil, merge{Merged: sourceIDs})
+	return nil
+}
+
+// ImageTags is not audited
+func (r *AuditedTagRepository) ImageTags(ctx context.Context, imageID int) ([]models.Tag, error) {
+	return r.store.ImageTags(ctx, imageID)
+}
+
+// SetImageTags replaces an image's tags and records the old and new set
+func (r *AuditedTagRepository) SetImageTags(ctx context.Context, imageID int, slugs []string) error {
+	before := r.tagSet(ctx, r.store.ImageTags, imageID)
+	if err := r.store.SetImageTags(ctx, imageID, slugs); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "image.tags", "image", strconv.Itoa(imageID), before, r.tagSet(ctx, r.store.ImageTags, imageID))
+	return nil
+}
+
+// CategoryTags is not audited
+func (r *AuditedTagRepository) CategoryTags(ctx context.Context, categoryID int) ([]models.Tag, error) {
+	return r.store.CategoryTags(ctx, categoryID)
+}
+
+// SetCategoryTags replaces a category's tags and records the old and new set
+func (r *AuditedTagRepository) SetCategoryTags(ctx context.Context, categoryID int, slugs []string) error {
+	before := r.tagSet(ctx, r.store.CategoryTags, categoryID)
+	if err := r.store.SetCategoryTags(ctx, categoryID, slugs); err != nil {
+		return err
+	}
+	r.audit.Record(ctx, "gallery.tags", "gallery", strconv.Itoa(categoryID), before, r.tagSet(ctx, r.store.CategoryTags, categoryID))
+	return nil
+}
+
+// PublicTags is not audited
+func (r *AuditedTagRepository) PublicTags(ctx context.Context) ([]models.TagUsage, error) {
+	return r.store.PublicTags(ctx)
+}
+
+// ImagesByTags is not audited
+func (r *AuditedTagRepository) ImagesByTags(ctx context.Context, q TagQuery) (*models.TaggedImages, error) {
+	return r.store.ImagesByTags(ctx, q)
+}
+
+// CategoriesByTags is not audited
+func (r *AuditedTagRepository) CategoriesByTags(ctx context.Context, q TagQuery) ([]models.TaggedGallery, error) {
+	return r.store.CategoriesByTags(ctx, q)
+}
+
+// tag returns the tag or nil, for before and after snapshots
+func (r *AuditedTagRepository) tag(ctx context.Context, id int) *models.Tag {
+	if _, ok := audit.ActorFrom(ctx); !ok {
+		return nil
+	}
+	tag, err := r.store.GetTag(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return tag
+}
+
+// merge is the audited state of a merge
+type merge struct {
+	Merged []int `json:"merged"`
+}
+
+// tagSet is the audited state of an item's tags
+type tagSet struct {
+	Tags []string `json:"tags"`
+}
+
+// tagSet returns the slugs of an item's tags or nil, for before and after snapshots
+func (r *AuditedTagRepository) tagSet(ctx context.Context, load func(context.Context, int) ([]models.Tag, error), id int) *tagSet {
+	if _, ok := audit.ActorFrom(ctx); !ok {
+		return nil
+	}
+	tags, err := load(ctx, id)
+	if err != nil {
+		return nil
+	}
+	set := &tagSet{Tags: []string{}}
+	for _, tag := range tags {
+		set.Tags = append(set.Tags, tag.Slug)
+	}
+	return set
+}
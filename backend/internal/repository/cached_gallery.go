@@ -0,0 +1,243 @@
+// backend/internal/repository/cached_gallery.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/cache"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// Cache tags. Every list entry carries galleriesTag; per-gallery entries
+// carry the tag of their category and of each image they contain.
+const galleriesTag = "galleries"
+
+func categoryTag(id int) string { return fmt.Sprintf("category:%d", id) }
+func imageTag(id int) string    { return fmt.Sprintf("image:%d", id) }
+
+// CachedGalleryRepository serves gallery reads from an in-process cache and
+// invalidates the affected entries on every write that goes through it.
+// Returned values are shared between callers and must not be modified.
+type CachedGalleryRepository struct {
+	store GalleryStore
+	cache *cache.Cache
+}
+
+var _ GalleryStore = (*CachedGalleryRepository)(nil)
+
+// NewCachedGalleryRepository wraps store with a read-through cache
+func NewCachedGalleryRepository(store GalleryStore, c *cache.Cache) *CachedGalleryRepository {
+	return &CachedGalleryRepository{store: store, cache: c}
+}
+
+// GetAllCategories retrieves all gallery categories through the cache
+func (r *CachedGalleryRepository) GetAllCategories(ctx context.Context, opts CategoryListOptions) ([]models.GalleryCategory, error) {
+	key := fmt.Sprintf("galleries:list:images=%t:limit=%d:all=%t", opts.IncludeImages, opts.ImagesLimit, opts.AllStatuses)
+
+	value, err := r.cache.GetOrLoad(ctx, key, func(ctx context.Context) (interface{}, []string, error) {
+		categories, err := r.store.GetAllCategories(ctx, opts)
+		return categories, []string{galleriesTag}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]models.GalleryCategory(nil), value.([]models.GalleryCategory)...), nil
+}
+
+// GetCategoryBySlug retrieves a single category through the cache
+func (r *CachedGalleryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*models.GalleryCategory, error) {
+	value, err := r.cache.GetOrLoad(ctx, "galleries:slug:"+slug, func(ctx context.Context) (interface{}, []string, error) {
+		cat, err := r.store.GetCategoryBySlug(ctx, slug)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cat, imageTags(categoryTag(cat.ID), cat.Images), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cat := *value.(*models.GalleryCategory)
+	return &cat, nil
+}
+
+// GetCategoryByID is not cached, only admin writes look categories up by ID
+func (r *CachedGalleryRepository) GetCategoryByID(ctx context.Context, id int) (*models.GalleryCategory, error) {
+	return r.store.GetCategoryByID(ctx, id)
+}
+
+// GetImageByID is not cached, only admin writes look images up by ID
+func (r *CachedGalleryRepository) GetImageByID(ctx context.Context, id int) (*models.GalleryImage, error) {
+	return r.store.GetImageByID(ctx, id)
+}
+
+// GetImagesByCategory retrieves a category's images through the cache
+func (r *CachedGalleryRepository) GetImagesByCategory(ctx context.Context, categoryID int) ([]models.GalleryImage, error) {
+	key := fmt.Sprintf("galleries:images:%d", categoryID)
+
+	value, err := r.cache.GetOrLoad(ctx, key, func(ctx context.Context) (interface{}, []string, error) {
+		images, err := r.store.GetImagesByCategory(ctx, categoryID)
+		return images, imageTags(categoryTag(categoryID), images), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]models.GalleryImage(nil), value.([]models.GalleryImage)...), nil
+}
+
+// Search is not cached, every query would need its own entry
+func (r *CachedGalleryRepository) Search(ctx context.Context, query string, limit int) (*models.ContentSearchResults, error) {
+	return r.store.Search(ctx, query, limit)
+}
+
+// CreateCategory creates a category and drops the cached lists
+func (r *CachedGalleryRepository) CreateCategory(ctx context.Context, cat *models.GalleryCategory) error {
+	defer r.cache.Invalidate(galleriesTag)
+	return r.store.CreateCategory(ctx, cat)
+}
+
+// UpdateCategory updates a category and drops every entry that contains it
+func (r *CachedGalleryRepository) UpdateCategory(ctx context.Context, cat *models.GalleryCategory) error {
+	defer r.cache.Invalidate(galleriesTag, categoryTag(cat.ID))
+	return r.store.UpdateCategory(ctx, cat)
+}
+
+// UpdateCategoryStatus changes a category's status and drops every entry that contains it
+func (r *CachedGalleryRepository) UpdateCategoryStatus(ctx context.Context, cat *models.GalleryCategory) error {
+	defer r.cache.Invalidate(galleriesTag, categoryTag(cat.ID))
+	return r.store.UpdateCategoryStatus(ctx, cat)
+}
+
+// UpdateCategoryAccess changes a category's access mode and drops every entry that contains it
+func (r *CachedGalleryRepository) UpdateCategoryAccess(ctx context.Context, cat *models.GalleryCategory, passwordHash string) error {
+	defer r.cache.Invalidate(galleriesTag, categoryTag(cat.ID))
+	return r.store.UpdateCategoryAccess(ctx, cat, passwordHash)
+}
+
+// CategoryPasswordHash is not cached so a new password applies at once
+func (r *CachedGalleryRepository) CategoryPasswordHash(ctx context.Context, id int) (string, error) {
+	return r.store.CategoryPasswordHash(ctx, id)
+}
+
+// ScheduleTransitions returns the categories whose publishing window opened
+// or closed in (after, until] and drops their entries, since their
+// visibility changed without a write going through the cache
+func (r *CachedGalleryRepository) ScheduleTransitions(ctx context.Context, after, until time.Time) ([]int, error) {
+	ids, err := r.store.ScheduleTransitions(ctx, after, until)
+	if err != nil || len(ids) == 0 {
+		return ids, err
+	}
+
+	tags := []string{galleriesTag}
+	for _, id := range ids {
+		tags = append(tags, categoryTag(id))
+	}
+	r.cache.Invalidate(tags...)
+	return ids, nil
+}
+
+// DeleteCategory deletes a category and drops every entry that contains it
+func (r *CachedGalleryRepository) DeleteCategory(ctx context.Context, id int) error {
+	defer r.cache.Invalidate(galleriesTag, categoryTag(id))
+	return r.store.DeleteCategory(ctx, id)
+}
+
+// CreateImage creates an image and drops the entries of its category
+func (r *CachedGalleryRepository) CreateImage(ctx context.Context, img *models.GalleryImage) error {
+	defer r.cache.Invalidate(galleriesTag, categoryTag(img.CategoryID))
+	return r.store.CreateImage(ctx, img)
+}
+
+// UpdateImage updates an image and drops every entry that contains it
+func (r *CachedGalleryRepository) UpdateImage(ctx context.Context, img *models.GalleryImage) error {
+	defer r.cache.Invalidate(galleriesTag, imageTag(img.ID))
+	return r.store.UpdateImage(ctx, img)
+}
+
+// DeleteImage deletes an image and drops every entry that contains it
+func (r *CachedGalleryRepository) DeleteImage(ctx context.Context, id int) error {
+	defer r.cache.Invalidate(galleriesTag, imageTag(id))
+	return r.store.DeleteImage(ctx, id)
+}
+
+// ReorderCategories reorders the categories and drops every entry that
+// contains one of them
+func (r *CachedGalleryRepository) ReorderCategories(ctx context.Context, ids []int) error {
+	tags := []string{galleriesTag}
+	for _, id := range ids {
+		tags = append(tags, categoryTag(id))
+	}
+	defer r.cache.Invalidate(tags...)
+	return r.store.ReorderCategories(ctx, ids)
+}
+
+// ReorderImages reorders a category's images and drops the entries of the category
+func (r *CachedGalleryRepository) ReorderImages(ctx context.Context, categoryID int, ids []int) error {
+	defer r.cache.Invalidate(galleriesTag, categoryTag(categoryID))
+	return r.store.ReorderImages(ctx, categoryID, ids)
+}
+
+// MoveImages moves images and drops the entries of their old and new categories
+func (r *CachedGalleryRepository) MoveImages(ctx context.Context, ids []int, categoryID, position int) ([]models.GalleryImage, error) {
+	tags := []string{galleriesTag, categoryTag(categoryID)}
+	for _, id := range ids {
+		tags = append(tags, imageTag(id))
+	}
+	defer r.cache.Invalidate(tags...)
+	return r.store.MoveImages(ctx, ids, categoryID, position)
+}
+
+// CopyImages copies images and drops the entries of the destination category
+func (r *CachedGalleryRepository) CopyImages(ctx context.Context, ids []int, categoryID, position int) ([]models.GalleryImage, error) {
+	defer r.cache.Invalidate(galleriesTag, categoryTag(categoryID))
+	return r.store.CopyImages(ctx, ids, categoryID, position)
+}
+
+// ListTrash is not cached, only the admin reads it
+func (r *CachedGalleryRepository) ListTrash(ctx context.Context) (*models.GalleryTrash, error) {
+	return r.store.ListTrash(ctx)
+}
+
+// RestoreCategory restores a category and drops the cached lists
+func (r *CachedGalleryRepository) RestoreCategory(ctx context.Context, id int) (*models.GalleryCategory, error) {
+	defer r.cache.Invalidate(galleriesTag, categoryTag(id))
+	return r.store.RestoreCategory(ctx, id)
+}
+
+// RestoreImage restores an image and drops the entries of its category
+func (r *CachedGalleryRepository) RestoreImage(ctx context.Context, id int) (*models.GalleryImage, error) {
+	img, err := r.store.RestoreImage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Invalidate(galleriesTag, categoryTag(img.CategoryID))
+	return img, nil
+}
+
+// PurgeCategory needs no invalidation, trashed rows are never cached
+func (r *CachedGalleryRepository) PurgeCategory(ctx context.Context, id int) ([]string, error) {
+	return r.store.PurgeCategory(ctx, id)
+}
+
+// PurgeImage needs no invalidation, trashed rows are never cached
+func (r *CachedGalleryRepository) PurgeImage(ctx context.Context, id int) ([]string, error) {
+	return r.store.PurgeImage(ctx, id)
+}
+
+// PurgeTrash needs no invalidation, trashed rows are never cached
+func (r *CachedGalleryRepository) PurgeTrash(ctx context.Context, before time.Time) (int64, int64, []string, error) {
+	return r.store.PurgeTrash(ctx, before)
+}
+
+// imageTags returns tag plus the tag of every image
+func imageTags(tag string, images []models.GalleryImage) []string {
+	tags := []string{tag}
+	for _, img := range images {
+		tags = append(tags, imageTag(img.ID))
+	}
+	return tags
+}
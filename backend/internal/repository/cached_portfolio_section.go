@@ -0,0 +1,45 @@
+// backend/internal/repository/cached_portfolio_section.go
+package repository
+
+import (
+	"context"
+
+	"github.com/supraik/Freelance-Portfolio/internal/cache"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// sectionsTag is carried by every cached portfolio section entry
+const sectionsTag = "sections"
+
+// CachedPortfolioSectionRepository serves portfolio sections from an
+// in-process cache and invalidates it on every write that goes through it
+type CachedPortfolioSectionRepository struct {
+	store PortfolioSectionStore
+	cache *cache.Cache
+}
+
+var _ PortfolioSectionStore = (*CachedPortfolioSectionRepository)(nil)
+
+// NewCachedPortfolioSectionRepository wraps store with a read-through cache
+func NewCachedPortfolioSectionRepository(store PortfolioSectionStore, c *cache.Cache) *CachedPortfolioSectionRepository {
+	return &CachedPortfolioSectionRepository{store: store, cache: c}
+}
+
+// GetAll returns all portfolio sections through the cache
+func (r *CachedPortfolioSectionRepository) GetAll(ctx context.Context) ([]models.PortfolioSection, error) {
+	value, err := r.cache.GetOrLoad(ctx, "sections:all", func(ctx context.Context) (interface{}, []string, error) {
+		sections, err := r.store.GetAll(ctx)
+		return sections, []string{sectionsTag}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]models.PortfolioSection(nil), value.([]models.PortfolioSection)...), nil
+}
+
+// UpdateImage updates a section's image and drops the cached sections
+func (r *CachedPortfolioSectionRepository) UpdateImage(ctx context.Context, sectionID int, cloudinaryID, imageURL string) error {
+	defer r.cache.Invalidate(sectionsTag)
+	return r.store.UpdateImage(ctx, sectionID, cloudinaryID, imageURL)
+}
@@ -2,24 +2,43 @@
 package repository
 
 import (
-	"database/sql"
+	"context"
 	"time"
 
+	"github.com/supraik/Freelance-Portfolio/internal/database"
 	"github.com/supraik/Freelance-Portfolio/internal/models"
 )
 
+// contactTables lists the columns ContactRepository reads and writes
+var contactTables = []database.Table{
+	{Name: "contact_messages", Columns: []database.Column{
+		{Name: "id", Type: database.TypeInteger},
+		{Name: "name", Type: database.TypeVarchar},
+		{Name: "email", Type: database.TypeVarchar},
+		{Name: "phone", Type: database.TypeVarchar},
+		{Name: "subject", Type: database.TypeVarchar},
+		{Name: "message", Type: database.TypeText},
+		{Name: "status", Type: database.TypeVarchar},
+		{Name: "created_at", Type: database.TypeTimestamp},
+		{Name: "updated_at", Type: database.TypeTimestamp},
+	}},
+}
+
 // ContactRepository handles contact message data operations
 type ContactRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
 // NewContactRepository creates a new contact repository
-func NewContactRepository(db *sql.DB) *ContactRepository {
+func NewContactRepository(db *database.DB) *ContactRepository {
 	return &ContactRepository{db: db}
 }
 
 // Create saves a new contact message
-func (r *ContactRepository) Create(req *models.ContactRequest) (*models.ContactMessage, error) {
+func (r *ContactRepository) Create(ctx context.Context, req *models.ContactRequest) (_ *models.ContactMessage, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "contact.create")
+	defer func() { err = finish(err) }()
+
 	msg := &models.ContactMessage{
 		Name:      req.Name,
 		Email:     req.Email,
@@ -37,7 +56,7 @@ func (r *ContactRepository) Create(req *models.ContactRequest) (*models.ContactM
 		RETURNING id
 	`
 
-	err := r.db.QueryRow(
+	err = r.db.QueryRow(
 		query,
 		msg.Name,
 		msg.Email,
@@ -57,14 +76,17 @@ func (r *ContactRepository) Create(req *models.ContactRequest) (*models.ContactM
 }
 
 // GetAll retrieves all contact messages
-func (r *ContactRepository) GetAll() ([]models.ContactMessage, error) {
+func (r *ContactRepository) GetAll(ctx context.Context) (_ []models.ContactMessage, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "contact.get_all")
+	defer func() { err = finish(err) }()
+
 	query := `
 		SELECT id, name, email, phone, subject, message, status, created_at, updated_at
 		FROM contact_messages
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -90,11 +112,14 @@ func (r *ContactRepository) GetAll() ([]models.ContactMessage, error) {
 		messages = append(messages, msg)
 	}
 
-	return messages, nil
+	return messages, rows.Err()
 }
 
 // GetByID retrieves a contact message by ID
-func (r *ContactRepository) GetByID(id int) (*models.ContactMessage, error) {
+func (r *ContactRepository) GetByID(ctx context.Context, id int) (_ *models.ContactMessage, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "contact.get_by_id")
+	defer func() { err = finish(err) }()
+
 	query := `
 		SELECT id, name, email, phone, subject, message, status, created_at, updated_at
 		FROM contact_messages
@@ -102,7 +127,7 @@ func (r *ContactRepository) GetByID(id int) (*models.ContactMessage, error) {
 	`
 
 	var msg models.ContactMessage
-	err := r.db.QueryRow(query, id).Scan(
+	err = r.db.QueryRowContext(ctx, query, id).Scan(
 		&msg.ID,
 		&msg.Name,
 		&msg.Email,
@@ -122,25 +147,100 @@ func (r *ContactRepository) GetByID(id int) (*models.ContactMessage, error) {
 }
 
 // UpdateStatus updates the status of a contact message
-func (r *ContactRepository) UpdateStatus(id int, status string) error {
+func (r *ContactRepository) UpdateStatus(ctx context.Context, id int, status string) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "contact.update_status")
+	defer func() { err = finish(err) }()
+
 	query := `
 		UPDATE contact_messages
 		SET status = $1, updated_at = $2
 		WHERE id = $3
 	`
 
-	_, err := r.db.Exec(query, status, time.Now(), id)
+	_, err = r.db.ExecContext(ctx, query, status, time.Now(), id)
 	return err
 }
 
 // MarkAsRead marks a contact message as read
-func (r *ContactRepository) MarkAsRead(id int) error {
-	return r.UpdateStatus(id, "read")
+func (r *ContactRepository) MarkAsRead(ctx context.Context, id int) error {
+	return r.UpdateStatus(ctx, id, "read")
 }
 
 // Delete removes a contact message
-func (r *ContactRepository) Delete(id int) error {
+func (r *ContactRepository) Delete(ctx context.Context, id int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "contact.delete")
+	defer func() { err = finish(err) }()
+
 	query := `DELETE FROM contact_messages WHERE id = $1`
-	_, err := r.db.Exec(query, id)
+	_, err = r.db.ExecContext(ctx, query, id)
 	return err
 }
+
+const (
+	pgContactSearch = `
+		SELECT m.id, m.name, m.email, m.phone, m.subject, m.message, m.status, m.created_at, m.updated_at,
+			ts_rank(m.search_vector, q.query) AS rank,
+			ts_headline('english', m.subject || ' ' || m.message, q.query, $3)
+		FROM contact_messages m
+		CROSS JOIN websearch_to_tsquery('english', $1) AS q(query)
+		WHERE m.search_vector @@ q.query
+		ORDER BY rank DESC, m.created_at DESC, m.id DESC
+		LIMIT $2
+	`
+	sqliteContactSearch = `
+		SELECT m.id, m.name, m.email, m.phone, m.subject, m.message, m.status, m.created_at, m.updated_at,
+			-bm25(contact_messages_fts, 10.0, 10.0, 4.0) AS rank,
+			snippet(contact_messages_fts, -1, char(2), char(3), ' ... ', 16)
+		FROM contact_messages_fts
+		JOIN contact_messages m ON m.id = contact_messages_fts.rowid
+		WHERE contact_messages_fts MATCH $1
+		ORDER BY rank DESC, m.created_at DESC, m.id DESC
+		LIMIT $2
+	`
+)
+
+// Search finds contact messages by name, subject and message, best matches first
+func (r *ContactRepository) Search(ctx context.Context, query string, limit int) (_ []models.ContactSearchHit, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "contact.search")
+	defer func() { err = finish(err) }()
+
+	hits := []models.ContactSearchHit{}
+	if len(SearchTerms(query)) == 0 {
+		return hits, nil
+	}
+
+	search, args := pgContactSearch, []interface{}{query, limit, headlineOptions}
+	if r.db.IsSQLite() {
+		search, args = sqliteContactSearch, []interface{}{ftsQuery(query), limit}
+	}
+
+	rows, err := r.db.QueryContext(ctx, search, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hit models.ContactSearchHit
+		err := rows.Scan(
+			&hit.ID,
+			&hit.Name,
+			&hit.Email,
+			&hit.Phone,
+			&hit.Subject,
+			&hit.Message,
+			&hit.Status,
+			&hit.CreatedAt,
+			&hit.UpdatedAt,
+			&hit.Rank,
+			&hit.Snippet,
+		)
+		if err != nil {
+			return nil, err
+		}
+		hit.Snippet = Highlight(hit.Snippet)
+		hits = append(hits, hit)
+	}
+
+	return hits, rows.Err()
+}
@@ -0,0 +1,242 @@
+// backend/internal/repository/gallery_move.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// MoveImages moves live images to another live category, inserting them in
+// the given order at position among the images already there (past the end
+// appends), and renumbers the destination. Source categories whose cover
+// was one of the moved images fall back to their first remaining image.
+func (r *GalleryRepository) MoveImages(ctx context.Context, ids []int, categoryID, position int) (_ []models.GalleryImage, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.move_images")
+	defer func() { err = finish(err) }()
+
+	return r.transfer(ctx, ids, categoryID, position, false)
+}
+
+// CopyImages copies live images to another live category like MoveImages
+// places them. Copies point at the same stored file as the originals.
+func (r *GalleryRepository) CopyImages(ctx context.Context, ids []int, categoryID, position int) (_ []models.GalleryImage, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.copy_images")
+	defer func() { err = finish(err) }()
+
+	return r.transfer(ctx, ids, categoryID, position, true)
+}
+
+// transfer implements MoveImages and CopyImages in one transaction
+func (r *GalleryRepository) transfer(ctx context.Context, ids []int, categoryID, position int, copying bool) ([]models.GalleryImage, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var live bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM gallery_categories WHERE id = $1 AND deleted_at IS NULL)
+	`, categoryID).Scan(&live)
+	if err != nil {
+		return nil, err
+	}
+	if !live {
+		return nil, ErrCategoryNotFound
+	}
+
+	sources := make([]*models.GalleryImage, 0, len(ids))
+	for _, id := range UniqueIDs(ids) {
+		img, err := imageByID(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, img)
+	}
+
+	existing, err := r.lockIDs(ctx, tx, `
+		SELECT id FROM gallery_images
+		WHERE category_id = $1 AND deleted_at IS NULL
+		ORDER BY display_order ASC, created_at DESC, id DESC
+	`, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var placed []int
+	for _, src := range sources {
+		if !copying {
+			_, err := tx.ExecContext(ctx, `
+				UPDATE gallery_images
+				SET category_id = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $2
+			`, categoryID, src.ID)
+			if err != nil {
+				return nil, err
+			}
+			placed = append(placed, src.ID)
+			continue
+		}
+
+		metadata, err := metadataValue(src.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		variants, err := variantsValue(src.Variants)
+		if err != nil {
+			return nil, err
+		}
+		var id int
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO gallery_images (category_id, src, alt, aspect_ratio, width, height, metadata,
+				blurhash, lqip, dominant_color, variants, original_filename, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, CURRENT_TIMESTAMP)
+			RETURNING id
+		`, categoryID, src.Src, src.Alt, src.AspectRatio, src.Width, src.Height, metadata,
+			src.BlurHash, src.LQIP, src.DominantColor, variants, src.Filename).Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		// Copies carry the tags of their original
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO image_tags (image_id, tag_id)
+			SELECT CAST($1 AS INTEGER), tag_id FROM image_tags WHERE image_id = $2
+		`, id, src.ID)
+		if err != nil {
+			return nil, err
+		}
+		placed = append(placed, id)
+	}
+
+	if err := renumber(ctx, tx, "gallery_images", InsertIDs(existing, placed, position)); err != nil {
+		return nil, err
+	}
+
+	if !copying {
+		for _, src := range sources {
+			if src.CategoryID == categoryID {
+				continue
+			}
+			if err := replaceCover(ctx, tx, src.CategoryID, src.Src); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	images := make([]models.GalleryImage, 0, len(placed))
+	for _, id := range placed {
+		img, err := imageByID(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, *img)
+	}
+
+	return images, tx.Commit()
+}
+
+// replaceCover points a category's cover at its first live image when the
+// cover is src and no image left in the category uses src
+func replaceCover(ctx context.Context, tx *database.Tx, categoryID int, src string) error {
+	var cover string
+	err := tx.QueryRowContext(ctx, `
+		SELECT COALESCE(cover_image, '') FROM gallery_categories WHERE id = $1
+	`, categoryID).Scan(&cover)
+	if err != nil || cover != src {
+		return err
+	}
+
+	// An image still using src sorts first and keeps the cover as it is
+	var first string
+	err = tx.QueryRowContext(ctx, `
+		SELECT src FROM gallery_images
+		WHERE category_id = $1 AND deleted_at IS NULL
+		ORDER BY src = $2 DESC, display_order ASC, created_at DESC, id DESC
+		LIMIT 1
+	`, categoryID, src).Scan(&first)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if first == src {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE gallery_categories
+		SET cover_image = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`, first, categoryID)
+	return err
+}
+
+// imageByID loads a live image inside a transaction
+func imageByID(ctx context.Context, tx *database.Tx, id int) (*models.GalleryImage, error) {
+	var img models.GalleryImage
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, category_id, src, original_filename, alt, aspect_ratio, width, height, display_order, version, created_at, updated_at, metadata, blurhash, lqip, dominant_color, variants
+		FROM gallery_images
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id).Scan(
+		&img.ID,
+		&img.CategoryID,
+		&img.Src,
+		&img.Filename,
+		&img.Alt,
+		&img.AspectRatio,
+		&img.Width,
+		&img.Height,
+		&img.DisplayOrder,
+		&img.Version,
+		&img.CreatedAt,
+		&img.UpdatedAt,
+		imageMetadata{&img.Metadata},
+		&img.BlurHash,
+		&img.LQIP,
+		&img.DominantColor,
+		imageVariants{&img.Variants},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &img, nil
+}
+
+// UniqueIDs returns ids without repeats, keeping the first occurrence
+func UniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// InsertIDs returns order with ids removed from wherever they were and
+// inserted at position, or at the end when position is out of range
+func InsertIDs(order, ids []int, position int) []int {
+	moving := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		moving[id] = true
+	}
+
+	rest := make([]int, 0, len(order))
+	for _, id := range order {
+		if !moving[id] {
+			rest = append(rest, id)
+		}
+	}
+	if position < 0 || position > len(rest) {
+		position = len(rest)
+	}
+
+	result := make([]int, 0, len(rest)+len(ids))
+	result = append(result, rest[:position]...)
+	result = append(result, ids...)
+	return append(result, rest[position:]...)
+}
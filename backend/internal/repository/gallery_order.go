@@ -0,0 +1,132 @@
+// backend/internal/repository/gallery_order.go
+package repository
+
+import (
+	"context"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+)
+
+// CheckOrder returns ErrOrderMismatch unless ids lists every id of current
+// exactly once
+func CheckOrder(current, ids []int) error {
+	if len(ids) != len(current) {
+		return ErrOrderMismatch
+	}
+
+	pending := make(map[int]bool, len(current))
+	for _, id := range current {
+		pending[id] = true
+	}
+	for _, id := range ids {
+		if !pending[id] {
+			return ErrOrderMismatch
+		}
+		delete(pending, id)
+	}
+	return nil
+}
+
+// ReorderCategories sets the display order of the live categories to their
+// position in ids, in one transaction
+func (r *GalleryRepository) ReorderCategories(ctx context.Context, ids []int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.reorder_categories")
+	defer func() { err = finish(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := r.lockIDs(ctx, tx, `SELECT id FROM gallery_categories WHERE deleted_at IS NULL`)
+	if err != nil {
+		return err
+	}
+	if err := CheckOrder(current, ids); err != nil {
+		return err
+	}
+	if err := renumber(ctx, tx, "gallery_categories", ids); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReorderImages sets the display order of a live category's images to their
+// position in ids, in one transaction
+func (r *GalleryRepository) ReorderImages(ctx context.Context, categoryID int, ids []int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.reorder_images")
+	defer func() { err = finish(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var live bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM gallery_categories WHERE id = $1 AND deleted_at IS NULL)
+	`, categoryID).Scan(&live)
+	if err != nil {
+		return err
+	}
+	if !live {
+		return ErrCategoryNotFound
+	}
+
+	current, err := r.lockIDs(ctx, tx, `SELECT id FROM gallery_images WHERE category_id = $1 AND deleted_at IS NULL`, categoryID)
+	if err != nil {
+		return err
+	}
+	if err := CheckOrder(current, ids); err != nil {
+		return err
+	}
+	if err := renumber(ctx, tx, "gallery_images", ids); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockIDs returns the ids selected by query. On Postgres the rows stay
+// locked until the transaction ends so concurrent edits wait for the
+// reorder; SQLite has no row locks and allows only one writer anyway.
+func (r *GalleryRepository) lockIDs(ctx context.Context, tx *database.Tx, query string, args ...interface{}) ([]int, error) {
+	if !r.db.IsSQLite() {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// renumber gives each row its 1-based position in ids. Rows whose order
+// changes get a new version, the others are left alone.
+func renumber(ctx context.Context, tx *database.Tx, table string, ids []int) error {
+	query := `
+		UPDATE ` + table + `
+		SET display_order = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND display_order <> $1
+	`
+	for i, id := range ids {
+		if _, err := tx.ExecContext(ctx, query, i+1, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
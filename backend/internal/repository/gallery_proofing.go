@@ -0,0 +1,417 @@
+// backend/internal/repository/gallery_proofing.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// proofingTables lists the columns the proofing queries of GalleryRepository read and write
+var proofingTables = []database.Table{
+	{Name: "proofing_galleries", Columns: []database.Column{
+		{Name: "category_id", Type: database.TypeInteger},
+		{Name: "max_picks", Type: database.TypeInteger},
+		{Name: "created_at", Type: database.TypeTimestamp},
+		{Name: "updated_at", Type: database.TypeTimestamp},
+	}},
+	{Name: "proofing_clients", Columns: []database.Column{
+		{Name: "id", Type: database.TypeInteger},
+		{Name: "category_id", Type: database.TypeInteger},
+		{Name: "name", Type: database.TypeVarchar},
+		{Name: "email", Type: database.TypeVarchar},
+		{Name: "submitted_at", Type: database.TypeTimestamp},
+		{Name: "revoked_at", Type: database.TypeTimestamp},
+		{Name: "created_at", Type: database.TypeTimestamp},
+	}},
+	{Name: "proofing_marks", Columns: []database.Column{
+		{Name: "client_id", Type: database.TypeInteger},
+		{Name: "image_id", Type: database.TypeInteger},
+		{Name: "favorite", Type: database.TypeBoolean},
+		{Name: "comment", Type: database.TypeText},
+		{Name: "updated_at", Type: database.TypeTimestamp},
+	}},
+}
+
+const proofingClientColumns = `id, category_id, name, email, submitted_at, revoked_at, created_at`
+
+// ProofingSettings returns a gallery's proofing settings, or sql.ErrNoRows
+// if it is not in proofing mode
+func (r *GalleryRepository) ProofingSettings(ctx context.Context, categoryID int) (_ *models.ProofingSettings, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "proofing.settings")
+	defer func() { err = finish(err) }()
+
+	var settings models.ProofingSettings
+	err = r.db.QueryRowContext(ctx, `
+		SELECT category_id, max_picks, created_at, updated_at
+		FROM proofing_galleries
+		WHERE category_id = $1
+	`, categoryID).Scan(
+		&settings.CategoryID,
+		&settings.MaxPicks,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	settings.CreatedAt = settings.CreatedAt.UTC()
+	settings.UpdatedAt = settings.UpdatedAt.UTC()
+	return &settings, nil
+}
+
+// EnableProofing puts a live gallery in proofing mode or changes its pick
+// limit, filling in CreatedAt and UpdatedAt
+func (r *GalleryRepository) EnableProofing(ctx context.Context, settings *models.ProofingSettings) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "proofing.enable")
+	defer func() { err = finish(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := requireLive(ctx, tx, "gallery_categories", settings.CategoryID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO proofing_galleries (category_id, max_picks, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (category_id) DO UPDATE
+		SET max_picks = excluded.max_picks, updated_at = excluded.updated_at
+		RETURNING created_at, updated_at
+	`
+	err = tx.QueryRowContext(ctx, query, settings.CategoryID, settings.MaxPicks, time.Now().UTC()).Scan(
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		return translateError(err)
+	}
+	settings.CreatedAt = settings.CreatedAt.UTC()
+	settings.UpdatedAt = settings.UpdatedAt.UTC()
+
+	return tx.Commit()
+}
+
+// DisableProofing takes a gallery out of proofing mode. Its clients and
+// their marks are kept.
+func (r *GalleryRepository) DisableProofing(ctx context.Context, categoryID int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "proofing.disable")
+	defer func() { err = finish(err) }()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM proofing_galleries WHERE category_id = $1`, categoryID)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CreateProofingClient invites a client, filling in its ID and CreatedAt.
+// It returns ErrCategoryNotFound if the gallery does not exist.
+func (r *GalleryRepository) CreateProofingClient(ctx context.Context, client *models.ProofingClient) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "proofing.client_create")
+	defer func() { err = finish(err) }()
+
+	client.CreatedAt = time.Now().UTC()
+	client.SubmittedAt = nil
+	client.RevokedAt = nil
+
+	query := `
+		INSERT INTO proofing_clients (category_id, name, email, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	err = r.db.QueryRowContext(ctx, query, client.CategoryID, client.Name, client.Email, client.CreatedAt).Scan(&client.ID)
+	return translateError(err)
+}
+
+// GetProofingClient returns a client, revoked ones included
+func (r *GalleryRepository) GetProofingClient(ctx context.Context, id int) (_ *models.ProofingClient, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "proofing.client_get")
+	defer func() { err = finish(err) }()
+
+	client, err := scanProofingClient(r.db.QueryRowContext(ctx, `SELECT `+proofingClientColumns+` FROM proofing_clients WHERE id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// ListProofingClients returns a gallery's clients in the order they were invited
+func (r *GalleryRepository) ListProofingClients(ctx context.Context, categoryID int) (_ []models.ProofingClient, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "proofing.client_list")
+	defer func() { err = finish(err) }()
+
+	query := `
+		SELECT ` + proofingClientColumns + `
+		FROM proofing_clients
+		WHERE category_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clients := []models.ProofingClient{}
+	for rows.Next() {
+		client, err := scanProofingClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, *client)
+	}
+
+	return clients, rows.Err()
+}
+
+// RevokeProofingClient stops a client's link from working.
+// It returns sql.ErrNoRows if the client does not exist or is already revoked.
+func (r *GalleryRepository) RevokeProofingClient(ctx context.Context, id int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "proofing.client_revoke")
+	defer func() { err = finish(err) }()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE proofing_clients SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ProofingMarks returns a client's marks on live images of its gallery, in gallery order
+func (r *GalleryRepository) ProofingMarks(ctx context.Context, clientID int) (_ []models.ProofingMark, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "proofing.marks")
+	defer func() { err = finish(err) }()
+
+	query := `
+		SELECT m.client_id, m.image_id, i.src, i.original_filename, m.favorite, m.comment, m.updated_at
+		FROM proofing_marks m
+		JOIN proofing_clients pc ON pc.id = m.client_id
+		JOIN gallery_images i ON i.id = m.image_id AND i.category_id = pc.category_id
+		WHERE m.client_id = $1 AND i.deleted_at IS NULL
+		ORDER BY i.display_order ASC, i.created_at DESC, i.id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, clientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	marks := []models.ProofingMark{}
+	for rows.Next() {
+		var mark models.ProofingMark
+		if err := rows.Scan(
+			&mark.ClientID,
+			&mark.ImageID,
+			&mark.Src,
+			&mark.Filename,
+			&mark.Favorite,
+			&mark.Comment,
+			&mark.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		mark.UpdatedAt = mark.UpdatedAt.UTC()
+		marks = append(marks, mark)
+	}
+
+	return marks, rows.Err()
+}
+
+// SetProofingMark stores a mark, filling in its Src, Filename and UpdatedAt,
+// or drops it when it is neither a favorite nor has a comment
+func (r *GalleryRepository) SetProofingMark(ctx context.Context, mark *models.ProofingMark) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "proofing.mark")
+	defer func() { err = finish(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	categoryID, maxPicks, err := r.lockProofingClient(ctx, tx, mark.ClientID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT src, original_filename FROM gallery_images
+		WHERE id = $1 AND category_id = $2 AND deleted_at IS NULL
+	`, mark.ImageID, categoryID).Scan(&mark.Src, &mark.Filename)
+	if err != nil {
+		return err
+	}
+
+	mark.UpdatedAt = time.Now().UTC()
+	if !mark.Favorite && mark.Comment == "" {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM proofing_marks WHERE client_id = $1 AND image_id = $2`, mark.ClientID, mark.ImageID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	if mark.Favorite && maxPicks > 0 {
+		others, err := countFavorites(ctx, tx, mark.ClientID, categoryID, mark.ImageID)
+		if err != nil {
+			return err
+		}
+		if others >= maxPicks {
+			return ErrPickLimit
+		}
+	}
+
+	query := `
+		INSERT INTO proofing_marks (client_id, image_id, favorite, comment, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (client_id, image_id) DO UPDATE
+		SET favorite = excluded.favorite, comment = excluded.comment, updated_at = excluded.updated_at
+	`
+	if _, err := tx.ExecContext(ctx, query, mark.ClientID, mark.ImageID, mark.Favorite, mark.Comment, mark.UpdatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SubmitProofingSelection makes a client's selection final
+func (r *GalleryRepository) SubmitProofingSelection(ctx context.Context, clientID int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "proofing.submit")
+	defer func() { err = finish(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	categoryID, maxPicks, err := r.lockProofingClient(ctx, tx, clientID)
+	if err != nil {
+		return err
+	}
+
+	picks, err := countFavorites(ctx, tx, clientID, categoryID, 0)
+	if err != nil {
+		return err
+	}
+	if picks == 0 {
+		return ErrEmptySelection
+	}
+	// The limit may have been lowered after the favorites were picked
+	if maxPicks > 0 && picks > maxPicks {
+		return ErrPickLimit
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE proofing_clients SET submitted_at = $1 WHERE id = $2`, time.Now().UTC(), clientID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReopenProofingSelection lets a client change a submitted selection again.
+// It returns sql.ErrNoRows if the selection was not submitted.
+func (r *GalleryRepository) ReopenProofingSelection(ctx context.Context, clientID int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "proofing.reopen")
+	defer func() { err = finish(err) }()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE proofing_clients SET submitted_at = NULL WHERE id = $1 AND submitted_at IS NOT NULL`, clientID)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// lockProofingClient locks a client that may still change its selection
+// and returns its gallery and pick limit. It returns sql.ErrNoRows for
+// missing or revoked clients and galleries no longer in proofing mode.
+func (r *GalleryRepository) lockProofingClient(ctx context.Context, tx *database.Tx, clientID int) (categoryID, maxPicks int, err error) {
+	query := `SELECT category_id, submitted_at FROM proofing_clients WHERE id = $1 AND revoked_at IS NULL`
+	if !r.db.IsSQLite() {
+		query += " FOR UPDATE"
+	}
+
+	var submittedAt *time.Time
+	if err := tx.QueryRowContext(ctx, query, clientID).Scan(&categoryID, &submittedAt); err != nil {
+		return 0, 0, err
+	}
+	if submittedAt != nil {
+		return 0, 0, ErrSelectionSubmitted
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT pg.max_picks
+		FROM proofing_galleries pg
+		JOIN gallery_categories c ON c.id = pg.category_id
+		WHERE pg.category_id = $1 AND c.deleted_at IS NULL
+	`, categoryID).Scan(&maxPicks)
+	if err != nil {
+		return 0, 0, err
+	}
+	return categoryID, maxPicks, nil
+}
+
+// countFavorites counts a client's favorites among the live images of its
+// gallery, leaving out exceptImageID
+func countFavorites(ctx context.Context, tx *database.Tx, clientID, categoryID, exceptImageID int) (int, error) {
+	var n int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM proofing_marks m
+		JOIN gallery_images i ON i.id = m.image_id
+		WHERE m.client_id = $1 AND m.favorite AND m.image_id <> $2
+			AND i.category_id = $3 AND i.deleted_at IS NULL
+	`, clientID, exceptImageID, categoryID).Scan(&n)
+	return n, err
+}
+
+// scanProofingClient reads one row selected with proofingClientColumns
+func scanProofingClient(row interface{ Scan(...interface{}) error }) (*models.ProofingClient, error) {
+	var client models.ProofingClient
+	if err := row.Scan(
+		&client.ID,
+		&client.CategoryID,
+		&client.Name,
+		&client.Email,
+		&client.SubmittedAt,
+		&client.RevokedAt,
+		&client.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	client.CreatedAt = client.CreatedAt.UTC()
+	client.SubmittedAt = utc(client.SubmittedAt)
+	client.RevokedAt = utc(client.RevokedAt)
+	return &client, nil
+}
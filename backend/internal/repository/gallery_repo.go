@@ -2,36 +2,126 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/supraik/Freelance-Portfolio/internal/database"
 	"github.com/supraik/Freelance-Portfolio/internal/models"
 )
 
+// galleryTables lists the columns GalleryRepository reads and writes
+var galleryTables = []database.Table{
+	{Name: "gallery_categories", Columns: []database.Column{
+		{Name: "id", Type: database.TypeInteger},
+		{Name: "slug", Type: database.TypeVarchar},
+		{Name: "title", Type: database.TypeVarchar},
+		{Name: "description", Type: database.TypeText},
+		{Name: "cover_image", Type: database.TypeVarchar},
+		{Name: "display_order", Type: database.TypeInteger},
+		{Name: "status", Type: database.TypeVarchar},
+		{Name: "publish_at", Type: database.TypeTimestamp},
+		{Name: "unpublish_at", Type: database.TypeTimestamp},
+		{Name: "access", Type: database.TypeVarchar},
+		{Name: "password_hash", Type: database.TypeVarchar},
+		{Name: "version", Type: database.TypeInteger},
+		{Name: "created_at", Type: database.TypeTimestamp},
+		{Name: "updated_at", Type: database.TypeTimestamp},
+		{Name: "deleted_at", Type: database.TypeTimestamp},
+	}},
+	{Name: "gallery_images", Columns: []database.Column{
+		{Name: "id", Type: database.TypeInteger},
+		{Name: "category_id", Type: database.TypeInteger},
+		{Name: "src", Type: database.TypeVarchar},
+		{Name: "alt", Type: database.TypeVarchar},
+		{Name: "aspect_ratio", Type: database.TypeVarchar},
+		{Name: "width", Type: database.TypeInteger},
+		{Name: "height", Type: database.TypeInteger},
+		{Name: "display_order", Type: database.TypeInteger},
+		{Name: "version", Type: database.TypeInteger},
+		{Name: "created_at", Type: database.TypeTimestamp},
+		{Name: "updated_at", Type: database.TypeTimestamp},
+		{Name: "deleted_at", Type: database.TypeTimestamp},
+		{Name: "metadata", Type: database.TypeJSONB},
+		{Name: "blurhash", Type: database.TypeVarchar},
+		{Name: "lqip", Type: database.TypeText},
+		{Name: "dominant_color", Type: database.TypeVarchar},
+		{Name: "variants", Type: database.TypeJSONB},
+		{Name: "original_filename", Type: database.TypeVarchar},
+	}},
+}
+
+// publicCondition limits a query to galleries the public can see, see
+// models.GalleryCategory.IsPublic. prefix qualifies the columns (e.g. "c.")
+// and $n is the placeholder for publicNow().
+func publicCondition(prefix string, n int) string {
+	return strings.NewReplacer("c.", prefix, "$n", "$"+strconv.Itoa(n)).Replace(
+		`c.status = 'published' AND (c.publish_at IS NULL OR c.publish_at <= $n) AND (c.unpublish_at IS NULL OR c.unpublish_at > $n)`,
+	)
+}
+
+// listedCondition limits a query to galleries shown in public lists, see
+// models.GalleryCategory.IsListed. Arguments are those of publicCondition.
+func listedCondition(prefix string, n int) string {
+	return publicCondition(prefix, n) + " AND " + prefix + "access = 'public'"
+}
+
+// publicNow is the time publish_at and unpublish_at are compared with. They
+// are stored in UTC so both dialects compare them correctly.
+func publicNow() time.Time {
+	return time.Now().UTC()
+}
+
+// utc converts an optional schedule time to UTC for storage
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
 // GalleryRepository handles database operations for galleries
 type GalleryRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
 // NewGalleryRepository creates a new repository
-func NewGalleryRepository(db *sql.DB) *GalleryRepository {
+func NewGalleryRepository(db *database.DB) *GalleryRepository {
 	return &GalleryRepository{db: db}
 }
 
-// GetAllCategories retrieves all gallery categories with their images
-func (r *GalleryRepository) GetAllCategories() ([]models.GalleryCategory, error) {
+// GetAllCategories retrieves the public gallery categories, or all of them
+// with opts.AllStatuses, and optionally their images, with two queries
+// regardless of the number of categories
+func (r *GalleryRepository) GetAllCategories(ctx context.Context, opts CategoryListOptions) (categories []models.GalleryCategory, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.get_all_categories")
+	defer func() { err = finish(err) }()
+
+	where := "deleted_at IS NULL"
+	var args []interface{}
+	if !opts.AllStatuses {
+		where += " AND " + listedCondition("", 1)
+		args = append(args, publicNow())
+	}
+
 	query := `
-		SELECT id, slug, title, description, cover_image, display_order, created_at, updated_at
+		SELECT id, slug, title, description, cover_image, display_order, status, publish_at, unpublish_at, access, version, created_at, updated_at
 		FROM gallery_categories
-		ORDER BY display_order ASC, created_at DESC
+		WHERE ` + where + `
+		ORDER BY display_order ASC, created_at DESC, id DESC
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var categories []models.GalleryCategory
 	for rows.Next() {
 		var cat models.GalleryCategory
 		if err := rows.Scan(
@@ -41,41 +131,139 @@ func (r *GalleryRepository) GetAllCategories() ([]models.GalleryCategory, error)
 			&cat.Description,
 			&cat.CoverImage,
 			&cat.DisplayOrder,
+			&cat.Status,
+			&cat.PublishAt,
+			&cat.UnpublishAt,
+			&cat.Access,
+			&cat.Version,
 			&cat.CreatedAt,
 			&cat.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
+		categories = append(categories, cat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Release the connection before loading images
+	rows.Close()
 
-		// Load images for this category
-		images, err := r.GetImagesByCategory(cat.ID)
-		if err != nil {
-			return nil, err
-		}
-		cat.Images = images
+	if !opts.IncludeImages || len(categories) == 0 {
+		return categories, nil
+	}
 
-		categories = append(categories, cat)
+	images, err := r.imagesForAll(ctx, opts.ImagesLimit, opts.AllStatuses)
+	if err != nil {
+		return nil, err
+	}
+	for i := range categories {
+		categories[i].Images = images[categories[i].ID]
 	}
 
 	return categories, nil
 }
 
-// GetCategoryBySlug retrieves a single category by slug
-func (r *GalleryRepository) GetCategoryBySlug(slug string) (*models.GalleryCategory, error) {
+// imagesForAll loads the images of every category GetAllCategories lists
+// in one query, keeping at most limit per category when limit > 0. Only
+// listed categories are joined unless allStatuses is set.
+func (r *GalleryRepository) imagesForAll(ctx context.Context, limit int, allStatuses bool) (map[int][]models.GalleryImage, error) {
+	where := "i.deleted_at IS NULL AND c.deleted_at IS NULL"
+	args := []interface{}{limit}
+	if !allStatuses {
+		where += " AND " + listedCondition("c.", 2)
+		args = append(args, publicNow())
+	}
+
 	query := `
-		SELECT id, slug, title, description, cover_image, display_order, created_at, updated_at
+		SELECT id, category_id, src, original_filename, alt, aspect_ratio, width, height, display_order, version, created_at, updated_at, metadata, blurhash, lqip, dominant_color, variants
+		FROM (
+			SELECT i.id, i.category_id, i.src, i.original_filename, i.alt, i.aspect_ratio, i.width, i.height, i.display_order, i.version, i.created_at, i.updated_at, i.metadata, i.blurhash, i.lqip, i.dominant_color, i.variants,
+				ROW_NUMBER() OVER (
+					PARTITION BY i.category_id
+					ORDER BY i.display_order ASC, i.created_at DESC, i.id DESC
+				) AS position
+			FROM gallery_images i
+			JOIN gallery_categories c ON c.id = i.category_id
+			WHERE ` + where + `
+		) ranked
+		WHERE $1 <= 0 OR position <= $1
+		ORDER BY category_id, position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := make(map[int][]models.GalleryImage)
+	for rows.Next() {
+		var img models.GalleryImage
+		if err := rows.Scan(
+			&img.ID,
+			&img.CategoryID,
+			&img.Src,
+			&img.Filename,
+			&img.Alt,
+			&img.AspectRatio,
+			&img.Width,
+			&img.Height,
+			&img.DisplayOrder,
+			&img.Version,
+			&img.CreatedAt,
+			&img.UpdatedAt,
+			imageMetadata{&img.Metadata},
+			&img.BlurHash,
+			&img.LQIP,
+			&img.DominantColor,
+			imageVariants{&img.Variants},
+		); err != nil {
+			return nil, err
+		}
+		images[img.CategoryID] = append(images[img.CategoryID], img)
+	}
+
+	return images, rows.Err()
+}
+
+// GetCategoryBySlug retrieves a single public category by slug
+func (r *GalleryRepository) GetCategoryBySlug(ctx context.Context, slug string) (_ *models.GalleryCategory, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.get_category_by_slug")
+	defer func() { err = finish(err) }()
+
+	return r.getCategory(ctx, "slug = $1 AND "+publicCondition("", 2), slug, publicNow())
+}
+
+// GetCategoryByID retrieves a single category by ID, whatever its status
+func (r *GalleryRepository) GetCategoryByID(ctx context.Context, id int) (_ *models.GalleryCategory, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.get_category_by_id")
+	defer func() { err = finish(err) }()
+
+	return r.getCategory(ctx, "id = $1", id)
+}
+
+// getCategory loads the live category matching condition, with its images
+func (r *GalleryRepository) getCategory(ctx context.Context, condition string, args ...interface{}) (*models.GalleryCategory, error) {
+	query := `
+		SELECT id, slug, title, description, cover_image, display_order, status, publish_at, unpublish_at, access, version, created_at, updated_at
 		FROM gallery_categories
-		WHERE slug = $1
+		WHERE ` + condition + ` AND deleted_at IS NULL
 	`
 
 	var cat models.GalleryCategory
-	err := r.db.QueryRow(query, slug).Scan(
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
 		&cat.ID,
 		&cat.Slug,
 		&cat.Title,
 		&cat.Description,
 		&cat.CoverImage,
 		&cat.DisplayOrder,
+		&cat.Status,
+		&cat.PublishAt,
+		&cat.UnpublishAt,
+		&cat.Access,
+		&cat.Version,
 		&cat.CreatedAt,
 		&cat.UpdatedAt,
 	)
@@ -85,7 +273,7 @@ func (r *GalleryRepository) GetCategoryBySlug(slug string) (*models.GalleryCateg
 	}
 
 	// Load images
-	images, err := r.GetImagesByCategory(cat.ID)
+	images, err := r.imagesByCategory(ctx, cat.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -95,15 +283,61 @@ func (r *GalleryRepository) GetCategoryBySlug(slug string) (*models.GalleryCateg
 }
 
 // GetImagesByCategory retrieves all images for a category
-func (r *GalleryRepository) GetImagesByCategory(categoryID int) ([]models.GalleryImage, error) {
+func (r *GalleryRepository) GetImagesByCategory(ctx context.Context, categoryID int) (_ []models.GalleryImage, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.get_images_by_category")
+	defer func() { err = finish(err) }()
+
+	return r.imagesByCategory(ctx, categoryID)
+}
+
+// GetImageByID retrieves a single image by ID
+func (r *GalleryRepository) GetImageByID(ctx context.Context, id int) (_ *models.GalleryImage, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.get_image_by_id")
+	defer func() { err = finish(err) }()
+
 	query := `
-		SELECT id, category_id, src, alt, aspect_ratio, display_order, created_at
+		SELECT id, category_id, src, original_filename, alt, aspect_ratio, width, height, display_order, version, created_at, updated_at, metadata, blurhash, lqip, dominant_color, variants
 		FROM gallery_images
-		WHERE category_id = $1
-		ORDER BY display_order ASC, created_at DESC
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	rows, err := r.db.Query(query, categoryID)
+	var img models.GalleryImage
+	err = r.db.QueryRowContext(ctx, query, id).Scan(
+		&img.ID,
+		&img.CategoryID,
+		&img.Src,
+		&img.Filename,
+		&img.Alt,
+		&img.AspectRatio,
+		&img.Width,
+		&img.Height,
+		&img.DisplayOrder,
+		&img.Version,
+		&img.CreatedAt,
+		&img.UpdatedAt,
+		imageMetadata{&img.Metadata},
+		&img.BlurHash,
+		&img.LQIP,
+		&img.DominantColor,
+		imageVariants{&img.Variants},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &img, nil
+}
+
+// imagesByCategory loads a category's images within the caller's deadline
+func (r *GalleryRepository) imagesByCategory(ctx context.Context, categoryID int) ([]models.GalleryImage, error) {
+	query := `
+		SELECT id, category_id, src, original_filename, alt, aspect_ratio, width, height, display_order, version, created_at, updated_at, metadata, blurhash, lqip, dominant_color, variants
+		FROM gallery_images
+		WHERE category_id = $1 AND deleted_at IS NULL
+		ORDER BY display_order ASC, created_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, categoryID)
 	if err != nil {
 		return nil, err
 	}
@@ -116,82 +350,393 @@ func (r *GalleryRepository) GetImagesByCategory(categoryID int) ([]models.Galler
 			&img.ID,
 			&img.CategoryID,
 			&img.Src,
+			&img.Filename,
 			&img.Alt,
 			&img.AspectRatio,
+			&img.Width,
+			&img.Height,
 			&img.DisplayOrder,
+			&img.Version,
 			&img.CreatedAt,
+			&img.UpdatedAt,
+			imageMetadata{&img.Metadata},
+			&img.BlurHash,
+			&img.LQIP,
+			&img.DominantColor,
+			imageVariants{&img.Variants},
 		); err != nil {
 			return nil, err
 		}
 		images = append(images, img)
 	}
 
-	return images, nil
+	return images, rows.Err()
 }
 
 // CreateCategory creates a new gallery category
-func (r *GalleryRepository) CreateCategory(cat *models.GalleryCategory) error {
+func (r *GalleryRepository) CreateCategory(ctx context.Context, cat *models.GalleryCategory) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.create_category")
+	defer func() { err = finish(err) }()
+
+	if cat.Status == "" {
+		cat.Status = models.GalleryPublished
+	}
+	if cat.Access == "" {
+		cat.Access = models.GalleryAccessPublic
+	}
+	cat.PublishAt, cat.UnpublishAt = utc(cat.PublishAt), utc(cat.UnpublishAt)
+
 	query := `
-		INSERT INTO gallery_categories (slug, title, description, cover_image, display_order)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at, updated_at
+		INSERT INTO gallery_categories (slug, title, description, cover_image, display_order, status, publish_at, unpublish_at, access)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, version, created_at, updated_at
 	`
 
-	return r.db.QueryRow(query, cat.Slug, cat.Title, cat.Description, cat.CoverImage, cat.DisplayOrder).Scan(
+	err = r.db.QueryRowContext(ctx, query, cat.Slug, cat.Title, cat.Description, cat.CoverImage, cat.DisplayOrder,
+		cat.Status, cat.PublishAt, cat.UnpublishAt, cat.Access).Scan(
 		&cat.ID,
+		&cat.Version,
 		&cat.CreatedAt,
 		&cat.UpdatedAt,
 	)
+	return translateError(err)
 }
 
-// UpdateCategory updates an existing category
-func (r *GalleryRepository) UpdateCategory(cat *models.GalleryCategory) error {
+// UpdateCategory updates an existing category and bumps its version.
+// A non-zero cat.Version is the version the caller last read; the update
+// only applies if it is still current.
+func (r *GalleryRepository) UpdateCategory(ctx context.Context, cat *models.GalleryCategory) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.update_category")
+	defer func() { err = finish(err) }()
+
 	query := `
 		UPDATE gallery_categories
-		SET title = $1, description = $2, cover_image = $3, display_order = $4, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $5
-		RETURNING updated_at
+		SET title = $1, description = $2, cover_image = $3, display_order = $4,
+			version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5 AND deleted_at IS NULL AND ($6 = 0 OR version = $6)
+		RETURNING version, updated_at
 	`
 
-	return r.db.QueryRow(query, cat.Title, cat.Description, cat.CoverImage, cat.DisplayOrder, cat.ID).Scan(&cat.UpdatedAt)
+	err = r.db.QueryRowContext(ctx, query, cat.Title, cat.Description, cat.CoverImage, cat.DisplayOrder, cat.ID, cat.Version).Scan(
+		&cat.Version,
+		&cat.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return r.missingOrConflict(ctx, "gallery_categories", cat.ID)
+	}
+	return err
 }
 
-// DeleteCategory deletes a category and all its images
-func (r *GalleryRepository) DeleteCategory(id int) error {
-	query := `DELETE FROM gallery_categories WHERE id = $1`
-	_, err := r.db.Exec(query, id)
+// UpdateCategoryStatus sets a category's status and publishing window,
+// checking a non-zero cat.Version like UpdateCategory does
+func (r *GalleryRepository) UpdateCategoryStatus(ctx context.Context, cat *models.GalleryCategory) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.update_category_status")
+	defer func() { err = finish(err) }()
+
+	cat.PublishAt, cat.UnpublishAt = utc(cat.PublishAt), utc(cat.UnpublishAt)
+
+	query := `
+		UPDATE gallery_categories
+		SET status = $1, publish_at = $2, unpublish_at = $3, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND deleted_at IS NULL AND ($5 = 0 OR version = $5)
+		RETURNING version, updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query, cat.Status, cat.PublishAt, cat.UnpublishAt, cat.ID, cat.Version).Scan(
+		&cat.Version,
+		&cat.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return r.missingOrConflict(ctx, "gallery_categories", cat.ID)
+	}
 	return err
 }
 
+// UpdateCategoryAccess sets a category's access mode, checking a non-zero
+// cat.Version like UpdateCategory does. An empty passwordHash keeps the
+// current one; leaving password access drops it.
+func (r *GalleryRepository) UpdateCategoryAccess(ctx context.Context, cat *models.GalleryCategory, passwordHash string) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.update_category_access")
+	defer func() { err = finish(err) }()
+
+	query := `
+		UPDATE gallery_categories
+		SET access = $1,
+			password_hash = CASE WHEN $1 <> 'password' THEN NULL WHEN $2 = '' THEN password_hash ELSE $2 END,
+			version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND deleted_at IS NULL AND ($4 = 0 OR version = $4)
+		RETURNING version, updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query, cat.Access, passwordHash, cat.ID, cat.Version).Scan(
+		&cat.Version,
+		&cat.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return r.missingOrConflict(ctx, "gallery_categories", cat.ID)
+	}
+	return err
+}
+
+// CategoryPasswordHash returns the bcrypt hash of a live category's
+// password, or "" when it has none
+func (r *GalleryRepository) CategoryPasswordHash(ctx context.Context, id int) (hash string, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.category_password_hash")
+	defer func() { err = finish(err) }()
+
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(password_hash, '') FROM gallery_categories WHERE id = $1 AND deleted_at IS NULL
+	`, id).Scan(&hash)
+	return hash, err
+}
+
+// ScheduleTransitions returns the live categories whose publish_at or
+// unpublish_at lies in (after, until], i.e. that appeared on or disappeared
+// from the public site in that interval
+func (r *GalleryRepository) ScheduleTransitions(ctx context.Context, after, until time.Time) (ids []int, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.schedule_transitions")
+	defer func() { err = finish(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id FROM gallery_categories
+		WHERE deleted_at IS NULL AND status = 'published'
+			AND ((publish_at > $1 AND publish_at <= $2) OR (unpublish_at > $1 AND unpublish_at <= $2))
+	`, after.UTC(), until.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// DeleteCategory moves a category and its images to the trash.
+// The images get the category's deleted_at so RestoreCategory can find them.
+func (r *GalleryRepository) DeleteCategory(ctx context.Context, id int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.delete_category")
+	defer func() { err = finish(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE gallery_categories SET deleted_at = $1
+		WHERE id = $2 AND deleted_at IS NULL
+	`, now, id)
+	if err != nil {
+		return err
+	}
+	// Missing or already in the trash: nothing to do
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE gallery_images SET deleted_at = $1
+		WHERE category_id = $2 AND deleted_at IS NULL
+	`, now, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // CreateImage creates a new gallery image
-func (r *GalleryRepository) CreateImage(img *models.GalleryImage) error {
+func (r *GalleryRepository) CreateImage(ctx context.Context, img *models.GalleryImage) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.create_image")
+	defer func() { err = finish(err) }()
+
+	// The foreign key does not know about the trash, so check the category here
+	var live bool
+	err = r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM gallery_categories WHERE id = $1 AND deleted_at IS NULL)
+	`, img.CategoryID).Scan(&live)
+	if err != nil {
+		return err
+	}
+	if !live {
+		return ErrCategoryNotFound
+	}
+
+	metadata, err := metadataValue(img.Metadata)
+	if err != nil {
+		return err
+	}
+	variants, err := variantsValue(img.Variants)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO gallery_images (category_id, src, alt, aspect_ratio, display_order)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at
+		INSERT INTO gallery_images (category_id, src, alt, aspect_ratio, width, height, display_order, metadata,
+			blurhash, lqip, dominant_color, variants, original_filename, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, CURRENT_TIMESTAMP)
+		RETURNING id, version, created_at, updated_at
 	`
 
-	return r.db.QueryRow(query, img.CategoryID, img.Src, img.Alt, img.AspectRatio, img.DisplayOrder).Scan(
+	err = r.db.QueryRowContext(ctx, query, img.CategoryID, img.Src, img.Alt, img.AspectRatio, img.Width, img.Height, img.DisplayOrder, metadata,
+		img.BlurHash, img.LQIP, img.DominantColor, variants, img.Filename).Scan(
 		&img.ID,
+		&img.Version,
 		&img.CreatedAt,
+		&img.UpdatedAt,
 	)
+	return translateError(err)
 }
 
-// DeleteImage deletes an image
-func (r *GalleryRepository) DeleteImage(id int) error {
-	query := `DELETE FROM gallery_images WHERE id = $1`
-	_, err := r.db.Exec(query, id)
+// DeleteImage moves an image to the trash
+func (r *GalleryRepository) DeleteImage(ctx context.Context, id int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.delete_image")
+	defer func() { err = finish(err) }()
+
+	query := `UPDATE gallery_images SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	_, err = r.db.ExecContext(ctx, query, time.Now(), id)
 	return err
 }
 
-// UpdateImage updates an existing gallery image
-func (r *GalleryRepository) UpdateImage(img *models.GalleryImage) error {
+// UpdateImage updates an existing gallery image and bumps its version,
+// checking a non-zero img.Version like UpdateCategory does. A nil
+// img.Metadata keeps the stored metadata and is filled in from it.
+func (r *GalleryRepository) UpdateImage(ctx context.Context, img *models.GalleryImage) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.update_image")
+	defer func() { err = finish(err) }()
+
+	metadata, err := metadataValue(img.Metadata)
+	if err != nil {
+		return err
+	}
+	variants, err := variantsValue(img.Variants)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE gallery_images
-		SET src = $1, alt = $2, aspect_ratio = $3
-		WHERE id = $4
+		SET src = $1, alt = $2, aspect_ratio = $3, width = $7, height = $8, metadata = COALESCE($6, metadata),
+			blurhash = $9, lqip = $10, dominant_color = $11, variants = $12, original_filename = $13,
+			version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND deleted_at IS NULL AND ($5 = 0 OR version = $5)
+		RETURNING version, updated_at, metadata
 	`
 
-	_, err := r.db.Exec(query, img.Src, img.Alt, img.AspectRatio, img.ID)
+	err = r.db.QueryRowContext(ctx, query, img.Src, img.Alt, img.AspectRatio, img.ID, img.Version, metadata, img.Width, img.Height,
+		img.BlurHash, img.LQIP, img.DominantColor, variants, img.Filename).Scan(
+		&img.Version,
+		&img.UpdatedAt,
+		imageMetadata{&img.Metadata},
+	)
+	if err == sql.ErrNoRows {
+		return r.missingOrConflict(ctx, "gallery_images", img.ID)
+	}
 	return err
 }
+
+// missingOrConflict explains why a versioned update matched no row: the
+// row is gone or in the trash (sql.ErrNoRows), or someone else updated it
+// since the caller read it (ErrVersionConflict)
+func (r *GalleryRepository) missingOrConflict(ctx context.Context, table string, id int) error {
+	var live bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM `+table+` WHERE id = $1 AND deleted_at IS NULL)
+	`, id).Scan(&live)
+	if err != nil {
+		return err
+	}
+	if live {
+		return ErrVersionConflict
+	}
+	return sql.ErrNoRows
+}
+
+// imageMetadata scans a nullable metadata column into an image
+type imageMetadata struct {
+	dest **models.ImageMetadata
+}
+
+// Scan decodes the JSON written by metadataValue
+func (m imageMetadata) Scan(src any) error {
+	*m.dest = nil
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("metadata: unexpected %T", src)
+	}
+	meta := &models.ImageMetadata{}
+	if err := json.Unmarshal(data, meta); err != nil {
+		return err
+	}
+	*m.dest = meta
+	return nil
+}
+
+// metadataValue encodes image metadata for the metadata column, storing
+// missing or empty metadata as NULL
+func metadataValue(meta *models.ImageMetadata) (any, error) {
+	if meta == nil || meta.IsEmpty() {
+		return nil, nil
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// imageVariants scans a nullable variants column into an image
+type imageVariants struct {
+	dest *[]models.ImageVariant
+}
+
+// Scan decodes the JSON written by variantsValue
+func (v imageVariants) Scan(src any) error {
+	*v.dest = nil
+	var data []byte
+	switch s := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = s
+	case string:
+		data = []byte(s)
+	default:
+		return fmt.Errorf("variants: unexpected %T", src)
+	}
+	list := []models.ImageVariant{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*v.dest = list
+	return nil
+}
+
+// variantsValue encodes image variants for the variants column. nil is
+// stored as NULL, an empty list as [] so images known to need none are
+// told apart from those not looked at yet.
+func variantsValue(list []models.ImageVariant) (any, error) {
+	if list == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
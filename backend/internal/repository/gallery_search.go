@@ -0,0 +1,118 @@
+// backend/internal/repository/gallery_search.go
+package repository
+
+import (
+	"context"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// Postgres matches the trigger-maintained search_vector columns
+var (
+	pgGallerySearch = `
+		SELECT c.id, c.slug, c.title, c.cover_image,
+			ts_rank(c.search_vector, q.query) AS rank,
+			ts_headline('english', c.title || ' ' || COALESCE(c.description, ''), q.query, $3)
+		FROM gallery_categories c
+		CROSS JOIN websearch_to_tsquery('english', $1) AS q(query)
+		WHERE c.search_vector @@ q.query AND c.deleted_at IS NULL AND ` + listedCondition("c.", 4) + `
+		ORDER BY rank DESC, c.id DESC
+		LIMIT $2
+	`
+	pgImageSearch = `
+		SELECT i.id, i.category_id, c.slug, i.src, i.alt,
+			ts_rank(i.search_vector, q.query) AS rank,
+			ts_headline('english', COALESCE(i.alt, ''), q.query, $3)
+		FROM gallery_images i
+		JOIN gallery_categories c ON c.id = i.category_id
+		CROSS JOIN websearch_to_tsquery('english', $1) AS q(query)
+		WHERE i.search_vector @@ q.query AND i.deleted_at IS NULL AND c.deleted_at IS NULL
+			AND ` + listedCondition("c.", 4) + `
+		ORDER BY rank DESC, i.id DESC
+		LIMIT $2
+	`
+)
+
+// SQLite matches the FTS5 tables; bm25 scores better matches lower
+var (
+	sqliteGallerySearch = `
+		SELECT c.id, c.slug, c.title, c.cover_image,
+			-bm25(gallery_categories_fts, 10.0, 4.0) AS rank,
+			snippet(gallery_categories_fts, -1, char(2), char(3), ' ... ', 16)
+		FROM gallery_categories_fts
+		JOIN gallery_categories c ON c.id = gallery_categories_fts.rowid
+		WHERE gallery_categories_fts MATCH $1 AND c.deleted_at IS NULL AND ` + listedCondition("c.", 3) + `
+		ORDER BY rank DESC, c.id DESC
+		LIMIT $2
+	`
+	sqliteImageSearch = `
+		SELECT i.id, i.category_id, c.slug, i.src, i.alt,
+			-bm25(gallery_images_fts) AS rank,
+			snippet(gallery_images_fts, -1, char(2), char(3), ' ... ', 16)
+		FROM gallery_images_fts
+		JOIN gallery_images i ON i.id = gallery_images_fts.rowid
+		JOIN gallery_categories c ON c.id = i.category_id
+		WHERE gallery_images_fts MATCH $1 AND i.deleted_at IS NULL AND c.deleted_at IS NULL
+			AND ` + listedCondition("c.", 3) + `
+		ORDER BY rank DESC, i.id DESC
+		LIMIT $2
+	`
+)
+
+// Search finds public galleries by title and description and their live
+// images by alt text, best matches first and at most limit of each
+func (r *GalleryRepository) Search(ctx context.Context, query string, limit int) (_ *models.ContentSearchResults, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.search")
+	defer func() { err = finish(err) }()
+
+	results := &models.ContentSearchResults{
+		Galleries: []models.GallerySearchHit{},
+		Images:    []models.ImageSearchHit{},
+	}
+	if len(SearchTerms(query)) == 0 {
+		return results, nil
+	}
+
+	galleryQuery, imageQuery := pgGallerySearch, pgImageSearch
+	args := []interface{}{query, limit, headlineOptions, publicNow()}
+	if r.db.IsSQLite() {
+		galleryQuery, imageQuery = sqliteGallerySearch, sqliteImageSearch
+		args = []interface{}{ftsQuery(query), limit, publicNow()}
+	}
+
+	rows, err := r.db.QueryContext(ctx, galleryQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hit models.GallerySearchHit
+		if err := rows.Scan(&hit.ID, &hit.Slug, &hit.Title, &hit.CoverImage, &hit.Rank, &hit.Snippet); err != nil {
+			return nil, err
+		}
+		hit.Snippet = Highlight(hit.Snippet)
+		results.Galleries = append(results.Galleries, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	rows, err = r.db.QueryContext(ctx, imageQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hit models.ImageSearchHit
+		if err := rows.Scan(&hit.ID, &hit.CategoryID, &hit.CategorySlug, &hit.Src, &hit.Alt, &hit.Rank, &hit.Snippet); err != nil {
+			return nil, err
+		}
+		hit.Snippet = Highlight(hit.Snippet)
+		results.Images = append(results.Images, hit)
+	}
+
+	return results, rows.Err()
+}
@@ -0,0 +1,608 @@
+// backend/internal/repository/gallery_tags.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// tagTables lists the columns the tag queries of GalleryRepository read and write
+var tagTables = []database.Table{
+	{Name: "tags", Columns: []database.Column{
+		{Name: "id", Type: database.TypeInteger},
+		{Name: "name", Type: database.TypeVarchar},
+		{Name: "slug", Type: database.TypeVarchar},
+		{Name: "created_at", Type: database.TypeTimestamp},
+		{Name: "updated_at", Type: database.TypeTimestamp},
+	}},
+	{Name: "image_tags", Columns: []database.Column{
+		{Name: "image_id", Type: database.TypeInteger},
+		{Name: "tag_id", Type: database.TypeInteger},
+	}},
+	{Name: "category_tags", Columns: []database.Column{
+		{Name: "category_id", Type: database.TypeInteger},
+		{Name: "tag_id", Type: database.TypeInteger},
+	}},
+}
+
+const tagColumns = `t.id, t.name, t.slug, t.created_at, t.updated_at`
+
+// tagOrder sorts tags by name the same way in both dialects
+const tagOrder = `LOWER(t.name), t.id`
+
+// Live images and galleries, for counting tag usage
+const (
+	liveImages    = `i.deleted_at IS NULL AND c.deleted_at IS NULL`
+	liveGalleries = `c.deleted_at IS NULL`
+)
+
+// ListTags returns every tag by name, counting live images and galleries
+// whatever their status
+func (r *GalleryRepository) ListTags(ctx context.Context) (_ []models.TagUsage, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.list")
+	defer func() { err = finish(err) }()
+
+	return r.tagUsage(ctx, liveImages, liveGalleries, false)
+}
+
+// PublicTags returns the tags of public images and galleries with their counts, by name
+func (r *GalleryRepository) PublicTags(ctx context.Context) (_ []models.TagUsage, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.public")
+	defer func() { err = finish(err) }()
+
+	return r.tagUsage(ctx,
+		liveImages+" AND "+listedCondition("c.", 1),
+		liveGalleries+" AND "+listedCondition("c.", 1),
+		true, publicNow())
+}
+
+// tagUsage counts the images and galleries matching the given conditions
+// for every tag, leaving out unused tags if skipUnused is set
+func (r *GalleryRepository) tagUsage(ctx context.Context, images, galleries string, skipUnused bool, args ...interface{}) ([]models.TagUsage, error) {
+	query := `
+		SELECT id, name, slug, created_at, updated_at, image_count, gallery_count
+		FROM (
+			SELECT ` + tagColumns + `,
+				(SELECT COUNT(*) FROM image_tags it
+					JOIN gallery_images i ON i.id = it.image_id
+					JOIN gallery_categories c ON c.id = i.category_id
+					WHERE it.tag_id = t.id AND ` + images + `) AS image_count,
+				(SELECT COUNT(*) FROM category_tags ct
+					JOIN gallery_categories c ON c.id = ct.category_id
+					WHERE ct.tag_id = t.id AND ` + galleries + `) AS gallery_count
+			FROM tags t
+		) t
+	`
+	if skipUnused {
+		query += ` WHERE image_count > 0 OR gallery_count > 0`
+	}
+	query += ` ORDER BY ` + tagOrder
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.TagUsage{}
+	for rows.Next() {
+		var tag models.TagUsage
+		if err := rows.Scan(
+			&tag.ID,
+			&tag.Name,
+			&tag.Slug,
+			&tag.CreatedAt,
+			&tag.UpdatedAt,
+			&tag.ImageCount,
+			&tag.GalleryCount,
+		); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// GetTag retrieves a tag by ID
+func (r *GalleryRepository) GetTag(ctx context.Context, id int) (_ *models.Tag, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.get")
+	defer func() { err = finish(err) }()
+
+	var tag models.Tag
+	err = r.db.QueryRowContext(ctx, `SELECT `+tagColumns+` FROM tags t WHERE t.id = $1`, id).Scan(
+		&tag.ID,
+		&tag.Name,
+		&tag.Slug,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// CreateTag creates a tag
+func (r *GalleryRepository) CreateTag(ctx context.Context, tag *models.Tag) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.create")
+	defer func() { err = finish(err) }()
+
+	query := `
+		INSERT INTO tags (name, slug, updated_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query, tag.Name, tag.Slug).Scan(&tag.ID, &tag.CreatedAt, &tag.UpdatedAt)
+	return translateError(err)
+}
+
+// UpdateTag renames a tag
+func (r *GalleryRepository) UpdateTag(ctx context.Context, tag *models.Tag) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.update")
+	defer func() { err = finish(err) }()
+
+	query := `
+		UPDATE tags SET name = $1, slug = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+		RETURNING created_at, updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query, tag.Name, tag.Slug, tag.ID).Scan(&tag.CreatedAt, &tag.UpdatedAt)
+	return translateError(err)
+}
+
+// DeleteTag deletes a tag and untags everything that carried it
+func (r *GalleryRepository) DeleteTag(ctx context.Context, id int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.delete")
+	defer func() { err = finish(err) }()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// MergeTags moves everything tagged with sourceIDs to targetID and deletes
+// the sources, in one transaction
+func (r *GalleryRepository) MergeTags(ctx context.Context, sourceIDs []int, targetID int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.merge")
+	defer func() { err = finish(err) }()
+
+	var sources []int
+	for _, id := range UniqueIDs(sourceIDs) {
+		if id != targetID {
+			sources = append(sources, id)
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	ids := append([]int{targetID}, sources...)
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	found, err := r.lockIDs(ctx, tx, `SELECT id FROM tags WHERE id IN (`+placeholders(1, len(ids))+`)`, args...)
+	if err != nil {
+		return err
+	}
+	if len(found) != len(ids) {
+		return sql.ErrNoRows
+	}
+	if len(sources) == 0 {
+		return tx.Commit()
+	}
+
+	// $1 is the target, the sources follow
+	in := placeholders(2, len(sources))
+	for _, link := range []struct{ table, column string }{{"image_tags", "image_id"}, {"category_tags", "category_id"}} {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO `+link.table+` (`+link.column+`, tag_id)
+			SELECT DISTINCT `+link.column+`, CAST($1 AS INTEGER) FROM `+link.table+`
+			WHERE tag_id IN (`+in+`)
+				AND `+link.column+` NOT IN (SELECT `+link.column+` FROM `+link.table+` WHERE tag_id = $1)
+		`, args...)
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id IN (`+placeholders(1, len(sources))+`)`, args[1:]...); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE tags SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`, targetID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ImageTags returns the tags of a live image by name
+func (r *GalleryRepository) ImageTags(ctx context.Context, imageID int) (_ []models.Tag, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.image_tags")
+	defer func() { err = finish(err) }()
+
+	if err := requireLive(ctx, r.db, "gallery_images", imageID); err != nil {
+		return nil, err
+	}
+	tags, err := r.tagsOf(ctx, "image_tags", "image_id", []int{imageID})
+	if err != nil {
+		return nil, err
+	}
+	return nonNilTags(tags[imageID]), nil
+}
+
+// SetImageTags replaces the tags of a live image
+func (r *GalleryRepository) SetImageTags(ctx context.Context, imageID int, slugs []string) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.set_image_tags")
+	defer func() { err = finish(err) }()
+
+	return r.setTags(ctx, "gallery_images", "image_tags", "image_id", imageID, slugs)
+}
+
+// CategoryTags returns the tags of a live category by name
+func (r *GalleryRepository) CategoryTags(ctx context.Context, categoryID int) (_ []models.Tag, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.category_tags")
+	defer func() { err = finish(err) }()
+
+	if err := requireLive(ctx, r.db, "gallery_categories", categoryID); err != nil {
+		return nil, err
+	}
+	tags, err := r.tagsOf(ctx, "category_tags", "category_id", []int{categoryID})
+	if err != nil {
+		return nil, err
+	}
+	return nonNilTags(tags[categoryID]), nil
+}
+
+// SetCategoryTags replaces the tags of a live category
+func (r *GalleryRepository) SetCategoryTags(ctx context.Context, categoryID int, slugs []string) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.set_category_tags")
+	defer func() { err = finish(err) }()
+
+	return r.setTags(ctx, "gallery_categories", "category_tags", "category_id", categoryID, slugs)
+}
+
+// setTags replaces the links of the live row id in table, in one transaction
+func (r *GalleryRepository) setTags(ctx context.Context, table, linkTable, column string, id int, slugs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := requireLive(ctx, tx, table, id); err != nil {
+		return err
+	}
+
+	slugs = UniqueStrings(slugs)
+	var tagIDs []int
+	if len(slugs) > 0 {
+		args := make([]interface{}, len(slugs))
+		for i, slug := range slugs {
+			args[i] = slug
+		}
+		tagIDs, err = r.lockIDs(ctx, tx, `SELECT id FROM tags WHERE slug IN (`+placeholders(1, len(slugs))+`)`, args...)
+		if err != nil {
+			return err
+		}
+		if len(tagIDs) != len(slugs) {
+			return ErrTagNotFound
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM `+linkTable+` WHERE `+column+` = $1`, id); err != nil {
+		return err
+	}
+	for _, tagID := range tagIDs {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO `+linkTable+` (`+column+`, tag_id) VALUES ($1, $2)`, id, tagID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rowQuerier is a *database.DB or *database.Tx
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// requireLive returns sql.ErrNoRows unless table has a live row with id
+func requireLive(ctx context.Context, q rowQuerier, table string, id int) error {
+	var live bool
+	err := q.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM `+table+` WHERE id = $1 AND deleted_at IS NULL)
+	`, id).Scan(&live)
+	if err != nil {
+		return err
+	}
+	if !live {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ImagesByTags returns a page of public images matching q, newest first,
+// with the total number of matches and the tag facets of all of them
+func (r *GalleryRepository) ImagesByTags(ctx context.Context, q TagQuery) (_ *models.TaggedImages, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.images")
+	defer func() { err = finish(err) }()
+
+	// $1 is publicNow(), the slugs follow
+	args := []interface{}{publicNow()}
+	from := `
+		FROM gallery_images i
+		JOIN gallery_categories c ON c.id = i.category_id
+		WHERE ` + liveImages + ` AND ` + listedCondition("c.", 1)
+	if match, slugs := tagMatch("i.id", "image_tags", "image_id", q, 2); match != "" {
+		from += ` AND ` + match
+		args = append(args, slugs...)
+	}
+
+	results := &models.TaggedImages{Images: []models.TaggedImage{}, Facets: []models.TagFacet{}}
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) `+from, args...).Scan(&results.Total); err != nil {
+		return nil, err
+	}
+	if results.Total == 0 {
+		return results, nil
+	}
+
+	query := `
+		SELECT i.id, i.category_id, i.src, i.original_filename, i.alt, i.aspect_ratio, i.width, i.height, i.display_order, i.version, i.created_at, i.updated_at, i.metadata, i.blurhash, i.lqip, i.dominant_color, i.variants, c.slug
+		` + from + `
+		ORDER BY i.created_at DESC, i.id DESC
+	`
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d OFFSET %d", q.Limit, q.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var img models.TaggedImage
+		if err := rows.Scan(
+			&img.ID,
+			&img.CategoryID,
+			&img.Src,
+			&img.Filename,
+			&img.Alt,
+			&img.AspectRatio,
+			&img.Width,
+			&img.Height,
+			&img.DisplayOrder,
+			&img.Version,
+			&img.CreatedAt,
+			&img.UpdatedAt,
+			imageMetadata{&img.Metadata},
+			&img.BlurHash,
+			&img.LQIP,
+			&img.DominantColor,
+			imageVariants{&img.Variants},
+			&img.CategorySlug,
+		); err != nil {
+			return nil, err
+		}
+		results.Images = append(results.Images, img)
+		ids = append(ids, img.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	tags, err := r.tagsOf(ctx, "image_tags", "image_id", ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results.Images {
+		results.Images[i].Tags = nonNilTags(tags[results.Images[i].ID])
+	}
+
+	facets, err := r.db.QueryContext(ctx, `
+		SELECT `+tagColumns+`, COUNT(*)
+		FROM image_tags it
+		JOIN tags t ON t.id = it.tag_id
+		WHERE it.image_id IN (SELECT i.id `+from+`)
+		GROUP BY t.id, t.name, t.slug, t.created_at, t.updated_at
+		ORDER BY COUNT(*) DESC, `+tagOrder, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer facets.Close()
+
+	for facets.Next() {
+		var facet models.TagFacet
+		if err := facets.Scan(
+			&facet.ID,
+			&facet.Name,
+			&facet.Slug,
+			&facet.CreatedAt,
+			&facet.UpdatedAt,
+			&facet.Count,
+		); err != nil {
+			return nil, err
+		}
+		results.Facets = append(results.Facets, facet)
+	}
+
+	return results, facets.Err()
+}
+
+// CategoriesByTags returns the public galleries matching q in display
+// order, without their images
+func (r *GalleryRepository) CategoriesByTags(ctx context.Context, q TagQuery) (_ []models.TaggedGallery, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "tag.categories")
+	defer func() { err = finish(err) }()
+
+	args := []interface{}{publicNow()}
+	where := liveGalleries + ` AND ` + listedCondition("c.", 1)
+	if match, slugs := tagMatch("c.id", "category_tags", "category_id", q, 2); match != "" {
+		where += ` AND ` + match
+		args = append(args, slugs...)
+	}
+
+	query := `
+		SELECT c.id, c.slug, c.title, c.description, c.cover_image, c.display_order, c.status, c.publish_at, c.unpublish_at, c.access, c.version, c.created_at, c.updated_at
+		FROM gallery_categories c
+		WHERE ` + where + `
+		ORDER BY c.display_order ASC, c.created_at DESC, c.id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	galleries := []models.TaggedGallery{}
+	var ids []int
+	for rows.Next() {
+		var cat models.TaggedGallery
+		if err := rows.Scan(
+			&cat.ID,
+			&cat.Slug,
+			&cat.Title,
+			&cat.Description,
+			&cat.CoverImage,
+			&cat.DisplayOrder,
+			&cat.Status,
+			&cat.PublishAt,
+			&cat.UnpublishAt,
+			&cat.Access,
+			&cat.Version,
+			&cat.CreatedAt,
+			&cat.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		galleries = append(galleries, cat)
+		ids = append(ids, cat.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	tags, err := r.tagsOf(ctx, "category_tags", "category_id", ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range galleries {
+		galleries[i].Tags = nonNilTags(tags[galleries[i].ID])
+	}
+
+	return galleries, nil
+}
+
+// tagsOf loads the tags linked to each of ids, by name
+func (r *GalleryRepository) tagsOf(ctx context.Context, linkTable, column string, ids []int) (map[int][]models.Tag, error) {
+	tags := make(map[int][]models.Tag)
+	if len(ids) == 0 {
+		return tags, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT l.`+column+`, `+tagColumns+`
+		FROM `+linkTable+` l
+		JOIN tags t ON t.id = l.tag_id
+		WHERE l.`+column+` IN (`+placeholders(1, len(ids))+`)
+		ORDER BY `+tagOrder, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var tag models.Tag
+		if err := rows.Scan(&id, &tag.ID, &tag.Name, &tag.Slug, &tag.CreatedAt, &tag.UpdatedAt); err != nil {
+			return nil, err
+		}
+		tags[id] = append(tags[id], tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// tagMatch returns the condition restricting idColumn to rows linked to
+// q's tags, with the slug arguments for placeholders from $first on.
+// Without slugs it returns an empty condition.
+func tagMatch(idColumn, linkTable, column string, q TagQuery, first int) (string, []interface{}) {
+	slugs := UniqueStrings(q.Slugs)
+	if len(slugs) == 0 {
+		return "", nil
+	}
+
+	args := make([]interface{}, len(slugs))
+	for i, slug := range slugs {
+		args[i] = slug
+	}
+
+	condition := idColumn + ` IN (
+		SELECT l.` + column + ` FROM ` + linkTable + ` l
+		JOIN tags tm ON tm.id = l.tag_id
+		WHERE tm.slug IN (` + placeholders(first, len(slugs)) + `)`
+	if q.MatchAll {
+		condition += ` GROUP BY l.` + column + ` HAVING COUNT(*) = ` + strconv.Itoa(len(slugs))
+	}
+	return condition + `)`, args
+}
+
+// placeholders returns n comma-separated placeholders starting at $first
+func placeholders(first, n int) string {
+	list := make([]string, n)
+	for i := range list {
+		list[i] = "$" + strconv.Itoa(first+i)
+	}
+	return strings.Join(list, ", ")
+}
+
+// nonNilTags makes an item without tags encode as [] rather than null
+func nonNilTags(tags []models.Tag) []models.Tag {
+	if tags == nil {
+		return []models.Tag{}
+	}
+	return tags
+}
+
+// UniqueStrings returns values without blanks and repeats, keeping the first occurrence
+func UniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,360 @@
+// backend/internal/repository/gallery_trash.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// ListTrash returns the trashed categories, with the images trashed along
+// with them, and the images trashed on their own from live categories
+func (r *GalleryRepository) ListTrash(ctx context.Context) (_ *models.GalleryTrash, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.list_trash")
+	defer func() { err = finish(err) }()
+
+	trash := &models.GalleryTrash{
+		Categories: []models.GalleryCategory{},
+		Images:     []models.GalleryImage{},
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, slug, title, description, cover_image, display_order, status, publish_at, unpublish_at, access, version, created_at, updated_at, deleted_at
+		FROM gallery_categories
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	index := make(map[int]int)
+	for rows.Next() {
+		var cat models.GalleryCategory
+		if err := rows.Scan(
+			&cat.ID,
+			&cat.Slug,
+			&cat.Title,
+			&cat.Description,
+			&cat.CoverImage,
+			&cat.DisplayOrder,
+			&cat.Status,
+			&cat.PublishAt,
+			&cat.UnpublishAt,
+			&cat.Access,
+			&cat.Version,
+			&cat.CreatedAt,
+			&cat.UpdatedAt,
+			&cat.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		index[cat.ID] = len(trash.Categories)
+		trash.Categories = append(trash.Categories, cat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	// Images trashed with their category share its deleted_at; images of a
+	// trashed category that were trashed earlier stay hidden until it is restored
+	rows, err = r.db.QueryContext(ctx, `
+		SELECT i.id, i.category_id, i.src, i.original_filename, i.alt, i.aspect_ratio, i.width, i.height, i.display_order, i.version, i.created_at, i.updated_at, i.metadata, i.blurhash, i.lqip, i.dominant_color, i.variants, i.deleted_at,
+			c.deleted_at IS NOT NULL AS category_trashed
+		FROM gallery_images i
+		JOIN gallery_categories c ON c.id = i.category_id
+		WHERE i.deleted_at IS NOT NULL
+			AND (c.deleted_at IS NULL OR c.deleted_at = i.deleted_at)
+		ORDER BY i.deleted_at DESC, i.display_order ASC, i.id DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var img models.GalleryImage
+		var categoryTrashed bool
+		if err := rows.Scan(
+			&img.ID,
+			&img.CategoryID,
+			&img.Src,
+			&img.Filename,
+			&img.Alt,
+			&img.AspectRatio,
+			&img.Width,
+			&img.Height,
+			&img.DisplayOrder,
+			&img.Version,
+			&img.CreatedAt,
+			&img.UpdatedAt,
+			imageMetadata{&img.Metadata},
+			&img.BlurHash,
+			&img.LQIP,
+			&img.DominantColor,
+			imageVariants{&img.Variants},
+			&img.DeletedAt,
+			&categoryTrashed,
+		); err != nil {
+			return nil, err
+		}
+
+		if categoryTrashed {
+			i := index[img.CategoryID]
+			trash.Categories[i].Images = append(trash.Categories[i].Images, img)
+		} else {
+			trash.Images = append(trash.Images, img)
+		}
+	}
+
+	return trash, rows.Err()
+}
+
+// RestoreCategory takes a category and the images trashed with it out of the trash
+func (r *GalleryRepository) RestoreCategory(ctx context.Context, id int) (_ *models.GalleryCategory, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.restore_category")
+	defer func() { err = finish(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Runs first, while the category still holds the shared deleted_at
+	_, err = tx.ExecContext(ctx, `
+		UPDATE gallery_images SET deleted_at = NULL
+		WHERE category_id = $1
+			AND deleted_at = (SELECT deleted_at FROM gallery_categories WHERE id = $1)
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var cat models.GalleryCategory
+	err = tx.QueryRowContext(ctx, `
+		UPDATE gallery_categories SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING id, slug, title, description, cover_image, display_order, status, publish_at, unpublish_at, access, version, created_at, updated_at
+	`, id).Scan(
+		&cat.ID,
+		&cat.Slug,
+		&cat.Title,
+		&cat.Description,
+		&cat.CoverImage,
+		&cat.DisplayOrder,
+		&cat.Status,
+		&cat.PublishAt,
+		&cat.UnpublishAt,
+		&cat.Access,
+		&cat.Version,
+		&cat.CreatedAt,
+		&cat.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &cat, nil
+}
+
+// RestoreImage takes an image out of the trash. Images of a trashed
+// category return ErrCategoryNotFound, the category has to be restored first.
+func (r *GalleryRepository) RestoreImage(ctx context.Context, id int) (_ *models.GalleryImage, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.restore_image")
+	defer func() { err = finish(err) }()
+
+	var categoryTrashed bool
+	err = r.db.QueryRowContext(ctx, `
+		SELECT c.deleted_at IS NOT NULL
+		FROM gallery_images i
+		JOIN gallery_categories c ON c.id = i.category_id
+		WHERE i.id = $1 AND i.deleted_at IS NOT NULL
+	`, id).Scan(&categoryTrashed)
+	if err != nil {
+		return nil, err
+	}
+	if categoryTrashed {
+		return nil, ErrCategoryNotFound
+	}
+
+	var img models.GalleryImage
+	err = r.db.QueryRowContext(ctx, `
+		UPDATE gallery_images SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING id, category_id, src, original_filename, alt, aspect_ratio, width, height, display_order, version, created_at, updated_at, metadata, blurhash, lqip, dominant_color, variants
+	`, id).Scan(
+		&img.ID,
+		&img.CategoryID,
+		&img.Src,
+		&img.Filename,
+		&img.Alt,
+		&img.AspectRatio,
+		&img.Width,
+		&img.Height,
+		&img.DisplayOrder,
+		&img.Version,
+		&img.CreatedAt,
+		&img.UpdatedAt,
+		imageMetadata{&img.Metadata},
+		&img.BlurHash,
+		&img.LQIP,
+		&img.DominantColor,
+		imageVariants{&img.Variants},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &img, nil
+}
+
+// PurgeCategory permanently deletes a trashed category and all its images
+func (r *GalleryRepository) PurgeCategory(ctx context.Context, id int) (unused []string, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.purge_category")
+	defer func() { err = finish(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	srcs, err := imageSources(ctx, tx, `category_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	// ON DELETE CASCADE removes the images
+	if err := execOne(ctx, tx.ExecContext, `DELETE FROM gallery_categories WHERE id = $1 AND deleted_at IS NOT NULL`, id); err != nil {
+		return nil, err
+	}
+	if unused, err = unusedSources(ctx, tx, srcs); err != nil {
+		return nil, err
+	}
+
+	return unused, tx.Commit()
+}
+
+// PurgeImage permanently deletes a trashed image
+func (r *GalleryRepository) PurgeImage(ctx context.Context, id int) (unused []string, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.purge_image")
+	defer func() { err = finish(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	srcs, err := imageSources(ctx, tx, `id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := execOne(ctx, tx.ExecContext, `DELETE FROM gallery_images WHERE id = $1 AND deleted_at IS NOT NULL`, id); err != nil {
+		return nil, err
+	}
+	if unused, err = unusedSources(ctx, tx, srcs); err != nil {
+		return nil, err
+	}
+
+	return unused, tx.Commit()
+}
+
+// PurgeTrash permanently deletes everything trashed before the cutoff and
+// returns the number of categories and images removed
+func (r *GalleryRepository) PurgeTrash(ctx context.Context, before time.Time) (categories, images int64, unused []string, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "gallery.purge_trash")
+	defer func() { err = finish(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	defer tx.Rollback()
+
+	// Count cascaded images too, they disappear with their category
+	expired := `deleted_at < $1 OR category_id IN (SELECT id FROM gallery_categories WHERE deleted_at < $1)`
+	srcs, err := imageSources(ctx, tx, expired, before)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM gallery_images WHERE `+expired, before)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if images, err = result.RowsAffected(); err != nil {
+		return 0, 0, nil, err
+	}
+
+	result, err = tx.ExecContext(ctx, `DELETE FROM gallery_categories WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if categories, err = result.RowsAffected(); err != nil {
+		return 0, 0, nil, err
+	}
+	if unused, err = unusedSources(ctx, tx, srcs); err != nil {
+		return 0, 0, nil, err
+	}
+
+	return categories, images, unused, tx.Commit()
+}
+
+// imageSources returns the distinct srcs of the images matching where
+func imageSources(ctx context.Context, tx *database.Tx, where string, args ...interface{}) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT DISTINCT src FROM gallery_images WHERE `+where+` ORDER BY src`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var srcs []string
+	for rows.Next() {
+		var src string
+		if err := rows.Scan(&src); err != nil {
+			return nil, err
+		}
+		srcs = append(srcs, src)
+	}
+	return srcs, rows.Err()
+}
+
+// unusedSources returns the srcs no image uses anymore, trashed ones included
+func unusedSources(ctx context.Context, tx *database.Tx, srcs []string) ([]string, error) {
+	var unused []string
+	for _, src := range srcs {
+		var used bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM gallery_images WHERE src = $1)`, src).Scan(&used); err != nil {
+			return nil, err
+		}
+		if !used {
+			unused = append(unused, src)
+		}
+	}
+	return unused, nil
+}
+
+// execOne runs a statement that must affect exactly one row, returning
+// sql.ErrNoRows when it matched nothing
+func execOne(ctx context.Context, exec func(context.Context, string, ...interface{}) (sql.Result, error), query string, args ...interface{}) error {
+	result, err := exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
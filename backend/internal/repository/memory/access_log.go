@@ -0,0 +1,69 @@
+// backend/internal/repository/memory/access_log.go
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// AccessLogRepository is an in-memory repository.AccessLogStore.
+// Like PreviewRepository it cannot reject entries for missing galleries.
+type AccessLogRepository struct {
+	mu      sync.RWMutex
+	entries []models.GalleryAccessEntry
+}
+
+// NewAccessLogRepository creates an empty in-memory access log repository
+func NewAccessLogRepository() *AccessLogRepository {
+	return &AccessLogRepository{}
+}
+
+var _ repository.AccessLogStore = (*AccessLogRepository)(nil)
+
+// Record appends an entry to the log
+func (r *AccessLogRepository) Record(ctx context.Context, entry *models.GalleryAccessEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry.ID = len(r.entries) + 1
+	entry.CreatedAt = time.Now().UTC()
+	r.entries = append(r.entries, *entry)
+
+	return nil
+}
+
+// List returns a page of a category's entries, newest first
+func (r *AccessLogRepository) List(ctx context.Context, filter repository.AccessLogFilter) ([]models.GalleryAccessEntry, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Entries are appended in order, so walking backwards is newest first
+	matches := []models.GalleryAccessEntry{}
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		entry := r.entries[i]
+		if entry.CategoryID == filter.CategoryID && (filter.Event == "" || entry.Event == filter.Event) {
+			matches = append(matches, entry)
+		}
+	}
+
+	total := len(matches)
+	if filter.Limit > 0 {
+		start := min(filter.Offset, total)
+		end := min(start+filter.Limit, total)
+		matches = matches[start:end]
+	}
+
+	return matches, total, nil
+}
@@ -0,0 +1,92 @@
+// backend/internal/repository/memory/audit.go
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// AuditRepository is an in-memory repository.AuditStore
+type AuditRepository struct {
+	mu      sync.RWMutex
+	entries []models.AuditEntry
+}
+
+// NewAuditRepository creates an empty in-memory audit repository
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{}
+}
+
+var _ repository.AuditStore = (*AuditRepository)(nil)
+
+// Record appends an entry to the audit log
+func (r *AuditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry.ID = len(r.entries) + 1
+	entry.CreatedAt = time.Now().UTC()
+	if len(entry.Changes) == 0 {
+		entry.Changes = json.RawMessage("{}")
+	}
+
+	stored := *entry
+	stored.Changes = append(json.RawMessage(nil), entry.Changes...)
+	r.entries = append(r.entries, stored)
+
+	return nil
+}
+
+// List returns a page of entries matching filter, newest first
+func (r *AuditRepository) List(ctx context.Context, filter repository.AuditFilter) ([]models.AuditEntry, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Entries are appended in order, so walking backwards is newest first
+	matches := []models.AuditEntry{}
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if entry := r.entries[i]; auditMatches(entry, filter) {
+			matches = append(matches, entry)
+		}
+	}
+
+	total := len(matches)
+	if filter.Limit > 0 {
+		start := min(filter.Offset, total)
+		end := min(start+filter.Limit, total)
+		matches = matches[start:end]
+	}
+
+	return matches, total, nil
+}
+
+func auditMatches(entry models.AuditEntry, filter repository.AuditFilter) bool {
+	switch {
+	case filter.ActorID != 0 && (entry.ActorID == nil || *entry.ActorID != filter.ActorID):
+		return false
+	case filter.Action != "" && entry.Action != filter.Action:
+		return false
+	case filter.EntityType != "" && entry.EntityType != filter.EntityType:
+		return false
+	case filter.EntityID != "" && entry.EntityID != filter.EntityID:
+		return false
+	case !filter.Since.IsZero() && entry.CreatedAt.Before(filter.Since):
+		return false
+	case !filter.Until.IsZero() && !entry.CreatedAt.Before(filter.Until):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,174 @@
+// backend/internal/repository/memory/contact.go
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// ContactRepository is an in-memory repository.ContactStore
+type ContactRepository struct {
+	mu       sync.RWMutex
+	messages map[int]models.ContactMessage
+	nextID   int
+}
+
+// NewContactRepository creates an empty in-memory contact repository
+func NewContactRepository() *ContactRepository {
+	return &ContactRepository{
+		messages: make(map[int]models.ContactMessage),
+		nextID:   1,
+	}
+}
+
+var _ repository.ContactStore = (*ContactRepository)(nil)
+
+// Create saves a new contact message
+func (r *ContactRepository) Create(ctx context.Context, req *models.ContactRequest) (*models.ContactMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	msg := models.ContactMessage{
+		ID:        r.nextID,
+		Name:      req.Name,
+		Email:     req.Email,
+		Phone:     req.Phone,
+		Subject:   req.Subject,
+		Message:   req.Message,
+		Status:    "pending",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	r.nextID++
+	r.messages[msg.ID] = msg
+
+	return &msg, nil
+}
+
+// GetAll retrieves all contact messages, newest first
+func (r *ContactRepository) GetAll(ctx context.Context) ([]models.ContactMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var messages []models.ContactMessage
+	for _, msg := range r.messages {
+		messages = append(messages, msg)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].CreatedAt.After(messages[j].CreatedAt)
+		}
+		return messages[i].ID > messages[j].ID
+	})
+
+	return messages, nil
+}
+
+// GetByID retrieves a contact message by ID
+func (r *ContactRepository) GetByID(ctx context.Context, id int) (*models.ContactMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	msg, ok := r.messages[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &msg, nil
+}
+
+// UpdateStatus updates the status of a contact message
+func (r *ContactRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if msg, ok := r.messages[id]; ok {
+		msg.Status = status
+		msg.UpdatedAt = time.Now()
+		r.messages[id] = msg
+	}
+	return nil
+}
+
+// MarkAsRead marks a contact message as read
+func (r *ContactRepository) MarkAsRead(ctx context.Context, id int) error {
+	return r.UpdateStatus(ctx, id, "read")
+}
+
+// Delete removes a contact message
+func (r *ContactRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.messages, id)
+	return nil
+}
+
+// Search finds contact messages by name, subject and message, best matches first
+func (r *ContactRepository) Search(ctx context.Context, query string, limit int) ([]models.ContactSearchHit, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	hits := []models.ContactSearchHit{}
+	s := newSearcher(query)
+	if s == nil {
+		return hits, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, msg := range r.messages {
+		rank, snippet, ok := s.match(
+			searchField{msg.Name, 1},
+			searchField{msg.Subject, 1},
+			searchField{msg.Message, 0.4},
+		)
+		if ok {
+			hits = append(hits, models.ContactSearchHit{ContactMessage: msg, Snippet: snippet, Rank: rank})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		if !hits[i].CreatedAt.Equal(hits[j].CreatedAt) {
+			return hits[i].CreatedAt.After(hits[j].CreatedAt)
+		}
+		return hits[i].ID > hits[j].ID
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,486 @@
+// backend/internal/repository/memory/gallery.go
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// GalleryRepository is an in-memory repository.GalleryStore
+type GalleryRepository struct {
+	mu         sync.RWMutex
+	categories map[int]models.GalleryCategory
+	images     map[int]models.GalleryImage
+	nextCatID  int
+	nextImgID  int
+	passwords  map[int]string // category ID -> bcrypt hash
+
+	tags         map[int]models.Tag
+	imageTags    map[int]map[int]bool // image ID -> tag IDs
+	categoryTags map[int]map[int]bool // category ID -> tag IDs
+	nextTagID    int
+
+	proofing     map[int]models.ProofingSettings // category ID -> settings
+	clients      map[int]models.ProofingClient
+	marks        map[int]map[int]models.ProofingMark // client ID -> image ID -> mark
+	nextClientID int
+}
+
+// NewGalleryRepository creates an empty in-memory gallery repository
+func NewGalleryRepository() *GalleryRepository {
+	return &GalleryRepository{
+		categories:   make(map[int]models.GalleryCategory),
+		images:       make(map[int]models.GalleryImage),
+		nextCatID:    1,
+		nextImgID:    1,
+		passwords:    make(map[int]string),
+		tags:         make(map[int]models.Tag),
+		imageTags:    make(map[int]map[int]bool),
+		categoryTags: make(map[int]map[int]bool),
+		nextTagID:    1,
+		proofing:     make(map[int]models.ProofingSettings),
+		clients:      make(map[int]models.ProofingClient),
+		marks:        make(map[int]map[int]models.ProofingMark),
+		nextClientID: 1,
+	}
+}
+
+var _ repository.GalleryStore = (*GalleryRepository)(nil)
+
+// GetAllCategories retrieves the public gallery categories, or all of them
+// with opts.AllStatuses, and optionally their images
+func (r *GalleryRepository) GetAllCategories(ctx context.Context, opts repository.CategoryListOptions) ([]models.GalleryCategory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var categories []models.GalleryCategory
+	for _, cat := range r.categories {
+		if cat.DeletedAt != nil || (!opts.AllStatuses && !cat.IsListed(now)) {
+			continue
+		}
+		if opts.IncludeImages {
+			cat.Images = r.imagesFor(cat.ID)
+			if opts.ImagesLimit > 0 && len(cat.Images) > opts.ImagesLimit {
+				cat.Images = cat.Images[:opts.ImagesLimit]
+			}
+		}
+		categories = append(categories, cat)
+	}
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		return lessByOrder(categories[i].DisplayOrder, categories[i].CreatedAt, categories[i].ID,
+			categories[j].DisplayOrder, categories[j].CreatedAt, categories[j].ID)
+	})
+
+	return categories, nil
+}
+
+// GetCategoryBySlug retrieves a single public category by slug
+func (r *GalleryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*models.GalleryCategory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	for _, cat := range r.categories {
+		if cat.Slug == slug && cat.DeletedAt == nil && cat.IsPublic(now) {
+			cat.Images = r.imagesFor(cat.ID)
+			return &cat, nil
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+// GetCategoryByID retrieves a single category by ID, whatever its status
+func (r *GalleryRepository) GetCategoryByID(ctx context.Context, id int) (*models.GalleryCategory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cat, ok := r.categories[id]
+	if !ok || cat.DeletedAt != nil {
+		return nil, sql.ErrNoRows
+	}
+	cat.Images = r.imagesFor(cat.ID)
+
+	return &cat, nil
+}
+
+// GetImageByID retrieves a single image by ID
+func (r *GalleryRepository) GetImageByID(ctx context.Context, id int) (*models.GalleryImage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	img, ok := r.images[id]
+	if !ok || img.DeletedAt != nil {
+		return nil, sql.ErrNoRows
+	}
+
+	return &img, nil
+}
+
+// GetImagesByCategory retrieves all images for a category
+func (r *GalleryRepository) GetImagesByCategory(ctx context.Context, categoryID int) ([]models.GalleryImage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.imagesFor(categoryID), nil
+}
+
+// CreateCategory creates a new gallery category
+func (r *GalleryRepository) CreateCategory(ctx context.Context, cat *models.GalleryCategory) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.categories {
+		if existing.Slug == cat.Slug {
+			return repository.ErrDuplicateSlug
+		}
+	}
+
+	if cat.Status == "" {
+		cat.Status = models.GalleryPublished
+	}
+	if cat.Access == "" {
+		cat.Access = models.GalleryAccessPublic
+	}
+	cat.PublishAt, cat.UnpublishAt = utc(cat.PublishAt), utc(cat.UnpublishAt)
+
+	now := time.Now()
+	cat.ID = r.nextCatID
+	cat.Version = 1
+	cat.CreatedAt = now
+	cat.UpdatedAt = now
+	r.nextCatID++
+
+	stored := *cat
+	stored.Images = nil
+	r.categories[cat.ID] = stored
+
+	return nil
+}
+
+// UpdateCategory updates an existing category and bumps its version
+func (r *GalleryRepository) UpdateCategory(ctx context.Context, cat *models.GalleryCategory) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.categories[cat.ID]
+	if !ok || stored.DeletedAt != nil {
+		return sql.ErrNoRows
+	}
+	if cat.Version != 0 && cat.Version != stored.Version {
+		return repository.ErrVersionConflict
+	}
+
+	// The slug is not updatable, matching the SQL UPDATE
+	stored.Title = cat.Title
+	stored.Description = cat.Description
+	stored.CoverImage = cat.CoverImage
+	stored.DisplayOrder = cat.DisplayOrder
+	stored.Version++
+	stored.UpdatedAt = time.Now()
+	r.categories[cat.ID] = stored
+
+	cat.Version = stored.Version
+	cat.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// UpdateCategoryStatus sets a category's status and publishing window
+func (r *GalleryRepository) UpdateCategoryStatus(ctx context.Context, cat *models.GalleryCategory) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.categories[cat.ID]
+	if !ok || stored.DeletedAt != nil {
+		return sql.ErrNoRows
+	}
+	if cat.Version != 0 && cat.Version != stored.Version {
+		return repository.ErrVersionConflict
+	}
+
+	cat.PublishAt, cat.UnpublishAt = utc(cat.PublishAt), utc(cat.UnpublishAt)
+	stored.Status = cat.Status
+	stored.PublishAt = cat.PublishAt
+	stored.UnpublishAt = cat.UnpublishAt
+	stored.Version++
+	stored.UpdatedAt = time.Now()
+	r.categories[cat.ID] = stored
+
+	cat.Version = stored.Version
+	cat.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// UpdateCategoryAccess sets a category's access mode. An empty passwordHash
+// keeps the current one; leaving password access drops it.
+func (r *GalleryRepository) UpdateCategoryAccess(ctx context.Context, cat *models.GalleryCategory, passwordHash string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.categories[cat.ID]
+	if !ok || stored.DeletedAt != nil {
+		return sql.ErrNoRows
+	}
+	if cat.Version != 0 && cat.Version != stored.Version {
+		return repository.ErrVersionConflict
+	}
+
+	switch {
+	case cat.Access != models.GalleryAccessPassword:
+		delete(r.passwords, cat.ID)
+	case passwordHash != "":
+		r.passwords[cat.ID] = passwordHash
+	}
+	stored.Access = cat.Access
+	stored.Version++
+	stored.UpdatedAt = time.Now()
+	r.categories[cat.ID] = stored
+
+	cat.Version = stored.Version
+	cat.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// CategoryPasswordHash returns the hash of a live category's password, or "" when it has none
+func (r *GalleryRepository) CategoryPasswordHash(ctx context.Context, id int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cat, ok := r.categories[id]
+	if !ok || cat.DeletedAt != nil {
+		return "", sql.ErrNoRows
+	}
+	return r.passwords[id], nil
+}
+
+// ScheduleTransitions returns the live categories whose publish_at or
+// unpublish_at lies in (after, until]
+func (r *GalleryRepository) ScheduleTransitions(ctx context.Context, after, until time.Time) ([]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	within := func(t *time.Time) bool {
+		return t != nil && t.After(after) && !t.After(until)
+	}
+
+	var ids []int
+	for id, cat := range r.categories {
+		if cat.DeletedAt == nil && cat.Status == models.GalleryPublished && (within(cat.PublishAt) || within(cat.UnpublishAt)) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// DeleteCategory moves a category and its images to the trash
+func (r *GalleryRepository) DeleteCategory(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cat, ok := r.categories[id]
+	if !ok || cat.DeletedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	cat.DeletedAt = &now
+	r.categories[id] = cat
+
+	for imgID, img := range r.images {
+		if img.CategoryID == id && img.DeletedAt == nil {
+			img.DeletedAt = &now
+			r.images[imgID] = img
+		}
+	}
+
+	return nil
+}
+
+// CreateImage creates a new gallery image
+func (r *GalleryRepository) CreateImage(ctx context.Context, img *models.GalleryImage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cat, ok := r.categories[img.CategoryID]; !ok || cat.DeletedAt != nil {
+		return repository.ErrCategoryNotFound
+	}
+	img.ID = r.nextImgID
+	img.Version = 1
+	img.CreatedAt = time.Now()
+	img.UpdatedAt = img.CreatedAt
+	r.nextImgID++
+	stored := *img
+	stored.Metadata = cloneMetadata(img.Metadata)
+	stored.Variants = slices.Clone(img.Variants)
+	r.images[img.ID] = stored
+
+	return nil
+}
+
+// DeleteImage moves an image to the trash
+func (r *GalleryRepository) DeleteImage(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if img, ok := r.images[id]; ok && img.DeletedAt == nil {
+		now := time.Now()
+		img.DeletedAt = &now
+		r.images[id] = img
+	}
+	return nil
+}
+
+// UpdateImage updates an existing gallery image and bumps its version. A
+// nil img.Metadata keeps the stored metadata and is filled in from it.
+func (r *GalleryRepository) UpdateImage(ctx context.Context, img *models.GalleryImage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.images[img.ID]
+	if !ok || stored.DeletedAt != nil {
+		return sql.ErrNoRows
+	}
+	if img.Version != 0 && img.Version != stored.Version {
+		return repository.ErrVersionConflict
+	}
+
+	stored.Src = img.Src
+	stored.Filename = img.Filename
+	stored.Alt = img.Alt
+	stored.AspectRatio = img.AspectRatio
+	stored.Width = img.Width
+	stored.Height = img.Height
+	stored.ImagePlaceholder = img.ImagePlaceholder
+	stored.Variants = slices.Clone(img.Variants)
+	if img.Metadata != nil && !img.Metadata.IsEmpty() {
+		stored.Metadata = cloneMetadata(img.Metadata)
+	}
+	stored.Version++
+	stored.UpdatedAt = time.Now()
+	r.images[img.ID] = stored
+
+	img.Version = stored.Version
+	img.UpdatedAt = stored.UpdatedAt
+	img.Metadata = stored.Metadata
+	return nil
+}
+
+// cloneMetadata copies metadata passed in by a caller, storing empty
+// metadata as none like the SQL store. Stored metadata is never modified,
+// so images and their copies may share it.
+func cloneMetadata(meta *models.ImageMetadata) *models.ImageMetadata {
+	if meta == nil || meta.IsEmpty() {
+		return nil
+	}
+	clone := *meta
+	return &clone
+}
+
+// imagesFor returns a category's images ordered like the SQL query.
+// Callers must hold the lock.
+func (r *GalleryRepository) imagesFor(categoryID int) []models.GalleryImage {
+	var images []models.GalleryImage
+	for _, img := range r.images {
+		if img.CategoryID == categoryID && img.DeletedAt == nil {
+			images = append(images, img)
+		}
+	}
+
+	sort.SliceStable(images, func(i, j int) bool {
+		return lessByOrder(images[i].DisplayOrder, images[i].CreatedAt, images[i].ID,
+			images[j].DisplayOrder, images[j].CreatedAt, images[j].ID)
+	})
+
+	return images
+}
+
+// utc converts an optional schedule time to UTC, like the SQL repository stores it
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// lessByOrder implements ORDER BY display_order ASC, created_at DESC.
+// IDs break ties so results are deterministic.
+func lessByOrder(orderA int, createdA time.Time, idA int, orderB int, createdB time.Time, idB int) bool {
+	if orderA != orderB {
+		return orderA < orderB
+	}
+	if !createdA.Equal(createdB) {
+		return createdA.After(createdB)
+	}
+	return idA > idB
+}
@@ -0,0 +1,118 @@
+// backend/internal/repository/memory/gallery_move.go
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// MoveImages moves live images to another live category at position
+func (r *GalleryRepository) MoveImages(ctx context.Context, ids []int, categoryID, position int) ([]models.GalleryImage, error) {
+	return r.transfer(ctx, ids, categoryID, position, false)
+}
+
+// CopyImages copies live images to another live category at position
+func (r *GalleryRepository) CopyImages(ctx context.Context, ids []int, categoryID, position int) ([]models.GalleryImage, error) {
+	return r.transfer(ctx, ids, categoryID, position, true)
+}
+
+// transfer implements MoveImages and CopyImages like the SQL repository
+func (r *GalleryRepository) transfer(ctx context.Context, ids []int, categoryID, position int, copying bool) ([]models.GalleryImage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cat, ok := r.categories[categoryID]; !ok || cat.DeletedAt != nil {
+		return nil, repository.ErrCategoryNotFound
+	}
+
+	var sources []models.GalleryImage
+	for _, id := range repository.UniqueIDs(ids) {
+		img, ok := r.images[id]
+		if !ok || img.DeletedAt != nil {
+			return nil, sql.ErrNoRows
+		}
+		sources = append(sources, img)
+	}
+
+	var existing []int
+	for _, img := range r.imagesFor(categoryID) {
+		existing = append(existing, img.ID)
+	}
+
+	now := time.Now()
+	var placed []int
+	for _, src := range sources {
+		if copying {
+			// Copies carry the tags of their original
+			if tags := r.imageTags[src.ID]; len(tags) > 0 {
+				r.imageTags[r.nextImgID] = copySet(tags)
+			}
+			src.ID = r.nextImgID
+			src.Version = 1
+			src.CreatedAt = now
+			r.nextImgID++
+		} else {
+			src.Version++
+		}
+		src.CategoryID = categoryID
+		src.UpdatedAt = now
+		r.images[src.ID] = src
+		placed = append(placed, src.ID)
+	}
+
+	for i, id := range repository.InsertIDs(existing, placed, position) {
+		if img := r.images[id]; img.DisplayOrder != i+1 {
+			img.DisplayOrder = i + 1
+			img.Version++
+			img.UpdatedAt = now
+			r.images[id] = img
+		}
+	}
+
+	if !copying {
+		for _, src := range sources {
+			if src.CategoryID != categoryID {
+				r.replaceCover(src.CategoryID, src.Src, now)
+			}
+		}
+	}
+
+	images := make([]models.GalleryImage, 0, len(placed))
+	for _, id := range placed {
+		images = append(images, r.images[id])
+	}
+	return images, nil
+}
+
+// replaceCover points a category's cover at its first live image when the
+// cover is src and no image left in the category uses src.
+// Callers must hold the lock.
+func (r *GalleryRepository) replaceCover(categoryID int, src string, now time.Time) {
+	cat := r.categories[categoryID]
+	if cat.CoverImage != src {
+		return
+	}
+
+	first := ""
+	for _, img := range r.imagesFor(categoryID) {
+		if img.Src == src {
+			return
+		}
+		if first == "" {
+			first = img.Src
+		}
+	}
+
+	cat.CoverImage = first
+	cat.Version++
+	cat.UpdatedAt = now
+	r.categories[categoryID] = cat
+}
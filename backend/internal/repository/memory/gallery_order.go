@@ -0,0 +1,77 @@
+// backend/internal/repository/memory/gallery_order.go
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// ReorderCategories sets the display order of the live categories to their
+// position in ids
+func (r *GalleryRepository) ReorderCategories(ctx context.Context, ids []int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var current []int
+	for id, cat := range r.categories {
+		if cat.DeletedAt == nil {
+			current = append(current, id)
+		}
+	}
+	if err := repository.CheckOrder(current, ids); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i, id := range ids {
+		if cat := r.categories[id]; cat.DisplayOrder != i+1 {
+			cat.DisplayOrder = i + 1
+			cat.Version++
+			cat.UpdatedAt = now
+			r.categories[id] = cat
+		}
+	}
+
+	return nil
+}
+
+// ReorderImages sets the display order of a live category's images to their
+// position in ids
+func (r *GalleryRepository) ReorderImages(ctx context.Context, categoryID int, ids []int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cat, ok := r.categories[categoryID]; !ok || cat.DeletedAt != nil {
+		return repository.ErrCategoryNotFound
+	}
+
+	var current []int
+	for _, img := range r.imagesFor(categoryID) {
+		current = append(current, img.ID)
+	}
+	if err := repository.CheckOrder(current, ids); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i, id := range ids {
+		if img := r.images[id]; img.DisplayOrder != i+1 {
+			img.DisplayOrder = i + 1
+			img.Version++
+			img.UpdatedAt = now
+			r.images[id] = img
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,325 @@
+// backend/internal/repository/memory/gallery_proofing.go
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+var _ repository.ProofingStore = (*GalleryRepository)(nil)
+
+// ProofingSettings returns a gallery's proofing settings, or sql.ErrNoRows
+// if it is not in proofing mode
+func (r *GalleryRepository) ProofingSettings(ctx context.Context, categoryID int) (*models.ProofingSettings, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	settings, ok := r.proofing[categoryID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &settings, nil
+}
+
+// EnableProofing puts a live gallery in proofing mode or changes its pick limit
+func (r *GalleryRepository) EnableProofing(ctx context.Context, settings *models.ProofingSettings) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cat, ok := r.categories[settings.CategoryID]; !ok || cat.DeletedAt != nil {
+		return sql.ErrNoRows
+	}
+
+	now := time.Now().UTC()
+	settings.CreatedAt = now
+	if stored, ok := r.proofing[settings.CategoryID]; ok {
+		settings.CreatedAt = stored.CreatedAt
+	}
+	settings.UpdatedAt = now
+	r.proofing[settings.CategoryID] = *settings
+
+	return nil
+}
+
+// DisableProofing takes a gallery out of proofing mode, keeping its clients and marks
+func (r *GalleryRepository) DisableProofing(ctx context.Context, categoryID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.proofing[categoryID]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.proofing, categoryID)
+
+	return nil
+}
+
+// CreateProofingClient invites a client, filling in its ID and CreatedAt
+func (r *GalleryRepository) CreateProofingClient(ctx context.Context, client *models.ProofingClient) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.categories[client.CategoryID]; !ok {
+		return repository.ErrCategoryNotFound
+	}
+
+	client.ID = r.nextClientID
+	client.CreatedAt = time.Now().UTC()
+	client.SubmittedAt = nil
+	client.RevokedAt = nil
+	r.nextClientID++
+	r.clients[client.ID] = *client
+
+	return nil
+}
+
+// GetProofingClient returns a client, revoked ones included
+func (r *GalleryRepository) GetProofingClient(ctx context.Context, id int) (*models.ProofingClient, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	client, ok := r.clients[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &client, nil
+}
+
+// ListProofingClients returns a gallery's clients in the order they were invited
+func (r *GalleryRepository) ListProofingClients(ctx context.Context, categoryID int) ([]models.ProofingClient, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	clients := []models.ProofingClient{}
+	for _, client := range r.clients {
+		if client.CategoryID == categoryID {
+			clients = append(clients, client)
+		}
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		if !clients[i].CreatedAt.Equal(clients[j].CreatedAt) {
+			return clients[i].CreatedAt.Before(clients[j].CreatedAt)
+		}
+		return clients[i].ID < clients[j].ID
+	})
+
+	return clients, nil
+}
+
+// RevokeProofingClient stops a client's link from working
+func (r *GalleryRepository) RevokeProofingClient(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	client, ok := r.clients[id]
+	if !ok || client.RevokedAt != nil {
+		return sql.ErrNoRows
+	}
+	now := time.Now().UTC()
+	client.RevokedAt = &now
+	r.clients[id] = client
+
+	return nil
+}
+
+// ProofingMarks returns a client's marks on live images of its gallery, in gallery order
+func (r *GalleryRepository) ProofingMarks(ctx context.Context, clientID int) ([]models.ProofingMark, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	marks := []models.ProofingMark{}
+	client, ok := r.clients[clientID]
+	if !ok {
+		return marks, nil
+	}
+	for imgID, mark := range r.marks[clientID] {
+		img, ok := r.images[imgID]
+		if !ok || img.DeletedAt != nil || img.CategoryID != client.CategoryID {
+			continue
+		}
+		mark.Src, mark.Filename = img.Src, img.Filename
+		marks = append(marks, mark)
+	}
+	sort.Slice(marks, func(i, j int) bool {
+		a, b := r.images[marks[i].ImageID], r.images[marks[j].ImageID]
+		return lessByOrder(a.DisplayOrder, a.CreatedAt, a.ID, b.DisplayOrder, b.CreatedAt, b.ID)
+	})
+
+	return marks, nil
+}
+
+// SetProofingMark stores a mark, filling in its Src, Filename and UpdatedAt,
+// or drops it when it is neither a favorite nor has a comment
+func (r *GalleryRepository) SetProofingMark(ctx context.Context, mark *models.ProofingMark) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	client, settings, err := r.openProofingClient(mark.ClientID)
+	if err != nil {
+		return err
+	}
+	img, ok := r.images[mark.ImageID]
+	if !ok || img.DeletedAt != nil || img.CategoryID != client.CategoryID {
+		return sql.ErrNoRows
+	}
+
+	mark.Src, mark.Filename = img.Src, img.Filename
+	mark.UpdatedAt = time.Now().UTC()
+	if !mark.Favorite && mark.Comment == "" {
+		delete(r.marks[mark.ClientID], mark.ImageID)
+		return nil
+	}
+
+	if mark.Favorite && settings.MaxPicks > 0 && r.countFavorites(client, mark.ImageID) >= settings.MaxPicks {
+		return repository.ErrPickLimit
+	}
+
+	if r.marks[mark.ClientID] == nil {
+		r.marks[mark.ClientID] = make(map[int]models.ProofingMark)
+	}
+	stored := *mark
+	stored.Src, stored.Filename = "", ""
+	r.marks[mark.ClientID][mark.ImageID] = stored
+
+	return nil
+}
+
+// SubmitProofingSelection makes a client's selection final
+func (r *GalleryRepository) SubmitProofingSelection(ctx context.Context, clientID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	client, settings, err := r.openProofingClient(clientID)
+	if err != nil {
+		return err
+	}
+
+	picks := r.countFavorites(client, 0)
+	if picks == 0 {
+		return repository.ErrEmptySelection
+	}
+	if settings.MaxPicks > 0 && picks > settings.MaxPicks {
+		return repository.ErrPickLimit
+	}
+
+	now := time.Now().UTC()
+	client.SubmittedAt = &now
+	r.clients[clientID] = client
+
+	return nil
+}
+
+// ReopenProofingSelection lets a client change a submitted selection again
+func (r *GalleryRepository) ReopenProofingSelection(ctx context.Context, clientID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	client, ok := r.clients[clientID]
+	if !ok || client.SubmittedAt == nil {
+		return sql.ErrNoRows
+	}
+	client.SubmittedAt = nil
+	r.clients[clientID] = client
+
+	return nil
+}
+
+// openProofingClient returns a client that may still change its selection
+// and its gallery's settings, like lockProofingClient in the database
+// repository. Callers must hold the lock.
+func (r *GalleryRepository) openProofingClient(clientID int) (models.ProofingClient, models.ProofingSettings, error) {
+	client, ok := r.clients[clientID]
+	if !ok || client.RevokedAt != nil {
+		return models.ProofingClient{}, models.ProofingSettings{}, sql.ErrNoRows
+	}
+	if client.SubmittedAt != nil {
+		return models.ProofingClient{}, models.ProofingSettings{}, repository.ErrSelectionSubmitted
+	}
+	settings, ok := r.proofing[client.CategoryID]
+	if cat := r.categories[client.CategoryID]; !ok || cat.DeletedAt != nil {
+		return models.ProofingClient{}, models.ProofingSettings{}, sql.ErrNoRows
+	}
+	return client, settings, nil
+}
+
+// countFavorites counts a client's favorites among the live images of its
+// gallery, leaving out exceptImageID. Callers must hold the lock.
+func (r *GalleryRepository) countFavorites(client models.ProofingClient, exceptImageID int) int {
+	n := 0
+	for imgID, mark := range r.marks[client.ID] {
+		img, ok := r.images[imgID]
+		if mark.Favorite && imgID != exceptImageID && ok && img.DeletedAt == nil && img.CategoryID == client.CategoryID {
+			n++
+		}
+	}
+	return n
+}
+
+// purgeProofing mirrors ON DELETE CASCADE from a purged gallery to its
+// proofing settings, clients and their marks. Callers must hold the lock.
+func (r *GalleryRepository) purgeProofing(categoryID int) {
+	delete(r.proofing, categoryID)
+	for id, client := range r.clients {
+		if client.CategoryID == categoryID {
+			delete(r.clients, id)
+			delete(r.marks, id)
+		}
+	}
+}
+
+// purgeMarks mirrors ON DELETE CASCADE from a purged image to its marks.
+// Callers must hold the lock.
+func (r *GalleryRepository) purgeMarks(imageID int) {
+	for _, marks := range r.marks {
+		delete(marks, imageID)
+	}
+}
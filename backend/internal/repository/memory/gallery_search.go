@@ -0,0 +1,90 @@
+// backend/internal/repository/memory/gallery_search.go
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// Search finds public galleries by title and description and their live
+// images by alt text, best matches first and at most limit of each
+func (r *GalleryRepository) Search(ctx context.Context, query string, limit int) (*models.ContentSearchResults, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	results := &models.ContentSearchResults{
+		Galleries: []models.GallerySearchHit{},
+		Images:    []models.ImageSearchHit{},
+	}
+	s := newSearcher(query)
+	if s == nil {
+		return results, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	for _, cat := range r.categories {
+		if cat.DeletedAt != nil || !cat.IsListed(now) {
+			continue
+		}
+		rank, snippet, ok := s.match(searchField{cat.Title, 1}, searchField{cat.Description, 0.4})
+		if ok {
+			results.Galleries = append(results.Galleries, models.GallerySearchHit{
+				ID:         cat.ID,
+				Slug:       cat.Slug,
+				Title:      cat.Title,
+				CoverImage: cat.CoverImage,
+				Snippet:    snippet,
+				Rank:       rank,
+			})
+		}
+	}
+
+	for _, img := range r.images {
+		cat := r.categories[img.CategoryID]
+		if img.DeletedAt != nil || cat.DeletedAt != nil || !cat.IsListed(now) {
+			continue
+		}
+		rank, snippet, ok := s.match(searchField{img.Alt, 1})
+		if ok {
+			results.Images = append(results.Images, models.ImageSearchHit{
+				ID:           img.ID,
+				CategoryID:   img.CategoryID,
+				CategorySlug: cat.Slug,
+				Src:          img.Src,
+				Alt:          img.Alt,
+				Snippet:      snippet,
+				Rank:         rank,
+			})
+		}
+	}
+
+	sort.Slice(results.Galleries, func(i, j int) bool {
+		a, b := results.Galleries[i], results.Galleries[j]
+		if a.Rank != b.Rank {
+			return a.Rank > b.Rank
+		}
+		return a.ID > b.ID
+	})
+	sort.Slice(results.Images, func(i, j int) bool {
+		a, b := results.Images[i], results.Images[j]
+		if a.Rank != b.Rank {
+			return a.Rank > b.Rank
+		}
+		return a.ID > b.ID
+	})
+	if len(results.Galleries) > limit {
+		results.Galleries = results.Galleries[:limit]
+	}
+	if len(results.Images) > limit {
+		results.Images = results.Images[:limit]
+	}
+
+	return results, nil
+}
@@ -0,0 +1,449 @@
+// backend/internal/repository/memory/gallery_tags.go
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+var _ repository.TagStore = (*GalleryRepository)(nil)
+
+// ListTags returns every tag by name, counting live images and galleries
+// whatever their status
+func (r *GalleryRepository) ListTags(ctx context.Context) ([]models.TagUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.tagUsage(func(cat models.GalleryCategory) bool { return cat.DeletedAt == nil }, false), nil
+}
+
+// PublicTags returns the tags of public images and galleries with their counts, by name
+func (r *GalleryRepository) PublicTags(ctx context.Context) ([]models.TagUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	return r.tagUsage(func(cat models.GalleryCategory) bool { return cat.DeletedAt == nil && cat.IsListed(now) }, true), nil
+}
+
+// tagUsage counts the live images and galleries in categories accepted by
+// visible for every tag. Callers must hold the lock.
+func (r *GalleryRepository) tagUsage(visible func(models.GalleryCategory) bool, skipUnused bool) []models.TagUsage {
+	usage := make(map[int]*models.TagUsage, len(r.tags))
+	for id, tag := range r.tags {
+		usage[id] = &models.TagUsage{Tag: tag}
+	}
+	for imgID, tagIDs := range r.imageTags {
+		img, ok := r.images[imgID]
+		if !ok || img.DeletedAt != nil || !visible(r.categories[img.CategoryID]) {
+			continue
+		}
+		for tagID := range tagIDs {
+			usage[tagID].ImageCount++
+		}
+	}
+	for catID, tagIDs := range r.categoryTags {
+		if cat, ok := r.categories[catID]; !ok || !visible(cat) {
+			continue
+		}
+		for tagID := range tagIDs {
+			usage[tagID].GalleryCount++
+		}
+	}
+
+	tags := []models.TagUsage{}
+	for _, u := range usage {
+		if !skipUnused || u.ImageCount > 0 || u.GalleryCount > 0 {
+			tags = append(tags, *u)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return lessByName(tags[i].Tag, tags[j].Tag) })
+	return tags
+}
+
+// GetTag retrieves a tag by ID
+func (r *GalleryRepository) GetTag(ctx context.Context, id int) (*models.Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tag, ok := r.tags[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &tag, nil
+}
+
+// CreateTag creates a tag
+func (r *GalleryRepository) CreateTag(ctx context.Context, tag *models.Tag) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.tagSlugTaken(tag.Slug, 0) {
+		return repository.ErrDuplicateSlug
+	}
+
+	now := time.Now()
+	tag.ID = r.nextTagID
+	tag.CreatedAt = now
+	tag.UpdatedAt = now
+	r.nextTagID++
+	r.tags[tag.ID] = *tag
+
+	return nil
+}
+
+// UpdateTag renames a tag
+func (r *GalleryRepository) UpdateTag(ctx context.Context, tag *models.Tag) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.tags[tag.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if r.tagSlugTaken(tag.Slug, tag.ID) {
+		return repository.ErrDuplicateSlug
+	}
+
+	existing.Name = tag.Name
+	existing.Slug = tag.Slug
+	existing.UpdatedAt = time.Now()
+	r.tags[tag.ID] = existing
+	*tag = existing
+
+	return nil
+}
+
+// DeleteTag deletes a tag and untags everything that carried it
+func (r *GalleryRepository) DeleteTag(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tags[id]; !ok {
+		return sql.ErrNoRows
+	}
+	r.deleteTag(id)
+
+	return nil
+}
+
+// MergeTags moves everything tagged with sourceIDs to targetID and deletes the sources
+func (r *GalleryRepository) MergeTags(ctx context.Context, sourceIDs []int, targetID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	target, ok := r.tags[targetID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	var sources []int
+	for _, id := range repository.UniqueIDs(sourceIDs) {
+		if _, ok := r.tags[id]; !ok {
+			return sql.ErrNoRows
+		}
+		if id != targetID {
+			sources = append(sources, id)
+		}
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+
+	for _, links := range []map[int]map[int]bool{r.imageTags, r.categoryTags} {
+		for _, tagIDs := range links {
+			for _, id := range sources {
+				if tagIDs[id] {
+					tagIDs[targetID] = true
+				}
+			}
+		}
+	}
+	for _, id := range sources {
+		r.deleteTag(id)
+	}
+	target.UpdatedAt = time.Now()
+	r.tags[targetID] = target
+
+	return nil
+}
+
+// ImageTags returns the tags of a live image by name
+func (r *GalleryRepository) ImageTags(ctx context.Context, imageID int) ([]models.Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if img, ok := r.images[imageID]; !ok || img.DeletedAt != nil {
+		return nil, sql.ErrNoRows
+	}
+	return r.tagList(r.imageTags[imageID]), nil
+}
+
+// SetImageTags replaces the tags of a live image
+func (r *GalleryRepository) SetImageTags(ctx context.Context, imageID int, slugs []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if img, ok := r.images[imageID]; !ok || img.DeletedAt != nil {
+		return sql.ErrNoRows
+	}
+	return r.setTags(r.imageTags, imageID, slugs)
+}
+
+// CategoryTags returns the tags of a live category by name
+func (r *GalleryRepository) CategoryTags(ctx context.Context, categoryID int) ([]models.Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if cat, ok := r.categories[categoryID]; !ok || cat.DeletedAt != nil {
+		return nil, sql.ErrNoRows
+	}
+	return r.tagList(r.categoryTags[categoryID]), nil
+}
+
+// SetCategoryTags replaces the tags of a live category
+func (r *GalleryRepository) SetCategoryTags(ctx context.Context, categoryID int, slugs []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cat, ok := r.categories[categoryID]; !ok || cat.DeletedAt != nil {
+		return sql.ErrNoRows
+	}
+	return r.setTags(r.categoryTags, categoryID, slugs)
+}
+
+// ImagesByTags returns a page of public images matching q, newest first,
+// with the total number of matches and the tag facets of all of them
+func (r *GalleryRepository) ImagesByTags(ctx context.Context, q repository.TagQuery) (*models.TaggedImages, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := r.tagIDsBySlug(q.Slugs)
+	now := time.Now()
+	var matches []models.TaggedImage
+	counts := make(map[int]int)
+	for _, img := range r.images {
+		cat := r.categories[img.CategoryID]
+		if img.DeletedAt != nil || cat.DeletedAt != nil || !cat.IsListed(now) || !tagsMatch(r.imageTags[img.ID], wanted, q) {
+			continue
+		}
+		matches = append(matches, models.TaggedImage{GalleryImage: img, CategorySlug: cat.Slug})
+		for tagID := range r.imageTags[img.ID] {
+			counts[tagID]++
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+
+	results := &models.TaggedImages{Images: []models.TaggedImage{}, Total: len(matches), Facets: []models.TagFacet{}}
+	if q.Limit > 0 {
+		start := min(q.Offset, len(matches))
+		matches = matches[start:min(start+q.Limit, len(matches))]
+	}
+	for _, img := range matches {
+		img.Tags = r.tagList(r.imageTags[img.ID])
+		results.Images = append(results.Images, img)
+	}
+
+	for tagID, count := range counts {
+		results.Facets = append(results.Facets, models.TagFacet{Tag: r.tags[tagID], Count: count})
+	}
+	sort.Slice(results.Facets, func(i, j int) bool {
+		a, b := results.Facets[i], results.Facets[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return lessByName(a.Tag, b.Tag)
+	})
+
+	return results, nil
+}
+
+// CategoriesByTags returns the public galleries matching q in display
+// order, without their images
+func (r *GalleryRepository) CategoriesByTags(ctx context.Context, q repository.TagQuery) ([]models.TaggedGallery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := r.tagIDsBySlug(q.Slugs)
+	now := time.Now()
+	galleries := []models.TaggedGallery{}
+	for _, cat := range r.categories {
+		if cat.DeletedAt != nil || !cat.IsListed(now) || !tagsMatch(r.categoryTags[cat.ID], wanted, q) {
+			continue
+		}
+		galleries = append(galleries, models.TaggedGallery{GalleryCategory: cat, Tags: r.tagList(r.categoryTags[cat.ID])})
+	}
+
+	sort.SliceStable(galleries, func(i, j int) bool {
+		return lessByOrder(galleries[i].DisplayOrder, galleries[i].CreatedAt, galleries[i].ID,
+			galleries[j].DisplayOrder, galleries[j].CreatedAt, galleries[j].ID)
+	})
+
+	return galleries, nil
+}
+
+// setTags replaces the tags of id in links. Callers must hold the lock.
+func (r *GalleryRepository) setTags(links map[int]map[int]bool, id int, slugs []string) error {
+	slugs = repository.UniqueStrings(slugs)
+	tagIDs := r.tagIDsBySlug(slugs)
+	if len(tagIDs) != len(slugs) {
+		return repository.ErrTagNotFound
+	}
+
+	if len(tagIDs) == 0 {
+		delete(links, id)
+	} else {
+		links[id] = tagIDs
+	}
+	return nil
+}
+
+// tagIDsBySlug returns the IDs of the existing tags among slugs.
+// Callers must hold the lock.
+func (r *GalleryRepository) tagIDsBySlug(slugs []string) map[int]bool {
+	ids := make(map[int]bool)
+	for _, slug := range slugs {
+		for id, tag := range r.tags {
+			if tag.Slug == slug {
+				ids[id] = true
+			}
+		}
+	}
+	return ids
+}
+
+// tagList returns the tags with the given IDs by name. Callers must hold the lock.
+func (r *GalleryRepository) tagList(ids map[int]bool) []models.Tag {
+	tags := []models.Tag{}
+	for id := range ids {
+		tags = append(tags, r.tags[id])
+	}
+	sort.Slice(tags, func(i, j int) bool { return lessByName(tags[i], tags[j]) })
+	return tags
+}
+
+// tagSlugTaken reports whether a tag other than id uses slug. Callers must hold the lock.
+func (r *GalleryRepository) tagSlugTaken(slug string, id int) bool {
+	for _, tag := range r.tags {
+		if tag.Slug == slug && tag.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteTag removes a tag and its links, mirroring ON DELETE CASCADE.
+// Callers must hold the lock.
+func (r *GalleryRepository) deleteTag(id int) {
+	delete(r.tags, id)
+	for _, tagIDs := range r.imageTags {
+		delete(tagIDs, id)
+	}
+	for _, tagIDs := range r.categoryTags {
+		delete(tagIDs, id)
+	}
+}
+
+// tagsMatch reports whether an item with tagIDs matches q, whose slugs
+// resolved to wanted. Unknown slugs match nothing.
+func tagsMatch(tagIDs, wanted map[int]bool, q repository.TagQuery) bool {
+	requested := len(repository.UniqueStrings(q.Slugs))
+	if requested == 0 {
+		return true
+	}
+	if q.MatchAll && len(wanted) < requested {
+		return false
+	}
+
+	found := 0
+	for id := range wanted {
+		if tagIDs[id] {
+			found++
+		}
+	}
+	if q.MatchAll {
+		return found == len(wanted)
+	}
+	return found > 0
+}
+
+// lessByName orders tags by name ignoring case, then by ID
+func lessByName(a, b models.Tag) bool {
+	if nameA, nameB := strings.ToLower(a.Name), strings.ToLower(b.Name); nameA != nameB {
+		return nameA < nameB
+	}
+	return a.ID < b.ID
+}
+
+// copySet returns a copy of a set of IDs
+func copySet(set map[int]bool) map[int]bool {
+	c := make(map[int]bool, len(set))
+	for id := range set {
+		c[id] = true
+	}
+	return c
+}
@@ -0,0 +1,239 @@
+// backend/internal/repository/memory/gallery_trash.go
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// ListTrash returns the trashed categories, with the images trashed along
+// with them, and the images trashed on their own from live categories
+func (r *GalleryRepository) ListTrash(ctx context.Context) (*models.GalleryTrash, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	trash := &models.GalleryTrash{
+		Categories: []models.GalleryCategory{},
+		Images:     []models.GalleryImage{},
+	}
+
+	for _, cat := range r.categories {
+		if cat.DeletedAt != nil {
+			trash.Categories = append(trash.Categories, cat)
+		}
+	}
+	sort.Slice(trash.Categories, func(i, j int) bool {
+		return newerDeleted(trash.Categories[i].DeletedAt, trash.Categories[i].ID,
+			trash.Categories[j].DeletedAt, trash.Categories[j].ID)
+	})
+
+	var images []models.GalleryImage
+	for _, img := range r.images {
+		if img.DeletedAt != nil {
+			images = append(images, img)
+		}
+	}
+	sort.Slice(images, func(i, j int) bool {
+		if !images[i].DeletedAt.Equal(*images[j].DeletedAt) {
+			return images[i].DeletedAt.After(*images[j].DeletedAt)
+		}
+		if images[i].DisplayOrder != images[j].DisplayOrder {
+			return images[i].DisplayOrder < images[j].DisplayOrder
+		}
+		return images[i].ID > images[j].ID
+	})
+
+	for _, img := range images {
+		cat := r.categories[img.CategoryID]
+		switch {
+		case cat.DeletedAt == nil:
+			trash.Images = append(trash.Images, img)
+		case cat.DeletedAt.Equal(*img.DeletedAt):
+			for i := range trash.Categories {
+				if trash.Categories[i].ID == cat.ID {
+					trash.Categories[i].Images = append(trash.Categories[i].Images, img)
+				}
+			}
+		}
+	}
+
+	return trash, nil
+}
+
+// RestoreCategory takes a category and the images trashed with it out of the trash
+func (r *GalleryRepository) RestoreCategory(ctx context.Context, id int) (*models.GalleryCategory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cat, ok := r.categories[id]
+	if !ok || cat.DeletedAt == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	for imgID, img := range r.images {
+		if img.CategoryID == id && img.DeletedAt != nil && img.DeletedAt.Equal(*cat.DeletedAt) {
+			img.DeletedAt = nil
+			r.images[imgID] = img
+		}
+	}
+
+	cat.DeletedAt = nil
+	cat.UpdatedAt = time.Now()
+	r.categories[id] = cat
+
+	return &cat, nil
+}
+
+// RestoreImage takes an image out of the trash
+func (r *GalleryRepository) RestoreImage(ctx context.Context, id int) (*models.GalleryImage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	img, ok := r.images[id]
+	if !ok || img.DeletedAt == nil {
+		return nil, sql.ErrNoRows
+	}
+	if r.categories[img.CategoryID].DeletedAt != nil {
+		return nil, repository.ErrCategoryNotFound
+	}
+
+	img.DeletedAt = nil
+	img.UpdatedAt = time.Now()
+	r.images[id] = img
+
+	return &img, nil
+}
+
+// PurgeCategory permanently deletes a trashed category and all its images
+func (r *GalleryRepository) PurgeCategory(ctx context.Context, id int) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cat, ok := r.categories[id]
+	if !ok || cat.DeletedAt == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	// Mirror ON DELETE CASCADE
+	delete(r.categories, id)
+	delete(r.categoryTags, id)
+	delete(r.passwords, id)
+	r.purgeProofing(id)
+	var srcs []string
+	for imgID, img := range r.images {
+		if img.CategoryID == id {
+			srcs = append(srcs, img.Src)
+			r.purgeImage(imgID)
+		}
+	}
+
+	return r.unusedSources(srcs), nil
+}
+
+// PurgeImage permanently deletes a trashed image
+func (r *GalleryRepository) PurgeImage(ctx context.Context, id int) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	img, ok := r.images[id]
+	if !ok || img.DeletedAt == nil {
+		return nil, sql.ErrNoRows
+	}
+	r.purgeImage(id)
+
+	return r.unusedSources([]string{img.Src}), nil
+}
+
+// PurgeTrash permanently deletes everything trashed before the cutoff
+func (r *GalleryRepository) PurgeTrash(ctx context.Context, before time.Time) (int64, int64, []string, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var categories, images int64
+	var srcs []string
+	for id, img := range r.images {
+		expired := img.DeletedAt != nil && img.DeletedAt.Before(before)
+		if cat := r.categories[img.CategoryID]; cat.DeletedAt != nil && cat.DeletedAt.Before(before) {
+			expired = true
+		}
+		if expired {
+			srcs = append(srcs, img.Src)
+			r.purgeImage(id)
+			images++
+		}
+	}
+	for id, cat := range r.categories {
+		if cat.DeletedAt != nil && cat.DeletedAt.Before(before) {
+			delete(r.categories, id)
+			delete(r.categoryTags, id)
+			delete(r.passwords, id)
+			r.purgeProofing(id)
+			categories++
+		}
+	}
+
+	return categories, images, r.unusedSources(srcs), nil
+}
+
+// purgeImage deletes an image with its tags and proofing marks.
+// Callers must hold the lock.
+func (r *GalleryRepository) purgeImage(id int) {
+	delete(r.images, id)
+	delete(r.imageTags, id)
+	r.purgeMarks(id)
+}
+
+// unusedSources returns the distinct srcs, sorted, that no image uses
+// anymore, trashed ones included. Callers must hold the lock.
+func (r *GalleryRepository) unusedSources(srcs []string) []string {
+	used := make(map[string]bool, len(r.images))
+	for _, img := range r.images {
+		used[img.Src] = true
+	}
+	var unused []string
+	for _, src := range srcs {
+		if !used[src] {
+			used[src] = true // once
+			unused = append(unused, src)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// newerDeleted orders by deleted_at DESC, id DESC
+func newerDeleted(a *time.Time, idA int, b *time.Time, idB int) bool {
+	if !a.Equal(*b) {
+		return a.After(*b)
+	}
+	return idA > idB
+}
@@ -0,0 +1,159 @@
+// backend/internal/repository/memory/memory_test.go
+package memory_test
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/audit"
+	"github.com/supraik/Freelance-Portfolio/internal/cache"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/internal/repository/memory"
+	"github.com/supraik/Freelance-Portfolio/internal/repository/repotest"
+)
+
+func TestGalleryRepository(t *testing.T) {
+	repotest.TestGalleryStore(t, func(t *testing.T) repository.GalleryStore {
+		return memory.NewGalleryRepository()
+	})
+}
+
+// The cache must be invisible to callers: every write is followed by reads
+// in the suite, so stale entries show up as failures
+func TestCachedGalleryRepository(t *testing.T) {
+	repotest.TestGalleryStore(t, func(t *testing.T) repository.GalleryStore {
+		return repository.NewCachedGalleryRepository(memory.NewGalleryRepository(), cache.New(time.Minute))
+	})
+}
+
+func TestGalleryRepositoryTags(t *testing.T) {
+	repotest.TestTagStore(t, func(t *testing.T) (repository.GalleryStore, repository.TagStore) {
+		store := memory.NewGalleryRepository()
+		return store, store
+	})
+}
+
+func TestAuditedTagRepository(t *testing.T) {
+	repotest.TestTagStore(t, func(t *testing.T) (repository.GalleryStore, repository.TagStore) {
+		store := memory.NewGalleryRepository()
+		return store, repository.NewAuditedTagRepository(store, audit.NewRecorder(memory.NewAuditRepository()))
+	})
+}
+
+func TestGalleryRepositoryProofing(t *testing.T) {
+	repotest.TestProofingStore(t, func(t *testing.T) (repository.GalleryStore, repository.ProofingStore) {
+		store := memory.NewGalleryRepository()
+		return store, store
+	})
+}
+
+func TestAuditedProofingRepository(t *testing.T) {
+	repotest.TestProofingStore(t, func(t *testing.T) (repository.GalleryStore, repository.ProofingStore) {
+		store := memory.NewGalleryRepository()
+		return store, repository.NewAuditedProofingRepository(store, audit.NewRecorder(memory.NewAuditRepository()))
+	})
+}
+
+func TestContactRepository(t *testing.T) {
+	repotest.TestContactStore(t, func(t *testing.T) repository.ContactStore {
+		return memory.NewContactRepository()
+	})
+}
+
+func TestUserRepository(t *testing.T) {
+	repotest.TestUserStore(t, func(t *testing.T) repository.UserStore {
+		return memory.NewUserRepository()
+	})
+}
+
+func TestPortfolioSectionRepository(t *testing.T) {
+	repotest.TestPortfolioSectionStore(t, func(t *testing.T, sections []models.PortfolioSection) repository.PortfolioSectionStore {
+		return memory.NewPortfolioSectionRepository(sections...)
+	})
+}
+
+func TestCachedPortfolioSectionRepository(t *testing.T) {
+	repotest.TestPortfolioSectionStore(t, func(t *testing.T, sections []models.PortfolioSection) repository.PortfolioSectionStore {
+		return repository.NewCachedPortfolioSectionRepository(memory.NewPortfolioSectionRepository(sections...), cache.New(time.Minute))
+	})
+}
+
+func TestAuditRepository(t *testing.T) {
+	repotest.TestAuditStore(t, func(t *testing.T) repository.AuditStore {
+		return memory.NewAuditRepository()
+	})
+}
+
+func TestPreviewRepository(t *testing.T) {
+	repotest.TestPreviewStore(t, func(t *testing.T) repository.PreviewStore {
+		return memory.NewPreviewRepository()
+	})
+}
+
+func TestAccessLogRepository(t *testing.T) {
+	repotest.TestAccessLogStore(t, func(t *testing.T) repository.AccessLogStore {
+		return memory.NewAccessLogRepository()
+	})
+}
+
+// Auditing must be invisible to callers, and without an actor in the
+// context (as in the suite) nothing is recorded
+func TestAuditedGalleryRepository(t *testing.T) {
+	repotest.TestGalleryStore(t, func(t *testing.T) repository.GalleryStore {
+		log := memory.NewAuditRepository()
+		t.Cleanup(func() {
+			if _, total, _ := log.List(context.Background(), repository.AuditFilter{}); total != 0 {
+				t.Errorf("recorded %d entries without an actor", total)
+			}
+		})
+		return repository.NewAuditedGalleryRepository(memory.NewGalleryRepository(), audit.NewRecorder(log))
+	})
+}
+
+func TestAuditedGalleryRepositoryRecordsChanges(t *testing.T) {
+	log := memory.NewAuditRepository()
+	store := repository.NewAuditedGalleryRepository(memory.NewGalleryRepository(), audit.NewRecorder(log))
+	ctx := audit.WithActor(context.Background(), audit.Actor{UserID: 4, Email: "admin@example.com", IP: "198.51.100.1", UserAgent: "test"})
+
+	cat := &models.GalleryCategory{Slug: "wedding", Title: "Wedding"}
+	if err := store.CreateCategory(ctx, cat); err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+	cat.Title = "Weddings"
+	if err := store.UpdateCategory(ctx, cat); err != nil {
+		t.Fatalf("UpdateCategory: %v", err)
+	}
+	if err := store.DeleteCategory(ctx, cat.ID); err != nil {
+		t.Fatalf("DeleteCategory: %v", err)
+	}
+
+	entries, total, err := log.List(context.Background(), repository.AuditFilter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 3 {
+		t.Fatalf("recorded %d entries, want 3", total)
+	}
+	for i, action := range []string{"gallery.delete", "gallery.update", "gallery.create"} {
+		e := entries[i]
+		if e.Action != action || e.EntityType != "gallery" || e.EntityID != strconv.Itoa(cat.ID) ||
+			e.ActorID == nil || *e.ActorID != 4 || e.ActorEmail != "admin@example.com" || e.IP != "198.51.100.1" || e.UserAgent != "test" {
+			t.Errorf("entries[%d] = %+v, want %s by the actor", i, e, action)
+		}
+	}
+
+	var update map[string]audit.Change
+	if err := json.Unmarshal(entries[1].Changes, &update); err != nil {
+		t.Fatalf("decode changes: %v", err)
+	}
+	if string(update["title"].Before) != `"Wedding"` || string(update["title"].After) != `"Weddings"` {
+		t.Errorf("update changes = %s", entries[1].Changes)
+	}
+	if _, ok := update["slug"]; ok {
+		t.Errorf("unchanged slug recorded: %s", entries[1].Changes)
+	}
+}
@@ -0,0 +1,80 @@
+// backend/internal/repository/memory/portfolio_section.go
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// PortfolioSectionRepository is an in-memory repository.PortfolioSectionStore
+type PortfolioSectionRepository struct {
+	mu       sync.RWMutex
+	sections map[int]models.PortfolioSection
+}
+
+// NewPortfolioSectionRepository creates an in-memory repository holding sections.
+// Sections are only ever seeded by migrations, so they are passed in up front.
+func NewPortfolioSectionRepository(sections ...models.PortfolioSection) *PortfolioSectionRepository {
+	r := &PortfolioSectionRepository{sections: make(map[int]models.PortfolioSection)}
+
+	now := time.Now()
+	for i, section := range sections {
+		if section.ID == 0 {
+			section.ID = i + 1
+		}
+		if section.CreatedAt.IsZero() {
+			section.CreatedAt = now
+			section.UpdatedAt = now
+		}
+		r.sections[section.ID] = section
+	}
+
+	return r
+}
+
+var _ repository.PortfolioSectionStore = (*PortfolioSectionRepository)(nil)
+
+// GetAll returns all portfolio sections
+func (r *PortfolioSectionRepository) GetAll(ctx context.Context) ([]models.PortfolioSection, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var sections []models.PortfolioSection
+	for _, section := range r.sections {
+		sections = append(sections, section)
+	}
+
+	sort.SliceStable(sections, func(i, j int) bool {
+		if sections[i].DisplayOrder != sections[j].DisplayOrder {
+			return sections[i].DisplayOrder < sections[j].DisplayOrder
+		}
+		return sections[i].ID < sections[j].ID
+	})
+
+	return sections, nil
+}
+
+// UpdateImage updates the featured image for a section
+func (r *PortfolioSectionRepository) UpdateImage(ctx context.Context, sectionID int, cloudinaryID, imageURL string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if section, ok := r.sections[sectionID]; ok {
+		section.UpdatedAt = time.Now()
+		r.sections[sectionID] = section
+	}
+	return nil
+}
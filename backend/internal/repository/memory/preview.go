@@ -0,0 +1,112 @@
+// backend/internal/repository/memory/preview.go
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// PreviewRepository is an in-memory repository.PreviewStore.
+// It knows nothing about galleries, so unlike the database it cannot
+// reject links to missing ones.
+type PreviewRepository struct {
+	mu     sync.RWMutex
+	links  map[int]models.PreviewLink
+	nextID int
+}
+
+// NewPreviewRepository creates an empty in-memory preview link repository
+func NewPreviewRepository() *PreviewRepository {
+	return &PreviewRepository{
+		links:  make(map[int]models.PreviewLink),
+		nextID: 1,
+	}
+}
+
+var _ repository.PreviewStore = (*PreviewRepository)(nil)
+
+// Create stores a link, filling in its ID and CreatedAt
+func (r *PreviewRepository) Create(ctx context.Context, link *models.PreviewLink) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	link.ID = r.nextID
+	link.CreatedAt = time.Now().UTC()
+	link.ExpiresAt = link.ExpiresAt.UTC()
+	link.RevokedAt = nil
+	r.nextID++
+	r.links[link.ID] = *link
+
+	return nil
+}
+
+// GetByID returns a link, revoked and expired ones included
+func (r *PreviewRepository) GetByID(ctx context.Context, id int) (*models.PreviewLink, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	link, ok := r.links[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &link, nil
+}
+
+// ListActive returns the links that are neither revoked nor expired at now, newest first
+func (r *PreviewRepository) ListActive(ctx context.Context, now time.Time) ([]models.PreviewLink, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	links := []models.PreviewLink{}
+	for _, link := range r.links {
+		if link.IsActive(now) {
+			links = append(links, link)
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if !links[i].CreatedAt.Equal(links[j].CreatedAt) {
+			return links[i].CreatedAt.After(links[j].CreatedAt)
+		}
+		return links[i].ID > links[j].ID
+	})
+
+	return links, nil
+}
+
+// Revoke stops a link from granting access
+func (r *PreviewRepository) Revoke(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	link, ok := r.links[id]
+	if !ok || link.RevokedAt != nil {
+		return sql.ErrNoRows
+	}
+	now := time.Now().UTC()
+	link.RevokedAt = &now
+	r.links[id] = link
+
+	return nil
+}
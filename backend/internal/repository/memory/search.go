@@ -0,0 +1,96 @@
+// backend/internal/repository/memory/search.go
+package memory
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// snippetRadius is how much text a snippet keeps on each side of the first match
+const snippetRadius = 60
+
+// searchField is a text to search and its weight, like setweight in Postgres
+type searchField struct {
+	text   string
+	weight float64
+}
+
+// searcher matches documents containing every word of a query, standing in
+// for Postgres full-text search without stemming or stop words
+type searcher struct {
+	terms   []*regexp.Regexp
+	pattern *regexp.Regexp
+}
+
+// newSearcher returns nil when the query has no words
+func newSearcher(query string) *searcher {
+	words := repository.SearchTerms(query)
+	if len(words) == 0 {
+		return nil
+	}
+
+	s := &searcher{}
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = regexp.QuoteMeta(word)
+		s.terms = append(s.terms, regexp.MustCompile(`(?i)`+quoted[i]))
+	}
+	s.pattern = regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+	return s
+}
+
+// match reports whether every term occurs in fields, with a rank weighted by
+// field and a highlighted snippet of the best field
+func (s *searcher) match(fields ...searchField) (rank float64, snippet string, ok bool) {
+	for _, term := range s.terms {
+		found := false
+		for _, f := range fields {
+			if term.MatchString(f.text) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, "", false
+		}
+	}
+
+	best := -1.0
+	for _, f := range fields {
+		n := float64(len(s.pattern.FindAllStringIndex(f.text, -1)))
+		rank += n * f.weight
+		if n > 0 && n*f.weight > best {
+			best = n * f.weight
+			snippet = s.snippet(f.text)
+		}
+	}
+
+	return rank, repository.Highlight(snippet), true
+}
+
+// snippet marks the matches in text, trimmed to the area around the first one
+func (s *searcher) snippet(text string) string {
+	if loc := s.pattern.FindStringIndex(text); loc != nil && len(text) > 2*snippetRadius {
+		start, end := loc[0]-snippetRadius, loc[1]+snippetRadius
+		prefix, suffix := " ... ", " ... "
+		if start <= 0 {
+			start, prefix = 0, ""
+		}
+		if end >= len(text) {
+			end, suffix = len(text), ""
+		}
+		// Keep whole runes
+		for start > 0 && !utf8.RuneStart(text[start]) {
+			start--
+		}
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end++
+		}
+		text = prefix + strings.TrimSpace(text[start:end]) + suffix
+	}
+
+	return s.pattern.ReplaceAllString(text, repository.SnippetStart+"$0"+repository.SnippetStop)
+}
@@ -0,0 +1,135 @@
+// backend/internal/repository/memory/user.go
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// UserRepository is an in-memory repository.UserStore
+type UserRepository struct {
+	mu     sync.RWMutex
+	users  map[int]models.User
+	nextID int
+}
+
+// NewUserRepository creates an empty in-memory user repository
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users:  make(map[int]models.User),
+		nextID: 1,
+	}
+}
+
+var _ repository.UserStore = (*UserRepository)(nil)
+
+// GetByEmail retrieves a user by email
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return withName(user), nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return withName(user), nil
+}
+
+// Create creates a new admin user
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user.Username == "" {
+		user.Username = user.Name
+	}
+
+	for _, existing := range r.users {
+		if existing.Email == user.Email {
+			return repository.ErrDuplicateEmail
+		}
+	}
+
+	user.ID = r.nextID
+	user.CreatedAt = time.Now()
+	r.nextID++
+
+	stored := *user
+	stored.Name = ""
+	r.users[user.ID] = stored
+
+	return nil
+}
+
+// UpdateLastLogin updates the last login timestamp
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user, ok := r.users[id]; ok {
+		now := time.Now()
+		user.LastLogin = &now
+		r.users[id] = user
+	}
+	return nil
+}
+
+// EmailExists checks if an email already exists
+func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// withName mirrors the Postgres repository, which has no name column
+// and falls back to the username
+func withName(user models.User) *models.User {
+	if user.Name == "" {
+		user.Name = user.Username
+	}
+	return &user
+}
@@ -0,0 +1,71 @@
+// backend/internal/repository/operations_test.go
+package repository_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+)
+
+// TestOperationNames checks that database.Operations lists exactly the
+// names the repositories pass to WithTimeout, so DB_OPERATION_TIMEOUTS
+// accepts every one of them and nothing else
+func TestOperationNames(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	used := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 2 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "WithTimeout" {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "context" {
+				return true
+			}
+			lit, ok := call.Args[1].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				t.Errorf("%s: WithTimeout with a computed operation name", fset.Position(call.Pos()))
+				return true
+			}
+			op, _ := strconv.Unquote(lit.Value)
+			used[op] = true
+			if !slices.Contains(database.Operations, op) {
+				t.Errorf("%s: operation %q is missing from database.Operations", fset.Position(call.Pos()), op)
+			}
+			return true
+		})
+	}
+
+	var unused []string
+	for _, op := range database.Operations {
+		if !used[op] {
+			unused = append(unused, op)
+		}
+	}
+	if len(unused) > 0 {
+		t.Errorf("database.Operations lists names no repository uses: %v", unused)
+	}
+}
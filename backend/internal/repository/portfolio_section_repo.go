@@ -3,21 +3,37 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 
+	"github.com/supraik/Freelance-Portfolio/internal/database"
 	"github.com/supraik/Freelance-Portfolio/internal/models"
 )
 
+// portfolioSectionTables lists the columns PortfolioSectionRepository reads and writes
+var portfolioSectionTables = []database.Table{
+	{Name: "portfolio_sections", Columns: []database.Column{
+		{Name: "id", Type: database.TypeInteger},
+		{Name: "name", Type: database.TypeVarchar},
+		{Name: "slug", Type: database.TypeVarchar},
+		{Name: "description", Type: database.TypeText},
+		{Name: "display_order", Type: database.TypeInteger},
+		{Name: "created_at", Type: database.TypeTimestamp},
+		{Name: "updated_at", Type: database.TypeTimestamp},
+	}},
+}
+
 type PortfolioSectionRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
-func NewPortfolioSectionRepository(db *sql.DB) *PortfolioSectionRepository {
+func NewPortfolioSectionRepository(db *database.DB) *PortfolioSectionRepository {
 	return &PortfolioSectionRepository{db: db}
 }
 
 // GetAll returns all portfolio sections
-func (r *PortfolioSectionRepository) GetAll(ctx context.Context) ([]models.PortfolioSection, error) {
+func (r *PortfolioSectionRepository) GetAll(ctx context.Context) (_ []models.PortfolioSection, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "portfolio_section.get_all")
+	defer func() { err = finish(err) }()
+
 	query := `
 		SELECT id, name, slug, description, display_order, created_at, updated_at
 		FROM portfolio_sections
@@ -48,17 +64,20 @@ func (r *PortfolioSectionRepository) GetAll(ctx context.Context) ([]models.Portf
 		sections = append(sections, section)
 	}
 
-	return sections, nil
+	return sections, rows.Err()
 }
 
 // UpdateImage updates the featured image for a section
-func (r *PortfolioSectionRepository) UpdateImage(ctx context.Context, sectionID int, cloudinaryID, imageURL string) error {
+func (r *PortfolioSectionRepository) UpdateImage(ctx context.Context, sectionID int, cloudinaryID, imageURL string) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "portfolio_section.update_image")
+	defer func() { err = finish(err) }()
+
 	query := `
 		UPDATE portfolio_sections
-		SET updated_at = NOW()
+		SET updated_at = CURRENT_TIMESTAMP
 		WHERE id = $1
 	`
 
-	_, err := r.db.ExecContext(ctx, query, sectionID)
+	_, err = r.db.ExecContext(ctx, query, sectionID)
 	return err
 }
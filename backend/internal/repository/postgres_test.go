@@ -0,0 +1,91 @@
+// backend/internal/repository/postgres_test.go
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database/databasetest"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+	"github.com/supraik/Freelance-Portfolio/internal/repository/repotest"
+)
+
+// openTestDB returns a migrated database with the given tables empty. It is
+// Postgres by default and SQLite in builds with -tags sqlite (sqlite_test.go).
+var openTestDB = databasetest.OpenPostgres
+
+func TestGalleryRepository(t *testing.T) {
+	repotest.TestGalleryStore(t, func(t *testing.T) repository.GalleryStore {
+		return repository.NewGalleryRepository(openTestDB(t, "gallery_images, gallery_categories"))
+	})
+}
+
+func TestGalleryRepositoryTags(t *testing.T) {
+	repotest.TestTagStore(t, func(t *testing.T) (repository.GalleryStore, repository.TagStore) {
+		store := repository.NewGalleryRepository(openTestDB(t, "image_tags, category_tags, tags, gallery_images, gallery_categories"))
+		return store, store
+	})
+}
+
+func TestGalleryRepositoryProofing(t *testing.T) {
+	repotest.TestProofingStore(t, func(t *testing.T) (repository.GalleryStore, repository.ProofingStore) {
+		store := repository.NewGalleryRepository(openTestDB(t, "proofing_marks, proofing_clients, proofing_galleries, gallery_images, gallery_categories"))
+		return store, store
+	})
+}
+
+func TestContactRepository(t *testing.T) {
+	repotest.TestContactStore(t, func(t *testing.T) repository.ContactStore {
+		return repository.NewContactRepository(openTestDB(t, "contact_messages"))
+	})
+}
+
+func TestUserRepository(t *testing.T) {
+	repotest.TestUserStore(t, func(t *testing.T) repository.UserStore {
+		return repository.NewUserRepository(openTestDB(t, "users"))
+	})
+}
+
+func TestPortfolioSectionRepository(t *testing.T) {
+	repotest.TestPortfolioSectionStore(t, func(t *testing.T, sections []models.PortfolioSection) repository.PortfolioSectionStore {
+		db := openTestDB(t, "portfolio_sections")
+		for _, s := range sections {
+			_, err := db.Exec(`INSERT INTO portfolio_sections (name, slug, description, display_order) VALUES ($1, $2, $3, $4)`,
+				s.Name, s.Slug, s.Description, s.DisplayOrder)
+			if err != nil {
+				t.Fatalf("seed section: %v", err)
+			}
+		}
+		return repository.NewPortfolioSectionRepository(db)
+	})
+}
+
+func TestAuditRepository(t *testing.T) {
+	repotest.TestAuditStore(t, func(t *testing.T) repository.AuditStore {
+		return repository.NewAuditRepository(openTestDB(t, "audit_log"))
+	})
+}
+
+func TestPreviewRepository(t *testing.T) {
+	repotest.TestPreviewStore(t, func(t *testing.T) repository.PreviewStore {
+		db := openTestDB(t, "preview_links, gallery_images, gallery_categories")
+		for _, slug := range []string{"one", "two"} {
+			if _, err := db.Exec(`INSERT INTO gallery_categories (slug, title) VALUES ($1, $1)`, slug); err != nil {
+				t.Fatalf("seed gallery: %v", err)
+			}
+		}
+		return repository.NewPreviewRepository(db)
+	})
+}
+
+func TestAccessLogRepository(t *testing.T) {
+	repotest.TestAccessLogStore(t, func(t *testing.T) repository.AccessLogStore {
+		db := openTestDB(t, "gallery_access_log, gallery_images, gallery_categories")
+		for _, slug := range []string{"one", "two"} {
+			if _, err := db.Exec(`INSERT INTO gallery_categories (slug, title) VALUES ($1, $1)`, slug); err != nil {
+				t.Fatalf("seed gallery: %v", err)
+			}
+		}
+		return repository.NewAccessLogRepository(db)
+	})
+}
@@ -0,0 +1,138 @@
+// backend/internal/repository/preview_repo.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// previewTables lists the columns PreviewRepository reads and writes
+var previewTables = []database.Table{
+	{Name: "preview_links", Columns: []database.Column{
+		{Name: "id", Type: database.TypeInteger},
+		{Name: "category_id", Type: database.TypeInteger},
+		{Name: "note", Type: database.TypeVarchar},
+		{Name: "created_by", Type: database.TypeInteger},
+		{Name: "expires_at", Type: database.TypeTimestamp},
+		{Name: "revoked_at", Type: database.TypeTimestamp},
+		{Name: "created_at", Type: database.TypeTimestamp},
+	}},
+}
+
+const previewColumns = `id, category_id, note, created_by, expires_at, revoked_at, created_at`
+
+// PreviewRepository handles gallery preview link data operations
+type PreviewRepository struct {
+	db *database.DB
+}
+
+// NewPreviewRepository creates a new preview link repository
+func NewPreviewRepository(db *database.DB) *PreviewRepository {
+	return &PreviewRepository{db: db}
+}
+
+// Create stores a link, filling in its ID and CreatedAt.
+// It returns ErrCategoryNotFound if the category does not exist.
+func (r *PreviewRepository) Create(ctx context.Context, link *models.PreviewLink) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "preview.create")
+	defer func() { err = finish(err) }()
+
+	// Set in Go rather than by the database so SQLite compares the same format in ListActive
+	link.CreatedAt = time.Now().UTC()
+	link.ExpiresAt = link.ExpiresAt.UTC()
+
+	query := `
+		INSERT INTO preview_links (category_id, note, created_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	err = r.db.QueryRowContext(ctx, query, link.CategoryID, link.Note, link.CreatedBy, link.ExpiresAt, link.CreatedAt).Scan(&link.ID)
+	return translateError(err)
+}
+
+// GetByID returns a link, revoked and expired ones included
+func (r *PreviewRepository) GetByID(ctx context.Context, id int) (_ *models.PreviewLink, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "preview.get")
+	defer func() { err = finish(err) }()
+
+	link, err := scanPreviewLink(r.db.QueryRowContext(ctx, `SELECT `+previewColumns+` FROM preview_links WHERE id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// ListActive returns the links that are neither revoked nor expired at now, newest first
+func (r *PreviewRepository) ListActive(ctx context.Context, now time.Time) (_ []models.PreviewLink, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "preview.list")
+	defer func() { err = finish(err) }()
+
+	query := `
+		SELECT ` + previewColumns + `
+		FROM preview_links
+		WHERE revoked_at IS NULL AND expires_at > $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []models.PreviewLink{}
+	for rows.Next() {
+		link, err := scanPreviewLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *link)
+	}
+
+	return links, rows.Err()
+}
+
+// Revoke stops a link from granting access.
+// It returns sql.ErrNoRows if the link does not exist or is already revoked.
+func (r *PreviewRepository) Revoke(ctx context.Context, id int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "preview.revoke")
+	defer func() { err = finish(err) }()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE preview_links SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// scanPreviewLink reads one row selected with previewColumns
+func scanPreviewLink(row interface{ Scan(...interface{}) error }) (*models.PreviewLink, error) {
+	var link models.PreviewLink
+	if err := row.Scan(
+		&link.ID,
+		&link.CategoryID,
+		&link.Note,
+		&link.CreatedBy,
+		&link.ExpiresAt,
+		&link.RevokedAt,
+		&link.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	link.ExpiresAt = link.ExpiresAt.UTC()
+	link.CreatedAt = link.CreatedAt.UTC()
+	link.RevokedAt = utc(link.RevokedAt)
+	return &link, nil
+}
@@ -0,0 +1,297 @@
+// backend/internal/repository/repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/supraik/Freelance-Portfolio/internal/database"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+)
+
+// Errors shared by every repository implementation.
+// Lookups of missing rows return sql.ErrNoRows, like database/sql does.
+var (
+	ErrDuplicateSlug    = errors.New("slug already exists")
+	ErrDuplicateEmail   = errors.New("email already exists")
+	ErrCategoryNotFound = errors.New("gallery category does not exist")
+	ErrTagNotFound      = errors.New("tag does not exist")
+
+	// ErrVersionConflict is returned by updates that carry a version which
+	// is no longer current, because someone else updated the row first
+	ErrVersionConflict = errors.New("row was modified since it was read")
+
+	// ErrOrderMismatch is returned by reorders whose ids are not exactly
+	// the current set, e.g. because a row was added or trashed meanwhile
+	ErrOrderMismatch = errors.New("ids do not match the current set")
+
+	// Proofing errors, see ProofingStore
+	ErrSelectionSubmitted = errors.New("selection has already been submitted")
+	ErrPickLimit          = errors.New("selection exceeds the pick limit")
+	ErrEmptySelection     = errors.New("selection has no favorites")
+
+	// ErrTimeout wraps errors from operations that ran past their deadline
+	ErrTimeout = database.ErrTimeout
+)
+
+// CategoryListOptions controls which categories and images GetAllCategories loads
+type CategoryListOptions struct {
+	IncludeImages bool
+	ImagesLimit   int  // images per category, 0 loads all of them
+	AllStatuses   bool // drafts, archived and scheduled categories too, for the admin
+}
+
+// GalleryStore is implemented by GalleryRepository and memory.GalleryRepository
+type GalleryStore interface {
+	GetAllCategories(ctx context.Context, opts CategoryListOptions) ([]models.GalleryCategory, error)
+	// GetCategoryBySlug only finds public categories, GetCategoryByID any live one
+	GetCategoryBySlug(ctx context.Context, slug string) (*models.GalleryCategory, error)
+	GetCategoryByID(ctx context.Context, id int) (*models.GalleryCategory, error)
+	GetImageByID(ctx context.Context, id int) (*models.GalleryImage, error)
+	GetImagesByCategory(ctx context.Context, categoryID int) ([]models.GalleryImage, error)
+	CreateCategory(ctx context.Context, cat *models.GalleryCategory) error
+	UpdateCategory(ctx context.Context, cat *models.GalleryCategory) error
+	UpdateCategoryStatus(ctx context.Context, cat *models.GalleryCategory) error
+	// UpdateCategoryAccess keeps the current password hash when passwordHash
+	// is empty; CategoryPasswordHash returns "" when there is none
+	UpdateCategoryAccess(ctx context.Context, cat *models.GalleryCategory, passwordHash string) error
+	CategoryPasswordHash(ctx context.Context, id int) (string, error)
+	ScheduleTransitions(ctx context.Context, after, until time.Time) ([]int, error)
+	DeleteCategory(ctx context.Context, id int) error
+	CreateImage(ctx context.Context, img *models.GalleryImage) error
+	UpdateImage(ctx context.Context, img *models.GalleryImage) error
+	DeleteImage(ctx context.Context, id int) error
+
+	// Reordering takes every live id in its new order, otherwise ErrOrderMismatch
+	ReorderCategories(ctx context.Context, ids []int) error
+	ReorderImages(ctx context.Context, categoryID int, ids []int) error
+
+	// MoveImages and CopyImages place images in another category at position
+	// (0 is first, out of range appends) and return them in their new state
+	MoveImages(ctx context.Context, ids []int, categoryID, position int) ([]models.GalleryImage, error)
+	CopyImages(ctx context.Context, ids []int, categoryID, position int) ([]models.GalleryImage, error)
+
+	// Search matches listed galleries and their images; see ContentSearchResults
+	Search(ctx context.Context, query string, limit int) (*models.ContentSearchResults, error)
+
+	// Trash. Deleting moves rows here; missing or live ids return sql.ErrNoRows.
+	// Purging returns the srcs of the deleted images that no image uses
+	// anymore, so their files can go too.
+	ListTrash(ctx context.Context) (*models.GalleryTrash, error)
+	RestoreCategory(ctx context.Context, id int) (*models.GalleryCategory, error)
+	RestoreImage(ctx context.Context, id int) (*models.GalleryImage, error)
+	PurgeCategory(ctx context.Context, id int) (unused []string, err error)
+	PurgeImage(ctx context.Context, id int) (unused []string, err error)
+	PurgeTrash(ctx context.Context, before time.Time) (categories, images int64, unused []string, err error)
+}
+
+// ContactStore is implemented by ContactRepository and memory.ContactRepository
+type ContactStore interface {
+	Create(ctx context.Context, req *models.ContactRequest) (*models.ContactMessage, error)
+	GetAll(ctx context.Context) ([]models.ContactMessage, error)
+	GetByID(ctx context.Context, id int) (*models.ContactMessage, error)
+	UpdateStatus(ctx context.Context, id int, status string) error
+	MarkAsRead(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int) error
+	Search(ctx context.Context, query string, limit int) ([]models.ContactSearchHit, error)
+}
+
+// UserStore is implemented by UserRepository and memory.UserRepository
+type UserStore interface {
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	UpdateLastLogin(ctx context.Context, id int) error
+	EmailExists(ctx context.Context, email string) (bool, error)
+}
+
+// PortfolioSectionStore is implemented by PortfolioSectionRepository and memory.PortfolioSectionRepository
+type PortfolioSectionStore interface {
+	GetAll(ctx context.Context) ([]models.PortfolioSection, error)
+	UpdateImage(ctx context.Context, sectionID int, cloudinaryID, imageURL string) error
+}
+
+// AuditFilter selects audit entries; zero fields match everything
+type AuditFilter struct {
+	ActorID    int
+	Action     string
+	EntityType string
+	EntityID   string
+	Since      time.Time // inclusive
+	Until      time.Time // exclusive
+	Limit      int
+	Offset     int
+}
+
+// AuditStore is implemented by AuditRepository and memory.AuditRepository
+type AuditStore interface {
+	Record(ctx context.Context, entry *models.AuditEntry) error
+	// List returns a page of matching entries, newest first, and the total number of matches
+	List(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, int, error)
+}
+
+// PreviewStore is implemented by PreviewRepository and memory.PreviewRepository
+type PreviewStore interface {
+	Create(ctx context.Context, link *models.PreviewLink) error
+	// GetByID returns the link whether or not it is still active
+	GetByID(ctx context.Context, id int) (*models.PreviewLink, error)
+	// ListActive returns the links that are neither revoked nor expired at now, newest first
+	ListActive(ctx context.Context, now time.Time) ([]models.PreviewLink, error)
+	// Revoke returns sql.ErrNoRows if the link does not exist or is already revoked
+	Revoke(ctx context.Context, id int) error
+}
+
+// AccessLogFilter selects a category's access log entries
+type AccessLogFilter struct {
+	CategoryID int
+	Event      string // empty matches every event
+	Limit      int
+	Offset     int
+}
+
+// AccessLogStore is implemented by AccessLogRepository and memory.AccessLogRepository
+type AccessLogStore interface {
+	// Record returns ErrCategoryNotFound if the category does not exist
+	Record(ctx context.Context, entry *models.GalleryAccessEntry) error
+	// List returns a page of matching entries, newest first, and the total number of matches
+	List(ctx context.Context, filter AccessLogFilter) ([]models.GalleryAccessEntry, int, error)
+}
+
+// ProofingStore is implemented by GalleryRepository and memory.GalleryRepository.
+// Marks only count while their image is live and still in the client's
+// gallery. Disabling proofing keeps clients and marks for when it is
+// enabled again.
+type ProofingStore interface {
+	// ProofingSettings returns sql.ErrNoRows if the gallery is not in proofing mode
+	ProofingSettings(ctx context.Context, categoryID int) (*models.ProofingSettings, error)
+	// EnableProofing puts a live gallery in proofing mode or changes its pick limit
+	EnableProofing(ctx context.Context, settings *models.ProofingSettings) error
+	DisableProofing(ctx context.Context, categoryID int) error
+
+	// CreateProofingClient returns ErrCategoryNotFound if the gallery does
+	// not exist. GetProofingClient returns revoked clients too.
+	CreateProofingClient(ctx context.Context, client *models.ProofingClient) error
+	GetProofingClient(ctx context.Context, id int) (*models.ProofingClient, error)
+	// ListProofingClients returns a gallery's clients in the order they were invited
+	ListProofingClients(ctx context.Context, categoryID int) ([]models.ProofingClient, error)
+	// RevokeProofingClient returns sql.ErrNoRows if the client does not exist or is already revoked
+	RevokeProofingClient(ctx context.Context, id int) error
+
+	// ProofingMarks returns a client's marks in gallery order
+	ProofingMarks(ctx context.Context, clientID int) ([]models.ProofingMark, error)
+	// SetProofingMark stores a mark, or drops it when it is neither a
+	// favorite nor has a comment. It returns sql.ErrNoRows for revoked
+	// clients and images not in their gallery, ErrSelectionSubmitted once
+	// the selection is final and ErrPickLimit for one favorite too many.
+	SetProofingMark(ctx context.Context, mark *models.ProofingMark) error
+	// SubmitProofingSelection makes a client's selection final. It returns
+	// ErrEmptySelection without favorites and ErrPickLimit with too many.
+	SubmitProofingSelection(ctx context.Context, clientID int) error
+	// ReopenProofingSelection returns sql.ErrNoRows if the selection was not submitted
+	ReopenProofingSelection(ctx context.Context, clientID int) error
+}
+
+// TagQuery selects public images or galleries by tag slug
+type TagQuery struct {
+	Slugs    []string // none matches everything
+	MatchAll bool     // carrying every tag rather than any of them
+	Limit    int      // images only, 0 returns every match
+	Offset   int
+}
+
+// TagStore is implemented by GalleryRepository and memory.GalleryRepository.
+// Tags of trashed images and galleries are kept but not counted or matched,
+// and the public queries only see listed galleries (public access).
+type TagStore interface {
+	// ListTags returns every tag by name, counting live images and
+	// galleries whatever their status
+	ListTags(ctx context.Context) ([]models.TagUsage, error)
+	GetTag(ctx context.Context, id int) (*models.Tag, error)
+	CreateTag(ctx context.Context, tag *models.Tag) error
+	// UpdateTag renames a tag; returns ErrDuplicateSlug if the slug is taken
+	UpdateTag(ctx context.Context, tag *models.Tag) error
+	DeleteTag(ctx context.Context, id int) error
+	// MergeTags moves everything tagged with sourceIDs to targetID and deletes
+	// the sources. It returns sql.ErrNoRows if any of the tags does not exist.
+	MergeTags(ctx context.Context, sourceIDs []int, targetID int) error
+
+	// ImageTags and CategoryTags return sql.ErrNoRows for missing or trashed
+	// items. The setters replace all tags of an item and return
+	// ErrTagNotFound for unknown slugs.
+	ImageTags(ctx context.Context, imageID int) ([]models.Tag, error)
+	SetImageTags(ctx context.Context, imageID int, slugs []string) error
+	CategoryTags(ctx context.Context, categoryID int) ([]models.Tag, error)
+	SetCategoryTags(ctx context.Context, categoryID int, slugs []string) error
+
+	// PublicTags returns the tags of public content with their counts, by name
+	PublicTags(ctx context.Context) ([]models.TagUsage, error)
+	// ImagesByTags returns public images matching q, newest first
+	ImagesByTags(ctx context.Context, q TagQuery) (*models.TaggedImages, error)
+	// CategoriesByTags returns public galleries matching q in display order
+	CategoriesByTags(ctx context.Context, q TagQuery) ([]models.TaggedGallery, error)
+}
+
+// Compile-time checks that the Postgres repositories satisfy the interfaces
+var (
+	_ GalleryStore          = (*GalleryRepository)(nil)
+	_ TagStore              = (*GalleryRepository)(nil)
+	_ ProofingStore         = (*GalleryRepository)(nil)
+	_ ContactStore          = (*ContactRepository)(nil)
+	_ UserStore             = (*UserRepository)(nil)
+	_ PortfolioSectionStore = (*PortfolioSectionRepository)(nil)
+	_ AuditStore            = (*AuditRepository)(nil)
+	_ PreviewStore          = (*PreviewRepository)(nil)
+	_ AccessLogStore        = (*AccessLogRepository)(nil)
+)
+
+// translateError maps Postgres and SQLite constraint violations onto the shared repository errors
+func translateError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return translateSQLiteError(err)
+	}
+
+	switch pqErr.Code.Name() {
+	case "unique_violation":
+		switch pqErr.Constraint {
+		case "gallery_categories_slug_key", "portfolio_sections_slug_key", "tags_slug_key":
+			return ErrDuplicateSlug
+		case "users_email_key":
+			return ErrDuplicateEmail
+		}
+	case "foreign_key_violation":
+		if pqErr.Table == "gallery_images" || pqErr.Table == "preview_links" ||
+			pqErr.Table == "gallery_access_log" || pqErr.Table == "proofing_clients" {
+			return ErrCategoryNotFound
+		}
+	}
+	return err
+}
+
+// translateSQLiteError matches SQLite constraint errors by message, so the
+// repository does not need to link the SQLite driver
+func translateSQLiteError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "UNIQUE constraint failed: gallery_categories.slug"),
+		strings.Contains(msg, "UNIQUE constraint failed: portfolio_sections.slug"),
+		strings.Contains(msg, "UNIQUE constraint failed: tags.slug"):
+		return ErrDuplicateSlug
+	case strings.Contains(msg, "UNIQUE constraint failed: users.email"):
+		return ErrDuplicateEmail
+	case strings.Contains(msg, "FOREIGN KEY constraint failed"):
+		// gallery_images, preview_links, gallery_access_log and
+		// proofing_clients.category_id are the only foreign keys that
+		// writes can violate
+		return ErrCategoryNotFound
+	}
+	return err
+}
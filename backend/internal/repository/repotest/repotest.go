@@ -0,0 +1,2313 @@
+// backend/internal/repository/repotest/repotest.go
+
+// Package repotest is a conformance suite every repository implementation must
+// pass, so the in-memory stores keep behaving like the Postgres ones.
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// TestGalleryStore runs the gallery conformance tests.
+// newStore must return an empty store for every call.
+func TestGalleryStore(t *testing.T, newStore func(t *testing.T) repository.GalleryStore) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGetBySlug", func(t *testing.T) {
+		store := newStore(t)
+
+		cat := &models.GalleryCategory{Slug: "wedding", Title: "Wedding", Description: "Bridal", CoverImage: "/c.jpg", DisplayOrder: 1}
+		if err := store.CreateCategory(ctx, cat); err != nil {
+			t.Fatalf("CreateCategory: %v", err)
+		}
+		if cat.ID == 0 || cat.CreatedAt.IsZero() || cat.UpdatedAt.IsZero() {
+			t.Fatalf("CreateCategory did not populate ID and timestamps: %+v", cat)
+		}
+
+		got, err := store.GetCategoryBySlug(ctx, "wedding")
+		if err != nil {
+			t.Fatalf("GetCategoryBySlug: %v", err)
+		}
+		if got.ID != cat.ID || got.Title != "Wedding" || got.Description != "Bridal" || got.CoverImage != "/c.jpg" || got.DisplayOrder != 1 {
+			t.Errorf("GetCategoryBySlug = %+v, want %+v", got, cat)
+		}
+		if len(got.Images) != 0 {
+			t.Errorf("new category has %d images, want 0", len(got.Images))
+		}
+
+		if _, err := store.GetCategoryBySlug(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetCategoryBySlug(missing) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		store := newStore(t)
+
+		cat := mustCreateCategory(t, store, "by-id", 1)
+		img := mustCreateImage(t, store, cat.ID, "/a.jpg", 1)
+
+		gotCat, err := store.GetCategoryByID(ctx, cat.ID)
+		if err != nil {
+			t.Fatalf("GetCategoryByID: %v", err)
+		}
+		if gotCat.Slug != "by-id" {
+			t.Errorf("GetCategoryByID = %+v", gotCat)
+		}
+		assertSrcs(t, gotCat.Images, "/a.jpg")
+
+		gotImg, err := store.GetImageByID(ctx, img.ID)
+		if err != nil {
+			t.Fatalf("GetImageByID: %v", err)
+		}
+		if gotImg.Src != "/a.jpg" || gotImg.CategoryID != cat.ID {
+			t.Errorf("GetImageByID = %+v", gotImg)
+		}
+
+		if err := store.DeleteCategory(ctx, cat.ID); err != nil {
+			t.Fatalf("DeleteCategory: %v", err)
+		}
+		if _, err := store.GetCategoryByID(ctx, cat.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetCategoryByID(trashed) error = %v, want sql.ErrNoRows", err)
+		}
+		if _, err := store.GetImageByID(ctx, img.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetImageByID(trashed) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("SlugIsUnique", func(t *testing.T) {
+		store := newStore(t)
+
+		mustCreateCategory(t, store, "makeup", 1)
+		err := store.CreateCategory(ctx, &models.GalleryCategory{Slug: "makeup", Title: "Again"})
+		if !errors.Is(err, repository.ErrDuplicateSlug) {
+			t.Errorf("duplicate slug error = %v, want ErrDuplicateSlug", err)
+		}
+	})
+
+	t.Run("CategoriesOrderedByDisplayOrder", func(t *testing.T) {
+		store := newStore(t)
+
+		mustCreateCategory(t, store, "third", 3)
+		mustCreateCategory(t, store, "first", 1)
+		mustCreateCategory(t, store, "second", 2)
+
+		categories, err := store.GetAllCategories(ctx, repository.CategoryListOptions{})
+		if err != nil {
+			t.Fatalf("GetAllCategories: %v", err)
+		}
+		assertSlugs(t, categories, "first", "second", "third")
+	})
+
+	t.Run("ImagesOrderedAndNested", func(t *testing.T) {
+		store := newStore(t)
+
+		cat := mustCreateCategory(t, store, "aesthetic", 1)
+		other := mustCreateCategory(t, store, "other", 2)
+		mustCreateImage(t, store, cat.ID, "/b.jpg", 2)
+		mustCreateImage(t, store, cat.ID, "/a.jpg", 1)
+		mustCreateImage(t, store, other.ID, "/x.jpg", 1)
+
+		images, err := store.GetImagesByCategory(ctx, cat.ID)
+		if err != nil {
+			t.Fatalf("GetImagesByCategory: %v", err)
+		}
+		assertSrcs(t, images, "/a.jpg", "/b.jpg")
+
+		categories, err := store.GetAllCategories(ctx, repository.CategoryListOptions{IncludeImages: true})
+		if err != nil {
+			t.Fatalf("GetAllCategories: %v", err)
+		}
+		if len(categories) != 2 {
+			t.Fatalf("GetAllCategories returned %d categories, want 2", len(categories))
+		}
+		assertSrcs(t, categories[0].Images, "/a.jpg", "/b.jpg")
+		assertSrcs(t, categories[1].Images, "/x.jpg")
+
+		bySlug, err := store.GetCategoryBySlug(ctx, "aesthetic")
+		if err != nil {
+			t.Fatalf("GetCategoryBySlug: %v", err)
+		}
+		assertSrcs(t, bySlug.Images, "/a.jpg", "/b.jpg")
+	})
+
+	t.Run("ImagesLimitAndCoversOnly", func(t *testing.T) {
+		store := newStore(t)
+
+		cat := mustCreateCategory(t, store, "wedding", 1)
+		empty := mustCreateCategory(t, store, "empty", 2)
+		mustCreateImage(t, store, cat.ID, "/c.jpg", 3)
+		mustCreateImage(t, store, cat.ID, "/a.jpg", 1)
+		mustCreateImage(t, store, cat.ID, "/b.jpg", 2)
+
+		limited, err := store.GetAllCategories(ctx, repository.CategoryListOptions{IncludeImages: true, ImagesLimit: 2})
+		if err != nil {
+			t.Fatalf("GetAllCategories(limit): %v", err)
+		}
+		assertSlugs(t, limited, "wedding", "empty")
+		assertSrcs(t, limited[0].Images, "/a.jpg", "/b.jpg")
+		if len(limited[1].Images) != 0 {
+			t.Errorf("category %d has %d images, want 0", empty.ID, len(limited[1].Images))
+		}
+
+		covers, err := store.GetAllCategories(ctx, repository.CategoryListOptions{})
+		if err != nil {
+			t.Fatalf("GetAllCategories(covers): %v", err)
+		}
+		for _, c := range covers {
+			if len(c.Images) != 0 {
+				t.Errorf("category %s loaded %d images without IncludeImages", c.Slug, len(c.Images))
+			}
+		}
+	})
+
+	t.Run("UpdateCategory", func(t *testing.T) {
+		store := newStore(t)
+
+		cat := mustCreateCategory(t, store, "saree", 1)
+		cat.Title = "Saree Branding"
+		cat.DisplayOrder = 5
+		if err := store.UpdateCategory(ctx, cat); err != nil {
+			t.Fatalf("UpdateCategory: %v", err)
+		}
+
+		got, err := store.GetCategoryBySlug(ctx, "saree")
+		if err != nil {
+			t.Fatalf("GetCategoryBySlug: %v", err)
+		}
+		if got.Title != "Saree Branding" || got.DisplayOrder != 5 {
+			t.Errorf("category after update = %+v", got)
+		}
+
+		missing := &models.GalleryCategory{ID: cat.ID + 1000, Title: "Nope"}
+		if err := store.UpdateCategory(ctx, missing); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("UpdateCategory(missing) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("DeleteCategoryCascadesImages", func(t *testing.T) {
+		store := newStore(t)
+
+		cat := mustCreateCategory(t, store, "gone", 1)
+		keep := mustCreateCategory(t, store, "kept", 2)
+		mustCreateImage(t, store, cat.ID, "/1.jpg", 1)
+		mustCreateImage(t, store, keep.ID, "/2.jpg", 1)
+
+		if err := store.DeleteCategory(ctx, cat.ID); err != nil {
+			t.Fatalf("DeleteCategory: %v", err)
+		}
+		if _, err := store.GetCategoryBySlug(ctx, "gone"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("deleted category lookup error = %v, want sql.ErrNoRows", err)
+		}
+
+		images, err := store.GetImagesByCategory(ctx, cat.ID)
+		if err != nil {
+			t.Fatalf("GetImagesByCategory: %v", err)
+		}
+		if len(images) != 0 {
+			t.Errorf("deleted category still has %d images", len(images))
+		}
+
+		kept, err := store.GetImagesByCategory(ctx, keep.ID)
+		if err != nil {
+			t.Fatalf("GetImagesByCategory: %v", err)
+		}
+		assertSrcs(t, kept, "/2.jpg")
+
+		if err := store.DeleteCategory(ctx, cat.ID); err != nil {
+			t.Errorf("deleting a missing category returned %v, want nil", err)
+		}
+	})
+
+	t.Run("CreateImageRequiresCategory", func(t *testing.T) {
+		store := newStore(t)
+
+		err := store.CreateImage(ctx, &models.GalleryImage{CategoryID: 4242, Src: "/x.jpg"})
+		if !errors.Is(err, repository.ErrCategoryNotFound) {
+			t.Errorf("CreateImage(missing category) error = %v, want ErrCategoryNotFound", err)
+		}
+	})
+
+	t.Run("UpdateAndDeleteImage", func(t *testing.T) {
+		store := newStore(t)
+
+		cat := mustCreateCategory(t, store, "edit", 1)
+		img := mustCreateImage(t, store, cat.ID, "/old.jpg", 1)
+
+		img.Src = "/new.jpg"
+		img.Alt = "New"
+		img.AspectRatio = "landscape"
+		if err := store.UpdateImage(ctx, img); err != nil {
+			t.Fatalf("UpdateImage: %v", err)
+		}
+
+		images, err := store.GetImagesByCategory(ctx, cat.ID)
+		if err != nil {
+			t.Fatalf("GetImagesByCategory: %v", err)
+		}
+		if len(images) != 1 || images[0].Src != "/new.jpg" || images[0].Alt != "New" || images[0].AspectRatio != "landscape" {
+			t.Errorf("image after update = %+v", images)
+		}
+
+		if err := store.DeleteImage(ctx, img.ID); err != nil {
+			t.Fatalf("DeleteImage: %v", err)
+		}
+		images, err = store.GetImagesByCategory(ctx, cat.ID)
+		if err != nil {
+			t.Fatalf("GetImagesByCategory: %v", err)
+		}
+		if len(images) != 0 {
+			t.Errorf("image still present after delete: %+v", images)
+		}
+	})
+
+	// Fields derived from the image file are stored, listed, updated, copied
+	// and restored with the image
+	shot := time.Date(2024, 5, 4, 18, 30, 0, 0, time.UTC)
+	sized := []models.ImageVariant{
+		{URL: "/uploads/variants/a-480.jpg", Width: 480, Height: 320, Type: "image/jpeg"},
+		{URL: "/uploads/variants/a-480.webp", Width: 480, Height: 320, Type: "image/webp"},
+	}
+	for _, field := range []struct {
+		name          string
+		first, second any   // values stored in turn
+		empty         any   // the value of images created without one
+		other         []any // further values that must read back as stored
+		keptOnUpdate  bool  // updates with the empty value keep the stored one
+		set           func(img *models.GalleryImage, value any)
+		get           func(img *models.GalleryImage) any
+		same          func(a, b any) bool // reflect.DeepEqual when nil
+	}{
+		{
+			name: "Metadata",
+			first: &models.ImageMetadata{
+				CameraMake: "FUJIFILM", CameraModel: "X-T5", Lens: "XF33mmF1.4 R LM WR",
+				FocalLength: 33, FocalLength35: 50, Aperture: 1.4, ExposureTime: "1/250", ISO: 160,
+				CapturedAt: &shot, Orientation: 1,
+			},
+			second:       &models.ImageMetadata{CameraMake: "Canon", ISO: 800},
+			empty:        (*models.ImageMetadata)(nil),
+			keptOnUpdate: true,
+			set:          func(img *models.GalleryImage, value any) { img.Metadata = value.(*models.ImageMetadata) },
+			get:          func(img *models.GalleryImage) any { return img.Metadata },
+			same: func(a, b any) bool {
+				x, y := a.(*models.ImageMetadata), b.(*models.ImageMetadata)
+				return x == nil && y == nil || x != nil && y != nil && sameMetadata(*x, *y)
+			},
+		},
+		{
+			name:   "Dimensions",
+			first:  [2]int{6000, 4000},
+			second: [2]int{4000, 6000},
+			empty:  [2]int{0, 0},
+			set: func(img *models.GalleryImage, value any) {
+				size := value.([2]int)
+				img.Width, img.Height = size[0], size[1]
+			},
+			get: func(img *models.GalleryImage) any { return [2]int{img.Width, img.Height} },
+		},
+		{
+			name:   "Placeholders",
+			first:  models.ImagePlaceholder{BlurHash: "LEHV6nWB2yk8pyo0adR*.7kCMdnj", LQIP: "data:image/jpeg;base64,/9j/", DominantColor: "#7f6a55"},
+			second: models.ImagePlaceholder{BlurHash: "L00000fQfQfQfQfQfQfQfQfQfQfQ", LQIP: "data:image/jpeg;base64,/9k/", DominantColor: "#000000"},
+			empty:  models.ImagePlaceholder{},
+			set:    func(img *models.GalleryImage, value any) { img.ImagePlaceholder = value.(models.ImagePlaceholder) },
+			get:    func(img *models.GalleryImage) any { return img.ImagePlaceholder },
+		},
+		{
+			name:   "Variants",
+			first:  sized,
+			second: sized[:1],
+			empty:  []models.ImageVariant(nil),
+			other:  []any{[]models.ImageVariant{}}, // none needed is kept apart from not made yet
+			set:    func(img *models.GalleryImage, value any) { img.Variants = value.([]models.ImageVariant) },
+			get:    func(img *models.GalleryImage) any { return img.Variants },
+		},
+		{
+			name:   "Filename",
+			first:  "DSC_0042.JPG",
+			second: "Hochzeit März.jpg",
+			empty:  "",
+			set:    func(img *models.GalleryImage, value any) { img.Filename = value.(string) },
+			get:    func(img *models.GalleryImage) any { return img.Filename },
+		},
+	} {
+		t.Run("Image"+field.name, func(t *testing.T) {
+			store := newStore(t)
+			same := field.same
+			if same == nil {
+				same = reflect.DeepEqual
+			}
+			assertField := func(t *testing.T, what string, img *models.GalleryImage, want any) {
+				t.Helper()
+				if got := field.get(img); !same(got, want) {
+					t.Errorf("%s %s = %+v, want %+v", what, strings.ToLower(field.name), got, want)
+				}
+			}
+			assertStored := func(t *testing.T, id int, want any) {
+				t.Helper()
+				got, err := store.GetImageByID(ctx, id)
+				if err != nil {
+					t.Fatalf("GetImageByID: %v", err)
+				}
+				assertField(t, fmt.Sprintf("image %d", id), got, want)
+			}
+
+			cat := mustCreateCategory(t, store, "derived", 1)
+			to := mustCreateCategory(t, store, "derived-copies", 2)
+			img := &models.GalleryImage{CategoryID: cat.ID, Src: "/uploads/a.jpg", AspectRatio: models.AspectLandscape}
+			field.set(img, field.first)
+			if err := store.CreateImage(ctx, img); err != nil {
+				t.Fatalf("CreateImage: %v", err)
+			}
+			plain := mustCreateImage(t, store, cat.ID, "/uploads/b.jpg", 2)
+			assertStored(t, img.ID, field.first)
+			assertStored(t, plain.ID, field.empty)
+			for i, value := range field.other {
+				other := &models.GalleryImage{CategoryID: cat.ID, Src: fmt.Sprintf("/uploads/other-%d.jpg", i), AspectRatio: models.AspectSquare, DisplayOrder: 3 + i}
+				field.set(other, value)
+				if err := store.CreateImage(ctx, other); err != nil {
+					t.Fatalf("CreateImage: %v", err)
+				}
+				assertStored(t, other.ID, value)
+			}
+
+			cats, err := store.GetAllCategories(ctx, repository.CategoryListOptions{IncludeImages: true})
+			if err != nil {
+				t.Fatalf("GetAllCategories: %v", err)
+			}
+			if len(cats) != 2 || len(cats[0].Images) != 2+len(field.other) {
+				t.Fatalf("listed categories = %+v, want 2 with the images in the first", cats)
+			}
+			assertField(t, "listed image", &cats[0].Images[0], field.first)
+
+			// Updates write the value they are given
+			field.set(img, field.second)
+			img.Version = 0
+			if err := store.UpdateImage(ctx, img); err != nil {
+				t.Fatalf("UpdateImage: %v", err)
+			}
+			assertStored(t, img.ID, field.second)
+
+			copies, err := store.CopyImages(ctx, []int{img.ID}, to.ID, -1)
+			if err != nil {
+				t.Fatalf("CopyImages: %v", err)
+			}
+			if len(copies) != 1 {
+				t.Fatalf("CopyImages returned %d images, want 1", len(copies))
+			}
+			assertField(t, "copy", &copies[0], field.second)
+			assertStored(t, copies[0].ID, field.second)
+
+			if err := store.DeleteImage(ctx, img.ID); err != nil {
+				t.Fatalf("DeleteImage: %v", err)
+			}
+			restored, err := store.RestoreImage(ctx, img.ID)
+			if err != nil {
+				t.Fatalf("RestoreImage: %v", err)
+			}
+			assertField(t, "restored image", restored, field.second)
+
+			// Updates with the empty value clear it, or keep what is stored
+			field.set(img, field.empty)
+			img.Version = 0
+			if err := store.UpdateImage(ctx, img); err != nil {
+				t.Fatalf("UpdateImage: %v", err)
+			}
+			if field.keptOnUpdate {
+				assertField(t, "updated image", img, field.second)
+				assertStored(t, img.ID, field.second)
+			} else {
+				assertStored(t, img.ID, field.empty)
+			}
+		})
+	}
+
+	t.Run("Reorder", func(t *testing.T) {
+		store := newStore(t)
+
+		a := mustCreateCategory(t, store, "a", 1)
+		b := mustCreateCategory(t, store, "b", 2)
+		c := mustCreateCategory(t, store, "c", 3)
+		trashed := mustCreateCategory(t, store, "trashed", 4)
+		if err := store.DeleteCategory(ctx, trashed.ID); err != nil {
+			t.Fatalf("DeleteCategory: %v", err)
+		}
+
+		for _, ids := range [][]int{
+			{c.ID, a.ID},                   // missing one
+			{c.ID, a.ID, b.ID, trashed.ID}, // trashed
+			{c.ID, a.ID, a.ID},             // duplicate
+			{c.ID, a.ID, b.ID + 1000},      // unknown
+		} {
+			if err := store.ReorderCategories(ctx, ids); !errors.Is(err, repository.ErrOrderMismatch) {
+				t.Errorf("ReorderCategories(%v) error = %v, want ErrOrderMismatch", ids, err)
+			}
+		}
+
+		if err := store.ReorderCategories(ctx, []int{a.ID, c.ID, b.ID}); err != nil {
+			t.Fatalf("ReorderCategories: %v", err)
+		}
+		categories, err := store.GetAllCategories(ctx, repository.CategoryListOptions{})
+		if err != nil {
+			t.Fatalf("GetAllCategories: %v", err)
+		}
+		assertSlugs(t, categories, "a", "c", "b")
+		for i, cat := range categories {
+			if cat.DisplayOrder != i+1 {
+				t.Errorf("%s has display order %d, want %d", cat.Slug, cat.DisplayOrder, i+1)
+			}
+		}
+		// Only rows that moved get a new version
+		if categories[0].Version != 1 || categories[1].Version != 2 {
+			t.Errorf("versions after reorder = %d, %d, want 1, 2", categories[0].Version, categories[1].Version)
+		}
+
+		first := mustCreateImage(t, store, a.ID, "/1.jpg", 1)
+		second := mustCreateImage(t, store, a.ID, "/2.jpg", 2)
+		other := mustCreateImage(t, store, b.ID, "/other.jpg", 1)
+
+		if err := store.ReorderImages(ctx, a.ID, []int{second.ID, other.ID}); !errors.Is(err, repository.ErrOrderMismatch) {
+			t.Errorf("ReorderImages(foreign image) error = %v, want ErrOrderMismatch", err)
+		}
+		if err := store.ReorderImages(ctx, trashed.ID, []int{}); !errors.Is(err, repository.ErrCategoryNotFound) {
+			t.Errorf("ReorderImages(trashed category) error = %v, want ErrCategoryNotFound", err)
+		}
+
+		if err := store.ReorderImages(ctx, a.ID, []int{second.ID, first.ID}); err != nil {
+			t.Fatalf("ReorderImages: %v", err)
+		}
+		images, err := store.GetImagesByCategory(ctx, a.ID)
+		if err != nil {
+			t.Fatalf("GetImagesByCategory: %v", err)
+		}
+		assertSrcs(t, images, "/2.jpg", "/1.jpg")
+	})
+
+	t.Run("MoveAndCopyImages", func(t *testing.T) {
+		store := newStore(t)
+
+		from := mustCreateCategory(t, store, "from", 1)
+		to := mustCreateCategory(t, store, "to", 2)
+		cover := mustCreateImage(t, store, from.ID, "/cover.jpg", 1)
+		stays := mustCreateImage(t, store, from.ID, "/stays.jpg", 2)
+		mustCreateImage(t, store, to.ID, "/t1.jpg", 1)
+		mustCreateImage(t, store, to.ID, "/t2.jpg", 2)
+
+		from.CoverImage = "/cover.jpg"
+		if err := store.UpdateCategory(ctx, from); err != nil {
+			t.Fatalf("UpdateCategory: %v", err)
+		}
+
+		moved, err := store.MoveImages(ctx, []int{cover.ID}, to.ID, 1)
+		if err != nil {
+			t.Fatalf("MoveImages: %v", err)
+		}
+		if len(moved) != 1 || moved[0].ID != cover.ID || moved[0].CategoryID != to.ID {
+			t.Errorf("MoveImages = %+v", moved)
+		}
+		images, err := store.GetImagesByCategory(ctx, to.ID)
+		if err != nil {
+			t.Fatalf("GetImagesByCategory: %v", err)
+		}
+		assertSrcs(t, images, "/t1.jpg", "/cover.jpg", "/t2.jpg")
+
+		got, err := store.GetCategoryBySlug(ctx, "from")
+		if err != nil {
+			t.Fatalf("GetCategoryBySlug: %v", err)
+		}
+		if got.CoverImage != "/stays.jpg" {
+			t.Errorf("source cover = %q, want the first remaining image", got.CoverImage)
+		}
+		assertSrcs(t, got.Images, "/stays.jpg")
+
+		// Copies go to the end without a position and leave the originals alone
+		copies, err := store.CopyImages(ctx, []int{stays.ID, stays.ID}, to.ID, -1)
+		if err != nil {
+			t.Fatalf("CopyImages: %v", err)
+		}
+		if len(copies) != 1 || copies[0].ID == stays.ID || copies[0].Src != "/stays.jpg" || copies[0].CategoryID != to.ID {
+			t.Errorf("CopyImages = %+v", copies)
+		}
+		images, err = store.GetImagesByCategory(ctx, to.ID)
+		if err != nil {
+			t.Fatalf("GetImagesByCategory: %v", err)
+		}
+		assertSrcs(t, images, "/t1.jpg", "/cover.jpg", "/t2.jpg", "/stays.jpg")
+		if original, err := store.GetImageByID(ctx, stays.ID); err != nil || original.CategoryID != from.ID {
+			t.Errorf("original after copy = %+v, %v", original, err)
+		}
+
+		if _, err := store.MoveImages(ctx, []int{stays.ID}, to.ID+1000, 0); !errors.Is(err, repository.ErrCategoryNotFound) {
+			t.Errorf("MoveImages(missing category) error = %v, want ErrCategoryNotFound", err)
+		}
+		if _, err := store.CopyImages(ctx, []int{stays.ID, stays.ID + 1000}, to.ID, 0); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("CopyImages(missing image) error = %v, want sql.ErrNoRows", err)
+		}
+		images, err = store.GetImagesByCategory(ctx, to.ID)
+		if err != nil {
+			t.Fatalf("GetImagesByCategory: %v", err)
+		}
+		if len(images) != 4 {
+			t.Errorf("failed copy left %d images, want 4", len(images))
+		}
+	})
+
+	t.Run("PublishingWindow", func(t *testing.T) {
+		store := newStore(t)
+
+		now := time.Now()
+		past, future := now.Add(-time.Hour), now.Add(time.Hour)
+		for _, cat := range []*models.GalleryCategory{
+			{Slug: "live", Title: "Live"},
+			{Slug: "draft", Title: "Draft", Status: models.GalleryDraft},
+			{Slug: "archived", Title: "Archived", Status: models.GalleryArchived},
+			{Slug: "scheduled", Title: "Scheduled", Status: models.GalleryPublished, PublishAt: &future},
+			{Slug: "expired", Title: "Expired", Status: models.GalleryPublished, UnpublishAt: &past},
+			{Slug: "window", Title: "Window", Status: models.GalleryPublished, PublishAt: &past, UnpublishAt: &future},
+		} {
+			if err := store.CreateCategory(ctx, cat); err != nil {
+				t.Fatalf("CreateCategory(%s): %v", cat.Slug, err)
+			}
+		}
+
+		public, err := store.GetAllCategories(ctx, repository.CategoryListOptions{})
+		if err != nil {
+			t.Fatalf("GetAllCategories: %v", err)
+		}
+		assertSlugSet(t, public, "live", "window")
+
+		all, err := store.GetAllCategories(ctx, repository.CategoryListOptions{AllStatuses: true})
+		if err != nil {
+			t.Fatalf("GetAllCategories(AllStatuses): %v", err)
+		}
+		assertSlugSet(t, all, "live", "draft", "archived", "scheduled", "expired", "window")
+
+		var draft models.GalleryCategory
+		for _, cat := range all {
+			if cat.Slug == "draft" {
+				draft = cat
+			}
+		}
+		if draft.Status != models.GalleryDraft {
+			t.Fatalf("draft has status %q", draft.Status)
+		}
+		if _, err := store.GetCategoryBySlug(ctx, "draft"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetCategoryBySlug(draft) error = %v, want sql.ErrNoRows", err)
+		}
+		if _, err := store.GetCategoryByID(ctx, draft.ID); err != nil {
+			t.Errorf("GetCategoryByID(draft): %v", err)
+		}
+
+		mustCreateImage(t, store, draft.ID, "/sunset.jpg", 1)
+		results, err := store.Search(ctx, "sunset", 10)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if len(results.Images) != 0 {
+			t.Errorf("search found images of a draft: %+v", results.Images)
+		}
+		withImages, err := store.GetAllCategories(ctx, repository.CategoryListOptions{IncludeImages: true, AllStatuses: true})
+		if err != nil {
+			t.Fatalf("GetAllCategories(IncludeImages, AllStatuses): %v", err)
+		}
+		for _, cat := range withImages {
+			if cat.Slug == "draft" {
+				assertSrcs(t, cat.Images, "/sunset.jpg")
+			}
+		}
+
+		stale := draft
+		draft.Status = models.GalleryPublished
+		if err := store.UpdateCategoryStatus(ctx, &draft); err != nil {
+			t.Fatalf("UpdateCategoryStatus: %v", err)
+		}
+		if draft.Version != stale.Version+1 {
+			t.Errorf("version after status change = %d, want %d", draft.Version, stale.Version+1)
+		}
+		stale.Status = models.GalleryArchived
+		if err := store.UpdateCategoryStatus(ctx, &stale); !errors.Is(err, repository.ErrVersionConflict) {
+			t.Errorf("stale UpdateCategoryStatus error = %v, want ErrVersionConflict", err)
+		}
+		if _, err := store.GetCategoryBySlug(ctx, "draft"); err != nil {
+			t.Errorf("GetCategoryBySlug after publishing: %v", err)
+		}
+		results, err = store.Search(ctx, "sunset", 10)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if len(results.Images) != 1 {
+			t.Errorf("search after publishing found %d images, want 1", len(results.Images))
+		}
+
+		assertTransitions := func(after, until time.Time, want ...string) {
+			t.Helper()
+			ids, err := store.ScheduleTransitions(ctx, after, until)
+			if err != nil {
+				t.Fatalf("ScheduleTransitions: %v", err)
+			}
+			var got []models.GalleryCategory
+			for _, id := range ids {
+				cat, err := store.GetCategoryByID(ctx, id)
+				if err != nil {
+					t.Fatalf("GetCategoryByID(%d): %v", id, err)
+				}
+				got = append(got, *cat)
+			}
+			assertSlugSet(t, got, want...)
+		}
+		assertTransitions(now.Add(-2*time.Hour), now, "expired", "window")
+		assertTransitions(now, now.Add(2*time.Hour), "scheduled", "window")
+	})
+
+	t.Run("AccessModes", func(t *testing.T) {
+		store := newStore(t)
+
+		open := mustCreateCategory(t, store, "open", 1)
+		hidden := mustCreateCategory(t, store, "hidden", 2)
+		locked := mustCreateCategory(t, store, "locked", 3)
+		if open.Access != models.GalleryAccessPublic {
+			t.Errorf("new category has access %q, want public", open.Access)
+		}
+		mustCreateImage(t, store, hidden.ID, "/hidden-sunset.jpg", 1)
+		mustCreateImage(t, store, locked.ID, "/locked-sunset.jpg", 1)
+
+		hidden.Access = models.GalleryAccessUnlisted
+		if err := store.UpdateCategoryAccess(ctx, hidden, ""); err != nil {
+			t.Fatalf("UpdateCategoryAccess(unlisted): %v", err)
+		}
+		stale := *locked
+		locked.Access = models.GalleryAccessPassword
+		if err := store.UpdateCategoryAccess(ctx, locked, "hash-1"); err != nil {
+			t.Fatalf("UpdateCategoryAccess(password): %v", err)
+		}
+		if locked.Version != stale.Version+1 {
+			t.Errorf("version after access change = %d, want %d", locked.Version, stale.Version+1)
+		}
+		stale.Access = models.GalleryAccessPublic
+		if err := store.UpdateCategoryAccess(ctx, &stale, ""); !errors.Is(err, repository.ErrVersionConflict) {
+			t.Errorf("stale UpdateCategoryAccess error = %v, want ErrVersionConflict", err)
+		}
+
+		// Only public access is listed and searched, but every mode is found by slug
+		listed, err := store.GetAllCategories(ctx, repository.CategoryListOptions{})
+		if err != nil {
+			t.Fatalf("GetAllCategories: %v", err)
+		}
+		assertSlugs(t, listed, "open")
+		all, err := store.GetAllCategories(ctx, repository.CategoryListOptions{AllStatuses: true})
+		if err != nil {
+			t.Fatalf("GetAllCategories(AllStatuses): %v", err)
+		}
+		assertSlugs(t, all, "open", "hidden", "locked")
+		for slug, want := range map[string]string{"hidden": models.GalleryAccessUnlisted, "locked": models.GalleryAccessPassword} {
+			cat, err := store.GetCategoryBySlug(ctx, slug)
+			if err != nil {
+				t.Fatalf("GetCategoryBySlug(%s): %v", slug, err)
+			}
+			if cat.Access != want || len(cat.Images) != 1 {
+				t.Errorf("GetCategoryBySlug(%s) = access %q with %d images, want %q with 1", slug, cat.Access, len(cat.Images), want)
+			}
+		}
+		results, err := store.Search(ctx, "sunset", 10)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if len(results.Images) != 0 {
+			t.Errorf("search found images of unlisted galleries: %+v", results.Images)
+		}
+
+		assertHash := func(id int, want string) {
+			t.Helper()
+			hash, err := store.CategoryPasswordHash(ctx, id)
+			if err != nil {
+				t.Fatalf("CategoryPasswordHash(%d): %v", id, err)
+			}
+			if hash != want {
+				t.Errorf("CategoryPasswordHash(%d) = %q, want %q", id, hash, want)
+			}
+		}
+		assertHash(open.ID, "")
+		assertHash(locked.ID, "hash-1")
+
+		// An empty hash keeps the password, leaving password access drops it
+		if err := store.UpdateCategoryAccess(ctx, locked, ""); err != nil {
+			t.Fatalf("UpdateCategoryAccess(keep): %v", err)
+		}
+		assertHash(locked.ID, "hash-1")
+		if err := store.UpdateCategoryAccess(ctx, locked, "hash-2"); err != nil {
+			t.Fatalf("UpdateCategoryAccess(new password): %v", err)
+		}
+		assertHash(locked.ID, "hash-2")
+		locked.Access = models.GalleryAccessUnlisted
+		if err := store.UpdateCategoryAccess(ctx, locked, "ignored"); err != nil {
+			t.Fatalf("UpdateCategoryAccess(unlisted): %v", err)
+		}
+		assertHash(locked.ID, "")
+
+		if err := store.DeleteCategory(ctx, hidden.ID); err != nil {
+			t.Fatalf("DeleteCategory: %v", err)
+		}
+		hidden.Access = models.GalleryAccessPublic
+		if err := store.UpdateCategoryAccess(ctx, hidden, ""); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("UpdateCategoryAccess(trashed) error = %v, want sql.ErrNoRows", err)
+		}
+		if _, err := store.CategoryPasswordHash(ctx, hidden.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("CategoryPasswordHash(trashed) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("VersionedUpdates", func(t *testing.T) {
+		store := newStore(t)
+
+		cat := mustCreateCategory(t, store, "versioned", 1)
+		img := mustCreateImage(t, store, cat.ID, "/v.jpg", 1)
+		if cat.Version != 1 || img.Version != 1 {
+			t.Fatalf("new rows have versions %d and %d, want 1", cat.Version, img.Version)
+		}
+
+		// Two admins read version 1; the first write wins
+		first, second := *cat, *cat
+		first.Title = "First"
+		if err := store.UpdateCategory(ctx, &first); err != nil {
+			t.Fatalf("UpdateCategory: %v", err)
+		}
+		if first.Version != 2 {
+			t.Errorf("version after update = %d, want 2", first.Version)
+		}
+		second.Title = "Second"
+		if err := store.UpdateCategory(ctx, &second); !errors.Is(err, repository.ErrVersionConflict) {
+			t.Errorf("stale UpdateCategory error = %v, want ErrVersionConflict", err)
+		}
+		got, err := store.GetCategoryByID(ctx, cat.ID)
+		if err != nil {
+			t.Fatalf("GetCategoryByID: %v", err)
+		}
+		if got.Title != "First" || got.Version != 2 {
+			t.Errorf("category after conflict = %+v", got)
+		}
+
+		// Version 0 skips the check
+		unchecked := *got
+		unchecked.Version = 0
+		unchecked.Title = "Unchecked"
+		if err := store.UpdateCategory(ctx, &unchecked); err != nil {
+			t.Fatalf("unversioned UpdateCategory: %v", err)
+		}
+		if unchecked.Version != 3 {
+			t.Errorf("version after unversioned update = %d, want 3", unchecked.Version)
+		}
+
+		stale := *img
+		img.Alt = "Fresh"
+		if err := store.UpdateImage(ctx, img); err != nil {
+			t.Fatalf("UpdateImage: %v", err)
+		}
+		if img.Version != 2 || img.UpdatedAt.IsZero() {
+			t.Errorf("image after update = %+v", img)
+		}
+		stale.Alt = "Stale"
+		if err := store.UpdateImage(ctx, &stale); !errors.Is(err, repository.ErrVersionConflict) {
+			t.Errorf("stale UpdateImage error = %v, want ErrVersionConflict", err)
+		}
+		gotImg, err := store.GetImageByID(ctx, img.ID)
+		if err != nil {
+			t.Fatalf("GetImageByID: %v", err)
+		}
+		if gotImg.Alt != "Fresh" || gotImg.Version != 2 {
+			t.Errorf("image after conflict = %+v", gotImg)
+		}
+
+		missing := &models.GalleryImage{ID: img.ID + 1000, Src: "/x.jpg", Version: 1}
+		if err := store.UpdateImage(ctx, missing); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("UpdateImage(missing) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		store := newStore(t)
+
+		stories := &models.GalleryCategory{Slug: "stories", Title: "Wedding Stories", Description: "Bridal portraits by the sea"}
+		studio := &models.GalleryCategory{Slug: "studio", Title: "Studio", Description: "Portraits for wedding guests"}
+		archive := &models.GalleryCategory{Slug: "archive", Title: "Wedding Archive"}
+		cartoon := &models.GalleryCategory{Slug: "cartoon", Title: "Tom & Jerry <b>"}
+		for _, cat := range []*models.GalleryCategory{stories, studio, archive, cartoon} {
+			if err := store.CreateCategory(ctx, cat); err != nil {
+				t.Fatalf("CreateCategory: %v", err)
+			}
+		}
+		bride := &models.GalleryImage{CategoryID: stories.ID, Src: "/bride.jpg", Alt: "Bride at the wedding"}
+		cake := &models.GalleryImage{CategoryID: stories.ID, Src: "/cake.jpg", Alt: "Wedding cake"}
+		lights := &models.GalleryImage{CategoryID: studio.ID, Src: "/lights.jpg", Alt: "Studio lighting"}
+		for _, img := range []*models.GalleryImage{bride, cake, lights} {
+			if err := store.CreateImage(ctx, img); err != nil {
+				t.Fatalf("CreateImage: %v", err)
+			}
+		}
+		if err := store.DeleteCategory(ctx, archive.ID); err != nil {
+			t.Fatalf("DeleteCategory: %v", err)
+		}
+		if err := store.DeleteImage(ctx, cake.ID); err != nil {
+			t.Fatalf("DeleteImage: %v", err)
+		}
+
+		results, err := store.Search(ctx, "wedding", 10)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		// Title matches rank above description matches; trashed rows never match
+		if len(results.Galleries) != 2 || results.Galleries[0].ID != stories.ID || results.Galleries[1].ID != studio.ID {
+			t.Fatalf("galleries = %+v, want stories then studio", results.Galleries)
+		}
+		if g := results.Galleries[0]; g.Slug != "stories" || g.Rank <= results.Galleries[1].Rank ||
+			!strings.Contains(g.Snippet, "<mark>Wedding</mark>") {
+			t.Errorf("best gallery hit = %+v", g)
+		}
+		if len(results.Images) != 1 || results.Images[0].ID != bride.ID || results.Images[0].CategorySlug != "stories" ||
+			!strings.Contains(results.Images[0].Snippet, "<mark>wedding</mark>") {
+			t.Errorf("images = %+v, want the bride", results.Images)
+		}
+
+		results, err = store.Search(ctx, "wedding sea", 10)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if len(results.Galleries) != 1 || results.Galleries[0].ID != stories.ID || len(results.Images) != 0 {
+			t.Errorf("every word must match, got %+v", results)
+		}
+
+		limited, err := store.Search(ctx, "wedding", 1)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if len(limited.Galleries) != 1 || limited.Galleries[0].ID != stories.ID {
+			t.Errorf("limited galleries = %+v", limited.Galleries)
+		}
+
+		escaped, err := store.Search(ctx, "tom", 10)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if len(escaped.Galleries) != 1 {
+			t.Fatalf("galleries = %+v, want the cartoon", escaped.Galleries)
+		}
+		if snippet := escaped.Galleries[0].Snippet; !strings.Contains(snippet, "<mark>Tom</mark>") ||
+			!strings.Contains(snippet, "&amp;") || strings.Contains(snippet, "<b>") {
+			t.Errorf("snippet %q is not escaped and highlighted", snippet)
+		}
+
+		empty, err := store.Search(ctx, "?!", 10)
+		if err != nil {
+			t.Fatalf("Search(punctuation): %v", err)
+		}
+		if len(empty.Galleries) != 0 || len(empty.Images) != 0 {
+			t.Errorf("punctuation matched %+v", empty)
+		}
+	})
+
+	t.Run("TrashAndRestoreCategory", func(t *testing.T) {
+		store := newStore(t)
+
+		cat := mustCreateCategory(t, store, "trashed", 1)
+		early := mustCreateImage(t, store, cat.ID, "/early.jpg", 1)
+		mustCreateImage(t, store, cat.ID, "/kept.jpg", 2)
+
+		if err := store.DeleteImage(ctx, early.ID); err != nil {
+			t.Fatalf("DeleteImage: %v", err)
+		}
+		// Keep the two deletions apart so they get different timestamps
+		time.Sleep(10 * time.Millisecond)
+		if err := store.DeleteCategory(ctx, cat.ID); err != nil {
+			t.Fatalf("DeleteCategory: %v", err)
+		}
+
+		categories, err := store.GetAllCategories(ctx, repository.CategoryListOptions{IncludeImages: true})
+		if err != nil {
+			t.Fatalf("GetAllCategories: %v", err)
+		}
+		if len(categories) != 0 {
+			t.Errorf("trashed category still listed: %+v", categories)
+		}
+		if err := store.CreateImage(ctx, &models.GalleryImage{CategoryID: cat.ID, Src: "/late.jpg"}); !errors.Is(err, repository.ErrCategoryNotFound) {
+			t.Errorf("CreateImage(trashed category) error = %v, want ErrCategoryNotFound", err)
+		}
+
+		trash, err := store.ListTrash(ctx)
+		if err != nil {
+			t.Fatalf("ListTrash: %v", err)
+		}
+		if len(trash.Categories) != 1 || trash.Categories[0].ID != cat.ID || trash.Categories[0].DeletedAt == nil {
+			t.Fatalf("trash categories = %+v, want %d", trash.Categories, cat.ID)
+		}
+		assertSrcs(t, trash.Categories[0].Images, "/kept.jpg")
+		if len(trash.Images) != 0 {
+			t.Errorf("images of a trashed category listed on their own: %+v", trash.Images)
+		}
+
+		if _, err := store.RestoreImage(ctx, early.ID); !errors.Is(err, repository.ErrCategoryNotFound) {
+			t.Errorf("RestoreImage(trashed category) error = %v, want ErrCategoryNotFound", err)
+		}
+
+		restored, err := store.RestoreCategory(ctx, cat.ID)
+		if err != nil {
+			t.Fatalf("RestoreCategory: %v", err)
+		}
+		if restored.Slug != "trashed" || restored.DeletedAt != nil {
+			t.Errorf("RestoreCategory = %+v", restored)
+		}
+		if _, err := store.RestoreCategory(ctx, cat.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("RestoreCategory(live) error = %v, want sql.ErrNoRows", err)
+		}
+
+		got, err := store.GetCategoryBySlug(ctx, "trashed")
+		if err != nil {
+			t.Fatalf("GetCategoryBySlug: %v", err)
+		}
+		// The image trashed on its own stays in the trash
+		assertSrcs(t, got.Images, "/kept.jpg")
+
+		trash, err = store.ListTrash(ctx)
+		if err != nil {
+			t.Fatalf("ListTrash: %v", err)
+		}
+		if len(trash.Categories) != 0 || len(trash.Images) != 1 || trash.Images[0].ID != early.ID {
+			t.Errorf("trash after restore = %+v", trash)
+		}
+
+		img, err := store.RestoreImage(ctx, early.ID)
+		if err != nil {
+			t.Fatalf("RestoreImage: %v", err)
+		}
+		if img.Src != "/early.jpg" || img.CategoryID != cat.ID {
+			t.Errorf("RestoreImage = %+v", img)
+		}
+		images, err := store.GetImagesByCategory(ctx, cat.ID)
+		if err != nil {
+			t.Fatalf("GetImagesByCategory: %v", err)
+		}
+		assertSrcs(t, images, "/early.jpg", "/kept.jpg")
+	})
+
+	t.Run("TrashedRowsAreReadOnly", func(t *testing.T) {
+		store := newStore(t)
+
+		cat := mustCreateCategory(t, store, "frozen", 1)
+		img := mustCreateImage(t, store, cat.ID, "/f.jpg", 1)
+		if err := store.DeleteCategory(ctx, cat.ID); err != nil {
+			t.Fatalf("DeleteCategory: %v", err)
+		}
+
+		cat.Title = "Changed"
+		if err := store.UpdateCategory(ctx, cat); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("UpdateCategory(trashed) error = %v, want sql.ErrNoRows", err)
+		}
+		img.Alt = "Changed"
+		if err := store.UpdateImage(ctx, img); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("UpdateImage(trashed) error = %v, want sql.ErrNoRows", err)
+		}
+		if err := store.CreateCategory(ctx, &models.GalleryCategory{Slug: "frozen", Title: "Again"}); !errors.Is(err, repository.ErrDuplicateSlug) {
+			t.Errorf("reusing a trashed slug error = %v, want ErrDuplicateSlug", err)
+		}
+
+		if _, err := store.RestoreCategory(ctx, cat.ID); err != nil {
+			t.Fatalf("RestoreCategory: %v", err)
+		}
+		got, err := store.GetCategoryBySlug(ctx, "frozen")
+		if err != nil {
+			t.Fatalf("GetCategoryBySlug: %v", err)
+		}
+		if got.Title != "frozen" || len(got.Images) != 1 || got.Images[0].Alt != "/f.jpg" {
+			t.Errorf("trashed rows were modified: %+v", got)
+		}
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		store := newStore(t)
+
+		cat := mustCreateCategory(t, store, "purged", 1)
+		live := mustCreateCategory(t, store, "live", 2)
+		mustCreateImage(t, store, cat.ID, "/1.jpg", 1)
+		mustCreateImage(t, store, cat.ID, "/1.jpg", 2)
+		mustCreateImage(t, store, cat.ID, "/4.jpg", 3) // still used in live
+		single := mustCreateImage(t, store, live.ID, "/2.jpg", 1)
+		old := mustCreateImage(t, store, live.ID, "/3.jpg", 2)
+		mustCreateImage(t, store, live.ID, "/4.jpg", 3)
+
+		if _, err := store.PurgeCategory(ctx, live.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("PurgeCategory(live) error = %v, want sql.ErrNoRows", err)
+		}
+		if _, err := store.PurgeImage(ctx, single.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("PurgeImage(live) error = %v, want sql.ErrNoRows", err)
+		}
+
+		if err := store.DeleteImage(ctx, single.ID); err != nil {
+			t.Fatalf("DeleteImage: %v", err)
+		}
+		unused, err := store.PurgeImage(ctx, single.ID)
+		if err != nil {
+			t.Fatalf("PurgeImage: %v", err)
+		}
+		if !reflect.DeepEqual(unused, []string{"/2.jpg"}) {
+			t.Errorf("PurgeImage unused = %q, want [/2.jpg]", unused)
+		}
+		if _, err := store.RestoreImage(ctx, single.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("RestoreImage(purged) error = %v, want sql.ErrNoRows", err)
+		}
+
+		if err := store.DeleteCategory(ctx, cat.ID); err != nil {
+			t.Fatalf("DeleteCategory: %v", err)
+		}
+		if err := store.DeleteImage(ctx, old.ID); err != nil {
+			t.Fatalf("DeleteImage: %v", err)
+		}
+
+		categories, images, unused, err := store.PurgeTrash(ctx, time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("PurgeTrash(past): %v", err)
+		}
+		if categories != 0 || images != 0 || unused != nil {
+			t.Errorf("PurgeTrash(past) removed %d categories and %d images leaving %q unused, want none", categories, images, unused)
+		}
+
+		categories, images, unused, err = store.PurgeTrash(ctx, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("PurgeTrash: %v", err)
+		}
+		if categories != 1 || images != 4 {
+			t.Errorf("PurgeTrash removed %d categories and %d images, want 1 and 4", categories, images)
+		}
+		// Each once, and not /4.jpg, which a live image still uses
+		if !reflect.DeepEqual(unused, []string{"/1.jpg", "/3.jpg"}) {
+			t.Errorf("PurgeTrash unused = %q, want [/1.jpg /3.jpg]", unused)
+		}
+
+		trash, err := store.ListTrash(ctx)
+		if err != nil {
+			t.Fatalf("ListTrash: %v", err)
+		}
+		if len(trash.Categories) != 0 || len(trash.Images) != 0 {
+			t.Errorf("trash after purge = %+v", trash)
+		}
+		kept, err := store.GetImagesByCategory(ctx, live.ID)
+		if err != nil {
+			t.Fatalf("GetImagesByCategory: %v", err)
+		}
+		assertSrcs(t, kept, "/4.jpg")
+	})
+}
+
+// TestContactStore runs the contact message conformance tests
+func TestContactStore(t *testing.T, newStore func(t *testing.T) repository.ContactStore) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		store := newStore(t)
+
+		msg, err := store.Create(ctx, &models.ContactRequest{
+			Name: "Jane", Email: "jane@example.com", Phone: "5551234567", Subject: "Booking", Message: "Hello there, booking please",
+		})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if msg.ID == 0 || msg.Status != "pending" {
+			t.Fatalf("Create returned %+v", msg)
+		}
+
+		got, err := store.GetByID(ctx, msg.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Email != "jane@example.com" || got.Phone != "5551234567" || got.Subject != "Booking" {
+			t.Errorf("GetByID = %+v", got)
+		}
+
+		if _, err := store.GetByID(ctx, msg.ID+1000); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetByID(missing) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("StatusAndDelete", func(t *testing.T) {
+		store := newStore(t)
+
+		msg, err := store.Create(ctx, &models.ContactRequest{Name: "Sam", Email: "sam@example.com", Subject: "Hello", Message: "Just saying hello"})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		if err := store.MarkAsRead(ctx, msg.ID); err != nil {
+			t.Fatalf("MarkAsRead: %v", err)
+		}
+		got, err := store.GetByID(ctx, msg.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != "read" {
+			t.Errorf("status = %q, want read", got.Status)
+		}
+
+		if err := store.Delete(ctx, msg.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		all, err := store.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		if len(all) != 0 {
+			t.Errorf("GetAll after delete returned %d messages", len(all))
+		}
+	})
+
+	t.Run("NewestFirst", func(t *testing.T) {
+		store := newStore(t)
+
+		for _, subject := range []string{"First", "Second", "Third"} {
+			if _, err := store.Create(ctx, &models.ContactRequest{Name: "A", Email: "a@example.com", Subject: subject, Message: "Message body"}); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+
+		all, err := store.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		if len(all) != 3 {
+			t.Fatalf("GetAll returned %d messages, want 3", len(all))
+		}
+		for i := 1; i < len(all); i++ {
+			if all[i].CreatedAt.After(all[i-1].CreatedAt) {
+				t.Errorf("messages not ordered newest first: %v before %v", all[i-1].CreatedAt, all[i].CreatedAt)
+			}
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		store := newStore(t)
+
+		subject, err := store.Create(ctx, &models.ContactRequest{Name: "Asha", Email: "asha@example.com", Subject: "Pricing for a shoot", Message: "What are your rates for next month?"})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		body, err := store.Create(ctx, &models.ContactRequest{Name: "Ravi", Email: "ravi@example.com", Subject: "Hello", Message: "<script>alert(1)</script> could you share pricing details?"})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if _, err := store.Create(ctx, &models.ContactRequest{Name: "Mira", Email: "mira@example.com", Subject: "Thanks", Message: "Loved the photos you took"}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		hits, err := store.Search(ctx, "pricing", 10)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if len(hits) != 2 || hits[0].ID != subject.ID || hits[1].ID != body.ID {
+			t.Fatalf("hits = %+v, want the subject match then the message match", hits)
+		}
+		if hits[0].Email != "asha@example.com" || hits[0].Rank <= hits[1].Rank || !strings.Contains(hits[0].Snippet, "<mark>Pricing</mark>") {
+			t.Errorf("best hit = %+v", hits[0])
+		}
+		if snippet := hits[1].Snippet; strings.Contains(snippet, "<script") || !strings.Contains(snippet, "<mark>pricing</mark>") {
+			t.Errorf("snippet %q is not escaped and highlighted", snippet)
+		}
+
+		limited, err := store.Search(ctx, "pricing", 1)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if len(limited) != 1 || limited[0].ID != subject.ID {
+			t.Errorf("limited hits = %+v", limited)
+		}
+
+		none, err := store.Search(ctx, "invoice", 10)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if len(none) != 0 {
+			t.Errorf("unrelated query matched %+v", none)
+		}
+	})
+}
+
+// TestUserStore runs the admin user conformance tests
+func TestUserStore(t *testing.T, newStore func(t *testing.T) repository.UserStore) {
+	ctx := context.Background()
+
+	t.Run("CreateAndLookup", func(t *testing.T) {
+		store := newStore(t)
+
+		user := &models.User{Email: "admin@example.com", PasswordHash: "hash", Name: "Admin"}
+		if err := store.Create(ctx, user); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if user.ID == 0 || user.Username != "Admin" {
+			t.Fatalf("Create returned %+v", user)
+		}
+
+		byEmail, err := store.GetByEmail(ctx, "admin@example.com")
+		if err != nil {
+			t.Fatalf("GetByEmail: %v", err)
+		}
+		if byEmail.ID != user.ID || byEmail.PasswordHash != "hash" || byEmail.Name != "Admin" {
+			t.Errorf("GetByEmail = %+v", byEmail)
+		}
+
+		byID, err := store.GetByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if byID.Email != "admin@example.com" {
+			t.Errorf("GetByID = %+v", byID)
+		}
+
+		if _, err := store.GetByEmail(ctx, "nobody@example.com"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetByEmail(missing) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("EmailIsUnique", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.Create(ctx, &models.User{Email: "dup@example.com", PasswordHash: "x", Username: "one"}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		err := store.Create(ctx, &models.User{Email: "dup@example.com", PasswordHash: "x", Username: "two"})
+		if !errors.Is(err, repository.ErrDuplicateEmail) {
+			t.Errorf("duplicate email error = %v, want ErrDuplicateEmail", err)
+		}
+
+		exists, err := store.EmailExists(ctx, "dup@example.com")
+		if err != nil || !exists {
+			t.Errorf("EmailExists = %v, %v, want true", exists, err)
+		}
+		exists, err = store.EmailExists(ctx, "other@example.com")
+		if err != nil || exists {
+			t.Errorf("EmailExists(other) = %v, %v, want false", exists, err)
+		}
+	})
+
+	t.Run("UpdateLastLogin", func(t *testing.T) {
+		store := newStore(t)
+
+		user := &models.User{Email: "login@example.com", PasswordHash: "x", Username: "login"}
+		if err := store.Create(ctx, user); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := store.UpdateLastLogin(ctx, user.ID); err != nil {
+			t.Fatalf("UpdateLastLogin: %v", err)
+		}
+
+		got, err := store.GetByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.LastLogin == nil {
+			t.Error("LastLogin not set")
+		}
+	})
+}
+
+// TestPortfolioSectionStore runs the portfolio section conformance tests.
+// newStore must return a store containing exactly the given sections.
+func TestPortfolioSectionStore(t *testing.T, newStore func(t *testing.T, sections []models.PortfolioSection) repository.PortfolioSectionStore) {
+	ctx := context.Background()
+
+	t.Run("OrderedByDisplayOrder", func(t *testing.T) {
+		store := newStore(t, []models.PortfolioSection{
+			{Name: "Services", Slug: "services", DisplayOrder: 3},
+			{Name: "Hero", Slug: "hero", DisplayOrder: 1},
+			{Name: "About", Slug: "about", DisplayOrder: 2},
+		})
+
+		sections, err := store.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		if len(sections) != 3 {
+			t.Fatalf("GetAll returned %d sections, want 3", len(sections))
+		}
+		for i, want := range []string{"hero", "about", "services"} {
+			if sections[i].Slug != want {
+				t.Errorf("sections[%d] = %s, want %s", i, sections[i].Slug, want)
+			}
+		}
+	})
+
+	t.Run("UpdateImageTouchesUpdatedAt", func(t *testing.T) {
+		store := newStore(t, []models.PortfolioSection{{Name: "Hero", Slug: "hero", DisplayOrder: 1}})
+
+		before, err := store.GetAll(ctx)
+		if err != nil || len(before) != 1 {
+			t.Fatalf("GetAll = %v, %v", before, err)
+		}
+
+		if err := store.UpdateImage(ctx, before[0].ID, "id", "https://example.com/x.jpg"); err != nil {
+			t.Fatalf("UpdateImage: %v", err)
+		}
+
+		after, err := store.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		if after[0].UpdatedAt.Before(before[0].UpdatedAt) {
+			t.Errorf("updated_at went backwards: %v -> %v", before[0].UpdatedAt, after[0].UpdatedAt)
+		}
+	})
+}
+
+// TestAuditStore runs the audit log conformance tests
+func TestAuditStore(t *testing.T, newStore func(t *testing.T) repository.AuditStore) {
+	ctx := context.Background()
+
+	t.Run("RecordAndList", func(t *testing.T) {
+		store := newStore(t)
+
+		actor := 7
+		entry := &models.AuditEntry{
+			ActorID:    &actor,
+			ActorEmail: "admin@example.com",
+			Action:     "gallery.update",
+			EntityType: "gallery",
+			EntityID:   "3",
+			Changes:    json.RawMessage(`{"title":{"before":"Old","after":"New"}}`),
+			IP:         "203.0.113.9",
+			UserAgent:  "curl/8.0",
+		}
+		if err := store.Record(ctx, entry); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+		if entry.ID == 0 || entry.CreatedAt.IsZero() {
+			t.Fatalf("Record did not populate ID and CreatedAt: %+v", entry)
+		}
+
+		entries, total, err := store.List(ctx, repository.AuditFilter{})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if total != 1 || len(entries) != 1 {
+			t.Fatalf("List returned %d of %d entries, want 1", len(entries), total)
+		}
+
+		got := entries[0]
+		if got.ID != entry.ID || got.ActorID == nil || *got.ActorID != 7 || got.ActorEmail != "admin@example.com" ||
+			got.Action != "gallery.update" || got.EntityType != "gallery" || got.EntityID != "3" ||
+			got.IP != "203.0.113.9" || got.UserAgent != "curl/8.0" {
+			t.Errorf("List = %+v, want %+v", got, entry)
+		}
+
+		var changes map[string]map[string]string
+		if err := json.Unmarshal(got.Changes, &changes); err != nil {
+			t.Fatalf("decode changes %s: %v", got.Changes, err)
+		}
+		if changes["title"]["before"] != "Old" || changes["title"]["after"] != "New" {
+			t.Errorf("changes = %s", got.Changes)
+		}
+	})
+
+	t.Run("FilterAndPaginate", func(t *testing.T) {
+		store := newStore(t)
+
+		one, two := 1, 2
+		for _, e := range []models.AuditEntry{
+			{ActorID: &one, Action: "gallery.create", EntityType: "gallery", EntityID: "1"},
+			{ActorID: &two, Action: "image.create", EntityType: "image", EntityID: "1"},
+			{ActorID: &one, Action: "gallery.update", EntityType: "gallery", EntityID: "1"},
+			{ActorID: &one, Action: "gallery.update", EntityType: "gallery", EntityID: "2"},
+			{Action: "contact.mark_read", EntityType: "contact", EntityID: "9"},
+		} {
+			if err := store.Record(ctx, &e); err != nil {
+				t.Fatalf("Record: %v", err)
+			}
+		}
+
+		cases := []struct {
+			name   string
+			filter repository.AuditFilter
+			want   []string // actions, newest first
+			total  int
+		}{
+			{"all", repository.AuditFilter{}, []string{"contact.mark_read", "gallery.update", "gallery.update", "image.create", "gallery.create"}, 5},
+			{"actor", repository.AuditFilter{ActorID: 1}, []string{"gallery.update", "gallery.update", "gallery.create"}, 3},
+			{"action", repository.AuditFilter{Action: "gallery.update"}, []string{"gallery.update", "gallery.update"}, 2},
+			{"entity", repository.AuditFilter{EntityType: "gallery", EntityID: "1"}, []string{"gallery.update", "gallery.create"}, 2},
+			{"page", repository.AuditFilter{Limit: 2, Offset: 2}, []string{"gallery.update", "image.create"}, 5},
+			{"past end", repository.AuditFilter{Limit: 2, Offset: 10}, nil, 5},
+			{"until", repository.AuditFilter{Until: time.Now().Add(-time.Hour)}, nil, 0},
+			{"since", repository.AuditFilter{Since: time.Now().Add(-time.Hour), Limit: 1}, []string{"contact.mark_read"}, 5},
+		}
+		for _, tc := range cases {
+			entries, total, err := store.List(ctx, tc.filter)
+			if err != nil {
+				t.Fatalf("%s: List: %v", tc.name, err)
+			}
+			if total != tc.total {
+				t.Errorf("%s: total = %d, want %d", tc.name, total, tc.total)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Action)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("%s: actions = %v, want %v", tc.name, got, tc.want)
+			}
+		}
+	})
+}
+
+// TestTagStore runs the tag conformance tests. newStore must return an empty
+// store, as both its gallery and its tag side.
+func TestTagStore(t *testing.T, newStore func(t *testing.T) (repository.GalleryStore, repository.TagStore)) {
+	ctx := context.Background()
+
+	t.Run("CRUD", func(t *testing.T) {
+		_, tags := newStore(t)
+
+		red := mustCreateTag(t, tags, "Red", "red")
+		bridal := mustCreateTag(t, tags, "bridal", "bridal")
+		if red.ID == 0 || red.CreatedAt.IsZero() || red.UpdatedAt.IsZero() {
+			t.Fatalf("CreateTag did not populate ID and timestamps: %+v", red)
+		}
+		if err := tags.CreateTag(ctx, &models.Tag{Name: "Bridal", Slug: "bridal"}); !errors.Is(err, repository.ErrDuplicateSlug) {
+			t.Errorf("CreateTag(duplicate) error = %v, want ErrDuplicateSlug", err)
+		}
+
+		got, err := tags.GetTag(ctx, red.ID)
+		if err != nil {
+			t.Fatalf("GetTag: %v", err)
+		}
+		if got.Name != "Red" || got.Slug != "red" {
+			t.Errorf("GetTag = %+v", got)
+		}
+
+		red.Name, red.Slug = "Red saree", "red-saree"
+		if err := tags.UpdateTag(ctx, red); err != nil {
+			t.Fatalf("UpdateTag: %v", err)
+		}
+		if err := tags.UpdateTag(ctx, &models.Tag{ID: red.ID, Name: "Bridal", Slug: "bridal"}); !errors.Is(err, repository.ErrDuplicateSlug) {
+			t.Errorf("UpdateTag(taken slug) error = %v, want ErrDuplicateSlug", err)
+		}
+		if err := tags.UpdateTag(ctx, &models.Tag{ID: red.ID + 100, Name: "X", Slug: "x"}); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("UpdateTag(missing) error = %v, want sql.ErrNoRows", err)
+		}
+
+		list, err := tags.ListTags(ctx)
+		if err != nil {
+			t.Fatalf("ListTags: %v", err)
+		}
+		assertTagUsage(t, list, "bridal:0:0", "red-saree:0:0")
+
+		if err := tags.DeleteTag(ctx, bridal.ID); err != nil {
+			t.Fatalf("DeleteTag: %v", err)
+		}
+		if err := tags.DeleteTag(ctx, bridal.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("DeleteTag(deleted) error = %v, want sql.ErrNoRows", err)
+		}
+		if _, err := tags.GetTag(ctx, bridal.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetTag(deleted) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("AssignAndFilter", func(t *testing.T) {
+		galleries, tags := newStore(t)
+
+		pub := mustCreateCategory(t, galleries, "pub", 1)
+		draft := &models.GalleryCategory{Slug: "draft", Title: "Draft", Status: models.GalleryDraft}
+		if err := galleries.CreateCategory(ctx, draft); err != nil {
+			t.Fatalf("CreateCategory(draft): %v", err)
+		}
+		mustCreateTag(t, tags, "Bridal", "bridal")
+		mustCreateTag(t, tags, "Red", "red")
+		mustCreateTag(t, tags, "Editorial", "editorial")
+
+		a := mustCreateImage(t, galleries, pub.ID, "/a.jpg", 1)
+		b := mustCreateImage(t, galleries, pub.ID, "/b.jpg", 2)
+		c := mustCreateImage(t, galleries, pub.ID, "/c.jpg", 3)
+		d := mustCreateImage(t, galleries, draft.ID, "/d.jpg", 1)
+		for img, slugs := range map[int][]string{
+			a.ID: {"red", "bridal", "red"},
+			b.ID: {"bridal"},
+			c.ID: {"editorial"},
+			d.ID: {"bridal", "red"},
+		} {
+			if err := tags.SetImageTags(ctx, img, slugs); err != nil {
+				t.Fatalf("SetImageTags(%d): %v", img, err)
+			}
+		}
+		for cat, slugs := range map[int][]string{pub.ID: {"bridal"}, draft.ID: {"bridal", "editorial"}} {
+			if err := tags.SetCategoryTags(ctx, cat, slugs); err != nil {
+				t.Fatalf("SetCategoryTags(%d): %v", cat, err)
+			}
+		}
+
+		if err := tags.SetImageTags(ctx, a.ID, []string{"bridal", "missing"}); !errors.Is(err, repository.ErrTagNotFound) {
+			t.Errorf("SetImageTags(unknown tag) error = %v, want ErrTagNotFound", err)
+		}
+		if err := tags.SetImageTags(ctx, d.ID+100, []string{"bridal"}); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("SetImageTags(missing image) error = %v, want sql.ErrNoRows", err)
+		}
+		if err := tags.SetCategoryTags(ctx, draft.ID+100, nil); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("SetCategoryTags(missing gallery) error = %v, want sql.ErrNoRows", err)
+		}
+
+		aTags, err := tags.ImageTags(ctx, a.ID)
+		if err != nil {
+			t.Fatalf("ImageTags: %v", err)
+		}
+		assertTagSlugs(t, aTags, "bridal", "red")
+		pubTags, err := tags.CategoryTags(ctx, pub.ID)
+		if err != nil {
+			t.Fatalf("CategoryTags: %v", err)
+		}
+		assertTagSlugs(t, pubTags, "bridal")
+
+		cases := []struct {
+			name   string
+			query  repository.TagQuery
+			want   []string // srcs, newest first
+			total  int
+			facets []string // slug:count, most used first
+		}{
+			{"everything", repository.TagQuery{}, []string{"/c.jpg", "/b.jpg", "/a.jpg"}, 3, []string{"bridal:2", "editorial:1", "red:1"}},
+			{"all", repository.TagQuery{Slugs: []string{"bridal", "red"}, MatchAll: true}, []string{"/a.jpg"}, 1, []string{"bridal:1", "red:1"}},
+			{"any", repository.TagQuery{Slugs: []string{"red", "editorial"}}, []string{"/c.jpg", "/a.jpg"}, 2, []string{"bridal:1", "editorial:1", "red:1"}},
+			{"unknown any", repository.TagQuery{Slugs: []string{"bridal", "missing"}}, []string{"/b.jpg", "/a.jpg"}, 2, []string{"bridal:2", "red:1"}},
+			{"unknown all", repository.TagQuery{Slugs: []string{"bridal", "missing"}, MatchAll: true}, nil, 0, nil},
+			{"page", repository.TagQuery{Slugs: []string{"bridal"}, Limit: 1, Offset: 1}, []string{"/a.jpg"}, 2, []string{"bridal:2", "red:1"}},
+		}
+		for _, tc := range cases {
+			results, err := tags.ImagesByTags(ctx, tc.query)
+			if err != nil {
+				t.Fatalf("%s: ImagesByTags: %v", tc.name, err)
+			}
+			if results.Total != tc.total {
+				t.Errorf("%s: total = %d, want %d", tc.name, results.Total, tc.total)
+			}
+			var srcs, facets []string
+			for _, img := range results.Images {
+				srcs = append(srcs, img.Src)
+				if img.CategorySlug != "pub" {
+					t.Errorf("%s: %s has category slug %q", tc.name, img.Src, img.CategorySlug)
+				}
+			}
+			for _, f := range results.Facets {
+				facets = append(facets, f.Slug+":"+strconv.Itoa(f.Count))
+			}
+			if strings.Join(srcs, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("%s: images = %v, want %v", tc.name, srcs, tc.want)
+			}
+			if strings.Join(facets, ",") != strings.Join(tc.facets, ",") {
+				t.Errorf("%s: facets = %v, want %v", tc.name, facets, tc.facets)
+			}
+		}
+
+		results, err := tags.ImagesByTags(ctx, repository.TagQuery{Slugs: []string{"red"}})
+		if err != nil {
+			t.Fatalf("ImagesByTags: %v", err)
+		}
+		if len(results.Images) != 1 {
+			t.Fatalf("ImagesByTags(red) = %+v", results.Images)
+		}
+		assertTagSlugs(t, results.Images[0].Tags, "bridal", "red")
+
+		found, err := tags.CategoriesByTags(ctx, repository.TagQuery{Slugs: []string{"bridal"}})
+		if err != nil {
+			t.Fatalf("CategoriesByTags: %v", err)
+		}
+		if len(found) != 1 || found[0].Slug != "pub" {
+			t.Fatalf("CategoriesByTags(bridal) = %+v, want only pub", found)
+		}
+		assertTagSlugs(t, found[0].Tags, "bridal")
+		if found, _ := tags.CategoriesByTags(ctx, repository.TagQuery{Slugs: []string{"editorial"}}); len(found) != 0 {
+			t.Errorf("CategoriesByTags(editorial) = %+v, want none public", found)
+		}
+
+		public, err := tags.PublicTags(ctx)
+		if err != nil {
+			t.Fatalf("PublicTags: %v", err)
+		}
+		assertTagUsage(t, public, "bridal:2:1", "editorial:1:0", "red:1:0")
+		all, err := tags.ListTags(ctx)
+		if err != nil {
+			t.Fatalf("ListTags: %v", err)
+		}
+		assertTagUsage(t, all, "bridal:3:2", "editorial:1:1", "red:2:0")
+
+		// Trashed images keep their tags but stop counting
+		if err := galleries.DeleteImage(ctx, b.ID); err != nil {
+			t.Fatalf("DeleteImage: %v", err)
+		}
+		if _, err := tags.ImageTags(ctx, b.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("ImageTags(trashed) error = %v, want sql.ErrNoRows", err)
+		}
+		public, err = tags.PublicTags(ctx)
+		if err != nil {
+			t.Fatalf("PublicTags: %v", err)
+		}
+		assertTagUsage(t, public, "bridal:1:1", "editorial:1:0", "red:1:0")
+		if _, err := galleries.RestoreImage(ctx, b.ID); err != nil {
+			t.Fatalf("RestoreImage: %v", err)
+		}
+		bTags, err := tags.ImageTags(ctx, b.ID)
+		if err != nil {
+			t.Fatalf("ImageTags(restored): %v", err)
+		}
+		assertTagSlugs(t, bTags, "bridal")
+
+		if err := tags.SetImageTags(ctx, a.ID, nil); err != nil {
+			t.Fatalf("SetImageTags(none): %v", err)
+		}
+		aTags, err = tags.ImageTags(ctx, a.ID)
+		if err != nil {
+			t.Fatalf("ImageTags: %v", err)
+		}
+		assertTagSlugs(t, aTags)
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		galleries, tags := newStore(t)
+
+		cat := mustCreateCategory(t, galleries, "pub", 1)
+		bridal := mustCreateTag(t, tags, "Bridal", "bridal")
+		bride := mustCreateTag(t, tags, "Bride", "bride")
+		wedding := mustCreateTag(t, tags, "Wedding", "wedding")
+		a := mustCreateImage(t, galleries, cat.ID, "/a.jpg", 1)
+		b := mustCreateImage(t, galleries, cat.ID, "/b.jpg", 2)
+		if err := tags.SetImageTags(ctx, a.ID, []string{"bridal", "bride"}); err != nil {
+			t.Fatalf("SetImageTags: %v", err)
+		}
+		if err := tags.SetImageTags(ctx, b.ID, []string{"wedding"}); err != nil {
+			t.Fatalf("SetImageTags: %v", err)
+		}
+		if err := tags.SetCategoryTags(ctx, cat.ID, []string{"bride"}); err != nil {
+			t.Fatalf("SetCategoryTags: %v", err)
+		}
+
+		if err := tags.MergeTags(ctx, []int{bride.ID, wedding.ID + 100}, bridal.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("MergeTags(missing source) error = %v, want sql.ErrNoRows", err)
+		}
+		if err := tags.MergeTags(ctx, []int{bride.ID, wedding.ID, bridal.ID}, bridal.ID); err != nil {
+			t.Fatalf("MergeTags: %v", err)
+		}
+
+		list, err := tags.ListTags(ctx)
+		if err != nil {
+			t.Fatalf("ListTags: %v", err)
+		}
+		assertTagUsage(t, list, "bridal:2:1")
+		for _, img := range []*models.GalleryImage{a, b} {
+			imgTags, err := tags.ImageTags(ctx, img.ID)
+			if err != nil {
+				t.Fatalf("ImageTags: %v", err)
+			}
+			assertTagSlugs(t, imgTags, "bridal")
+		}
+	})
+
+	t.Run("CopiesKeepTags", func(t *testing.T) {
+		galleries, tags := newStore(t)
+
+		from := mustCreateCategory(t, galleries, "from", 1)
+		to := mustCreateCategory(t, galleries, "to", 2)
+		mustCreateTag(t, tags, "Bridal", "bridal")
+		img := mustCreateImage(t, galleries, from.ID, "/a.jpg", 1)
+		if err := tags.SetImageTags(ctx, img.ID, []string{"bridal"}); err != nil {
+			t.Fatalf("SetImageTags: %v", err)
+		}
+
+		copies, err := galleries.CopyImages(ctx, []int{img.ID}, to.ID, 0)
+		if err != nil {
+			t.Fatalf("CopyImages: %v", err)
+		}
+		copyTags, err := tags.ImageTags(ctx, copies[0].ID)
+		if err != nil {
+			t.Fatalf("ImageTags(copy): %v", err)
+		}
+		assertTagSlugs(t, copyTags, "bridal")
+	})
+}
+
+// TestPreviewStore runs the preview link conformance tests.
+// newStore must return an empty store in which galleries 1 and 2 exist.
+func TestPreviewStore(t *testing.T, newStore func(t *testing.T) repository.PreviewStore) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		store := newStore(t)
+
+		admin := 3
+		link := &models.PreviewLink{CategoryID: 1, Note: "for the couple", CreatedBy: &admin, ExpiresAt: now.Add(48 * time.Hour)}
+		if err := store.Create(ctx, link); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if link.ID == 0 || link.CreatedAt.IsZero() {
+			t.Fatalf("Create did not populate ID and CreatedAt: %+v", link)
+		}
+
+		got, err := store.GetByID(ctx, link.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.CategoryID != 1 || got.Note != "for the couple" || got.CreatedBy == nil || *got.CreatedBy != 3 ||
+			!got.ExpiresAt.Equal(link.ExpiresAt) || got.RevokedAt != nil || !got.IsActive(now) {
+			t.Errorf("GetByID = %+v, want %+v", got, link)
+		}
+
+		if _, err := store.GetByID(ctx, link.ID+100); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetByID(missing) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("ListActive", func(t *testing.T) {
+		store := newStore(t)
+
+		create := func(categoryID int, expires time.Time) *models.PreviewLink {
+			t.Helper()
+			link := &models.PreviewLink{CategoryID: categoryID, ExpiresAt: expires}
+			if err := store.Create(ctx, link); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			return link
+		}
+		first := create(1, now.Add(time.Hour))
+		create(1, now.Add(-time.Hour)) // already expired
+		revoked := create(2, now.Add(time.Hour))
+		last := create(2, now.Add(3*time.Hour))
+		if err := store.Revoke(ctx, revoked.ID); err != nil {
+			t.Fatalf("Revoke: %v", err)
+		}
+
+		links, err := store.ListActive(ctx, now)
+		if err != nil {
+			t.Fatalf("ListActive: %v", err)
+		}
+		assertLinkIDs(t, links, last.ID, first.ID)
+
+		links, err = store.ListActive(ctx, now.Add(2*time.Hour))
+		if err != nil {
+			t.Fatalf("ListActive(later): %v", err)
+		}
+		assertLinkIDs(t, links, last.ID)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		store := newStore(t)
+
+		link := &models.PreviewLink{CategoryID: 1, ExpiresAt: now.Add(time.Hour)}
+		if err := store.Create(ctx, link); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := store.Revoke(ctx, link.ID); err != nil {
+			t.Fatalf("Revoke: %v", err)
+		}
+
+		got, err := store.GetByID(ctx, link.ID)
+		if err != nil {
+			t.Fatalf("GetByID(revoked): %v", err)
+		}
+		if got.RevokedAt == nil || got.IsActive(now) {
+			t.Errorf("revoked link = %+v, want RevokedAt set", got)
+		}
+
+		if err := store.Revoke(ctx, link.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Revoke(revoked) error = %v, want sql.ErrNoRows", err)
+		}
+		if err := store.Revoke(ctx, link.ID+100); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Revoke(missing) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+}
+
+// TestAccessLogStore checks an AccessLogStore; galleries 1 and 2 must exist
+func TestAccessLogStore(t *testing.T, newStore func(t *testing.T) repository.AccessLogStore) {
+	ctx := context.Background()
+
+	t.Run("RecordAndList", func(t *testing.T) {
+		store := newStore(t)
+
+		record := func(categoryID int, event string) *models.GalleryAccessEntry {
+			t.Helper()
+			entry := &models.GalleryAccessEntry{CategoryID: categoryID, Event: event, IP: "203.0.113.7", UserAgent: "test"}
+			if err := store.Record(ctx, entry); err != nil {
+				t.Fatalf("Record: %v", err)
+			}
+			if entry.ID == 0 || entry.CreatedAt.IsZero() {
+				t.Fatalf("Record did not populate ID and CreatedAt: %+v", entry)
+			}
+			return entry
+		}
+		denied := record(1, models.AccessDenied)
+		unlocked := record(1, models.AccessUnlocked)
+		record(2, models.AccessViewed)
+		viewed := record(1, models.AccessViewed)
+
+		assertEntries := func(filter repository.AccessLogFilter, wantTotal int, want ...int) {
+			t.Helper()
+			entries, total, err := store.List(ctx, filter)
+			if err != nil {
+				t.Fatalf("List(%+v): %v", filter, err)
+			}
+			if total != wantTotal {
+				t.Errorf("List(%+v) total = %d, want %d", filter, total, wantTotal)
+			}
+			var got []int
+			for _, entry := range entries {
+				got = append(got, entry.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("List(%+v) = %v, want %v", filter, got, want)
+			}
+		}
+		assertEntries(repository.AccessLogFilter{CategoryID: 1}, 3, viewed.ID, unlocked.ID, denied.ID)
+		assertEntries(repository.AccessLogFilter{CategoryID: 1, Limit: 2, Offset: 1}, 3, unlocked.ID, denied.ID)
+		assertEntries(repository.AccessLogFilter{CategoryID: 1, Event: models.AccessDenied}, 1, denied.ID)
+		assertEntries(repository.AccessLogFilter{CategoryID: 3}, 0)
+
+		entries, _, err := store.List(ctx, repository.AccessLogFilter{CategoryID: 1, Limit: 1})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if got := entries[0]; got.Event != models.AccessViewed || got.IP != "203.0.113.7" || got.UserAgent != "test" {
+			t.Errorf("List()[0] = %+v, want %+v", got, viewed)
+		}
+	})
+}
+
+// TestProofingStore runs the proofing conformance tests.
+// newStore must return an empty store for every call; both values must share data.
+func TestProofingStore(t *testing.T, newStore func(t *testing.T) (repository.GalleryStore, repository.ProofingStore)) {
+	ctx := context.Background()
+
+	t.Run("Settings", func(t *testing.T) {
+		galleries, store := newStore(t)
+		cat := mustCreateCategory(t, galleries, "proofs", 1)
+
+		if _, err := store.ProofingSettings(ctx, cat.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("ProofingSettings(off) error = %v, want sql.ErrNoRows", err)
+		}
+
+		settings := &models.ProofingSettings{CategoryID: cat.ID, MaxPicks: 2}
+		if err := store.EnableProofing(ctx, settings); err != nil {
+			t.Fatalf("EnableProofing: %v", err)
+		}
+		if settings.CreatedAt.IsZero() || settings.UpdatedAt.IsZero() {
+			t.Fatalf("EnableProofing did not populate timestamps: %+v", settings)
+		}
+		created := settings.CreatedAt
+
+		if err := store.EnableProofing(ctx, &models.ProofingSettings{CategoryID: cat.ID, MaxPicks: 5}); err != nil {
+			t.Fatalf("EnableProofing(again): %v", err)
+		}
+		got, err := store.ProofingSettings(ctx, cat.ID)
+		if err != nil {
+			t.Fatalf("ProofingSettings: %v", err)
+		}
+		if got.MaxPicks != 5 || !got.CreatedAt.Equal(created) {
+			t.Errorf("ProofingSettings = %+v, want max_picks 5 created at %v", got, created)
+		}
+
+		if err := store.EnableProofing(ctx, &models.ProofingSettings{CategoryID: cat.ID + 100}); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("EnableProofing(missing) error = %v, want sql.ErrNoRows", err)
+		}
+		trashed := mustCreateCategory(t, galleries, "trashed", 2)
+		if err := galleries.DeleteCategory(ctx, trashed.ID); err != nil {
+			t.Fatalf("DeleteCategory: %v", err)
+		}
+		if err := store.EnableProofing(ctx, &models.ProofingSettings{CategoryID: trashed.ID}); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("EnableProofing(trashed) error = %v, want sql.ErrNoRows", err)
+		}
+
+		if err := store.DisableProofing(ctx, cat.ID); err != nil {
+			t.Fatalf("DisableProofing: %v", err)
+		}
+		if err := store.DisableProofing(ctx, cat.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("DisableProofing(again) error = %v, want sql.ErrNoRows", err)
+		}
+		if _, err := store.ProofingSettings(ctx, cat.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("ProofingSettings(disabled) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("Clients", func(t *testing.T) {
+		galleries, store := newStore(t)
+		cat := mustCreateCategory(t, galleries, "proofs", 1)
+		other := mustCreateCategory(t, galleries, "other", 2)
+
+		asha := mustCreateProofingClient(t, store, cat.ID, "Asha")
+		mustCreateProofingClient(t, store, other.ID, "Other")
+		ravi := mustCreateProofingClient(t, store, cat.ID, "Ravi")
+		if asha.CreatedAt.IsZero() || asha.SubmittedAt != nil || asha.RevokedAt != nil {
+			t.Errorf("CreateProofingClient = %+v", asha)
+		}
+		if err := store.CreateProofingClient(ctx, &models.ProofingClient{CategoryID: cat.ID + 100, Name: "Nobody"}); !errors.Is(err, repository.ErrCategoryNotFound) {
+			t.Errorf("CreateProofingClient(missing gallery) error = %v, want ErrCategoryNotFound", err)
+		}
+
+		clients, err := store.ListProofingClients(ctx, cat.ID)
+		if err != nil {
+			t.Fatalf("ListProofingClients: %v", err)
+		}
+		if len(clients) != 2 || clients[0].ID != asha.ID || clients[1].ID != ravi.ID {
+			t.Errorf("ListProofingClients = %+v, want Asha then Ravi", clients)
+		}
+
+		got, err := store.GetProofingClient(ctx, asha.ID)
+		if err != nil {
+			t.Fatalf("GetProofingClient: %v", err)
+		}
+		if got.Name != "Asha" || got.Email != "asha@example.com" || got.CategoryID != cat.ID {
+			t.Errorf("GetProofingClient = %+v", got)
+		}
+		if _, err := store.GetProofingClient(ctx, ravi.ID+100); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetProofingClient(missing) error = %v, want sql.ErrNoRows", err)
+		}
+
+		if err := store.RevokeProofingClient(ctx, asha.ID); err != nil {
+			t.Fatalf("RevokeProofingClient: %v", err)
+		}
+		if err := store.RevokeProofingClient(ctx, asha.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("RevokeProofingClient(again) error = %v, want sql.ErrNoRows", err)
+		}
+		if got, err := store.GetProofingClient(ctx, asha.ID); err != nil || got.RevokedAt == nil {
+			t.Errorf("GetProofingClient(revoked) = %+v, %v, want revoked_at set", got, err)
+		}
+	})
+
+	t.Run("Marks", func(t *testing.T) {
+		galleries, store := newStore(t)
+		cat := mustCreateCategory(t, galleries, "proofs", 1)
+		other := mustCreateCategory(t, galleries, "other", 2)
+		one := mustCreateImage(t, galleries, cat.ID, "/uploads/one.jpg", 1)
+		two := mustCreateImage(t, galleries, cat.ID, "/uploads/two.jpg", 2)
+		three := mustCreateImage(t, galleries, cat.ID, "/uploads/three.jpg", 3)
+		elsewhere := mustCreateImage(t, galleries, other.ID, "/uploads/elsewhere.jpg", 1)
+		if err := store.EnableProofing(ctx, &models.ProofingSettings{CategoryID: cat.ID, MaxPicks: 2}); err != nil {
+			t.Fatalf("EnableProofing: %v", err)
+		}
+		client := mustCreateProofingClient(t, store, cat.ID, "Asha")
+
+		mark := func(imageID int, favorite bool, comment string) error {
+			t.Helper()
+			return store.SetProofingMark(ctx, &models.ProofingMark{ClientID: client.ID, ImageID: imageID, Favorite: favorite, Comment: comment})
+		}
+
+		first := &models.ProofingMark{ClientID: client.ID, ImageID: two.ID, Favorite: true}
+		if err := store.SetProofingMark(ctx, first); err != nil {
+			t.Fatalf("SetProofingMark: %v", err)
+		}
+		if first.Src != two.Src || first.UpdatedAt.IsZero() {
+			t.Errorf("SetProofingMark did not populate Src and UpdatedAt: %+v", first)
+		}
+		if err := mark(three.ID, false, "warmer please"); err != nil {
+			t.Fatalf("SetProofingMark(comment): %v", err)
+		}
+		if err := mark(one.ID, true, "cover"); err != nil {
+			t.Fatalf("SetProofingMark(second favorite): %v", err)
+		}
+		if err := mark(three.ID, true, "warmer please"); !errors.Is(err, repository.ErrPickLimit) {
+			t.Errorf("SetProofingMark(third favorite) error = %v, want ErrPickLimit", err)
+		}
+		// Changing the comment on a favorite does not count as another pick
+		if err := mark(one.ID, true, "cover image"); err != nil {
+			t.Errorf("SetProofingMark(same favorite) error = %v", err)
+		}
+		if err := mark(elsewhere.ID, true, ""); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("SetProofingMark(other gallery) error = %v, want sql.ErrNoRows", err)
+		}
+		if err := mark(three.ID+100, true, ""); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("SetProofingMark(missing image) error = %v, want sql.ErrNoRows", err)
+		}
+		assertMarks(t, store, client.ID, "one.jpg:true:cover image", "two.jpg:true:", "three.jpg:false:warmer please")
+
+		// A mark without favorite or comment is dropped
+		if err := mark(two.ID, false, ""); err != nil {
+			t.Fatalf("SetProofingMark(clear): %v", err)
+		}
+		assertMarks(t, store, client.ID, "one.jpg:true:cover image", "three.jpg:false:warmer please")
+
+		// Trashed images and images moved away no longer count
+		if err := galleries.DeleteImage(ctx, three.ID); err != nil {
+			t.Fatalf("DeleteImage: %v", err)
+		}
+		if _, err := galleries.MoveImages(ctx, []int{one.ID}, other.ID, 0); err != nil {
+			t.Fatalf("MoveImages: %v", err)
+		}
+		assertMarks(t, store, client.ID)
+		if err := mark(three.ID, true, ""); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("SetProofingMark(trashed image) error = %v, want sql.ErrNoRows", err)
+		}
+		if err := mark(two.ID, true, ""); err != nil {
+			t.Fatalf("SetProofingMark: %v", err)
+		}
+
+		if err := store.DisableProofing(ctx, cat.ID); err != nil {
+			t.Fatalf("DisableProofing: %v", err)
+		}
+		if err := mark(two.ID, false, "x"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("SetProofingMark(proofing off) error = %v, want sql.ErrNoRows", err)
+		}
+		assertMarks(t, store, client.ID, "two.jpg:true:")
+
+		if err := store.EnableProofing(ctx, &models.ProofingSettings{CategoryID: cat.ID}); err != nil {
+			t.Fatalf("EnableProofing: %v", err)
+		}
+		if err := store.RevokeProofingClient(ctx, client.ID); err != nil {
+			t.Fatalf("RevokeProofingClient: %v", err)
+		}
+		if err := mark(two.ID, false, "x"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("SetProofingMark(revoked) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("Submit", func(t *testing.T) {
+		galleries, store := newStore(t)
+		cat := mustCreateCategory(t, galleries, "proofs", 1)
+		one := mustCreateImage(t, galleries, cat.ID, "/uploads/one.jpg", 1)
+		two := mustCreateImage(t, galleries, cat.ID, "/uploads/two.jpg", 2)
+		if err := store.EnableProofing(ctx, &models.ProofingSettings{CategoryID: cat.ID}); err != nil {
+			t.Fatalf("EnableProofing: %v", err)
+		}
+		client := mustCreateProofingClient(t, store, cat.ID, "Asha")
+
+		if err := store.SetProofingMark(ctx, &models.ProofingMark{ClientID: client.ID, ImageID: one.ID, Comment: "maybe"}); err != nil {
+			t.Fatalf("SetProofingMark: %v", err)
+		}
+		if err := store.SubmitProofingSelection(ctx, client.ID); !errors.Is(err, repository.ErrEmptySelection) {
+			t.Errorf("SubmitProofingSelection(no favorites) error = %v, want ErrEmptySelection", err)
+		}
+		for _, img := range []*models.GalleryImage{one, two} {
+			if err := store.SetProofingMark(ctx, &models.ProofingMark{ClientID: client.ID, ImageID: img.ID, Favorite: true}); err != nil {
+				t.Fatalf("SetProofingMark: %v", err)
+			}
+		}
+
+		// Lowering the limit below the picks blocks submitting
+		if err := store.EnableProofing(ctx, &models.ProofingSettings{CategoryID: cat.ID, MaxPicks: 1}); err != nil {
+			t.Fatalf("EnableProofing: %v", err)
+		}
+		if err := store.SubmitProofingSelection(ctx, client.ID); !errors.Is(err, repository.ErrPickLimit) {
+			t.Errorf("SubmitProofingSelection(over limit) error = %v, want ErrPickLimit", err)
+		}
+		if err := store.EnableProofing(ctx, &models.ProofingSettings{CategoryID: cat.ID, MaxPicks: 2}); err != nil {
+			t.Fatalf("EnableProofing: %v", err)
+		}
+
+		if err := store.SubmitProofingSelection(ctx, client.ID); err != nil {
+			t.Fatalf("SubmitProofingSelection: %v", err)
+		}
+		if got, err := store.GetProofingClient(ctx, client.ID); err != nil || got.SubmittedAt == nil {
+			t.Errorf("GetProofingClient(submitted) = %+v, %v, want submitted_at set", got, err)
+		}
+		if err := store.SubmitProofingSelection(ctx, client.ID); !errors.Is(err, repository.ErrSelectionSubmitted) {
+			t.Errorf("SubmitProofingSelection(again) error = %v, want ErrSelectionSubmitted", err)
+		}
+		if err := store.SetProofingMark(ctx, &models.ProofingMark{ClientID: client.ID, ImageID: one.ID}); !errors.Is(err, repository.ErrSelectionSubmitted) {
+			t.Errorf("SetProofingMark(submitted) error = %v, want ErrSelectionSubmitted", err)
+		}
+
+		if err := store.ReopenProofingSelection(ctx, client.ID); err != nil {
+			t.Fatalf("ReopenProofingSelection: %v", err)
+		}
+		if err := store.ReopenProofingSelection(ctx, client.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("ReopenProofingSelection(open) error = %v, want sql.ErrNoRows", err)
+		}
+		if err := store.SetProofingMark(ctx, &models.ProofingMark{ClientID: client.ID, ImageID: one.ID}); err != nil {
+			t.Errorf("SetProofingMark(reopened) error = %v", err)
+		}
+		assertMarks(t, store, client.ID, "two.jpg:true:")
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		galleries, store := newStore(t)
+		cat := mustCreateCategory(t, galleries, "proofs", 1)
+		img := mustCreateImage(t, galleries, cat.ID, "/uploads/one.jpg", 1)
+		if err := store.EnableProofing(ctx, &models.ProofingSettings{CategoryID: cat.ID}); err != nil {
+			t.Fatalf("EnableProofing: %v", err)
+		}
+		client := mustCreateProofingClient(t, store, cat.ID, "Asha")
+		if err := store.SetProofingMark(ctx, &models.ProofingMark{ClientID: client.ID, ImageID: img.ID, Favorite: true}); err != nil {
+			t.Fatalf("SetProofingMark: %v", err)
+		}
+
+		if err := galleries.DeleteCategory(ctx, cat.ID); err != nil {
+			t.Fatalf("DeleteCategory: %v", err)
+		}
+		if err := store.SubmitProofingSelection(ctx, client.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("SubmitProofingSelection(trashed gallery) error = %v, want sql.ErrNoRows", err)
+		}
+		if unused, err := galleries.PurgeCategory(ctx, cat.ID); err != nil || !reflect.DeepEqual(unused, []string{"/uploads/one.jpg"}) {
+			t.Fatalf("PurgeCategory = %q, %v, want [/uploads/one.jpg]", unused, err)
+		}
+		if _, err := store.ProofingSettings(ctx, cat.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("ProofingSettings(purged) error = %v, want sql.ErrNoRows", err)
+		}
+		if _, err := store.GetProofingClient(ctx, client.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetProofingClient(purged) error = %v, want sql.ErrNoRows", err)
+		}
+		assertMarks(t, store, client.ID)
+	})
+}
+
+func mustCreateCategory(t *testing.T, store repository.GalleryStore, slug string, order int) *models.GalleryCategory {
+	t.Helper()
+
+	cat := &models.GalleryCategory{Slug: slug, Title: slug, DisplayOrder: order}
+	if err := store.CreateCategory(context.Background(), cat); err != nil {
+		t.Fatalf("CreateCategory(%s): %v", slug, err)
+	}
+	return cat
+}
+
+func mustCreateImage(t *testing.T, store repository.GalleryStore, categoryID int, src string, order int) *models.GalleryImage {
+	t.Helper()
+
+	img := &models.GalleryImage{CategoryID: categoryID, Src: src, Alt: src, AspectRatio: "portrait", DisplayOrder: order}
+	if err := store.CreateImage(context.Background(), img); err != nil {
+		t.Fatalf("CreateImage(%s): %v", src, err)
+	}
+	return img
+}
+
+func assertSlugs(t *testing.T, categories []models.GalleryCategory, want ...string) {
+	t.Helper()
+
+	if len(categories) != len(want) {
+		t.Fatalf("got %d categories, want %d", len(categories), len(want))
+	}
+	for i := range want {
+		if categories[i].Slug != want[i] {
+			t.Errorf("categories[%d] = %s, want %s", i, categories[i].Slug, want[i])
+		}
+	}
+}
+
+// assertSlugSet checks the slugs of categories in any order
+func assertSlugSet(t *testing.T, categories []models.GalleryCategory, want ...string) {
+	t.Helper()
+
+	var got []string
+	for _, cat := range categories {
+		got = append(got, cat.Slug)
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("slugs = %v, want %v", got, want)
+	}
+}
+
+func assertSrcs(t *testing.T, images []models.GalleryImage, want ...string) {
+	t.Helper()
+
+	if len(images) != len(want) {
+		t.Fatalf("got %d images, want %d", len(images), len(want))
+	}
+	for i := range want {
+		if images[i].Src != want[i] {
+			t.Errorf("images[%d] = %s, want %s", i, images[i].Src, want[i])
+		}
+	}
+}
+
+// sameMetadata compares metadata by value, capture times by instant
+func sameMetadata(a, b models.ImageMetadata) bool {
+	if (a.CapturedAt == nil) != (b.CapturedAt == nil) {
+		return false
+	}
+	if a.CapturedAt != nil && !a.CapturedAt.Equal(*b.CapturedAt) {
+		return false
+	}
+	a.CapturedAt, b.CapturedAt = nil, nil
+	return a == b
+}
+
+func assertLinkIDs(t *testing.T, links []models.PreviewLink, want ...int) {
+	t.Helper()
+
+	if len(links) != len(want) {
+		t.Fatalf("got %d links, want %d", len(links), len(want))
+	}
+	for i := range want {
+		if links[i].ID != want[i] {
+			t.Errorf("links[%d] = %d, want %d", i, links[i].ID, want[i])
+		}
+	}
+}
+
+func mustCreateTag(t *testing.T, store repository.TagStore, name, slug string) *models.Tag {
+	t.Helper()
+
+	tag := &models.Tag{Name: name, Slug: slug}
+	if err := store.CreateTag(context.Background(), tag); err != nil {
+		t.Fatalf("CreateTag(%s): %v", slug, err)
+	}
+	return tag
+}
+
+func assertTagSlugs(t *testing.T, tags []models.Tag, want ...string) {
+	t.Helper()
+
+	if tags == nil {
+		t.Errorf("tags are nil, want an empty list")
+	}
+	var got []string
+	for _, tag := range tags {
+		got = append(got, tag.Slug)
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("tags = %v, want %v", got, want)
+	}
+}
+
+// assertTagUsage compares tags as slug:images:galleries, in order
+func assertTagUsage(t *testing.T, tags []models.TagUsage, want ...string) {
+	t.Helper()
+
+	var got []string
+	for _, tag := range tags {
+		got = append(got, tag.Slug+":"+strconv.Itoa(tag.ImageCount)+":"+strconv.Itoa(tag.GalleryCount))
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("tags = %v, want %v", got, want)
+	}
+}
+
+func mustCreateProofingClient(t *testing.T, store repository.ProofingStore, categoryID int, name string) *models.ProofingClient {
+	t.Helper()
+
+	client := &models.ProofingClient{CategoryID: categoryID, Name: name, Email: strings.ToLower(name) + "@example.com"}
+	if err := store.CreateProofingClient(context.Background(), client); err != nil {
+		t.Fatalf("CreateProofingClient(%s): %v", name, err)
+	}
+	return client
+}
+
+// assertMarks compares a client's marks as "file:favorite:comment", in order
+func assertMarks(t *testing.T, store repository.ProofingStore, clientID int, want ...string) {
+	t.Helper()
+
+	marks, err := store.ProofingMarks(context.Background(), clientID)
+	if err != nil {
+		t.Fatalf("ProofingMarks: %v", err)
+	}
+	got := []string{}
+	for _, mark := range marks {
+		got = append(got, fmt.Sprintf("%s:%t:%s", strings.TrimPrefix(mark.Src, "/uploads/"), mark.Favorite, mark.Comment))
+	}
+	if want == nil {
+		want = []string{}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ProofingMarks = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,20 @@
+// backend/internal/repository/schema.go
+package repository
+
+import "github.com/supraik/Freelance-Portfolio/internal/database"
+
+// Manifest returns every table and column the repositories depend on.
+// It is checked against the live database by database.VerifySchema.
+func Manifest() []database.Table {
+	var tables []database.Table
+	tables = append(tables, contactTables...)
+	tables = append(tables, galleryTables...)
+	tables = append(tables, tagTables...)
+	tables = append(tables, userTables...)
+	tables = append(tables, portfolioSectionTables...)
+	tables = append(tables, auditTables...)
+	tables = append(tables, previewTables...)
+	tables = append(tables, accessLogTables...)
+	tables = append(tables, proofingTables...)
+	return tables
+}
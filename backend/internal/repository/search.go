@@ -0,0 +1,43 @@
+// backend/internal/repository/search.go
+package repository
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Snippet markers. Queries wrap matches in these rather than in HTML so the
+// surrounding text, which may come from visitors, can be escaped first.
+const (
+	SnippetStart = "\x02"
+	SnippetStop  = "\x03"
+)
+
+// Postgres ts_headline options producing marked snippets
+const headlineOptions = "StartSel=" + SnippetStart + ", StopSel=" + SnippetStop + ", MaxWords=30, MinWords=10, MaxFragments=2"
+
+var snippetMarks = strings.NewReplacer(SnippetStart, "<mark>", SnippetStop, "</mark>")
+
+// Highlight escapes a marked snippet and wraps its matches in <mark>
+func Highlight(snippet string) string {
+	return snippetMarks.Replace(html.EscapeString(snippet))
+}
+
+// SearchTerms splits a search query into lowercase words, dropping punctuation
+func SearchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// ftsQuery turns a search query into an SQLite FTS5 expression matching
+// documents that contain every word. Each word is quoted so user input
+// cannot form FTS5 syntax.
+func ftsQuery(query string) string {
+	terms := SearchTerms(query)
+	for i, term := range terms {
+		terms[i] = `"` + term + `"`
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,12 @@
+// backend/internal/repository/sqlite_test.go
+
+//go:build sqlite
+
+package repository_test
+
+import "github.com/supraik/Freelance-Portfolio/internal/database/databasetest"
+
+// With -tags sqlite the repository suites run against SQLite instead
+func init() {
+	openTestDB = databasetest.OpenSQLite
+}
@@ -2,37 +2,54 @@
 package repository
 
 import (
-	"database/sql"
+	"context"
 	"time"
 
+	"github.com/supraik/Freelance-Portfolio/internal/database"
 	"github.com/supraik/Freelance-Portfolio/internal/models"
 )
 
+// userTables lists the columns UserRepository reads and writes
+var userTables = []database.Table{
+	{Name: "users", Columns: []database.Column{
+		{Name: "id", Type: database.TypeInteger},
+		{Name: "email", Type: database.TypeVarchar},
+		{Name: "password_hash", Type: database.TypeVarchar},
+		{Name: "username", Type: database.TypeVarchar},
+		{Name: "created_at", Type: database.TypeTimestamp},
+		{Name: "last_login", Type: database.TypeTimestamp},
+	}},
+}
+
 // UserRepository handles database operations for users
 type UserRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
 // NewUserRepository creates a new repository
-func NewUserRepository(db *sql.DB) *UserRepository {
+func NewUserRepository(db *database.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
 // GetByEmail retrieves a user by email
-func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (_ *models.User, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "user.get_by_email")
+	defer func() { err = finish(err) }()
+
 	query := `
-		SELECT id, email, password_hash, username, created_at
+		SELECT id, email, password_hash, username, created_at, last_login
 		FROM users
 		WHERE email = $1
 	`
 
 	var user models.User
-	err := r.db.QueryRow(query, email).Scan(
+	err = r.db.QueryRowContext(ctx, query, email).Scan(
 		&user.ID,
 		&user.Email,
 		&user.PasswordHash,
 		&user.Username,
 		&user.CreatedAt,
+		&user.LastLogin,
 	)
 
 	if err != nil {
@@ -48,20 +65,24 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 }
 
 // GetByID retrieves a user by ID
-func (r *UserRepository) GetByID(id int) (*models.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id int) (_ *models.User, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "user.get_by_id")
+	defer func() { err = finish(err) }()
+
 	query := `
-		SELECT id, email, password_hash, username, created_at
+		SELECT id, email, password_hash, username, created_at, last_login
 		FROM users
 		WHERE id = $1
 	`
 
 	var user models.User
-	err := r.db.QueryRow(query, id).Scan(
+	err = r.db.QueryRowContext(ctx, query, id).Scan(
 		&user.ID,
 		&user.Email,
 		&user.PasswordHash,
 		&user.Username,
 		&user.CreatedAt,
+		&user.LastLogin,
 	)
 
 	if err != nil {
@@ -77,37 +98,52 @@ func (r *UserRepository) GetByID(id int) (*models.User, error) {
 }
 
 // Create creates a new admin user
-func (r *UserRepository) Create(user *models.User) error {
+func (r *UserRepository) Create(ctx context.Context, user *models.User) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "user.create")
+	defer func() { err = finish(err) }()
+
+	// users has no separate name column, the display name doubles as username
+	if user.Username == "" {
+		user.Username = user.Name
+	}
+
 	query := `
-		INSERT INTO admin_users (email, password_hash, name)
+		INSERT INTO users (email, password_hash, username)
 		VALUES ($1, $2, $3)
 		RETURNING id, created_at
 	`
 
-	return r.db.QueryRow(query, user.Email, user.PasswordHash, user.Name).Scan(
+	err = r.db.QueryRowContext(ctx, query, user.Email, user.PasswordHash, user.Username).Scan(
 		&user.ID,
 		&user.CreatedAt,
 	)
+	return translateError(err)
 }
 
 // UpdateLastLogin updates the last login timestamp
-func (r *UserRepository) UpdateLastLogin(id int) error {
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, id int) (err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "user.update_last_login")
+	defer func() { err = finish(err) }()
+
 	query := `
-		UPDATE admin_users
+		UPDATE users
 		SET last_login = $1
 		WHERE id = $2
 	`
 
 	now := time.Now()
-	_, err := r.db.Exec(query, now, id)
+	_, err = r.db.ExecContext(ctx, query, now, id)
 	return err
 }
 
 // EmailExists checks if an email already exists
-func (r *UserRepository) EmailExists(email string) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM admin_users WHERE email = $1)`
+func (r *UserRepository) EmailExists(ctx context.Context, email string) (_ bool, err error) {
+	ctx, finish := r.db.WithTimeout(ctx, "user.email_exists")
+	defer func() { err = finish(err) }()
+
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
 
 	var exists bool
-	err := r.db.QueryRow(query, email).Scan(&exists)
+	err = r.db.QueryRowContext(ctx, query, email).Scan(&exists)
 	return exists, err
 }
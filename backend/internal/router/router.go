@@ -2,19 +2,30 @@
 package router
 
 import (
-	"database/sql"
+	"log"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/supraik/Freelance-Portfolio/internal/access"
+	"github.com/supraik/Freelance-Portfolio/internal/audit"
+	"github.com/supraik/Freelance-Portfolio/internal/cache"
 	"github.com/supraik/Freelance-Portfolio/internal/config"
+	"github.com/supraik/Freelance-Portfolio/internal/database"
 	"github.com/supraik/Freelance-Portfolio/internal/handlers"
+	"github.com/supraik/Freelance-Portfolio/internal/imagesize"
 	"github.com/supraik/Freelance-Portfolio/internal/middleware"
+	"github.com/supraik/Freelance-Portfolio/internal/preview"
+	"github.com/supraik/Freelance-Portfolio/internal/proofing"
 	"github.com/supraik/Freelance-Portfolio/internal/repository"
 	"github.com/supraik/Freelance-Portfolio/internal/services"
+	"github.com/supraik/Freelance-Portfolio/internal/variants"
+	"github.com/supraik/Freelance-Portfolio/pkg/response"
 )
 
-// New creates and configures the router
-func New(db *sql.DB, cfg *config.Config) *gin.Engine {
+// New creates and configures the router.
+// contentCache fronts public content reads; nil disables caching.
+func New(db *database.DB, cfg *config.Config, contentCache *cache.Cache) *gin.Engine {
 	// Set Gin mode
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -28,23 +39,53 @@ func New(db *sql.DB, cfg *config.Config) *gin.Engine {
 
 	// Initialize services
 	emailService := services.NewEmailService(cfg)
-	storageService := services.NewStorageService(cfg.UploadDir, cfg.MaxFileSize)
+	generator := variants.New(variants.Options{Widths: cfg.ImageVariantWidths, Quality: cfg.ImageVariantQuality, CWebP: cfg.CWebPPath})
+	if generator.Enabled() && !generator.WebP() && cfg.CWebPPath != "" {
+		log.Printf("⚠️  %s not found, image variants are made without WebP", cfg.CWebPPath)
+	}
+	storageService := services.NewStorageService(cfg.UploadDir, cfg.MaxFileSize, generator)
+	aspect := imagesize.Thresholds{Landscape: cfg.AspectLandscapeMin, Portrait: cfg.AspectPortraitMax}
 	cloudinaryService, err := services.NewCloudinaryService(cfg)
 	if err != nil {
 		panic("Failed to initialize Cloudinary: " + err.Error())
 	}
 
 	// Initialize repositories
-	contactRepo := repository.NewContactRepository(db)
-	galleryRepo := repository.NewGalleryRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	recorder := audit.NewRecorder(auditRepo)
+	var contactRepo repository.ContactStore = repository.NewContactRepository(db)
+	var galleryRepo repository.GalleryStore = repository.NewGalleryRepository(db)
+	var portfolioSectionRepo repository.PortfolioSectionStore = repository.NewPortfolioSectionRepository(db)
+	if contentCache != nil {
+		galleryRepo = repository.NewCachedGalleryRepository(galleryRepo, contentCache)
+		portfolioSectionRepo = repository.NewCachedPortfolioSectionRepository(portfolioSectionRepo, contentCache)
+	}
+	// Admin writes are audited; the audit wraps the cache so lookups for
+	// before and after snapshots see the same data as the write
+	contactRepo = repository.NewAuditedContactRepository(contactRepo, recorder)
+	galleryRepo = repository.NewAuditedGalleryRepository(galleryRepo, recorder)
+	portfolioSectionRepo = repository.NewAuditedPortfolioSectionRepository(portfolioSectionRepo, recorder)
+	// Tag queries are not cached, so tags use the gallery repository directly
+	tagRepo := repository.NewAuditedTagRepository(repository.NewGalleryRepository(db), recorder)
+	// Proofing is not cached either, and clients' writes must be seen at once
+	proofingRepo := repository.NewAuditedProofingRepository(repository.NewGalleryRepository(db), recorder)
+	previewRepo := repository.NewAuditedPreviewRepository(repository.NewPreviewRepository(db), recorder)
+	accessLogRepo := repository.NewAccessLogRepository(db)
 	userRepo := repository.NewUserRepository(db)
-	portfolioSectionRepo := repository.NewPortfolioSectionRepository(db)
 
 	// Initialize handlers
 	contactHandler := handlers.NewContactHandler(contactRepo, emailService)
-	galleryHandler := handlers.NewGalleryHandler(galleryRepo)
+	galleryHandler := handlers.NewGalleryHandler(galleryRepo, storageService, aspect)
+	trashHandler := handlers.NewTrashHandler(galleryRepo, storageService)
+	searchHandler := handlers.NewSearchHandler(galleryRepo)
+	previewHandler := handlers.NewPreviewHandler(previewRepo, galleryRepo, preview.NewSigner(cfg.PreviewSecret))
+	tagHandler := handlers.NewTagHandler(tagRepo)
+	galleryAccessHandler := handlers.NewGalleryAccessHandler(galleryRepo, accessLogRepo,
+		access.NewSigner(cfg.GalleryAccessSecret), access.NewLimiter(cfg.GalleryAccessAttempts, cfg.GalleryAccessWindow), cfg.GalleryAccessTTL)
+	proofingHandler := handlers.NewProofingHandler(proofingRepo, galleryRepo, proofing.NewSigner(cfg.ProofingSecret))
 	authHandler := handlers.NewAuthHandler(userRepo, cfg)
-	uploadHandler := handlers.NewUploadHandler(storageService)
+	uploadHandler := handlers.NewUploadHandler(storageService, aspect, recorder)
+	auditHandler := handlers.NewAuditHandler(auditRepo)
 	portfolioHandler := handlers.NewPortfolioHandler(portfolioSectionRepo, galleryRepo, cloudinaryService)
 
 	// Health check
@@ -62,8 +103,24 @@ func New(db *sql.DB, cfg *config.Config) *gin.Engine {
 		api.POST("/contact", contactHandler.Submit)
 
 		// Gallery routes (public read)
-		api.GET("/galleries", galleryHandler.GetAll)
-		api.GET("/galleries/:slug", galleryHandler.GetBySlug)
+		// ?tags=a,b is answered by the tag handler
+		api.GET("/galleries", tagHandler.Galleries, galleryHandler.GetAll)
+		// ?preview=<token> is answered by the preview handler, even for drafts;
+		// password galleries need the token from POST /galleries/:slug/access
+		api.GET("/galleries/:slug", previewHandler.Gallery, galleryAccessHandler.Gallery, galleryHandler.GetBySlug)
+		api.POST("/galleries/:slug/access", galleryAccessHandler.Unlock)
+
+		// Proofing galleries, through the link handed to each client
+		api.GET("/proofing/:token", proofingHandler.Gallery)
+		api.PUT("/proofing/:token/images/:imageID", proofingHandler.Mark)
+		api.POST("/proofing/:token/submit", proofingHandler.Submit)
+
+		// Full-text search over galleries and images
+		api.GET("/search", searchHandler.Search)
+
+		// Tags and images filtered by tag
+		api.GET("/tags", tagHandler.PublicTags)
+		api.GET("/images", tagHandler.Images)
 
 		// Authentication
 		api.POST("/auth/login", authHandler.Login)
@@ -72,29 +129,87 @@ func New(db *sql.DB, cfg *config.Config) *gin.Engine {
 
 		// Protected routes (admin only)
 		admin := api.Group("/admin")
-		admin.Use(middleware.AuthRequired(cfg.JWTSecret))
+		admin.Use(middleware.AuthRequired(cfg.JWTSecret), middleware.AuditActor())
 		{
 			// Contact management
 			admin.GET("/contacts", contactHandler.GetAll)
+			admin.GET("/contacts/search", contactHandler.Search)
 			admin.PATCH("/contacts/:id/read", contactHandler.MarkAsRead)
 
 			// Portfolio sections
 			admin.GET("/portfolio/sections", portfolioHandler.GetSections)
 			admin.PUT("/portfolio/sections/:id/image", portfolioHandler.UpdateSectionImage)
 
+			// Cache hit/miss counters
+			admin.GET("/cache/stats", func(c *gin.Context) {
+				if contentCache == nil {
+					response.Success(c, http.StatusOK, "Cache disabled", gin.H{"enabled": false})
+					return
+				}
+				response.Success(c, http.StatusOK, "Cache stats retrieved", gin.H{
+					"enabled": true,
+					"content": contentCache.Stats(),
+				})
+			})
+
 			// Gallery management
+			admin.GET("/galleries", galleryHandler.GetAllAdmin)
+			admin.GET("/galleries/:id", galleryHandler.GetByID)
 			admin.POST("/galleries", galleryHandler.Create)
+			admin.PUT("/galleries/order", galleryHandler.ReorderCategories)
 			admin.PUT("/galleries/:id", galleryHandler.Update)
+			admin.PUT("/galleries/:id/status", galleryHandler.UpdateStatus)
+			admin.PUT("/galleries/:id/access", galleryHandler.UpdateAccess)
+			admin.GET("/galleries/:id/access-log", galleryAccessHandler.Log)
 			admin.DELETE("/galleries/:id", galleryHandler.Delete)
 
+			// Preview links to unpublished galleries
+			admin.POST("/galleries/:id/previews", previewHandler.Create)
+			admin.GET("/previews", previewHandler.List)
+			admin.DELETE("/previews/:id", previewHandler.Revoke)
+
+			// Client proofing
+			admin.GET("/galleries/:id/proofing", proofingHandler.Get)
+			admin.PUT("/galleries/:id/proofing", proofingHandler.Enable)
+			admin.DELETE("/galleries/:id/proofing", proofingHandler.Disable)
+			admin.POST("/galleries/:id/proofing/clients", proofingHandler.Invite)
+			admin.DELETE("/proofing/clients/:id", proofingHandler.Revoke)
+			admin.POST("/proofing/clients/:id/reopen", proofingHandler.Reopen)
+			admin.GET("/proofing/clients/:id/selection", proofingHandler.Selection)
+
+			// Tags
+			admin.GET("/tags", tagHandler.List)
+			admin.POST("/tags", tagHandler.Create)
+			admin.PUT("/tags/:id", tagHandler.Update)
+			admin.DELETE("/tags/:id", tagHandler.Delete)
+			admin.POST("/tags/:id/merge", tagHandler.Merge)
+			admin.GET("/galleries/:id/tags", tagHandler.GetCategoryTags)
+			admin.PUT("/galleries/:id/tags", tagHandler.SetCategoryTags)
+			admin.GET("/images/:id/tags", tagHandler.GetImageTags)
+			admin.PUT("/images/:id/tags", tagHandler.SetImageTags)
+
 			// Image management
 			admin.POST("/galleries/:id/images", galleryHandler.CreateImage)
+			admin.PUT("/galleries/:id/images/order", galleryHandler.ReorderImages)
+			admin.POST("/images/move", galleryHandler.MoveImages)
+			admin.POST("/images/copy", galleryHandler.CopyImages)
+			admin.GET("/images/:id", galleryHandler.GetImage)
 			admin.PUT("/images/:id", galleryHandler.UpdateImage)
 			admin.DELETE("/images/:id", galleryHandler.DeleteImage)
 
+			// Trash bin
+			admin.GET("/trash", trashHandler.List)
+			admin.POST("/trash/galleries/:id/restore", trashHandler.RestoreCategory)
+			admin.POST("/trash/images/:id/restore", trashHandler.RestoreImage)
+			admin.DELETE("/trash/galleries/:id", trashHandler.PurgeCategory)
+			admin.DELETE("/trash/images/:id", trashHandler.PurgeImage)
+
 			// Image upload
 			admin.POST("/upload", uploadHandler.Upload)
 			admin.POST("/upload/multiple", uploadHandler.UploadMultiple)
+
+			// Audit log of admin changes
+			admin.GET("/audit", auditHandler.List)
 		}
 	}
 
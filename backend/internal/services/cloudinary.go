@@ -5,12 +5,15 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"mime/multipart"
 	"path/filepath"
 
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 	"github.com/supraik/Freelance-Portfolio/internal/config"
+	"github.com/supraik/Freelance-Portfolio/internal/exif"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
 )
 
 // CloudinaryService handles image uploads to Cloudinary
@@ -45,10 +48,20 @@ type UploadResult struct {
 	Height       int
 	Format       string
 	Size         int64
+	Metadata     *models.ImageMetadata // camera metadata read before upload, nil if none
 }
 
 // UploadImage uploads an image to Cloudinary
 func (s *CloudinaryService) UploadImage(ctx context.Context, file multipart.File, filename string) (*UploadResult, error) {
+	// Read camera metadata; an unreadable block never fails the upload
+	metadata, err := exif.Decode(file)
+	if err != nil {
+		log.Printf("Failed to read metadata of %s: %v", filename, err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
 	// Upload to Cloudinary
 	uploadResult, err := s.cld.Upload.Upload(ctx, file, uploader.UploadParams{
 		Folder:         s.folder,
@@ -74,6 +87,7 @@ func (s *CloudinaryService) UploadImage(ctx context.Context, file multipart.File
 		Height:       uploadResult.Height,
 		Format:       uploadResult.Format,
 		Size:         int64(uploadResult.Bytes),
+		Metadata:     metadata,
 	}, nil
 }
 
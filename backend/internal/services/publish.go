@@ -0,0 +1,52 @@
+// backend/internal/services/publish.go
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// PublishCheckInterval is how often publishing windows are checked
+const PublishCheckInterval = time.Minute
+
+// PublishScheduler asks a cached gallery store which galleries went live or
+// came down since the last check, which drops their cached pages, so the
+// public site follows the schedule instead of waiting for entries to expire
+type PublishScheduler struct {
+	store repository.GalleryStore
+}
+
+// NewPublishScheduler creates a scheduler for store
+func NewPublishScheduler(store repository.GalleryStore) *PublishScheduler {
+	return &PublishScheduler{store: store}
+}
+
+// Run checks every PublishCheckInterval until ctx is done
+func (s *PublishScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(PublishCheckInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		ids, err := s.store.ScheduleTransitions(ctx, last, now)
+		if err != nil {
+			// Keep last so the next check covers this interval too
+			log.Printf("Failed to check gallery schedules: %v", err)
+			continue
+		}
+		last = now
+		if len(ids) > 0 {
+			log.Printf("%d scheduled galleries went live or came down", len(ids))
+		}
+	}
+}
@@ -2,31 +2,51 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"mime/multipart"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/google/uuid"
+
+	"github.com/supraik/Freelance-Portfolio/internal/exif"
+	"github.com/supraik/Freelance-Portfolio/internal/imagesize"
+	"github.com/supraik/Freelance-Portfolio/internal/imaging"
+	"github.com/supraik/Freelance-Portfolio/internal/models"
+	"github.com/supraik/Freelance-Portfolio/internal/placeholder"
+	"github.com/supraik/Freelance-Portfolio/internal/variants"
 )
 
+// ErrNotUpload is returned for URLs that do not name a file in the upload
+// directory, e.g. /uploads/../config.env
+var ErrNotUpload = errors.New("not an uploaded file")
+
+// variantsDir is where resized variants live, within the upload directory
+const variantsDir = "variants"
+
 // StorageService handles file storage operations
 type StorageService struct {
 	uploadDir    string
 	maxFileSize  int64
 	allowedTypes map[string]bool
+	variants     *variants.Generator
 }
 
-// NewStorageService creates a new storage service
-func NewStorageService(uploadDir string, maxFileSize int64) *StorageService {
+// NewStorageService creates a new storage service. Resized variants of
+// uploads are made with generator; none are when it is nil.
+func NewStorageService(uploadDir string, maxFileSize int64, generator *variants.Generator) *StorageService {
 	// Create upload directory if not exists
 	os.MkdirAll(uploadDir, 0755)
 
 	return &StorageService{
 		uploadDir:   uploadDir,
 		maxFileSize: maxFileSize,
+		variants:    generator,
 		allowedTypes: map[string]bool{
 			"image/jpeg": true,
 			"image/jpg":  true,
@@ -78,26 +98,287 @@ func (s *StorageService) SaveFile(file *multipart.FileHeader) (string, error) {
 	return "/uploads/" + filename, nil
 }
 
-// DeleteFile removes a file from storage
+// DeleteFile removes a file from storage, along with its variants
 func (s *StorageService) DeleteFile(url string) error {
-	// Extract filename from URL
-	filename := strings.TrimPrefix(url, "/uploads/")
-	filepath := filepath.Join(s.uploadDir, filename)
+	filePath, err := s.GetFilePath(url)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil {
+		return err
+	}
+	return s.DeleteVariants(url)
+}
+
+// DeleteVariants removes the resized variants of an uploaded image, also
+// when variants are no longer made. Files that are not uploads here have
+// none.
+func (s *StorageService) DeleteVariants(url string) error {
+	names, err := s.variantNames(url)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, name := range names {
+		p, err := s.GetFilePath(name)
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
 
-	return os.Remove(filepath)
+// DeleteUnused removes the variants of images that were purged and whose
+// src no image uses anymore. The uploads themselves are kept, they may still
+// be used elsewhere, e.g. as a cover. Failures are logged.
+func (s *StorageService) DeleteUnused(urls []string) {
+	for _, url := range urls {
+		if err := s.DeleteVariants(url); err != nil {
+			log.Printf("Failed to delete the variants of %s: %v", url, err)
+		}
+	}
 }
 
 // FileExists checks if a file exists in storage
 func (s *StorageService) FileExists(url string) bool {
-	filename := strings.TrimPrefix(url, "/uploads/")
-	filepath := filepath.Join(s.uploadDir, filename)
-
-	_, err := os.Stat(filepath)
+	filePath, err := s.GetFilePath(url)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filePath)
 	return err == nil
 }
 
-// GetFilePath returns the absolute path of a file
-func (s *StorageService) GetFilePath(url string) string {
-	filename := strings.TrimPrefix(url, "/uploads/")
-	return filepath.Join(s.uploadDir, filename)
+// Metadata reads the camera metadata of an uploaded image. It returns nil
+// without an error for files that are not uploads here or carry none.
+func (s *StorageService) Metadata(url string) (*models.ImageMetadata, error) {
+	filePath, ok := s.uploadPath(url)
+	if !ok {
+		return nil, nil
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return exif.Decode(f)
+}
+
+// Dimensions reads the displayed pixel size of an uploaded image. It
+// returns zero without an error for files that are not uploads here or not
+// images of a known format.
+func (s *StorageService) Dimensions(url string) (width, height int, err error) {
+	filePath, ok := s.uploadPath(url)
+	if !ok {
+		return 0, 0, nil
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	return imagesize.Displayed(f)
+}
+
+// Placeholder computes the loading placeholder of an uploaded image. It
+// returns nil without an error for files that are not uploads here or that
+// cannot be decoded (e.g. WebP).
+func (s *StorageService) Placeholder(url string) (*models.ImagePlaceholder, error) {
+	filePath, ok := s.uploadPath(url)
+	if !ok {
+		return nil, nil
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return placeholder.Generate(f)
+}
+
+// Upload is what Describe derives from an uploaded image
+type Upload struct {
+	Width, Height int // displayed size, zero when unknown
+	Metadata      *models.ImageMetadata
+	Placeholder   *models.ImagePlaceholder
+	Variants      []models.ImageVariant
+}
+
+// Describe derives what Dimensions, Metadata, Placeholder and Variants do
+// from an uploaded image, reading the file once and decoding its pixels
+// once for both the placeholder and the variants. What cannot be derived
+// is left empty and the errors are joined; files that are not uploads here
+// get an empty Upload.
+func (s *StorageService) Describe(url string) (*Upload, error) {
+	upload := &Upload{}
+	filePath, ok := s.uploadPath(url)
+	if !ok {
+		return upload, nil
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return upload, err
+	}
+	defer f.Close()
+
+	var errs []error
+	orientation := 1
+	meta, err := exif.Decode(f)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("metadata: %w", err))
+	} else if meta != nil {
+		upload.Metadata = meta
+		orientation = max(1, meta.Orientation)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return upload, err
+	}
+	width, height, err := imagesize.Decode(f)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("size: %w", err))
+	}
+	upload.Width, upload.Height = imagesize.Oriented(width, height, orientation)
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return upload, err
+	}
+	img, format, err := imaging.Decode(f)
+	if img == nil || err != nil {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("decode: %w", err))
+		}
+		return upload, errors.Join(errs...)
+	}
+
+	if upload.Placeholder, err = placeholder.FromImage(img, orientation); err != nil {
+		errs = append(errs, fmt.Errorf("placeholder: %w", err))
+	}
+	if _, stem, ok := s.variantStem(url); ok {
+		list, err := s.variants.GenerateFrom(img, format, orientation, filepath.Join(s.uploadDir, variantsDir), stem, false)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("variants: %w", err))
+		}
+		upload.Variants = variantURLs(list)
+	}
+	return upload, errors.Join(errs...)
+}
+
+// VariantsEnabled reports whether resized variants of uploads are made
+func (s *StorageService) VariantsEnabled() bool {
+	return s.variants.Enabled()
+}
+
+// Variants writes the resized variants of an uploaded image that are
+// missing (all of them with replace) and lists them. It returns nil
+// without an error when variants are off, for files that are not uploads
+// here and for images that cannot be decoded (e.g. WebP).
+func (s *StorageService) Variants(url string, replace bool) ([]models.ImageVariant, error) {
+	filePath, stem, ok := s.variantStem(url)
+	if !ok {
+		return nil, nil
+	}
+	list, err := s.variants.Generate(filePath, filepath.Join(s.uploadDir, variantsDir), stem, replace)
+	return variantURLs(list), err
+}
+
+// PlanVariants lists the variants Variants would make, without writing
+func (s *StorageService) PlanVariants(url string) ([]models.ImageVariant, error) {
+	filePath, stem, ok := s.variantStem(url)
+	if !ok {
+		return nil, nil
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	list, err := s.variants.Plan(f, stem)
+	return variantURLs(list), err
+}
+
+// variantStem returns the path of url and what its variant files are named
+// after, and whether url is an upload variants are made of
+func (s *StorageService) variantStem(url string) (string, string, bool) {
+	if !s.variants.Enabled() {
+		return "", "", false
+	}
+	filePath, ok := s.uploadPath(url)
+	if !ok {
+		return "", "", false
+	}
+	return filePath, stemOf(url), true
+}
+
+// variantNames lists the URLs of the variant files of url on disk
+func (s *StorageService) variantNames(url string) ([]string, error) {
+	if _, ok := s.uploadPath(url); !ok {
+		return nil, nil
+	}
+	matches, err := filepath.Glob(filepath.Join(s.uploadDir, variantsDir, stemOf(url)+"-*"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(matches))
+	for i, match := range matches {
+		names[i] = "/uploads/" + variantsDir + "/" + filepath.Base(match)
+	}
+	return names, nil
+}
+
+// stemOf returns the file name of url without its extension, which the
+// variant files of an upload are named after
+func stemOf(url string) string {
+	name := path.Base(url)
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// variantURLs turns generated variants into the model served to clients
+func variantURLs(list []variants.Variant) []models.ImageVariant {
+	if list == nil {
+		return nil
+	}
+	out := make([]models.ImageVariant, len(list))
+	for i, v := range list {
+		out[i] = models.ImageVariant{
+			URL:    "/uploads/" + variantsDir + "/" + v.Name,
+			Width:  v.Width,
+			Height: v.Height,
+			Type:   v.Type,
+		}
+	}
+	return out
+}
+
+// GetFilePath returns the path of the file an /uploads/ URL names. URLs
+// that resolve outside the upload directory get ErrNotUpload.
+func (s *StorageService) GetFilePath(url string) (string, error) {
+	clean := path.Clean(url)
+	if !strings.HasPrefix(clean, "/uploads/") {
+		return "", ErrNotUpload
+	}
+	name := filepath.FromSlash(strings.TrimPrefix(clean, "/uploads/"))
+	if !filepath.IsLocal(name) {
+		return "", ErrNotUpload
+	}
+	return filepath.Join(s.uploadDir, name), nil
+}
+
+// uploadPath returns the path of url when it names a file as SaveFile
+// issues them, directly in the upload directory. Only those are read to
+// derive metadata, sizes, placeholders and variants; other URLs, variants
+// included, are not.
+func (s *StorageService) uploadPath(url string) (string, bool) {
+	if path.Dir(url) != "/uploads" || path.Clean(url) != url {
+		return "", false
+	}
+	filePath, err := s.GetFilePath(url)
+	return filePath, err == nil
 }
@@ -0,0 +1,183 @@
+// backend/internal/services/storage_test.go
+package services
+
+import (
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/supraik/Freelance-Portfolio/internal/exif/exiftest"
+	"github.com/supraik/Freelance-Portfolio/internal/variants"
+)
+
+// writePNG writes a width×height PNG to path
+func writePNG(t *testing.T, path string, width, height int) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testStorage returns a storage service on uploads/ in a temporary
+// directory that also holds outside.png next to it, and uploads/photo.png
+func testStorage(t *testing.T) (*StorageService, string) {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "uploads")
+	s := NewStorageService(dir, 1<<20, variants.New(variants.Options{Widths: []int{4}, Quality: 80}))
+	writePNG(t, filepath.Join(root, "outside.png"), 10, 10)
+	writePNG(t, filepath.Join(dir, "photo.png"), 10, 10)
+	return s, dir
+}
+
+func TestGetFilePath(t *testing.T) {
+	s, dir := testStorage(t)
+
+	tests := []struct {
+		url  string
+		want string // empty for ErrNotUpload
+	}{
+		{"/uploads/photo.png", filepath.Join(dir, "photo.png")},
+		{"/uploads/variants/photo-4.png", filepath.Join(dir, "variants", "photo-4.png")},
+		{"/uploads/./photo.png", filepath.Join(dir, "photo.png")},
+		{"/uploads/../outside.png", ""},
+		{"/uploads/../../etc/passwd", ""},
+		{"/uploads/variants/../../outside.png", ""},
+		{"/uploads/..", ""},
+		{"/uploads/", ""},
+		{"/elsewhere/photo.png", ""},
+		{"https://cdn.example.com/uploads/photo.png", ""},
+	}
+	for _, tt := range tests {
+		got, err := s.GetFilePath(tt.url)
+		switch {
+		case tt.want == "" && !errors.Is(err, ErrNotUpload):
+			t.Errorf("GetFilePath(%q) = %q, %v, want ErrNotUpload", tt.url, got, err)
+		case tt.want != "" && (err != nil || got != tt.want):
+			t.Errorf("GetFilePath(%q) = %q, %v, want %q", tt.url, got, err, tt.want)
+		}
+	}
+}
+
+func TestDeleteVariants(t *testing.T) {
+	s, dir := testStorage(t)
+	if list, err := s.Variants("/uploads/photo.png", false); err != nil || len(list) != 1 {
+		t.Fatalf("Variants = %v, %v, want one", list, err)
+	}
+	writePNG(t, filepath.Join(dir, "other.png"), 10, 10)
+	if _, err := s.Variants("/uploads/other.png", false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Also once variants are turned off
+	off := NewStorageService(dir, 1<<20, nil)
+	off.DeleteUnused([]string{"/uploads/photo.png", "/uploads/../outside.png", "https://cdn.example.com/photo.png"})
+
+	if matches, _ := filepath.Glob(filepath.Join(dir, "variants", "photo-*")); len(matches) != 0 {
+		t.Errorf("variants left after DeleteUnused: %v", matches)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "variants", "other-*")); len(matches) != 1 {
+		t.Errorf("variants of other uploads = %v, want kept", matches)
+	}
+	if !s.FileExists("/uploads/photo.png") {
+		t.Error("DeleteUnused removed the upload itself")
+	}
+}
+
+func TestStorageDerivesOnlyIssuedUploads(t *testing.T) {
+	s, dir := testStorage(t)
+
+	if w, h, err := s.Dimensions("/uploads/photo.png"); err != nil || w != 10 || h != 10 {
+		t.Errorf("Dimensions(upload) = %d×%d, %v, want 10×10", w, h, err)
+	}
+	if p, err := s.Placeholder("/uploads/photo.png"); err != nil || p == nil {
+		t.Errorf("Placeholder(upload) = %v, %v, want one", p, err)
+	}
+	if list, err := s.Variants("/uploads/photo.png", false); err != nil || len(list) != 1 {
+		t.Errorf("Variants(upload) = %v, %v, want one", list, err)
+	}
+
+	for _, url := range []string{
+		"/uploads/../outside.png",
+		"/uploads/variants/../../outside.png",
+		"/uploads/./photo.png",
+		"/uploads/variants/photo-4.png",
+	} {
+		if w, h, err := s.Dimensions(url); err != nil || w != 0 || h != 0 {
+			t.Errorf("Dimensions(%q) = %d×%d, %v, want nothing", url, w, h, err)
+		}
+		if meta, err := s.Metadata(url); err != nil || meta != nil {
+			t.Errorf("Metadata(%q) = %v, %v, want nothing", url, meta, err)
+		}
+		if p, err := s.Placeholder(url); err != nil || p != nil {
+			t.Errorf("Placeholder(%q) = %v, %v, want nothing", url, p, err)
+		}
+		if list, err := s.Variants(url, false); err != nil || list != nil {
+			t.Errorf("Variants(%q) = %v, %v, want nothing", url, list, err)
+		}
+	}
+
+	// Nothing was written for the file outside the upload directory
+	if matches, _ := filepath.Glob(filepath.Join(dir, "variants", "outside-*")); len(matches) != 0 {
+		t.Errorf("variants written for a file outside uploads: %v", matches)
+	}
+	if err := s.DeleteFile("/uploads/../outside.png"); !errors.Is(err, ErrNotUpload) {
+		t.Errorf("DeleteFile outside uploads: %v, want ErrNotUpload", err)
+	}
+}
+
+func TestDescribeMatchesSeparateReads(t *testing.T) {
+	s, dir := testStorage(t)
+	data := exiftest.JPEG(t, exiftest.Quadrants(20, 10), exiftest.Orientation(6))
+	if err := os.WriteFile(filepath.Join(dir, "turned.jpg"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, url := range []string{"/uploads/turned.jpg", "/uploads/photo.png", "/uploads/../outside.png"} {
+		got, err := s.Describe(url)
+		if err != nil {
+			t.Fatalf("Describe(%q): %v", url, err)
+		}
+		variantFiles := readVariants(t, dir)
+
+		width, height, _ := s.Dimensions(url)
+		meta, _ := s.Metadata(url)
+		p, _ := s.Placeholder(url)
+		list, _ := s.Variants(url, true) // rewritten, to compare the files
+		want := &Upload{Width: width, Height: height, Metadata: meta, Placeholder: p, Variants: list}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Describe(%q) = %+v, want %+v", url, got, want)
+		}
+		if again := readVariants(t, dir); !reflect.DeepEqual(variantFiles, again) {
+			t.Errorf("Describe(%q) wrote other variant files than Variants", url)
+		}
+	}
+
+	if got, _ := s.Describe("/uploads/turned.jpg"); got.Width != 10 || got.Height != 20 || len(got.Variants) != 1 {
+		t.Errorf("Describe(turned) = %+v, want 10×20 with one variant", got)
+	}
+}
+
+// readVariants returns the contents of the variant files by name
+func readVariants(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	matches, _ := filepath.Glob(filepath.Join(dir, "variants", "*"))
+	for _, name := range matches {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[filepath.Base(name)] = string(data)
+	}
+	return files
+}
@@ -0,0 +1,55 @@
+// backend/internal/services/trash.go
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/repository"
+)
+
+// TrashPurgeInterval is how often expired trash is looked for
+const TrashPurgeInterval = time.Hour
+
+// TrashJanitor permanently deletes galleries and images that have been in
+// the trash longer than the retention period, along with the resized
+// variants of uploads no image uses anymore
+type TrashJanitor struct {
+	store     repository.GalleryStore
+	storage   *StorageService
+	retention time.Duration
+}
+
+// NewTrashJanitor creates a janitor for store whose uploads are in storage
+func NewTrashJanitor(store repository.GalleryStore, storage *StorageService, retention time.Duration) *TrashJanitor {
+	return &TrashJanitor{store: store, storage: storage, retention: retention}
+}
+
+// Run purges expired trash on start and every TrashPurgeInterval until ctx is done
+func (j *TrashJanitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(TrashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		j.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *TrashJanitor) purge(ctx context.Context) {
+	categories, images, unused, err := j.store.PurgeTrash(ctx, time.Now().Add(-j.retention))
+	if err != nil {
+		log.Printf("Failed to purge trash: %v", err)
+		return
+	}
+	j.storage.DeleteUnused(unused)
+	if categories > 0 || images > 0 {
+		log.Printf("Purged %d galleries and %d images from trash", categories, images)
+	}
+}
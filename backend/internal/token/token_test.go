@@ -0,0 +1,82 @@
+// backend/internal/token/token_test.go
+package token_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/access"
+	"github.com/supraik/Freelance-Portfolio/internal/preview"
+	"github.com/supraik/Freelance-Portfolio/internal/proofing"
+	"github.com/supraik/Freelance-Portfolio/internal/token"
+)
+
+func TestSignerVerify(t *testing.T) {
+	s := token.NewSigner("secret", "test-v1")
+	tok := s.Sign("7.1800000000", "hash")
+
+	if payload, err := s.Verify(tok, "hash"); err != nil || payload != "7.1800000000" {
+		t.Fatalf("Verify = %q, %v, want the payload", payload, err)
+	}
+
+	tests := []struct {
+		name   string
+		signer *token.Signer
+		token  string
+		bound  []string
+	}{
+		{"other bound value", s, tok, []string{"changed"}},
+		{"bound value missing", s, tok, nil},
+		{"extra bound value", s, tok, []string{"hash", ""}},
+		{"other label", token.NewSigner("secret", "other-v1"), tok, []string{"hash"}},
+		{"other secret", token.NewSigner("other", "test-v1"), tok, []string{"hash"}},
+		{"tampered payload", s, "OC4xODAwMDAwMDAw" + tok[len("Ny4xODAwMDAwMDAw"):], []string{"hash"}},
+		{"no signature", s, "Ny4xODAwMDAwMDAw", []string{"hash"}},
+		{"not base64", s, "!!.!!", []string{"hash"}},
+		{"empty", s, "", nil},
+	}
+	for _, tt := range tests {
+		if payload, err := tt.signer.Verify(tt.token, tt.bound...); !errors.Is(err, token.ErrInvalid) {
+			t.Errorf("%s: Verify = %q, %v, want ErrInvalid", tt.name, payload, err)
+		}
+	}
+}
+
+// Links already handed out must keep working: these tokens were minted
+// before the signers shared this package
+func TestSignersKeepTokens(t *testing.T) {
+	expires := time.Unix(1800000000, 0)
+	now := expires.Add(-time.Hour)
+
+	const accessToken = "Ny4xODAwMDAwMDAw.ug2bUy4BDsGsYTElp7pn5tKxDay755fjZXLCQUExSKM"
+	if got := access.NewSigner("secret").Sign(7, expires, "$2a$hash"); got != accessToken {
+		t.Errorf("access token = %s, want %s", got, accessToken)
+	}
+	if _, err := access.NewSigner("secret").Verify(accessToken, 7, "$2a$hash", now); err != nil {
+		t.Errorf("access Verify: %v", err)
+	}
+
+	const previewToken = "My43LjE4MDAwMDAwMDA.WGogBh4TZR_bVnQzAkz91ElHzVm8jLMVljJawhfRFIo"
+	claims := preview.Claims{LinkID: 3, CategoryID: 7, ExpiresAt: expires.UTC()}
+	if got := preview.NewSigner("secret").Sign(claims); got != previewToken {
+		t.Errorf("preview token = %s, want %s", got, previewToken)
+	}
+	if got, err := preview.NewSigner("secret").Verify(previewToken, now); err != nil || got != claims {
+		t.Errorf("preview Verify = %+v, %v, want %+v", got, err, claims)
+	}
+
+	const proofingToken = "NS43.vNl2bv0-jxOQpGF4288by3l9HmRKs38G2SwaDv-ViPk"
+	client := proofing.Claims{ClientID: 5, CategoryID: 7}
+	if got := proofing.NewSigner("secret").Sign(client); got != proofingToken {
+		t.Errorf("proofing token = %s, want %s", got, proofingToken)
+	}
+	if got, err := proofing.NewSigner("secret").Verify(proofingToken); err != nil || got != client {
+		t.Errorf("proofing Verify = %+v, %v, want %+v", got, err, client)
+	}
+
+	// The labels keep the kinds apart even where payloads look alike
+	if _, err := proofing.NewSigner("secret").Verify(accessToken); err == nil {
+		t.Error("an access token verified as a proofing token")
+	}
+}
@@ -0,0 +1,306 @@
+// backend/internal/variants/variants.go
+
+// Package variants writes the resized copies of uploaded images that
+// browsers choose from with srcset. JPEG sources get JPEG variants, PNG and
+// GIF sources PNG ones, and each also gets a WebP variant when the cwebp
+// command is available; Go cannot encode WebP. WebP sources get none, as
+// the standard library cannot decode them.
+package variants
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // register the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/supraik/Freelance-Portfolio/internal/exif"
+	"github.com/supraik/Freelance-Portfolio/internal/imagesize"
+	"github.com/supraik/Freelance-Portfolio/internal/imaging"
+)
+
+// cwebpTimeout bounds one cwebp run
+const cwebpTimeout = time.Minute
+
+// Options configure a Generator
+type Options struct {
+	Widths  []int  // variant widths; only those narrower than an image are made
+	Quality int    // JPEG and WebP quality, 1 to 100
+	CWebP   string // cwebp command for WebP variants, none when empty or not found
+}
+
+// Variant is one file written for an image
+type Variant struct {
+	Name   string // file name within the variants directory
+	Width  int
+	Height int
+	Type   string // MIME type
+}
+
+// Generator writes image variants
+type Generator struct {
+	widths  []int
+	quality int
+	cwebp   string // resolved path, empty without WebP variants
+}
+
+// New returns a Generator for opts. WebP variants are left out when
+// opts.CWebP cannot be found.
+func New(opts Options) *Generator {
+	widths := slices.Clone(opts.Widths)
+	slices.Sort(widths)
+	g := &Generator{widths: slices.Compact(widths), quality: opts.Quality}
+	if opts.CWebP != "" {
+		if path, err := exec.LookPath(opts.CWebP); err == nil {
+			g.cwebp = path
+		}
+	}
+	return g
+}
+
+// Enabled reports whether any variant widths are configured
+func (g *Generator) Enabled() bool {
+	return g != nil && len(g.widths) > 0
+}
+
+// WebP reports whether WebP variants are written
+func (g *Generator) WebP() bool {
+	return g != nil && g.cwebp != ""
+}
+
+// source is what Plan reads from an image's headers
+type source struct {
+	width, height int // as stored, before orientation
+	orientation   int
+	variants      []Variant
+}
+
+// Plan lists the variants of the image in r, named after stem, without
+// writing anything. It returns nil for formats it cannot decode and an
+// empty list for images no wider than the smallest width.
+func (g *Generator) Plan(r io.ReadSeeker, stem string) ([]Variant, error) {
+	src, err := g.plan(r, stem)
+	if src == nil {
+		return nil, err
+	}
+	return src.variants, nil
+}
+
+func (g *Generator) plan(r io.ReadSeeker, stem string) (*source, error) {
+	if !g.Enabled() {
+		return nil, nil
+	}
+	config, format, err := image.DecodeConfig(r)
+	switch {
+	case errors.Is(err, image.ErrFormat):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	case config.Width <= 0 || config.Height <= 0:
+		return nil, nil
+	}
+
+	orientation := 1
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if meta, err := exif.Decode(r); err == nil && meta != nil && meta.Orientation != 0 {
+		orientation = meta.Orientation
+	}
+	return g.newSource(config.Width, config.Height, format, orientation, stem), nil
+}
+
+// newSource lists the variants of an image of the given stored size and
+// format, as image.Decode names it. It returns nil for formats that get
+// no variants.
+func (g *Generator) newSource(width, height int, format string, orientation int, stem string) *source {
+	var mime, ext string
+	switch format {
+	case "jpeg":
+		mime, ext = "image/jpeg", ".jpg"
+	case "png", "gif":
+		mime, ext = "image/png", ".png"
+	default:
+		return nil
+	}
+
+	src := &source{width: width, height: height, orientation: orientation}
+	shownWidth, shownHeight := imagesize.Oriented(src.width, src.height, src.orientation)
+	src.variants = []Variant{}
+	for _, types := range [][2]string{{mime, ext}, {"image/webp", ".webp"}} {
+		if types[0] == "image/webp" && !g.WebP() {
+			continue
+		}
+		for _, width := range g.widths {
+			if width >= shownWidth {
+				break
+			}
+			src.variants = append(src.variants, Variant{
+				Name:   fmt.Sprintf("%s-%d%s", stem, width, types[1]),
+				Width:  width,
+				Height: max(1, (shownHeight*width+shownWidth/2)/shownWidth),
+				Type:   types[0],
+			})
+		}
+	}
+	return src
+}
+
+// Generate writes the variants of the image at path into dir, named after
+// stem, and lists them like Plan. Files already there are kept unless
+// replace is set.
+func (g *Generator) Generate(path, dir, stem string, replace bool) ([]Variant, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	src, err := g.plan(f, stem)
+	if src == nil || err != nil {
+		return nil, err
+	}
+
+	// The image is only decoded when a variant is missing
+	missing := src.missing(dir, replace)
+	if len(missing) == 0 {
+		return src.variants, nil
+	}
+	if src.width*src.height > imaging.MaxPixels {
+		return nil, imaging.ErrTooLarge
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.writeAll(dir, img, src.orientation, missing); err != nil {
+		return nil, err
+	}
+	return src.variants, nil
+}
+
+// GenerateFrom is Generate for an image already decoded from a file of the
+// given format, as image.Decode names it, and EXIF orientation
+func (g *Generator) GenerateFrom(img image.Image, format string, orientation int, dir, stem string, replace bool) ([]Variant, error) {
+	if !g.Enabled() {
+		return nil, nil
+	}
+	src := g.newSource(img.Bounds().Dx(), img.Bounds().Dy(), format, orientation, stem)
+	if src == nil {
+		return nil, nil
+	}
+	if err := g.writeAll(dir, img, orientation, src.missing(dir, replace)); err != nil {
+		return nil, err
+	}
+	return src.variants, nil
+}
+
+// missing returns the variants of src not yet in dir, all of them with
+// replace
+func (src *source) missing(dir string, replace bool) []Variant {
+	var missing []Variant
+	for _, v := range src.variants {
+		if _, err := os.Stat(filepath.Join(dir, v.Name)); replace || err != nil {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+// writeAll writes the variants list of img, turned by orientation, into dir
+func (g *Generator) writeAll(dir string, img image.Image, orientation int, list []Variant) error {
+	if len(list) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// Each width is resized once for all its formats
+	resized := make(map[int]*image.RGBA)
+	for _, v := range list {
+		small, ok := resized[v.Width]
+		if !ok {
+			// Resize before turning, so the stored axes are scaled
+			width, height := v.Width, v.Height
+			if orientation >= 5 {
+				width, height = height, width
+			}
+			small = imaging.Orient(imaging.Resize(img, width, height), orientation)
+			resized[v.Width] = small
+		}
+		if err := g.write(filepath.Join(dir, v.Name), v.Type, small); err != nil {
+			return fmt.Errorf("%s: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+// write encodes img as mime into path. The file is written under a
+// temporary name and renamed, so it is never seen half written.
+func (g *Generator) write(path, mime string, img image.Image) (err error) {
+	tmp := path + ".tmp"
+	defer func() {
+		if err != nil {
+			os.Remove(tmp)
+		}
+	}()
+
+	if mime == "image/webp" {
+		err = g.encodeWebP(tmp, img)
+	} else {
+		err = encodeFile(tmp, mime, img, g.quality)
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// encodeWebP converts img with cwebp, by way of a lossless PNG
+func (g *Generator) encodeWebP(path string, img image.Image) error {
+	lossless := path + ".png"
+	if err := encodeFile(lossless, "image/png", img, 0); err != nil {
+		return err
+	}
+	defer os.Remove(lossless)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cwebpTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, g.cwebp, "-quiet", "-metadata", "none",
+		"-q", fmt.Sprint(g.quality), lossless, "-o", path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("cwebp: %w: %s", err, out)
+	}
+	return nil
+}
+
+// encodeFile writes img to path as JPEG or PNG
+func encodeFile(path, mime string, img image.Image, quality int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if mime == "image/jpeg" {
+		err = jpeg.Encode(f, img, &jpeg.Options{Quality: quality})
+	} else {
+		err = png.Encode(f, img)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,163 @@
+// backend/internal/variants/variants_test.go
+package variants_test
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/supraik/Freelance-Portfolio/internal/exif/exiftest"
+	"github.com/supraik/Freelance-Portfolio/internal/variants"
+)
+
+func newGenerator(widths ...int) *variants.Generator {
+	return variants.New(variants.Options{Widths: widths, Quality: 80})
+}
+
+// writeFile writes data to name in a temporary directory and returns its path
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, exiftest.Quadrants(width, height)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestPlan(t *testing.T) {
+	g := newGenerator(960, 480, 480, 2000) // sorted and deduplicated
+
+	tests := []struct {
+		name string
+		file []byte
+		want []variants.Variant
+	}{
+		{"png", encodePNG(t, 1200, 800), []variants.Variant{
+			{Name: "a-480.png", Width: 480, Height: 320, Type: "image/png"},
+			{Name: "a-960.png", Width: 960, Height: 640, Type: "image/png"},
+		}},
+		{"jpeg", exiftest.JPEG(t, exiftest.Quadrants(1000, 333), nil), []variants.Variant{
+			{Name: "a-480.jpg", Width: 480, Height: 160, Type: "image/jpeg"},
+			{Name: "a-960.jpg", Width: 960, Height: 320, Type: "image/jpeg"},
+		}},
+		{"turned jpeg", exiftest.JPEG(t, exiftest.Quadrants(1200, 800), exiftest.Orientation(6)), []variants.Variant{
+			{Name: "a-480.jpg", Width: 480, Height: 720, Type: "image/jpeg"},
+		}},
+		{"no upscaling", encodePNG(t, 960, 100), []variants.Variant{
+			{Name: "a-480.png", Width: 480, Height: 50, Type: "image/png"},
+		}},
+		{"smaller than every width", encodePNG(t, 480, 480), []variants.Variant{}},
+	}
+	for _, tt := range tests {
+		got, err := g.Plan(bytes.NewReader(tt.file), "a")
+		if err != nil {
+			t.Fatalf("%s: Plan: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Plan = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlanNothing(t *testing.T) {
+	webp := []byte("RIFF\x1a\x00\x00\x00WEBPVP8L\x0d\x00\x00\x00\x2f\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+	if got, err := newGenerator(480).Plan(bytes.NewReader(webp), "a"); err != nil || got != nil {
+		t.Errorf("Plan(webp) = %v, %v, want nothing", got, err)
+	}
+	if got, err := newGenerator().Plan(bytes.NewReader(encodePNG(t, 1000, 1000)), "a"); err != nil || got != nil {
+		t.Errorf("Plan without widths = %v, %v, want nothing", got, err)
+	}
+	if newGenerator().Enabled() {
+		t.Error("a generator without widths is enabled")
+	}
+	if g := variants.New(variants.Options{Widths: []int{480}, CWebP: "no-such-cwebp-command"}); g.WebP() {
+		t.Error("WebP enabled without a cwebp command")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	src := writeFile(t, "a.jpg", exiftest.JPEG(t, exiftest.Quadrants(1200, 800), exiftest.Orientation(6)))
+	dir := filepath.Join(t.TempDir(), "variants")
+
+	list, err := newGenerator(480, 640).Generate(src, dir, "a", false)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("Generate = %+v, want 2 variants", list)
+	}
+	for _, v := range list {
+		f, err := os.Open(filepath.Join(dir, v.Name))
+		if err != nil {
+			t.Fatalf("variant %s: %v", v.Name, err)
+		}
+		img, format, err := image.Decode(f)
+		f.Close()
+		if err != nil || format != "jpeg" {
+			t.Fatalf("variant %s: format %q, %v", v.Name, format, err)
+		}
+		if b := img.Bounds(); b.Dx() != v.Width || b.Dy() != v.Height {
+			t.Errorf("variant %s is %dx%d, want %dx%d", v.Name, b.Dx(), b.Dy(), v.Width, v.Height)
+		}
+		// Turned right, the blue bottom left quarter is shown top left
+		if r, g, b, _ := img.At(5, 5).RGBA(); b < 0x8000 || r > 0x8000 || g > 0x8000 {
+			t.Errorf("variant %s: top left %v, want blue", v.Name, img.At(5, 5))
+		}
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(tmp) != 0 {
+		t.Errorf("temporary files left: %v", tmp)
+	}
+}
+
+func TestGenerateKeepsExisting(t *testing.T) {
+	src := writeFile(t, "a.png", encodePNG(t, 1000, 500))
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "a-480.png")
+	if err := os.WriteFile(existing, []byte("kept"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(480, 640)
+
+	if _, err := g.Generate(src, dir, "a", false); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "kept" {
+		t.Error("an existing variant was rewritten without replace")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a-640.png")); err != nil {
+		t.Errorf("missing variant not written: %v", err)
+	}
+
+	if _, err := g.Generate(src, dir, "a", true); err != nil {
+		t.Fatalf("Generate(replace): %v", err)
+	}
+	if data, _ := os.ReadFile(existing); string(data) == "kept" {
+		t.Error("an existing variant was kept with replace")
+	}
+}
+
+func TestGenerateGIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := gif.Encode(&buf, exiftest.Quadrants(600, 600), nil); err != nil {
+		t.Fatal(err)
+	}
+	list, err := newGenerator(300).Generate(writeFile(t, "a.gif", buf.Bytes()), t.TempDir(), "a", false)
+	want := []variants.Variant{{Name: "a-300.png", Width: 300, Height: 300, Type: "image/png"}}
+	if err != nil || !reflect.DeepEqual(list, want) {
+		t.Errorf("Generate(gif) = %+v, %v, want %+v", list, err, want)
+	}
+}
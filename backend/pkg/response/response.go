@@ -31,6 +31,16 @@ func Error(c *gin.Context, status int, message string) {
 	})
 }
 
+// ErrorWithData sends an error response that carries data, such as the
+// current state of a resource an update conflicted with
+func ErrorWithData(c *gin.Context, status int, message string, data interface{}) {
+	c.JSON(status, APIResponse{
+		Success: false,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // ValidationError formats validation errors
 func ValidationError(c *gin.Context, err error) {
 	var errors []map[string]string
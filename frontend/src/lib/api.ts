@@ -103,6 +103,7 @@ export const galleryAPI = {
     // Then create the gallery image entry
     const imageData = {
       src: imageUrl,
+      original_filename: file.name,
       alt: alt || file.name,
       aspect_ratio: aspectRatio,
     };
@@ -126,6 +127,7 @@ export const galleryAPI = {
     // Update image record
     const response = await api.put(`/admin/images/${imageId}`, {
       src: imageUrl,
+      original_filename: file.name,
       alt: alt,
     });
     return response.data;